@@ -0,0 +1,158 @@
+package pigo8
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+)
+
+// --- Asset source ---
+
+// AssetSource controls where spritesheet.json, map.json and palette.hex are
+// loaded from: files on disk, resources embedded in the binary (see
+// RegisterEmbeddedResources and cmd/embedgen), or both.
+type AssetSource int
+
+const (
+	// FilesystemThenEmbedded loads an asset from disk if the file exists and
+	// falls back to the embedded copy otherwise. This is the default.
+	FilesystemThenEmbedded AssetSource = iota
+	// Filesystem only loads assets from disk and never uses embedded ones.
+	// Use it during development so edits are never shadowed by stale embeds.
+	Filesystem
+	// Embedded only uses embedded assets and ignores files on disk. Use it
+	// for release builds, so files next to the binary can't change the game.
+	Embedded
+)
+
+// String returns the name of the asset source.
+func (s AssetSource) String() string {
+	switch s {
+	case FilesystemThenEmbedded:
+		return "FilesystemThenEmbedded"
+	case Filesystem:
+		return "Filesystem"
+	case Embedded:
+		return "Embedded"
+	default:
+		return "Unknown"
+	}
+}
+
+// assetSource is the active AssetSource.
+var assetSource = FilesystemThenEmbedded
+
+// SetAssetSource chooses where assets are loaded from. Call it before
+// PlayGameWith (or before the first Spr, Map or Mget), as assets are
+// loaded once at startup.
+//
+// On disk, each file is looked up in the current directory first and then
+// in the assets, resources, data and static folders.
+//
+// Example:
+//
+//	func main() {
+//		if os.Getenv("DEV") != "" {
+//			p8.SetAssetSource(p8.Filesystem) // always use the files being edited
+//		}
+//		p8.InsertGame(&Game{})
+//		p8.Play()
+//	}
+func SetAssetSource(source AssetSource) {
+	if source < FilesystemThenEmbedded || source > Embedded {
+		log.Printf("Warning: SetAssetSource() called with unknown source %d. Ignoring.", source)
+		return
+	}
+	assetSource = source
+}
+
+// GetAssetSource returns the active asset source.
+func GetAssetSource() AssetSource {
+	return assetSource
+}
+
+// readAssetFile reads the asset file name from disk or from embedded
+// resources (using loadEmbedded), following the active AssetSource.
+func readAssetFile(name string, loadEmbedded func() ([]byte, error)) ([]byte, error) {
+	if assetSource != Embedded {
+		if path := findAssetFile(name); path != "" {
+			data, err := os.ReadFile(path)
+			if err == nil {
+				log.Printf("Using %s file from %s", name, path)
+				return data, nil
+			}
+			if assetSource == Filesystem {
+				return nil, fmt.Errorf("error reading %s: %w", path, err)
+			}
+		} else if assetSource == Filesystem {
+			return nil, fmt.Errorf("%s not found on disk (asset source is %s)", name, assetSource)
+		}
+		log.Printf("%s not found on disk, trying embedded resources", name)
+	}
+
+	data, err := loadEmbedded()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// --- Asset loading ---
+
+// strictAssets makes a spritesheet that fails to load lazily a fatal error,
+// see Settings.StrictAssets.
+var strictAssets bool
+
+// EnsureAssetsLoaded loads spritesheet.json and map.json now, instead of on
+// the first Spr or Map, and returns an error if either is missing or invalid.
+// Call it in Init to handle a broken install gracefully, e.g. by showing an
+// error screen; otherwise a missing asset only logs a warning and the sprites
+// or map stay empty. Games that don't use one of the files can ignore its
+// error.
+//
+// Example:
+//
+//	func (g *Game) Init() {
+//		if err := EnsureAssetsLoaded(); err != nil {
+//			g.fatalError = err.Error()
+//		}
+//	}
+func EnsureAssetsLoaded() error {
+	var errs []error
+	if len(currentSprites) == 0 {
+		loaded, err := loadSpritesheet()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error loading spritesheet.json: %w", err))
+		} else {
+			currentSprites = loaded
+		}
+	}
+	if _, err := loadAndParseMapJSON("map.json"); err != nil {
+		errs = append(errs, fmt.Errorf("error loading map.json: %w", err))
+	}
+	ensureStreamingSystemInitialized()
+	return errors.Join(errs...)
+}
+
+// ensureSpritesLoaded loads the spritesheet if no sprites are loaded yet and
+// reports whether sprites are available. caller names the function in the
+// warning. If loading fails, the warning is logged once and the sprites stay
+// empty instead of being loaded again on every call, unless strictAssets
+// makes it fatal.
+func ensureSpritesLoaded(caller string) bool {
+	if currentSprites != nil {
+		return true
+	}
+	loaded, err := loadSpritesheet()
+	if err != nil {
+		if strictAssets {
+			log.Fatalf("Fatal: Failed to load required spritesheet for %s(): %v", caller, err)
+		}
+		logWarningOnce("Warning: %s() called but failed to load spritesheet: %v. No sprites are available.", caller, err)
+		currentSprites = []spriteInfo{}
+		return false
+	}
+	currentSprites = loaded
+	return true
+}
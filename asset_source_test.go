@@ -0,0 +1,88 @@
+package pigo8
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAssetFile(t *testing.T) {
+	t.Cleanup(func() { SetAssetSource(FilesystemThenEmbedded) })
+	t.Chdir(t.TempDir())
+
+	embedded := func() ([]byte, error) { return []byte("embedded"), nil }
+	noEmbedded := func() ([]byte, error) { return nil, errors.New("no embedded file") }
+	require.NoError(t, os.WriteFile("map.json", []byte("disk"), 0o644))
+
+	tests := []struct {
+		source   AssetSource
+		name     string
+		embedded func() ([]byte, error)
+		want     string
+		wantErr  bool
+	}{
+		{FilesystemThenEmbedded, "map.json", embedded, "disk", false},
+		{FilesystemThenEmbedded, "palette.hex", embedded, "embedded", false},
+		{FilesystemThenEmbedded, "palette.hex", noEmbedded, "", true},
+		{Filesystem, "map.json", embedded, "disk", false},
+		{Filesystem, "palette.hex", embedded, "", true},
+		{Embedded, "map.json", embedded, "embedded", false},
+		{Embedded, "map.json", noEmbedded, "", true},
+	}
+	for _, tt := range tests {
+		SetAssetSource(tt.source)
+		data, err := readAssetFile(tt.name, tt.embedded)
+		if tt.wantErr {
+			assert.Error(t, err, "%s with source %s", tt.name, tt.source)
+			continue
+		}
+		require.NoError(t, err, "%s with source %s", tt.name, tt.source)
+		assert.Equal(t, tt.want, string(data), "%s with source %s", tt.name, tt.source)
+	}
+}
+
+func TestSetAssetSource(t *testing.T) {
+	t.Cleanup(func() { SetAssetSource(FilesystemThenEmbedded) })
+
+	assert.Equal(t, FilesystemThenEmbedded, GetAssetSource(), "default should keep the old behavior")
+	SetAssetSource(Embedded)
+	assert.Equal(t, Embedded, GetAssetSource())
+	SetAssetSource(AssetSource(9))
+	assert.Equal(t, Embedded, GetAssetSource(), "unknown sources should be ignored")
+	assert.Equal(t, "Filesystem", Filesystem.String())
+}
+
+func TestEnsureSpritesLoaded_FailsSoft(t *testing.T) {
+	savedSprites := currentSprites
+	t.Cleanup(func() {
+		currentSprites = savedSprites
+		SetAssetSource(FilesystemThenEmbedded)
+	})
+	t.Chdir(t.TempDir())
+	SetAssetSource(Filesystem)
+
+	currentSprites = nil
+	assert.False(t, ensureSpritesLoaded("Spr"), "no spritesheet.json on disk")
+	assert.NotNil(t, currentSprites, "the failed load isn't retried on every call")
+	assert.Empty(t, currentSprites)
+	assert.True(t, ensureSpritesLoaded("Spr"))
+}
+
+func TestEnsureAssetsLoaded(t *testing.T) {
+	useTestConsoleState(t)
+	t.Cleanup(func() { SetAssetSource(FilesystemThenEmbedded) })
+	t.Chdir(t.TempDir())
+	SetAssetSource(Filesystem)
+
+	err := EnsureAssetsLoaded()
+	assert.ErrorContains(t, err, "spritesheet.json")
+	assert.ErrorContains(t, err, "map.json")
+
+	require.NoError(t, os.WriteFile("map.json", []byte(`{"width": 16, "height": 16, "cells": []}`), 0o644))
+	err = EnsureAssetsLoaded()
+	assert.ErrorContains(t, err, "spritesheet.json")
+	assert.NotContains(t, err.Error(), "map.json")
+}
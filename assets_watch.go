@@ -0,0 +1,214 @@
+package pigo8
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// --- Asset hot reloading ---
+
+// assetPollInterval is how often watched asset files are checked for changes.
+const assetPollInterval = 500 * time.Millisecond
+
+// watchedAsset is an asset file on disk that is reloaded when it changes.
+type watchedAsset struct {
+	path   string
+	reload func(path string) error
+
+	// Only used by the polling goroutine
+	modTime  time.Time
+	size     int64
+	changing bool // Changed at the last poll; reloaded once it stays the same for a poll
+
+	// Guarded by assetWatchMutex
+	pending bool
+}
+
+var (
+	assetWatchMutex  sync.Mutex
+	assetsToReload   []*watchedAsset // Changed assets waiting for the next Update
+	onAssetsReloaded func(paths []string)
+	assetWatchStop   chan struct{}
+)
+
+// SetOnAssetsReloaded registers a function that is called, before the next
+// Update, after Settings.WatchAssets reloaded changed asset files. It
+// receives the paths of the files that were reloaded. Pass nil to remove it.
+//
+// Example:
+//
+//	SetOnAssetsReloaded(func(paths []string) {
+//		log.Printf("reloaded %v", paths)
+//		g.rebuildLevel() // e.g. re-read map tiles cached by the game
+//	})
+func SetOnAssetsReloaded(fn func(paths []string)) {
+	assetWatchMutex.Lock()
+	defer assetWatchMutex.Unlock()
+	onAssetsReloaded = fn
+}
+
+// findAssetFile returns the path of an asset file on disk, looking in the
+// same places the loaders do, or "" if it only exists embedded (or not at all).
+func findAssetFile(name string) string {
+	for _, dir := range []string{"", "assets", "resources", "data", "static"} {
+		path := filepath.Join(dir, name)
+		if fileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// startAssetWatcher starts polling spritesheet.json, map.json and palette.hex
+// for changes. Only files on disk are watched; embedded assets never change,
+// so with embedded assets only it does nothing.
+func startAssetWatcher() {
+	if assetSource == Embedded {
+		log.Println("WatchAssets: asset source is Embedded, nothing to watch")
+		return
+	}
+
+	candidates := []struct {
+		name   string
+		reload func(path string) error
+	}{
+		{"spritesheet.json", reloadSpritesheetFile},
+		{"map.json", reloadMapFile},
+		{"palette.hex", reloadPaletteFile},
+	}
+
+	var assets []*watchedAsset
+	for _, c := range candidates {
+		path := findAssetFile(c.name)
+		if path == "" {
+			continue
+		}
+		asset := &watchedAsset{path: path, reload: c.reload}
+		if info, err := os.Stat(path); err == nil {
+			asset.modTime, asset.size = info.ModTime(), info.Size()
+		}
+		assets = append(assets, asset)
+	}
+
+	if len(assets) == 0 {
+		log.Println("WatchAssets: no asset files found on disk, nothing to watch (embedded assets are not reloaded)")
+		return
+	}
+
+	stop := make(chan struct{})
+	assetWatchStop = stop
+	for _, asset := range assets {
+		log.Printf("WatchAssets: watching %s", asset.path)
+	}
+	go pollAssets(assets, stop)
+}
+
+// stopAssetWatcher stops the polling goroutine started by startAssetWatcher.
+func stopAssetWatcher() {
+	if assetWatchStop != nil {
+		close(assetWatchStop)
+		assetWatchStop = nil
+	}
+}
+
+// pollAssets checks the assets for changes until stop is closed.
+func pollAssets(assets []*watchedAsset, stop <-chan struct{}) {
+	ticker := time.NewTicker(assetPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			for _, asset := range assets {
+				if asset.poll() {
+					queueAssetReload(asset)
+				}
+			}
+		}
+	}
+}
+
+// poll reports whether the asset file changed and is ready to be reloaded.
+// A change is only reported once the file stayed the same for a whole poll,
+// so a file that is still being written is not read half-way.
+func (a *watchedAsset) poll() bool {
+	info, err := os.Stat(a.path)
+	if err != nil {
+		return false // Editors may delete and recreate the file while saving
+	}
+	if !info.ModTime().Equal(a.modTime) || info.Size() != a.size {
+		a.modTime, a.size = info.ModTime(), info.Size()
+		a.changing = true
+		return false
+	}
+	if a.changing {
+		a.changing = false
+		return true
+	}
+	return false
+}
+
+// queueAssetReload schedules an asset to be reloaded before the next Update.
+func queueAssetReload(asset *watchedAsset) {
+	assetWatchMutex.Lock()
+	defer assetWatchMutex.Unlock()
+	if !asset.pending {
+		asset.pending = true
+		assetsToReload = append(assetsToReload, asset)
+	}
+}
+
+// applyAssetReloads reloads the changed assets and calls the OnAssetsReloaded
+// callback. It runs on the game loop between frames, so Update and Draw never
+// see a half-reloaded asset.
+func applyAssetReloads() {
+	assetWatchMutex.Lock()
+	queued := assetsToReload
+	assetsToReload = nil
+	for _, asset := range queued {
+		asset.pending = false
+	}
+	fn := onAssetsReloaded
+	assetWatchMutex.Unlock()
+
+	var reloaded []string
+	for _, asset := range queued {
+		if err := asset.reload(asset.path); err != nil {
+			log.Printf("Warning: WatchAssets: failed to reload %s: %v", asset.path, err)
+			continue
+		}
+		reloaded = append(reloaded, asset.path)
+	}
+
+	if len(reloaded) > 0 && fn != nil {
+		fn(reloaded)
+	}
+}
+
+// reloadSpritesheetFile reloads the spritesheet and drops everything cached from the old one.
+func reloadSpritesheetFile(path string) error {
+	if err := LoadSpritesheet(path); err != nil {
+		return err
+	}
+	ClearSpriteCache()
+	ClearFlagCache()
+	mapCacheIsValid = false
+	return nil
+}
+
+// reloadPaletteFile reloads a palette.hex file.
+func reloadPaletteFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading palette file %s: %w", path, err)
+	}
+	if err := applyHexPalette(data); err != nil {
+		return fmt.Errorf("error parsing palette file %s: %w", path, err)
+	}
+	return nil
+}
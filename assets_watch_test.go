@@ -0,0 +1,69 @@
+package pigo8
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchedAssetPoll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "map.json")
+	require.NoError(t, os.WriteFile(path, []byte("{}"), 0o644))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	asset := &watchedAsset{path: path, modTime: info.ModTime(), size: info.Size()}
+
+	assert.False(t, asset.poll(), "an unchanged file should not be reloaded")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"width":16}`), 0o644))
+	require.NoError(t, os.Chtimes(path, time.Now(), info.ModTime().Add(time.Second)))
+	assert.False(t, asset.poll(), "a file that just changed may still be being written")
+	assert.True(t, asset.poll(), "a file that stayed the same for a poll should be reloaded")
+	assert.False(t, asset.poll(), "a change should be reported once")
+
+	require.NoError(t, os.Remove(path))
+	assert.False(t, asset.poll(), "a missing file should be ignored")
+}
+
+func TestApplyAssetReloads(t *testing.T) {
+	t.Cleanup(func() {
+		SetOnAssetsReloaded(nil)
+		assetsToReload = nil
+	})
+
+	var reloads []string
+	good := &watchedAsset{path: "spritesheet.json", reload: func(path string) error {
+		reloads = append(reloads, path)
+		return nil
+	}}
+	bad := &watchedAsset{path: "map.json", reload: func(string) error { return errors.New("broken JSON") }}
+
+	var notified []string
+	SetOnAssetsReloaded(func(paths []string) { notified = paths })
+
+	queueAssetReload(good)
+	queueAssetReload(good) // Queued twice before the next frame, reloaded once
+	queueAssetReload(bad)
+	applyAssetReloads()
+
+	assert.Equal(t, []string{"spritesheet.json"}, reloads)
+	assert.Equal(t, []string{"spritesheet.json"}, notified, "only assets that reloaded should be reported")
+
+	notified = nil
+	applyAssetReloads()
+	assert.Nil(t, notified, "nothing should be reported without changes")
+}
+
+func TestFindAssetFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.Mkdir("assets", 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join("assets", "map.json"), []byte("{}"), 0o644))
+
+	assert.Equal(t, filepath.Join("assets", "map.json"), findAssetFile("map.json"))
+	assert.Equal(t, "", findAssetFile("palette.hex"), "assets that are not on disk should not be watched")
+}
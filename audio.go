@@ -3,11 +3,15 @@ package pigo8
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/audio/wav"
@@ -23,6 +27,12 @@ type audioPlayer struct {
 	audioContext *audio.Context
 	musicPlayers map[int]*audio.Player
 	musicData    map[int][]byte
+	musicLoop    map[int]bool // Tracks loaded with LoadMusic loop until stopped
+	musicLength  map[int]time.Duration
+	lastMusic    int             // Track most recently started by Music, -1 if none
+	sfxData      map[int][]byte  // Decoded PCM of the sound effects loaded with LoadSfx
+	sfxPlayers   []*audio.Player // Sound effects started by Sfx that may still be playing
+	menuPaused   []*audio.Player // Players paused while the pause menu is open, see SetPauseAudio
 	mutex        sync.Mutex
 }
 
@@ -38,6 +48,10 @@ func getAudioPlayer() *audioPlayer {
 			audioContext: audioContext,
 			musicPlayers: make(map[int]*audio.Player),
 			musicData:    make(map[int][]byte),
+			musicLoop:    make(map[int]bool),
+			musicLength:  make(map[int]time.Duration),
+			lastMusic:    -1,
+			sfxData:      make(map[int][]byte),
 			mutex:        sync.Mutex{},
 		}
 		// Load all audio files at initialization
@@ -103,6 +117,9 @@ func (ap *audioPlayer) loadAudioFiles() {
 // If n is a valid audio ID, it plays that audio file.
 // If exclusive is true, it stops all other audio files before playing.
 func Music(n int, exclusive ...bool) {
+	if simOnly {
+		return
+	}
 	if n == -1 {
 		// Special case: stop all music
 		StopMusic(-1)
@@ -115,6 +132,7 @@ func Music(n int, exclusive ...bool) {
 	ap := getAudioPlayer()
 	ap.mutex.Lock()
 	defer ap.mutex.Unlock()
+	musicPositionCache.valid = false
 
 	// Stop other audio if requested
 	if shouldBeExclusive {
@@ -147,6 +165,7 @@ func Music(n int, exclusive ...bool) {
 			log.Printf("Error rewinding player: %v", err)
 		}
 		player.Play()
+		ap.lastMusic = n
 		return
 	}
 
@@ -158,7 +177,12 @@ func Music(n int, exclusive ...bool) {
 		return
 	}
 
-	player, err = ap.audioContext.NewPlayer(wavReader)
+	var stream io.Reader = wavReader
+	if ap.musicLoop[n] {
+		stream = audio.NewInfiniteLoop(wavReader, wavReader.Length())
+	}
+
+	player, err = ap.audioContext.NewPlayer(stream)
 	if err != nil {
 		log.Printf("Error creating audio player (ID: %d): %v", n, err)
 		return
@@ -166,6 +190,8 @@ func Music(n int, exclusive ...bool) {
 
 	// Store the player and play
 	ap.musicPlayers[n] = player
+	ap.musicLength[n] = pcmDuration(wavReader.Length())
+	ap.lastMusic = n
 	player.Play()
 }
 
@@ -175,11 +201,13 @@ func StopMusic(id int) {
 	ap := getAudioPlayer()
 	ap.mutex.Lock()
 	defer ap.mutex.Unlock()
+	musicPositionCache.valid = false
 
 	if id == -1 {
 		// Stop all audio
 		for _, player := range ap.musicPlayers {
 			if player != nil {
+				ap.menuPaused = forgetMenuPaused(ap.menuPaused, player)
 				player.Pause()
 				if err := player.Rewind(); err != nil {
 					log.Printf("Error rewinding player: %v", err)
@@ -192,9 +220,203 @@ func StopMusic(id int) {
 	// Stop specific audio
 	player, exists := ap.musicPlayers[id]
 	if exists && player != nil {
+		ap.menuPaused = forgetMenuPaused(ap.menuPaused, player)
 		player.Pause()
 		if err := player.Rewind(); err != nil {
 			log.Printf("Error rewinding player: %v", err)
 		}
 	}
 }
+
+// LoadMusic loads a WAV file into music slot n, replacing the track that was
+// there (including one embedded as musicN.wav). Music(n) then plays it, and
+// it loops until StopMusic(n) or Music(-1) stops it. Mono and 8-bit files are
+// converted to 16-bit stereo, and other sample rates are resampled to the
+// engine's 44100 Hz when the track plays.
+//
+// Only WAV files are supported; OGG and other formats return an error.
+//
+// Example:
+//
+//	func (g *Game) Init() {
+//		if err := LoadMusic(0, "assets/theme.wav"); err != nil {
+//			log.Printf("no theme music: %v", err)
+//		}
+//		Music(0)
+//	}
+func LoadMusic(n int, path string) error {
+	if n < 0 {
+		return fmt.Errorf("invalid music slot %d", n)
+	}
+	data, err := readMusicFile(path)
+	if err != nil {
+		return err
+	}
+	setMusicData(n, data, path)
+	return nil
+}
+
+// readMusicFile reads and checks a music file without touching the audio
+// player, so Preload can run it off the game loop.
+func readMusicFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading music file %s: %w", path, err)
+	}
+	// Decode the header now, so a broken file fails here and not when it plays
+	if _, err := decodeAudioFile(data, filepath.Ext(path)); err != nil {
+		return nil, fmt.Errorf("error decoding music file %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// setMusicData puts the music read from path into slot n.
+func setMusicData(n int, data []byte, path string) {
+	ap := getAudioPlayer()
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	// The old track's player would keep playing the old data
+	if player := ap.musicPlayers[n]; player != nil {
+		if err := player.Close(); err != nil {
+			log.Printf("Error closing player: %v", err)
+		}
+		delete(ap.musicPlayers, n)
+	}
+	ap.musicData[n] = data
+	ap.musicLoop[n] = true
+	log.Printf("Loaded music file: %s (ID: %d)", path, n)
+}
+
+// LoadSfx loads a WAV file into sound effect slot n, replacing the sound that
+// was there. Sfx(n) then plays it once. The file is decoded and resampled to
+// the engine's 44100 Hz right away, so starting a sound effect is cheap.
+//
+// Only WAV files are supported; OGG and other formats return an error.
+//
+// Example:
+//
+//	func (g *Game) Init() {
+//		if err := LoadSfx(0, "assets/jump.wav"); err != nil {
+//			log.Printf("no jump sound: %v", err)
+//		}
+//	}
+func LoadSfx(n int, path string) error {
+	if n < 0 {
+		return fmt.Errorf("invalid sfx slot %d", n)
+	}
+	pcm, err := readSfxFile(path)
+	if err != nil {
+		return err
+	}
+	setSfxData(n, pcm, path)
+	return nil
+}
+
+// readSfxFile reads and decodes a sound effect file without touching the
+// audio player, so Preload can run it off the game loop.
+func readSfxFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading sfx file %s: %w", path, err)
+	}
+	stream, err := decodeAudioFile(data, filepath.Ext(path))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding sfx file %s: %w", path, err)
+	}
+	pcm, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding sfx file %s: %w", path, err)
+	}
+	return pcm, nil
+}
+
+// setSfxData puts the decoded sound effect read from path into slot n.
+func setSfxData(n int, pcm []byte, path string) {
+	ap := getAudioPlayer()
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+	ap.sfxData[n] = pcm
+	log.Printf("Loaded sfx file: %s (ID: %d)", path, n)
+}
+
+// Sfx plays the sound effect in slot n (see LoadSfx) once. Calling it again
+// before the sound ends starts another copy on top, so rapid shots or hits
+// all get heard. If n is -1, it stops all sound effects.
+//
+// Example:
+//
+//	if Btnp(O) && g.player.Jump() {
+//		Sfx(0)
+//	}
+func Sfx(n int) {
+	playSfx(n, 0, 1)
+}
+
+// playSfx plays sound effect n with a stereo pan (-1 to 1) and a volume (0 to 1),
+// or stops all sound effects if n is -1.
+func playSfx(n int, pan, volume float64) {
+	if simOnly {
+		return
+	}
+	ap := getAudioPlayer()
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	ap.pruneSfxPlayers(n == -1)
+	if n == -1 {
+		return
+	}
+
+	pcm, exists := ap.sfxData[n]
+	if !exists {
+		log.Printf("Warning: Sfx with ID %d not found", n)
+		return
+	}
+	var player *audio.Player
+	if pan == 0 {
+		player = ap.audioContext.NewPlayerFromBytes(pcm)
+	} else {
+		var err error
+		player, err = ap.audioContext.NewPlayer(&panStream{src: bytes.NewReader(pcm), pan: pan})
+		if err != nil {
+			log.Printf("Error creating audio player (ID: %d): %v", n, err)
+			return
+		}
+	}
+	player.SetVolume(volume)
+	player.Play()
+	ap.sfxPlayers = append(ap.sfxPlayers, player)
+}
+
+// pruneSfxPlayers closes the sound effect players that have finished, or all
+// of them if stopAll is set. The caller must hold ap.mutex.
+func (ap *audioPlayer) pruneSfxPlayers(stopAll bool) {
+	playing := ap.sfxPlayers[:0]
+	for _, player := range ap.sfxPlayers {
+		if !stopAll && (player.IsPlaying() || slices.Contains(ap.menuPaused, player)) {
+			playing = append(playing, player)
+			continue
+		}
+		ap.menuPaused = forgetMenuPaused(ap.menuPaused, player)
+		if err := player.Close(); err != nil {
+			log.Printf("Error closing player: %v", err)
+		}
+	}
+	clear(ap.sfxPlayers[len(playing):])
+	ap.sfxPlayers = playing
+}
+
+// decodeAudioFile checks that data is a WAV file, using the file extension ext
+// to name the format in the error otherwise, and returns a 16-bit stereo
+// stream at sampleRate.
+func decodeAudioFile(data []byte, ext string) (*wav.Stream, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("RIFF")):
+	case bytes.HasPrefix(data, []byte("OggS")) || strings.EqualFold(ext, ".ogg"):
+		return nil, fmt.Errorf("OGG audio is not supported, convert the file to WAV")
+	default:
+		return nil, fmt.Errorf("unsupported audio format %q, only WAV files are supported", ext)
+	}
+	return wav.DecodeWithSampleRate(sampleRate, bytes.NewReader(data))
+}
@@ -19,6 +19,7 @@ type audioPlayerF32 struct {
 	audioContext *audio.Context
 	musicPlayers map[int]*audio.Player
 	musicData    map[int][]byte
+	menuPaused   []*audio.Player // Players paused while the pause menu is open, see SetPauseAudio
 	mutex        sync.Mutex
 }
 
@@ -101,6 +102,9 @@ func (ap *audioPlayerF32) loadAudioFiles() {
 // If n is a valid audio ID, it plays that audio file.
 // If exclusive is true, it stops all other audio files before playing.
 func MusicF32(n int, exclusive ...bool) {
+	if simOnly {
+		return
+	}
 	if n == -1 {
 		// Special case: stop all music
 		StopMusicF32(-1)
@@ -178,6 +182,7 @@ func StopMusicF32(id int) {
 		// Stop all audio
 		for _, player := range ap.musicPlayers {
 			if player != nil {
+				ap.menuPaused = forgetMenuPaused(ap.menuPaused, player)
 				player.Pause()
 				if err := player.Rewind(); err != nil {
 					log.Printf("Error rewinding player: %v", err)
@@ -190,6 +195,7 @@ func StopMusicF32(id int) {
 	// Stop specific audio
 	player, exists := ap.musicPlayers[id]
 	if exists && player != nil {
+		ap.menuPaused = forgetMenuPaused(ap.menuPaused, player)
 		player.Pause()
 		if err := player.Rewind(); err != nil {
 			log.Printf("Error rewinding player: %v", err)
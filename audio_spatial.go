@@ -0,0 +1,92 @@
+package pigo8
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// --- Panned sound effects ---
+
+// SfxPan plays the sound effect in slot n (see LoadSfx) once, panned between
+// the left (-1) and right (1) speaker. 0 plays it centered like Sfx; values
+// outside -1 to 1 are clamped.
+//
+// Panning works like a balance control: panning right fades out the left
+// channel and leaves the right one at full volume. A stereo sound keeps its
+// own stereo image, so a sound that is only on the left channel goes silent
+// when panned fully right.
+//
+// Example:
+//
+//	SfxPan(2, -0.5) // Somewhat to the left
+func SfxPan(n int, pan float64) {
+	playSfx(n, clampPan(pan), 1)
+}
+
+// SfxAt plays the sound effect in slot n (see LoadSfx) once, panned by where
+// worldX is relative to the center of the camera view: the left edge of the
+// screen is fully left, the right edge fully right, and anything beyond the
+// edges stays fully to that side.
+//
+// With a falloff distance in pixels, the sound also gets quieter the further
+// worldX is from the center of the view, and is silent at falloff pixels
+// away. Without it, the sound plays at full volume.
+//
+// Example:
+//
+//	// An explosion anywhere in the level, heard up to two screens away
+//	SfxAt(3, enemy.x, 256)
+func SfxAt[X Number](n int, worldX X, falloff ...float64) {
+	pan, volume := sfxPanAndVolume(float64(worldX), cameraX, float64(GetScreenWidth()), falloff...)
+	if volume <= 0 {
+		return // Too far away to be heard
+	}
+	playSfx(n, pan, volume)
+}
+
+// sfxPanAndVolume returns the pan and volume of a sound at worldX heard from
+// a view of screenWidth pixels starting at camX.
+func sfxPanAndVolume(worldX, camX, screenWidth float64, falloff ...float64) (pan, volume float64) {
+	halfWidth := screenWidth / 2
+	if halfWidth <= 0 {
+		return 0, 1
+	}
+	dx := worldX - (camX + halfWidth)
+	pan = clampPan(dx / halfWidth)
+
+	volume = 1
+	if len(falloff) > 0 && falloff[0] > 0 {
+		volume = math.Max(0, 1-math.Abs(dx)/falloff[0])
+	}
+	return pan, volume
+}
+
+// clampPan limits a pan to the -1 (left) to 1 (right) range.
+func clampPan(pan float64) float64 {
+	return math.Max(-1, math.Min(1, pan))
+}
+
+// panStream scales the channels of 16-bit stereo PCM read from src to pan it.
+type panStream struct {
+	src io.Reader
+	pan float64
+}
+
+// Read reads from src and applies the pan to every complete sample frame.
+func (s *panStream) Read(p []byte) (int, error) {
+	n, err := s.src.Read(p)
+	left, right := panGains(s.pan)
+	for i := 0; i+4 <= n; i += 4 {
+		l := float64(int16(binary.LittleEndian.Uint16(p[i:])))
+		r := float64(int16(binary.LittleEndian.Uint16(p[i+2:])))
+		binary.LittleEndian.PutUint16(p[i:], uint16(int16(l*left)))
+		binary.LittleEndian.PutUint16(p[i+2:], uint16(int16(r*right)))
+	}
+	return n, err
+}
+
+// panGains returns the gains of the left and right channel for a pan.
+func panGains(pan float64) (left, right float64) {
+	return math.Min(1-pan, 1), math.Min(1+pan, 1)
+}
@@ -0,0 +1,66 @@
+package pigo8
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSfxPanAndVolume(t *testing.T) {
+	tests := []struct {
+		name         string
+		worldX, camX float64
+		falloff      []float64
+		pan, volume  float64
+	}{
+		{"center of the view", 64, 0, nil, 0, 1},
+		{"left edge", 0, 0, nil, -1, 1},
+		{"halfway to the right edge", 96, 0, nil, 0.5, 1},
+		{"beyond the right edge is clamped", 500, 0, nil, 1, 1},
+		{"relative to the camera", 164, 100, nil, 0, 1},
+		{"falloff halfway", 128, 0, []float64{128}, 1, 0.5},
+		{"beyond the falloff is silent", 0, 500, []float64{128}, -1, 0},
+		{"zero falloff is ignored", 0, 0, []float64{0}, -1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pan, volume := sfxPanAndVolume(tt.worldX, tt.camX, 128, tt.falloff...)
+			assert.InDelta(t, tt.pan, pan, 1e-9)
+			assert.InDelta(t, tt.volume, volume, 1e-9)
+		})
+	}
+
+	pan, volume := sfxPanAndVolume(10, 0, 0)
+	assert.Equal(t, 0.0, pan, "no screen yet plays centered")
+	assert.Equal(t, 1.0, volume)
+}
+
+func TestClampPan(t *testing.T) {
+	assert.Equal(t, -1.0, clampPan(-3))
+	assert.Equal(t, 0.25, clampPan(0.25))
+	assert.Equal(t, 1.0, clampPan(2))
+}
+
+func TestPanStream(t *testing.T) {
+	frame := func(l, r int16) []byte {
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint16(b, uint16(l))
+		binary.LittleEndian.PutUint16(b[2:], uint16(r))
+		return b
+	}
+	pcm := append(frame(1000, -1000), frame(-2000, 2000)...)
+
+	read := func(pan float64) []byte {
+		out, err := io.ReadAll(&panStream{src: bytes.NewReader(pcm), pan: pan})
+		require.NoError(t, err)
+		return out
+	}
+
+	assert.Equal(t, pcm, read(0), "centered leaves both channels alone")
+	assert.Equal(t, append(frame(1000, 0), frame(-2000, 0)...), read(-1), "fully left silences the right channel")
+	assert.Equal(t, append(frame(500, -1000), frame(-1000, 2000)...), read(0.5), "right of center fades the left channel")
+}
@@ -1,7 +1,14 @@
 package pigo8
 
 import (
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestMusicFunctions tests the public music API functions
@@ -25,3 +32,84 @@ func TestMusicFunctions(t *testing.T) {
 		StopMusic(-1) // Stop all music
 	})
 }
+
+// testWAV builds a mono 8-bit PCM WAV file with the given number of samples.
+func testWAV(rate, samples int) []byte {
+	le32 := func(v int) []byte { return []byte{byte(v), byte(v >> 8), byte(v >> 16), byte(v >> 24)} }
+	var b []byte
+	b = append(b, "RIFF"...)
+	b = append(b, le32(36+samples)...)
+	b = append(b, "WAVEfmt "...)
+	b = append(b, le32(16)...)
+	b = append(b, 1, 0, 1, 0) // Linear PCM, mono
+	b = append(b, le32(rate)...)
+	b = append(b, le32(rate)...) // Byte rate
+	b = append(b, 1, 0, 8, 0)    // Block align, bits per sample
+	b = append(b, "data"...)
+	b = append(b, le32(samples)...)
+	for i := 0; i < samples; i++ {
+		b = append(b, byte(128+i%64))
+	}
+	return b
+}
+
+func TestDecodeAudioFile(t *testing.T) {
+	stream, err := decodeAudioFile(testWAV(sampleRate, 100), ".wav")
+	require.NoError(t, err)
+	pcm, err := io.ReadAll(stream)
+	require.NoError(t, err)
+	assert.Len(t, pcm, 100*4, "mono 8-bit becomes 16-bit stereo")
+
+	stream, err = decodeAudioFile(testWAV(sampleRate/2, 100), ".wav")
+	require.NoError(t, err)
+	assert.InDelta(t, 200*4, stream.Length(), 8, "resampled to the engine's sample rate")
+
+	_, err = decodeAudioFile([]byte("OggS\x00\x02"), ".ogg")
+	assert.ErrorContains(t, err, "OGG")
+
+	_, err = decodeAudioFile([]byte("ID3\x03"), ".mp3")
+	assert.ErrorContains(t, err, "unsupported audio format")
+
+	_, err = decodeAudioFile([]byte("RIFF\x00\x00"), ".wav")
+	assert.Error(t, err, "truncated header")
+}
+
+func TestLoadMusicAndSfx(t *testing.T) {
+	dir := t.TempDir()
+	wavPath := filepath.Join(dir, "beep.wav")
+	require.NoError(t, os.WriteFile(wavPath, testWAV(sampleRate, 100), 0o644))
+	oggPath := filepath.Join(dir, "beep.ogg")
+	require.NoError(t, os.WriteFile(oggPath, []byte("OggS\x00\x02"), 0o644))
+
+	assert.Error(t, LoadMusic(-1, wavPath), "negative slot")
+	assert.Error(t, LoadSfx(-1, wavPath), "negative slot")
+	assert.ErrorIs(t, LoadMusic(0, filepath.Join(dir, "missing.wav")), os.ErrNotExist)
+	assert.ErrorContains(t, LoadSfx(0, oggPath), "OGG")
+
+	require.NoError(t, LoadMusic(90, wavPath))
+	require.NoError(t, LoadSfx(90, wavPath))
+	ap := getAudioPlayer()
+	assert.True(t, ap.musicLoop[90], "loaded music loops")
+	assert.Len(t, ap.sfxData[90], 100*4)
+
+	// Just testing that these don't panic
+	Sfx(90)
+	Sfx(90)
+	Sfx(91)
+	Sfx(-1)
+	assert.Empty(t, ap.sfxPlayers, "Sfx(-1) stops every sound effect")
+}
+
+func TestMusicFraction(t *testing.T) {
+	second := time.Second
+	assert.Equal(t, 0.0, musicFraction(0, 4*second, false))
+	assert.Equal(t, 0.25, musicFraction(second, 4*second, false))
+	assert.Equal(t, 1.0, musicFraction(5*second, 4*second, false), "a finished track stays at the end")
+	assert.Equal(t, 0.25, musicFraction(9*second, 4*second, true), "a looping track wraps around")
+	assert.Equal(t, 0.0, musicFraction(second, 0, true), "unknown lengths report the start")
+}
+
+func TestPcmDuration(t *testing.T) {
+	assert.Equal(t, time.Second, pcmDuration(sampleRate*4))
+	assert.Equal(t, 500*time.Millisecond, pcmDuration(sampleRate*2))
+}
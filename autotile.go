@@ -0,0 +1,205 @@
+package pigo8
+
+import (
+	"log"
+	"slices"
+)
+
+// --- Auto-tiling ---
+
+// AutoTileScheme selects which neighbors decide the sprite of an auto-tiled cell.
+type AutoTileScheme int
+
+const (
+	// AutoTile4 looks at the 4 edge neighbors (north, east, south, west).
+	// It needs 16 sprites, one per combination: Tiles[mask], where mask adds
+	// up 1 for north, 2 for east, 4 for south and 8 for west.
+	AutoTile4 AutoTileScheme = iota
+	// AutoTile8 also looks at the 4 corner neighbors, to draw inner corners
+	// (the "blob" tile set). A corner only counts when both edges next to it
+	// are set, which leaves 47 combinations, so it needs 47 sprites in the
+	// order of AutoTileBlobMasks.
+	AutoTile8
+)
+
+// Neighbor bits of an AutoTile8 mask. AutoTile4 masks use 1, 2, 4 and 8 for
+// north, east, south and west instead.
+const (
+	autoTileNW = 1 << iota
+	autoTileN
+	autoTileNE
+	autoTileW
+	autoTileE
+	autoTileSW
+	autoTileS
+	autoTileSE
+)
+
+// AutoTileConfig describes a tile set for ApplyAutoTile.
+type AutoTileConfig struct {
+	Scheme AutoTileScheme
+	// Tiles are the sprites to choose from: 16 for AutoTile4, 47 for AutoTile8.
+	Tiles []int
+	// IsSolid reports whether a sprite belongs to the tile set, i.e. is
+	// rewritten and connects to its neighbors. If nil, a sprite belongs to
+	// it when it is one of Tiles, so ApplyAutoTile can be run again after
+	// editing the map.
+	IsSolid func(sprite int) bool
+	// EdgesSolid makes cells outside the map count as solid neighbors, so
+	// walls connect into the map border instead of getting an edge there.
+	EdgesSolid bool
+}
+
+// blobMasks are the 47 AutoTile8 masks in ascending order, and blobIndex maps
+// every 8-bit neighbor combination to the index of its reduced mask.
+var (
+	blobMasks []int
+	blobIndex [256]int
+)
+
+func init() {
+	for mask := 0; mask < 256; mask++ {
+		if reduceBlobMask(mask) == mask {
+			blobMasks = append(blobMasks, mask)
+		}
+	}
+	for mask := 0; mask < 256; mask++ {
+		blobIndex[mask], _ = slices.BinarySearch(blobMasks, reduceBlobMask(mask))
+	}
+}
+
+// AutoTileBlobMasks returns the 47 neighbor masks of the AutoTile8 scheme in
+// the order AutoTileConfig.Tiles must follow. Each mask adds up 1 for the
+// north-west neighbor, 2 for north, 4 for north-east, 8 for west, 16 for
+// east, 32 for south-west, 64 for south and 128 for south-east.
+func AutoTileBlobMasks() []int {
+	return slices.Clone(blobMasks)
+}
+
+// reduceBlobMask clears the corner bits whose two edge neighbors are not
+// both set, as those corners don't change how the tile looks.
+func reduceBlobMask(mask int) int {
+	corners := []struct{ corner, edge1, edge2 int }{
+		{autoTileNW, autoTileN, autoTileW},
+		{autoTileNE, autoTileN, autoTileE},
+		{autoTileSW, autoTileS, autoTileW},
+		{autoTileSE, autoTileS, autoTileE},
+	}
+	for _, c := range corners {
+		if mask&c.edge1 == 0 || mask&c.edge2 == 0 {
+			mask &^= c.corner
+		}
+	}
+	return mask
+}
+
+// ApplyAutoTile rewrites the solid cells in a region of the map with the
+// sprite of the tile set that connects to their neighbors. Cells that are
+// not solid are left alone. Neighbors just outside the region are taken
+// into account but not rewritten. Returns the number of cells that changed.
+//
+// Args:
+//   - column, row: top-left map cell of the region
+//   - width, height: size of the region in cells
+//   - config: the tile set and which sprites count as solid
+//
+// Example:
+//
+//	// Sprites 64-79 are walls for every combination of N, E, S, W neighbors.
+//	// Paint the level with any of them, then let the walls connect:
+//	walls := AutoTileConfig{Scheme: AutoTile4, Tiles: make([]int, 16)}
+//	for i := range walls.Tiles {
+//		walls.Tiles[i] = 64 + i
+//	}
+//	ApplyAutoTile(0, 0, 128, 64, walls)
+func ApplyAutoTile(column, row, width, height int, config AutoTileConfig) int {
+	if !config.valid() {
+		return 0
+	}
+	return updateMapTiles(func(data []int, worldW, worldH int) int {
+		return autoTileMapTiles(data, worldW, worldH, column, row, width, height, config)
+	})
+}
+
+// valid checks the config and logs what is wrong with it.
+func (c AutoTileConfig) valid() bool {
+	want := 16
+	switch c.Scheme {
+	case AutoTile4:
+	case AutoTile8:
+		want = len(blobMasks)
+	default:
+		log.Printf("Warning: ApplyAutoTile() called with unknown scheme %d. Ignoring.", c.Scheme)
+		return false
+	}
+	if len(c.Tiles) != want {
+		log.Printf("Warning: ApplyAutoTile() needs %d tiles for this scheme, got %d. Ignoring.", want, len(c.Tiles))
+		return false
+	}
+	return true
+}
+
+// autoTileMapTiles auto-tiles a region of a row-major tile slice and returns
+// the number of changed cells. Masks are computed from the tiles as they
+// were before any cell was rewritten.
+func autoTileMapTiles(data []int, worldW, worldH, column, row, width, height int, config AutoTileConfig) int {
+	isSolid := config.IsSolid
+	if isSolid == nil {
+		isSolid = func(sprite int) bool { return slices.Contains(config.Tiles, sprite) }
+	}
+
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+
+	// Snapshot the region plus a one cell border, as solid or not
+	snapW, snapH := width+2, height+2
+	solid := make([]bool, snapW*snapH)
+	for y := 0; y < snapH; y++ {
+		for x := 0; x < snapW; x++ {
+			wx, wy := column+x-1, row+y-1
+			if wx < 0 || wy < 0 || wx >= worldW || wy >= worldH {
+				solid[y*snapW+x] = config.EdgesSolid
+				continue
+			}
+			solid[y*snapW+x] = isSolid(data[wy*worldW+wx])
+		}
+	}
+	at := func(x, y int) bool { return solid[(y+1)*snapW+x+1] }
+
+	changed := 0
+	for y := 0; y < height; y++ {
+		wy := row + y
+		for x := 0; x < width; x++ {
+			wx := column + x
+			if wx < 0 || wy < 0 || wx >= worldW || wy >= worldH || !at(x, y) {
+				continue
+			}
+
+			var sprite int
+			if config.Scheme == AutoTile4 {
+				mask := 0
+				for i, d := range [4][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+					if at(x+d[0], y+d[1]) {
+						mask |= 1 << i
+					}
+				}
+				sprite = config.Tiles[mask]
+			} else {
+				mask := 0
+				for i, d := range [8][2]int{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}} {
+					if at(x+d[0], y+d[1]) {
+						mask |= 1 << i
+					}
+				}
+				sprite = config.Tiles[blobIndex[mask]]
+			}
+
+			if data[wy*worldW+wx] != sprite {
+				data[wy*worldW+wx] = sprite
+				changed++
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,109 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// autoTile4Config uses sprites 100-115, so a cell's sprite minus 100 is its mask.
+func autoTile4Config() AutoTileConfig {
+	config := AutoTileConfig{Scheme: AutoTile4, Tiles: make([]int, 16)}
+	for i := range config.Tiles {
+		config.Tiles[i] = 100 + i
+	}
+	return config
+}
+
+func TestAutoTileBlobMasks(t *testing.T) {
+	masks := AutoTileBlobMasks()
+	assert.Len(t, masks, 47)
+	assert.Equal(t, 0, masks[0])
+	assert.Equal(t, 255, masks[46])
+
+	// A corner without both of its edges doesn't count
+	assert.Equal(t, autoTileN, reduceBlobMask(autoTileN|autoTileNE|autoTileNW))
+	assert.Equal(t, autoTileN|autoTileE|autoTileNE, reduceBlobMask(autoTileN|autoTileE|autoTileNE|autoTileSE))
+}
+
+func TestAutoTile4(t *testing.T) {
+	config := autoTile4Config()
+	// A plus-shaped wall in a 5x5 world (1 is any wall sprite)
+	config.IsSolid = func(sprite int) bool { return sprite == 1 || sprite >= 100 }
+	data := []int{
+		0, 0, 0, 0, 0,
+		0, 0, 1, 0, 0,
+		0, 1, 1, 1, 0,
+		0, 0, 1, 0, 0,
+		0, 0, 0, 0, 0,
+	}
+	changed := autoTileMapTiles(data, 5, 5, 0, 0, 5, 5, config)
+	assert.Equal(t, 5, changed)
+	assert.Equal(t, []int{
+		0, 0, 0, 0, 0,
+		0, 0, 100 + 4, 0, 0, // Only south
+		0, 100 + 2, 100 + 15, 100 + 8, 0, // East only, all four, west only
+		0, 0, 100 + 1, 0, 0, // Only north
+		0, 0, 0, 0, 0,
+	}, data)
+
+	// Running it again is a no-op
+	assert.Equal(t, 0, autoTileMapTiles(data, 5, 5, 0, 0, 5, 5, config))
+}
+
+func TestAutoTile4RegionAndEdges(t *testing.T) {
+	config := autoTile4Config() // Default IsSolid: any of the tile set's sprites
+	data := []int{
+		100, 100, 100,
+		0, 0, 0,
+	}
+
+	// Only the middle cell is rewritten, but it sees both neighbors
+	changed := autoTileMapTiles(data, 3, 2, 1, 0, 1, 1, config)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, []int{100, 100 + 2 + 8, 100}, data[:3])
+
+	// With EdgesSolid, the map border connects to the walls
+	config.EdgesSolid = true
+	autoTileMapTiles(data, 3, 2, 0, 0, 3, 2, config)
+	assert.Equal(t, []int{100 + 1 + 2 + 8, 100 + 1 + 2 + 8, 100 + 1 + 2 + 8}, data[:3])
+	assert.Equal(t, []int{0, 0, 0}, data[3:], "empty cells are left alone")
+}
+
+func TestAutoTile8(t *testing.T) {
+	config := AutoTileConfig{Scheme: AutoTile8, Tiles: make([]int, 47)}
+	for i := range config.Tiles {
+		config.Tiles[i] = 200 + i
+	}
+	config.IsSolid = func(sprite int) bool { return sprite != 0 }
+
+	// A 3x3 block: the center sees all 8 neighbors, the corners 3 each
+	data := []int{
+		1, 1, 1,
+		1, 1, 1,
+		1, 1, 1,
+	}
+	autoTileMapTiles(data, 3, 3, 0, 0, 3, 3, config)
+
+	index := func(mask int) int { return 200 + blobIndex[mask] }
+	assert.Equal(t, index(255), data[4], "center")
+	assert.Equal(t, index(autoTileE|autoTileS|autoTileSE), data[0], "top-left corner")
+	assert.Equal(t, index(autoTileW|autoTileE|autoTileSW|autoTileS|autoTileSE), data[1], "top edge")
+
+	// Removing a corner of a block gives its neighbor an inner corner
+	data = []int{
+		0, 1, 1,
+		1, 1, 1,
+		1, 1, 1,
+	}
+	autoTileMapTiles(data, 3, 3, 0, 0, 3, 3, config)
+	assert.Equal(t, index(255&^autoTileNW), data[4])
+}
+
+func TestAutoTileConfigValid(t *testing.T) {
+	assert.True(t, autoTile4Config().valid())
+	assert.False(t, AutoTileConfig{Scheme: AutoTile4, Tiles: make([]int, 15)}.valid())
+	assert.False(t, AutoTileConfig{Scheme: AutoTile8, Tiles: make([]int, 16)}.valid())
+	assert.False(t, AutoTileConfig{Scheme: AutoTileScheme(7), Tiles: make([]int, 16)}.valid())
+	assert.Equal(t, 0, ApplyAutoTile(0, 0, 1, 1, AutoTileConfig{}), "an invalid config changes nothing")
+}
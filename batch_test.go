@@ -2,6 +2,7 @@ package pigo8
 
 import (
 	"image/color"
+	"reflect"
 	"testing"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -187,3 +188,165 @@ func TestSetScreenSize(t *testing.T) {
 		t.Error("Buffer should be marked as dirty after setting pixel in new buffer")
 	}
 }
+
+// setupRowTest gives the row functions a 16x16 screen and a clean pixel buffer.
+func setupRowTest(tb testing.TB) {
+	tb.Helper()
+	originalScreen := currentScreen
+	originalW, originalH := screenWidth, screenHeight
+	tb.Cleanup(func() {
+		currentScreen = originalScreen
+		setScreenSize(originalW, originalH)
+		cameraX, cameraY = 0, 0
+		Palt()
+	})
+	currentScreen = ebiten.NewImage(16, 16)
+	setScreenSize(16, 16)
+	clearPixelBuffer()
+	cameraX, cameraY = 0, 0
+	Palt()
+}
+
+// bufferColorAt returns the palette index stored in the pixel buffer at (x, y), or -1 if empty.
+func bufferColorAt(x, y int) int {
+	offset := (y*pixelBufferWidth + x) * 4
+	c := color.RGBA{pixelBuffer[offset], pixelBuffer[offset+1], pixelBuffer[offset+2], pixelBuffer[offset+3]}
+	if c.A == 0 {
+		return -1
+	}
+	for i, p := range pico8Palette {
+		if colorEquals(c, p) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestPsetRow(t *testing.T) {
+	setupRowTest(t)
+
+	// Clipped on the left, color 0 is transparent, 99 is invalid
+	PsetRow(-1, 2, []int{8, 9, 0, 10, 99, 11})
+	if got := bufferColorAt(0, 2); got != 9 {
+		t.Errorf("pixel (0,2) = %d, want 9", got)
+	}
+	if got := bufferColorAt(1, 2); got != -1 {
+		t.Errorf("transparent pixel (1,2) was drawn as %d", got)
+	}
+	if got := bufferColorAt(2, 2); got != 10 {
+		t.Errorf("pixel (2,2) = %d, want 10", got)
+	}
+	if got := bufferColorAt(3, 2); got != -1 {
+		t.Errorf("invalid color at (3,2) was drawn as %d", got)
+	}
+	if got := bufferColorAt(4, 2); got != 11 {
+		t.Errorf("pixel (4,2) = %d, want 11", got)
+	}
+
+	// The camera offset is applied like Pset
+	cameraX, cameraY = 4, 4
+	PsetRow(10, 10, []int{7})
+	if got := bufferColorAt(6, 6); got != 7 {
+		t.Errorf("pixel (6,6) = %d, want 7 with the camera at (4,4)", got)
+	}
+}
+
+func TestPsetRect(t *testing.T) {
+	setupRowTest(t)
+
+	PsetRect(14, 14, 3, 2, []int{
+		1, 2, 3,
+		4, 5, 6,
+	})
+	want := map[[2]int]int{{14, 14}: 1, {15, 14}: 2, {14, 15}: 4, {15, 15}: 5}
+	for pos, c := range want {
+		if got := bufferColorAt(pos[0], pos[1]); got != c {
+			t.Errorf("pixel %v = %d, want %d", pos, got, c)
+		}
+	}
+}
+
+func TestReadPixelRow(t *testing.T) {
+	pixels := make([]byte, 4*2*4) // 4x2 image
+	r, g, b, a := pico8Palette[8].RGBA()
+	offset := (1*4 + 2) * 4
+	copy(pixels[offset:], []byte{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)})
+
+	out := make([]int, 5)
+	readPixelRow(pixels, 4, 2, -1, 1, out, false)
+	if want := []int{0, 0, 0, 8, 0}; !reflect.DeepEqual(out, want) {
+		t.Errorf("readPixelRow = %v, want %v", out, want)
+	}
+}
+
+func TestPgetSeesPendingPixels(t *testing.T) {
+	setupRowTest(t)
+
+	// Nothing was flushed, so these are only in the pixel buffer
+	Pset(3, 4, 8)
+	PsetRow(0, 5, []int{9, 10})
+	if got := Pget(3, 4); got != 8 {
+		t.Errorf("Pget(3, 4) = %d, want pending color 8", got)
+	}
+	if _, ok := pendingPixelIndex(3, 5); ok {
+		t.Error("pendingPixelIndex reported a pixel that was never set")
+	}
+
+	// Pending pixels are laid over the screen contents
+	out := make([]int, 3)
+	readPixelRow(pixelBuffer, pixelBufferWidth, pixelBufferHeight, 0, 5, out, true)
+	if want := []int{9, 10, 0}; !reflect.DeepEqual(out, want) {
+		t.Errorf("pending row = %v, want %v", out, want)
+	}
+	out = []int{1, 1, 1}
+	readPixelRow(pixelBuffer, pixelBufferWidth, pixelBufferHeight, 0, 5, out, true)
+	if want := []int{9, 10, 1}; !reflect.DeepEqual(out, want) {
+		t.Errorf("pending row over the screen = %v, want %v", out, want)
+	}
+}
+
+func TestSetPixelFlushMode(t *testing.T) {
+	t.Cleanup(func() { SetPixelFlushMode(FlushBeforeDraw) })
+
+	if got := GetPixelFlushMode(); got != FlushBeforeDraw {
+		t.Errorf("default flush mode = %d, want FlushBeforeDraw", got)
+	}
+	SetPixelFlushMode(FlushDeferred)
+	if got := GetPixelFlushMode(); got != FlushDeferred {
+		t.Errorf("flush mode = %d, want FlushDeferred", got)
+	}
+	SetPixelFlushMode(PixelFlushMode(42))
+	if got := GetPixelFlushMode(); got != FlushDeferred {
+		t.Errorf("unknown mode changed the flush mode to %d", got)
+	}
+}
+
+func BenchmarkPsetLoop(b *testing.B) {
+	setupRowTest(b)
+	setScreenSize(128, 128)
+	colors := make([]int, 128*128)
+	for i := range colors {
+		colors[i] = i % 16
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for y := 0; y < 128; y++ {
+			for x := 0; x < 128; x++ {
+				Pset(x, y, colors[y*128+x])
+			}
+		}
+	}
+}
+
+func BenchmarkPsetRect(b *testing.B) {
+	setupRowTest(b)
+	setScreenSize(128, 128)
+	colors := make([]int, 128*128)
+	for i := range colors {
+		colors[i] = i % 16
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		PsetRect(0, 0, 128, 128, colors)
+	}
+}
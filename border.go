@@ -0,0 +1,86 @@
+package pigo8
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Window border ---
+
+var (
+	// borderColor fills the window around the screen; nil means black.
+	borderColor color.Color
+	// borderImage is drawn over borderColor, stretched to the window.
+	borderImage *ebiten.Image
+)
+
+// SetBorderColor sets the color of the bars around the screen when it
+// doesn't fill the window, e.g. in fullscreen or with ScaleInteger. The
+// default is black; nil restores it. Settings.BorderColor sets it at start.
+// Any color can be used, including one from the palette:
+//
+//	SetBorderColor(GetPaletteColor(1)) // Dark blue bars
+//	SetBorderColor(color.RGBA{40, 20, 60, 255})
+func SetBorderColor(c color.Color) {
+	borderColor = c
+}
+
+// GetBorderColor returns the color set with SetBorderColor, black by default.
+func GetBorderColor() color.Color {
+	if borderColor == nil {
+		return color.Black
+	}
+	return borderColor
+}
+
+// SetBorderImage draws img behind the screen, stretched to fill the whole
+// window, e.g. an arcade bezel or a themed backdrop for the bars around the
+// screen. The screen is drawn on top, scaled as usual, and the border color
+// shows through transparent parts of img. Pass nil to remove it.
+//
+// Example:
+//
+//	f, _ := os.Open("bezel.png")
+//	bezel, _, err := image.Decode(f)
+//	if err == nil {
+//		SetBorderImage(bezel)
+//	}
+func SetBorderImage(img image.Image) {
+	if borderImage != nil {
+		borderImage.Deallocate()
+		borderImage = nil
+	}
+	if img != nil {
+		borderImage = ebiten.NewImageFromImage(img)
+	}
+}
+
+// hasCustomBorder reports whether the border is anything but plain black,
+// which needs the engine to draw the border itself.
+func hasCustomBorder() bool {
+	if borderImage != nil {
+		return true
+	}
+	if borderColor == nil {
+		return false
+	}
+	r, g, b, a := borderColor.RGBA()
+	return r != 0 || g != 0 || b != 0 || a != 0xffff
+}
+
+// drawBorder fills screen, the whole window, with the border color and image.
+func drawBorder(screen *ebiten.Image) {
+	screen.Fill(GetBorderColor())
+	if borderImage == nil {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.Filter = ebiten.FilterLinear
+	op.GeoM.Scale(
+		float64(screen.Bounds().Dx())/float64(borderImage.Bounds().Dx()),
+		float64(screen.Bounds().Dy())/float64(borderImage.Bounds().Dy()),
+	)
+	screen.DrawImage(borderImage, op)
+}
@@ -1,6 +1,9 @@
 package pigo8
 
-import "math"
+import (
+	"log"
+	"math"
+)
 
 // Camera state
 var (
@@ -8,6 +11,8 @@ var (
 	cameraX float64
 	// cameraY is the current camera Y offset
 	cameraY float64
+	// cameraStack holds the camera offsets saved by CameraPush
+	cameraStack [][2]float64
 )
 
 // Camera sets the camera position, offsetting all subsequent drawing operations.
@@ -61,6 +66,38 @@ func Camera(args ...any) {
 	}
 }
 
+// CameraPush saves the current camera offset so CameraPop can restore it.
+// Pushes nest, so helpers that draw with their own camera can save and
+// restore it without knowing what the caller had set.
+//
+// Example:
+//
+//	Camera(playerX-64, playerY-64) // World camera
+//	Map()
+//	Spr(1, playerX, playerY)
+//
+//	CameraPush()
+//	Camera() // HUD is drawn in screen coordinates
+//	Print("SCORE: 1000", 2, 2, 7)
+//	CameraPop() // Back to the world camera
+//
+//	Spr(2, enemyX, enemyY)
+func CameraPush() {
+	cameraStack = append(cameraStack, [2]float64{cameraX, cameraY})
+}
+
+// CameraPop restores the camera offset saved by the last CameraPush.
+// Calling it without a matching CameraPush logs a warning and does nothing.
+func CameraPop() {
+	if len(cameraStack) == 0 {
+		log.Printf("Warning: CameraPop() called without a matching CameraPush()")
+		return
+	}
+	offset := cameraStack[len(cameraStack)-1]
+	cameraStack = cameraStack[:len(cameraStack)-1]
+	cameraX, cameraY = offset[0], offset[1]
+}
+
 // convertToFloat64 attempts to convert a value to float64
 func convertToFloat64(value any) (float64, bool) {
 	switch v := value.(type) {
@@ -98,3 +135,32 @@ func convertToFloat64(value any) (float64, bool) {
 func applyCameraOffset(x, y float64) (float64, float64) {
 	return x - cameraX, y - cameraY
 }
+
+// WorldToScreen converts world coordinates, as passed to Spr, Map and the
+// other drawing functions, to the screen coordinates they are drawn at with
+// the current camera.
+//
+// Example:
+//
+//	sx, sy := WorldToScreen(enemy.x, enemy.y)
+//	if sx < 0 || sx >= float64(GetScreenWidth()) {
+//		drawOffscreenArrow(sy)
+//	}
+func WorldToScreen(x, y float64) (float64, float64) {
+	return applyCameraOffset(x, y)
+}
+
+// ScreenToWorld converts screen coordinates, such as the mouse position, to
+// world coordinates with the current camera. It is the inverse of
+// WorldToScreen, so use it before passing screen positions to functions that
+// work in world coordinates like MapCollision.
+//
+// Example:
+//
+//	mouseX, mouseY := GetMouseXY()
+//	mx, my := ScreenToWorld(float64(mouseX), float64(mouseY))
+//	Mset(Flr(mx/8), Flr(my/8), 5) // Place a tile under the mouse
+func ScreenToWorld(x, y float64) (float64, float64) {
+	offsetX, offsetY := applyCameraOffset(0, 0)
+	return x - offsetX, y - offsetY
+}
@@ -0,0 +1,284 @@
+package pigo8
+
+import "math"
+
+// --- Camera follow ---
+
+// DeadZoneShape is the shape of the camera's dead zone, see CameraFollowOptions.
+type DeadZoneShape int
+
+const (
+	// DeadZoneRect lets the target move freely inside a rectangle; the camera
+	// moves on each axis separately once the target leaves it.
+	DeadZoneRect DeadZoneShape = iota
+	// DeadZoneEllipse lets the target move freely inside an ellipse that fits
+	// the dead zone size, which feels smoother when moving diagonally.
+	DeadZoneEllipse
+)
+
+// CameraFollowOptions configures how the camera follows the target set with
+// SetCameraTarget. The zero value centers the camera on the target every
+// frame.
+type CameraFollowOptions struct {
+	// DeadZoneWidth and DeadZoneHeight are the size in pixels of the area in
+	// the middle of the screen where the target can move without moving the
+	// camera. 0 keeps the target centered on that axis.
+	DeadZoneWidth, DeadZoneHeight float64
+	// DeadZoneShape is DeadZoneRect (the default) or DeadZoneEllipse.
+	DeadZoneShape DeadZoneShape
+	// Lerp is the fraction of the remaining distance the camera moves each
+	// frame, e.g. 0.1 for a smooth follow. 0 or 1 moves it at once.
+	Lerp float64
+	// Snap ignores the dead zone and Lerp: the camera jumps to center the
+	// target every frame. SnapCamera does this once, e.g. after a teleport.
+	Snap bool
+	// ClampToMap keeps the camera inside the map (see GetMapSize), so the
+	// area outside it is never shown. A map smaller than the screen is
+	// centered.
+	ClampToMap bool
+	// PaddingLeft, PaddingTop, PaddingRight and PaddingBottom let a clamped
+	// camera show that many pixels beyond each edge of the map, e.g. sky
+	// above the level.
+	PaddingLeft, PaddingTop, PaddingRight, PaddingBottom float64
+	// MinZoom and MaxZoom limit the zoom that SetCameraTargets picks to
+	// frame a group, see GetCameraZoom. 0 means 1, no zoom.
+	MinZoom, MaxZoom float64
+}
+
+var (
+	// cameraFollowing is true while the camera follows cameraTarget.
+	cameraFollowing bool
+	// cameraTarget is the point the camera follows, in world pixels.
+	cameraTarget Vector2D
+	// cameraTargetSize is the size of the group framed by SetCameraTargets,
+	// 0 by 0 for a single target.
+	cameraTargetSize Vector2D
+	// cameraZoom is the zoom returned by GetCameraZoom.
+	cameraZoom = 1.0
+	// cameraFollowPos is the unrounded position of the following camera.
+	cameraFollowPos Vector2D
+	// cameraSnapPending makes the next update jump to the target.
+	cameraSnapPending bool
+	cameraFollowOpts  CameraFollowOptions
+)
+
+// SetCameraOptions sets how the camera follows its target. It can be called
+// at any time, e.g. to widen the dead zone in a boss room.
+//
+// Example:
+//
+//	SetCameraOptions(CameraFollowOptions{
+//		DeadZoneWidth:  32,
+//		DeadZoneHeight: 24,
+//		DeadZoneShape:  DeadZoneEllipse,
+//		Lerp:           0.15,
+//		ClampToMap:     true,
+//		PaddingTop:     32, // Show some sky above the level
+//	})
+func SetCameraOptions(opts CameraFollowOptions) {
+	cameraFollowOpts = opts
+}
+
+// GetCameraOptions returns the options set with SetCameraOptions.
+func GetCameraOptions() CameraFollowOptions {
+	return cameraFollowOpts
+}
+
+// SetCameraTarget makes the camera follow the point (x, y) in world pixels,
+// usually the middle of the player. Call it every frame in Update with the
+// new position; after Update the engine moves the camera, as if by Camera,
+// following the options set with SetCameraOptions. The first call jumps to
+// the target. Draw with the camera reset for a HUD as usual; following picks
+// up from its own position next frame.
+//
+// Example:
+//
+//	func (g *Game) Update() {
+//		g.player.update()
+//		SetCameraTarget(g.player.x+4, g.player.y+4)
+//	}
+func SetCameraTarget(x, y float64) {
+	setCameraTarget(Vector2D{x, y}, Vector2D{})
+}
+
+// SetCameraTargets makes the camera frame a group of targets, e.g. the
+// players of a local co-op game. The camera follows the middle of the box
+// around them like SetCameraTarget, and picks the zoom at which the box fits
+// the dead zone (or the screen if there is none), limited to MinZoom and
+// MaxZoom and smoothed with Lerp. An empty group is ignored.
+//
+// The engine doesn't scale drawing: GetCameraZoom returns the zoom for games
+// that draw the world scaled around the middle of the screen, and
+// ClampToMap keeps the area visible at that zoom inside the map.
+//
+// Example:
+//
+//	SetCameraOptions(CameraFollowOptions{MinZoom: 0.5, MaxZoom: 1, Lerp: 0.1})
+//	SetCameraTargets([]Vector2D{p1.pos, p2.pos})
+func SetCameraTargets(targets []Vector2D) {
+	if len(targets) == 0 {
+		return
+	}
+	lo, hi := targets[0], targets[0]
+	for _, t := range targets[1:] {
+		lo = Vector2D{math.Min(lo.X, t.X), math.Min(lo.Y, t.Y)}
+		hi = Vector2D{math.Max(hi.X, t.X), math.Max(hi.Y, t.Y)}
+	}
+	setCameraTarget(LerpVector(lo, hi, 0.5), hi.Sub(lo))
+}
+
+// setCameraTarget follows a target, or a group of the given size around it.
+func setCameraTarget(target, size Vector2D) {
+	if !cameraFollowing {
+		cameraSnapPending = true
+	}
+	cameraFollowing = true
+	cameraTarget = target
+	cameraTargetSize = size
+}
+
+// GetCameraZoom returns the zoom picked by SetCameraTargets to frame its
+// group: 2 shows the world twice as big, 0.5 shows twice as much of it. It is
+// 1 unless MinZoom or MaxZoom allow another zoom.
+func GetCameraZoom() float64 {
+	return cameraZoom
+}
+
+// StopCameraFollow stops following the target. The camera stays where it is
+// until moved with Camera or a new SetCameraTarget.
+func StopCameraFollow() {
+	cameraFollowing = false
+}
+
+// SnapCamera moves the camera at once to center the target, ignoring the dead
+// zone and Lerp, e.g. after a teleport or a respawn. It still respects
+// ClampToMap. Without a target it does nothing.
+func SnapCamera() {
+	if !cameraFollowing {
+		return
+	}
+	cameraSnapPending = true
+	updateCameraFollow()
+}
+
+// updateCameraFollow moves the camera towards the target. The engine calls
+// it every frame after Update.
+func updateCameraFollow() {
+	if !cameraFollowing {
+		return
+	}
+	w, h := float64(GetScreenWidth()), float64(GetScreenHeight())
+	opts := cameraFollowOpts
+	snap := opts.Snap || cameraSnapPending
+	cameraSnapPending = false
+	smooth := !snap && opts.Lerp > 0 && opts.Lerp < 1
+
+	zoom := cameraZoomFor(cameraTargetSize, w, h, opts)
+	if smooth {
+		zoom = Lerp(cameraZoom, zoom, opts.Lerp)
+	}
+	cameraZoom = zoom
+
+	// Work with the center of the screen, where the dead zone is. At a zoom
+	// the dead zone and the visible area cover less of the world, and the
+	// middle of a group has less room so the whole group stays inside.
+	center := cameraFollowPos.Add(Vector2D{w / 2, h / 2})
+	var desired Vector2D
+	if snap {
+		desired = cameraTarget
+	} else {
+		zoomed := opts
+		zoomed.DeadZoneWidth = math.Max(opts.DeadZoneWidth/zoom-cameraTargetSize.X, 0)
+		zoomed.DeadZoneHeight = math.Max(opts.DeadZoneHeight/zoom-cameraTargetSize.Y, 0)
+		desired = followDeadZone(center, cameraTarget, zoomed)
+	}
+	if opts.ClampToMap {
+		desired = clampCameraToMap(desired, w/zoom, h/zoom, opts)
+	}
+	pos := desired.Sub(Vector2D{w / 2, h / 2})
+	if smooth {
+		pos = LerpVector(cameraFollowPos, pos, opts.Lerp)
+	}
+
+	cameraFollowPos = pos
+	cameraX, cameraY = math.Round(pos.X), math.Round(pos.Y)
+}
+
+// followDeadZone returns where the screen center must move so that target is
+// inside the dead zone around it.
+func followDeadZone(center, target Vector2D, opts CameraFollowOptions) Vector2D {
+	halfW, halfH := opts.DeadZoneWidth/2, opts.DeadZoneHeight/2
+	d := target.Sub(center)
+
+	if opts.DeadZoneShape == DeadZoneEllipse && halfW > 0 && halfH > 0 {
+		// Outside the ellipse, move the center towards the target until the
+		// target is on its edge
+		dist := math.Sqrt((d.X/halfW)*(d.X/halfW) + (d.Y/halfH)*(d.Y/halfH))
+		if dist <= 1 {
+			return center
+		}
+		return target.Sub(d.Scale(1 / dist))
+	}
+
+	return Vector2D{
+		X: followAxis(center.X, target.X, halfW),
+		Y: followAxis(center.Y, target.Y, halfH),
+	}
+}
+
+// followAxis moves center on one axis so that target is within half of it.
+func followAxis(center, target, half float64) float64 {
+	switch {
+	case target > center+half:
+		return target - half
+	case target < center-half:
+		return target + half
+	}
+	return center
+}
+
+// cameraZoomFor returns the zoom at which a group of the given size fits the
+// dead zone, or the w x h screen without one, within MinZoom and MaxZoom.
+func cameraZoomFor(size Vector2D, w, h float64, opts CameraFollowOptions) float64 {
+	minZoom, maxZoom := opts.MinZoom, opts.MaxZoom
+	if minZoom <= 0 {
+		minZoom = 1
+	}
+	if maxZoom <= 0 {
+		maxZoom = 1
+	}
+	if opts.DeadZoneWidth > 0 {
+		w = opts.DeadZoneWidth
+	}
+	if opts.DeadZoneHeight > 0 {
+		h = opts.DeadZoneHeight
+	}
+
+	zoom := maxZoom
+	if size.X > 0 {
+		zoom = math.Min(zoom, w/size.X)
+	}
+	if size.Y > 0 {
+		zoom = math.Min(zoom, h/size.Y)
+	}
+	return math.Max(minZoom, math.Min(zoom, maxZoom))
+}
+
+// clampCameraToMap keeps a w x h view around center within the map and its
+// padding, and returns the new center.
+func clampCameraToMap(center Vector2D, w, h float64, opts CameraFollowOptions) Vector2D {
+	mapW, mapH := GetMapSize()
+	return Vector2D{
+		X: clampCameraAxis(center.X-w/2, w, -opts.PaddingLeft, float64(mapW*tileSize)+opts.PaddingRight) + w/2,
+		Y: clampCameraAxis(center.Y-h/2, h, -opts.PaddingTop, float64(mapH*tileSize)+opts.PaddingBottom) + h/2,
+	}
+}
+
+// clampCameraAxis keeps a view of the given size starting at pos within
+// [lo, hi], centering it if the range is smaller than the view.
+func clampCameraAxis(pos, size, lo, hi float64) float64 {
+	if hi-lo <= size {
+		return (lo + hi - size) / 2
+	}
+	return math.Max(lo, math.Min(pos, hi-size))
+}
@@ -0,0 +1,194 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCameraPushPop(t *testing.T) {
+	t.Cleanup(func() {
+		Camera()
+		cameraStack = nil
+	})
+
+	Camera(10, 20)
+	CameraPush()
+	Camera(-5, 7)
+	CameraPush()
+	Camera()
+	assert.Equal(t, [2]float64{0, 0}, [2]float64{cameraX, cameraY})
+
+	CameraPop()
+	assert.Equal(t, [2]float64{-5, 7}, [2]float64{cameraX, cameraY}, "pops should unwind in order")
+	CameraPop()
+	assert.Equal(t, [2]float64{10, 20}, [2]float64{cameraX, cameraY})
+
+	CameraPop() // Unbalanced pop is ignored
+	assert.Equal(t, [2]float64{10, 20}, [2]float64{cameraX, cameraY})
+}
+
+// useCameraFollow resets the camera follow state for a test, with a 128x128
+// screen and map.
+func useCameraFollow(t *testing.T, opts CameraFollowOptions) {
+	useTestConsoleState(t)
+	originalW, originalH := screenWidth, screenHeight
+	t.Cleanup(func() {
+		setScreenSize(originalW, originalH)
+		StopCameraFollow()
+		SetCameraOptions(CameraFollowOptions{})
+		cameraFollowPos = Vector2D{}
+		cameraTargetSize, cameraZoom = Vector2D{}, 1
+		Camera()
+	})
+	setScreenSize(128, 128)
+	StopCameraFollow()
+	cameraFollowPos = Vector2D{}
+	SetCameraOptions(opts)
+}
+
+func cameraPos() Vector2D {
+	return Vector2D{cameraX, cameraY}
+}
+
+func TestCameraFollowDeadZoneRect(t *testing.T) {
+	useCameraFollow(t, CameraFollowOptions{DeadZoneWidth: 32, DeadZoneHeight: 16})
+
+	SetCameraTarget(200, 100)
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{136, 36}, cameraPos(), "the first target is centered")
+
+	SetCameraTarget(215, 107)
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{136, 36}, cameraPos(), "inside the dead zone")
+
+	SetCameraTarget(220, 90)
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{140, 34}, cameraPos(), "each axis follows to the edge of the dead zone")
+}
+
+func TestCameraFollowDeadZoneEllipse(t *testing.T) {
+	useCameraFollow(t, CameraFollowOptions{DeadZoneWidth: 40, DeadZoneHeight: 40, DeadZoneShape: DeadZoneEllipse})
+
+	SetCameraTarget(200, 200)
+	updateCameraFollow()
+
+	// In the corner of the rectangle, but outside the circle
+	SetCameraTarget(218, 218)
+	updateCameraFollow()
+	center := cameraFollowPos.Add(Vector2D{64, 64})
+	assert.InDelta(t, 20, Vector2D{218, 218}.Sub(center).Length(), 1e-9, "the target ends on the edge of the circle")
+	assert.InDelta(t, center.X, center.Y, 1e-9, "the camera moves towards the target")
+
+	before := cameraFollowPos
+	SetCameraTarget(center.X+10, center.Y)
+	updateCameraFollow()
+	assert.Equal(t, before, cameraFollowPos, "inside the circle")
+}
+
+func TestCameraFollowLerpAndSnap(t *testing.T) {
+	useCameraFollow(t, CameraFollowOptions{Lerp: 0.5})
+
+	SetCameraTarget(64, 64)
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{0, 0}, cameraPos())
+
+	SetCameraTarget(164, 64)
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{50, 0}, cameraPos(), "half the way each frame")
+
+	SnapCamera()
+	assert.Equal(t, Vector2D{100, 0}, cameraPos(), "SnapCamera jumps at once")
+
+	SetCameraOptions(CameraFollowOptions{Lerp: 0.5, DeadZoneWidth: 100, Snap: true})
+	SetCameraTarget(174, 64)
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{110, 0}, cameraPos(), "Snap ignores the dead zone and Lerp")
+
+	StopCameraFollow()
+	SetCameraTarget(500, 500)
+	StopCameraFollow()
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{110, 0}, cameraPos(), "the camera stays put when not following")
+}
+
+func TestCameraFollowClampToMap(t *testing.T) {
+	useCameraFollow(t, CameraFollowOptions{ClampToMap: true})
+
+	SetCameraTarget(10, 10)
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{0, 0}, cameraPos())
+
+	SetCameraTarget(1020, 1020)
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{1024 - 128, 1024 - 128}, cameraPos())
+
+	SetCameraOptions(CameraFollowOptions{ClampToMap: true, PaddingTop: 32, PaddingRight: 16})
+	SetCameraTarget(10, 10)
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{0, -32}, cameraPos(), "padding shows beyond the map")
+	SetCameraTarget(1020, 1020)
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{1024 - 128 + 16, 1024 - 128}, cameraPos())
+
+	SetMapSize(8, 20) // 64 pixels wide, narrower than the screen
+	SetCameraOptions(CameraFollowOptions{ClampToMap: true})
+	updateCameraFollow()
+	assert.Equal(t, -32.0, cameraX, "a narrow map is centered")
+}
+
+func TestSetCameraTargets(t *testing.T) {
+	useCameraFollow(t, CameraFollowOptions{MinZoom: 0.5, MaxZoom: 2})
+
+	SetCameraTargets([]Vector2D{{100, 100}, {140, 120}, {120, 80}})
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{120 - 64, 100 - 64}, cameraPos(), "the middle of the group is centered")
+	assert.Equal(t, 2.0, GetCameraZoom(), "a small group zooms in up to MaxZoom")
+
+	SetCameraTargets([]Vector2D{{0, 100}, {200, 100}})
+	updateCameraFollow()
+	assert.InDelta(t, 128.0/200, GetCameraZoom(), 1e-9, "the group fits the screen")
+
+	SetCameraTargets([]Vector2D{{0, 100}, {1000, 100}})
+	updateCameraFollow()
+	assert.Equal(t, 0.5, GetCameraZoom(), "zooming out stops at MinZoom")
+
+	SetCameraTargets(nil)
+	assert.Equal(t, Vector2D{500, 100}, cameraTarget, "an empty group is ignored")
+
+	SetCameraTarget(64, 64)
+	updateCameraFollow()
+	assert.Equal(t, 2.0, GetCameraZoom(), "a single target is a group of size 0")
+}
+
+func TestSetCameraTargetsDeadZoneAndClamp(t *testing.T) {
+	useCameraFollow(t, CameraFollowOptions{DeadZoneWidth: 64, DeadZoneHeight: 64, ClampToMap: true})
+	SetCameraTargets([]Vector2D{{200, 200}, {240, 200}})
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{220 - 64, 200 - 64}, cameraPos())
+
+	// The group is 40 wide, so its middle can move 12 pixels before the
+	// group leaves the 64 pixel dead zone
+	SetCameraTargets([]Vector2D{{210, 200}, {250, 200}})
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{220 - 64, 200 - 64}, cameraPos())
+	SetCameraTargets([]Vector2D{{220, 200}, {260, 200}})
+	updateCameraFollow()
+	assert.Equal(t, Vector2D{228 - 64, 200 - 64}, cameraPos())
+
+	SetCameraOptions(CameraFollowOptions{ClampToMap: true, MinZoom: 0.5})
+	SetCameraTargets([]Vector2D{{0, 0}, {256, 0}})
+	updateCameraFollow()
+	assert.Equal(t, 0.5, GetCameraZoom())
+	assert.Equal(t, Vector2D{128 - 64, 128 - 64}, cameraPos(), "the 256 pixel view at zoom 0.5 is kept inside the map")
+}
+
+func TestScreenToWorld(t *testing.T) {
+	t.Cleanup(func() { Camera() })
+
+	Camera(100, -20)
+	x, y := WorldToScreen(110, 5)
+	assert.Equal(t, [2]float64{10, 25}, [2]float64{x, y})
+	x, y = ScreenToWorld(x, y)
+	assert.Equal(t, [2]float64{110, 5}, [2]float64{x, y}, "ScreenToWorld undoes WorldToScreen")
+}
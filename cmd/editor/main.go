@@ -5,13 +5,15 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"image/color"
 	"log"
 	"os"
+	"slices"
 	"strconv"
-	"sync"
+	"strings"
 	"time"
 
 	p8 "github.com/drpaneas/pigo8"
@@ -28,52 +30,98 @@ const (
 	defaultGridSize = 1 // 8x8 grid (1 sprite)
 	mediumGridSize  = 2 // 16x16 grid (4 sprites)
 	largeGridSize   = 4 // 32x32 grid (16 sprites)
+	maxGridSize     = 8 // 64x64 grid (64 sprites)
 
-	// Map dimensions
-	mapWidth  = 320
-	mapHeight = 320
+	// Map dimensions (in tiles), matching mapData
+	mapWidth  = 128
+	mapHeight = 128
+
+	// Map view
+	defaultMapZoom = 1 // Index into mapZoomLevels (8 pixels per tile)
+	minimapGap     = 10
 
 	// Default colors
 	defaultColor     = 1 // Default color
 	transparentColor = 0 // Transparent color
 
 	// UI constants
-	paletteColumns = 8 // Number of columns in the palette display
-	numFlags       = 8 // Number of sprite flags
+	paletteColumns = 8  // Number of columns in the palette display
+	numFlags       = 8  // Number of sprite flags
+	noFlagFilter   = -1 // flagFilter when no flag is highlighted
+	filterColor    = 10 // Color marking the filtered flag
+	noFlag         = -1 // No flag, e.g. renamingFlag when no name is being typed
+	maxFlagName    = 12 // Longest flag name, in characters
+
+	// Undo/redo
+	maxUndoStates = 50 // Number of snapshots kept in the undo history
+
+	// Map drag modes
+	dragNone    = 0 // No drag in progress
+	dragCapture = 1 // S + drag: capture a region as stamp
+	dragFill    = 2 // Shift + drag: fill a region with the current sprite
 
 	// Screen dimensions (default from PICO8)
 	defaultViewportWidth  = 128
 	defaultViewportHeight = 128
+
+	// Auto-save
+	defaultAutoSaveInterval = time.Minute            // How often unsaved work is written to recoveryFile
+	recoveryFile            = "editor-recovery.json" // Unsaved spritesheet and map, next to spritesheet.json
+	recoveryVersion         = 1                      // Format of recoveryFile
+
+	// Map export as Go source
+	mapGoFile = "map_data.go" // File the E key writes in map mode
+	mapGoVar  = "mapTiles"    // Variable holding the map in mapGoFile
 )
 
 type myGame struct {
 	currentColor  int
 	currentSprite int
-	hoverX        int       // X coordinate of the pixel being hovered over (-1 if none)
-	hoverY        int       // Y coordinate of the pixel being hovered over (-1 if none)
-	gridSize      int       // Size of the working grid (1=8x8, 2=16x16, 4=32x32, 8=64x64)
-	lastWheelTime int64     // Last time the mouse wheel was scrolled or keyboard was used (for debouncing)
-	mapMode       bool      // Whether we are in map mode
-	copiedSprite  [8][8]int // Buffer for copied sprite data
+	hoverX        int           // X coordinate of the pixel being hovered over (-1 if none)
+	hoverY        int           // Y coordinate of the pixel being hovered over (-1 if none)
+	gridSize      int           // Size of the working grid (1=8x8, 2=16x16, 4=32x32, 8=64x64), see maxGridSize
+	lastWheelTime int64         // Last time the mouse wheel was scrolled or keyboard was used (for debouncing)
+	mapMode       bool          // Whether we are in map mode
+	flagFilter    int           // Flag whose sprites are highlighted on the spritesheet, or noFlagFilter
+	renamingFlag  int           // Flag whose name is being typed, or noFlag
+	copiedSprites [][][8][8]int // Buffer for the copied block of sprites, by row and column in the block
 
 	// Undo/Redo state
-	undoStack      []string      // Stack of saved state filenames for undo
-	redoStack      []string      // Stack of saved state filenames for redo
-	fs             afero.Fs      // Virtual filesystem for state snapshots
-	stateMutex     sync.Mutex    // Mutex for thread-safe access to state
-	lastSaveTime   time.Time     // Last time a state was saved
-	saveCooldown   time.Duration // Minimum time between saves
-	undoInProgress bool          // Flag to prevent re-entrant undo/redo operations
+	history        *p8.UndoStack[editorState] // Snapshots of the editor state
+	undoInProgress bool                       // Flag to prevent re-entrant undo/redo operations
 
 	// Key state tracking
 	lastUndoTime int64 // Last time undo was triggered
 	lastRedoTime int64 // Last time redo was triggered
 	keyCooldown  int64 // Minimum time between undo/redo actions in milliseconds
 
+	// Map stamp (brush) state
+	stamp      p8.MapStamp // Captured brush; Width is 0 when no stamp is active
+	dragMode   int         // dragNone, dragCapture or dragFill
+	dragStartX int         // Map cell where the current drag started
+	dragStartY int
+	dragEndX   int // Map cell under the mouse during the current drag
+	dragEndY   int
+
+	// Map view state
+	mapZoom     int  // Index into mapZoomLevels
+	showMapGrid bool // Whether the tile grid and coordinates are drawn
+	mapSnap     int  // Tiles the placement cursor snaps to (1 = every tile), see mapSnapSizes
+
 	// Map editor state
 	mapCameraX int                                              // Camera X position in the map (in sprites)
 	mapCameraY int                                              // Camera Y position in the map (in sprites)
 	mapData    [defaultViewportHeight][defaultViewportWidth]int // Represents the full 128x128 map area editable by the streaming system
+
+	// skippedMapCells counts the cells of map.json outside mapData, which
+	// saving would drop, so map.json isn't saved while it's above 0
+	skippedMapCells int
+
+	// Auto-save state
+	lastAutoSave  time.Time     // When the recovery file was last checked
+	savedMap      []byte        // map.json as last loaded or saved, to tell unsaved changes
+	lastRecovery  []byte        // Contents of the recovery file as last written
+	recoveryFound *recoveryData // Recovery file waiting for the restore prompt, or nil
 }
 
 type mapData struct {
@@ -110,10 +158,7 @@ func forEachSpritePixel(fn func(row, col, r, c int)) {
 func (g *myGame) forEachSelectedSprite(fn func(row, col int)) {
 	baseRow := g.currentSprite / spriteSheetCols
 	baseCol := g.currentSprite % spriteSheetCols
-	size := g.gridSize
-	if size < 1 {
-		size = 1
-	}
+	size := g.safeGridSize()
 	for r := 0; r < size; r++ {
 		for c := 0; c < size; c++ {
 			sprRow := baseRow + r
@@ -129,11 +174,13 @@ func (g *myGame) forEachSelectedSprite(fn func(row, col int)) {
 func (g *myGame) Init() {
 	initSquareColors()
 
-	// Initialize virtual filesystem and undo/redo stacks
-	g.fs = afero.NewMemMapFs()
-	g.undoStack = make([]string, 0)
-	g.redoStack = make([]string, 0)
-	g.saveCooldown = 100 * time.Millisecond
+	// The editor debounces held keys itself, and holding X must not keep
+	// toggling map mode
+	p8.SetBtnRepeat(0, 0)
+
+	// Initialize undo/redo history, keeping JSON snapshots in a virtual filesystem
+	g.history = p8.NewUndoStack[editorState](maxUndoStates, p8.NewAferoSnapshotStore[editorState](afero.NewMemMapFs()))
+	g.history.SetCooldown(100 * time.Millisecond)
 
 	// Initialize sprite flags to false
 	for row := range spriteSheetRows {
@@ -174,6 +221,10 @@ func (g *myGame) Init() {
 	g.hoverX = -1                 // No hover initially
 	g.hoverY = -1                 // No hover initially
 	g.gridSize = defaultGridSize  // Start with 8x8 grid (1 sprite)
+	g.mapZoom = defaultMapZoom    // Start at 8 pixels per map tile
+	g.flagFilter = noFlagFilter   // Show every sprite
+	g.renamingFlag = noFlag
+	g.mapSnap = max(1, initialMapSnap)
 
 	// Ensure grid size is never less than 1
 	if g.gridSize < defaultGridSize {
@@ -188,6 +239,12 @@ func (g *myGame) Init() {
 	if err := g.saveState(); err != nil {
 		log.Printf("Failed to save initial state: %v", err)
 	}
+
+	// Offer to restore work the last session didn't save
+	savedSpritesheet = mustMarshal(convertSpritesheetToData())
+	g.savedMap = mustMarshal(g.convertMapToData())
+	g.lastAutoSave = time.Now()
+	g.checkRecovery()
 }
 
 // Define the sprite structure to match PIGO8's format
@@ -205,11 +262,13 @@ type spriteData struct {
 // Define the spritesheet structure
 type spriteSheetData struct {
 	// Custom spritesheet dimensions
-	SpriteSheetColumns int          `json:"SpriteSheetColumns"`
-	SpriteSheetRows    int          `json:"SpriteSheetRows"`
-	SpriteSheetWidth   int          `json:"SpriteSheetWidth"`
-	SpriteSheetHeight  int          `json:"SpriteSheetHeight"`
-	Sprites            []spriteData `json:"sprites"`
+	SpriteSheetColumns int                  `json:"SpriteSheetColumns"`
+	SpriteSheetRows    int                  `json:"SpriteSheetRows"`
+	SpriteSheetWidth   int                  `json:"SpriteSheetWidth"`
+	SpriteSheetHeight  int                  `json:"SpriteSheetHeight"`
+	Sprites            []spriteData         `json:"sprites"`
+	Animations         []p8.SpriteAnimation `json:"animations,omitempty"`
+	FlagNames          []string             `json:"flagNames,omitempty"`
 }
 
 // convertSpriteToData converts a sprite at the given row and column to PIGO8's spriteData format
@@ -305,6 +364,8 @@ func loadSpritesheet() error {
 	for _, sprite := range sheet.Sprites {
 		applySpriteData(sprite)
 	}
+	spriteAnimations = sheet.Animations
+	setFlagNames(sheet.FlagNames)
 
 	fmt.Println("Loaded spritesheet from spritesheet.json")
 	return nil
@@ -312,6 +373,16 @@ func loadSpritesheet() error {
 
 // saveSpritesheet saves the current spritesheet to a JSON file
 func saveSpritesheet() error {
+	sheet := convertSpritesheetToData()
+	if err := saveJSONToFile("spritesheet.json", sheet); err != nil {
+		return err
+	}
+	savedSpritesheet = mustMarshal(sheet)
+	return nil
+}
+
+// convertSpritesheetToData converts the whole spritesheet to PIGO8's format
+func convertSpritesheetToData() spriteSheetData {
 	// Create the spritesheet structure following the PIGO8 format
 	sheet := spriteSheetData{
 		SpriteSheetColumns: spriteSheetCols,
@@ -319,6 +390,8 @@ func saveSpritesheet() error {
 		SpriteSheetWidth:   spriteSheetCols * spriteSize, // Each sprite is 8x8 pixels
 		SpriteSheetHeight:  spriteSheetRows * spriteSize, // Each sprite is 8x8 pixels
 		Sprites:            make([]spriteData, 0, spriteSheetRows*spriteSheetCols),
+		Animations:         spriteAnimations,
+		FlagNames:          flagNamesForSheet(),
 	}
 
 	// Convert all sprites
@@ -328,7 +401,7 @@ func saveSpritesheet() error {
 		}
 	}
 
-	return saveJSONToFile("spritesheet.json", sheet)
+	return sheet
 }
 
 func (g *myGame) Draw() {
@@ -336,13 +409,13 @@ func (g *myGame) Draw() {
 
 	if g.mapMode {
 		g.drawMapMode()
-		return
+	} else {
+		g.updateDrawingCanvas()
+		g.drawEditorCanvas()
+		g.drawSpritesheetPanel()
+		g.drawSelectionAndPalette()
 	}
-
-	g.updateDrawingCanvas()
-	g.drawEditorCanvas()
-	g.drawSpritesheetPanel()
-	g.drawSelectionAndPalette()
+	g.drawRecoveryPrompt()
 }
 
 // drawMapMode draws everything when in map‐editing mode
@@ -354,6 +427,12 @@ func (g *myGame) drawMapMode() {
 	)
 	// 1) map viewport tiles
 	g.drawMapTiles(viewX, viewY)
+	if g.showMapGrid {
+		g.drawMapGrid(viewX, viewY)
+	}
+	if g.mapSnap > 1 {
+		g.drawSnapGrid(viewX, viewY)
+	}
 
 	// 2) hover highlight on map
 	mx, my := p8.GetMouseXY()
@@ -364,45 +443,283 @@ func (g *myGame) drawMapMode() {
 		viewX+mapViewWidth, viewY+mapViewHeight, g.getUIElementColor())
 	p8.Camera() // reset for text
 	g.printMapInfo(viewX, viewY, mx, my)
+
+	// 4) minimap of the whole map with the visible area
+	g.drawMinimap(viewX+mapViewWidth+minimapGap, viewY)
+}
+
+// tilePx returns the on-screen size of a map tile at the current zoom
+func (g *myGame) tilePx() int {
+	return mapZoomLevels[max(0, min(g.mapZoom, len(mapZoomLevels)-1))]
+}
+
+// viewTiles returns how many map tiles fit in the viewport at the current zoom
+func (g *myGame) viewTiles() (cols, rows int) {
+	return min(mapViewWidth/g.tilePx(), mapWidth), min(mapViewHeight/g.tilePx(), mapHeight)
+}
+
+// clampMapCamera keeps the viewport inside the map
+func (g *myGame) clampMapCamera() {
+	cols, rows := g.viewTiles()
+	g.mapCameraX = max(0, min(g.mapCameraX, mapWidth-cols))
+	g.mapCameraY = max(0, min(g.mapCameraY, mapHeight-rows))
+}
+
+// setMapZoom changes the zoom level, keeping the tile at the center of the view in place
+func (g *myGame) setMapZoom(level int) {
+	level = max(0, min(level, len(mapZoomLevels)-1))
+	if level == g.mapZoom {
+		return
+	}
+	cols, rows := g.viewTiles()
+	centerX, centerY := g.mapCameraX+cols/2, g.mapCameraY+rows/2
+
+	g.mapZoom = level
+	cols, rows = g.viewTiles()
+	g.mapCameraX, g.mapCameraY = centerX-cols/2, centerY-rows/2
+	g.clampMapCamera()
+}
+
+// drawMapTile draws sprite spr as a map tile of the current zoom at (x, y)
+func (g *myGame) drawMapTile(spr, x, y int) {
+	size := g.tilePx()
+	if size == unit {
+		p8.Spr(spr, x, y)
+		return
+	}
+
+	row, col := spr/spriteSheetCols, spr%spriteSheetCols
+	if row < 0 || row >= spriteSheetRows {
+		return
+	}
+	// Scale from the editor's own copy of the pixels; color 0 is transparent like in Spr
+	step := max(1, unit/size)  // source pixels per screen pixel when zoomed out
+	scale := max(1, size/unit) // screen pixels per source pixel when zoomed in
+	for py := 0; py < unit; py += step {
+		for px := 0; px < unit; px += step {
+			c := spritesheet[row][col][py][px]
+			if c == 0 {
+				continue
+			}
+			sx, sy := x+px/step*scale, y+py/step*scale
+			if scale > 1 {
+				p8.Rectfill(sx, sy, sx+scale-1, sy+scale-1, c)
+			} else {
+				p8.Pset(sx, sy, c)
+			}
+		}
+	}
+}
+
+// drawMapGrid draws tile boundaries and map coordinates over the viewport
+func (g *myGame) drawMapGrid(vx, vy int) {
+	size := g.tilePx()
+	cols, rows := g.viewTiles()
+	gridColor := g.getUIElementColor()
+	if p8.IsDefaultPico8PaletteActive() {
+		gridColor = 5 // PICO-8 dark grey
+	}
+
+	for x := 1; x < cols; x++ {
+		p8.Line(vx+x*size, vy, vx+x*size, vy+rows*size-1, gridColor)
+	}
+	for y := 1; y < rows; y++ {
+		p8.Line(vx, vy+y*size, vx+cols*size-1, vy+y*size, gridColor)
+	}
+
+	// Label every few tiles so the numbers don't overlap
+	labelStep := max(1, 32/size)
+	for x := 0; x < cols; x++ {
+		if tx := g.mapCameraX + x; tx%labelStep == 0 {
+			p8.Print(fmt.Sprintf("%d", tx), vx+x*size+1, vy+1, g.getUIElementColor())
+		}
+	}
+	for y := 0; y < rows; y++ {
+		if ty := g.mapCameraY + y; ty%labelStep == 0 && y > 0 {
+			p8.Print(fmt.Sprintf("%d", ty), vx+1, vy+y*size+1, g.getUIElementColor())
+		}
+	}
+}
+
+// drawSnapGrid draws the lines of the snap grid over the viewport, every
+// mapSnap tiles of the map
+func (g *myGame) drawSnapGrid(vx, vy int) {
+	size := g.tilePx()
+	cols, rows := g.viewTiles()
+	snapColor := g.getUIElementColor()
+	if p8.IsDefaultPico8PaletteActive() {
+		snapColor = 13 // PICO-8 lavender, apart from the dark grey tile grid
+	}
+
+	for x := 0; x < cols; x++ {
+		if (g.mapCameraX+x)%g.mapSnap == 0 {
+			p8.Line(vx+x*size, vy, vx+x*size, vy+rows*size-1, snapColor)
+		}
+	}
+	for y := 0; y < rows; y++ {
+		if (g.mapCameraY+y)%g.mapSnap == 0 {
+			p8.Line(vx, vy+y*size, vx+cols*size-1, vy+y*size, snapColor)
+		}
+	}
+}
+
+// activeMapSnap returns the snap step placement uses right now: mapSnap, or
+// single tiles while Alt is held
+func (g *myGame) activeMapSnap() int {
+	if g.mapSnap <= 1 || ebiten.IsKeyPressed(ebiten.KeyAlt) {
+		return 1
+	}
+	return g.mapSnap
+}
+
+// snapCell moves map cell (x, y) to the top-left corner of its snap block
+func (g *myGame) snapCell(x, y int) (int, int) {
+	step := g.activeMapSnap()
+	return x - x%step, y - y%step
+}
+
+// cycleMapSnap switches to the next snap step in mapSnapSizes, back to single
+// tiles after the largest
+func (g *myGame) cycleMapSnap() {
+	next := 1
+	for _, size := range mapSnapSizes {
+		if size > g.mapSnap {
+			next = size
+			break
+		}
+	}
+	g.mapSnap = next
+}
+
+// minimapColor picks the color that represents sprite spr on the minimap:
+// its most common non-transparent color
+func minimapColor(spr int) int {
+	row, col := spr/spriteSheetCols, spr%spriteSheetCols
+	if spr <= 0 || row >= spriteSheetRows {
+		return 0
+	}
+	var counts [64]int
+	best := 0
+	for py := 0; py < unit; py++ {
+		for px := 0; px < unit; px++ {
+			c := spritesheet[row][col][py][px]
+			if c <= 0 || c >= len(counts) {
+				continue
+			}
+			counts[c]++
+			if counts[c] > counts[best] {
+				best = c
+			}
+		}
+	}
+	return best
+}
+
+// drawMinimap draws the whole map at one pixel per tile at (x, y),
+// with a rectangle around the area shown in the viewport
+func (g *myGame) drawMinimap(x, y int) {
+	colors := make(map[int]int)
+	for ty := 0; ty < mapHeight; ty++ {
+		for tx := 0; tx < mapWidth; tx++ {
+			spr := g.mapData[ty][tx]
+			if spr == 0 {
+				continue
+			}
+			c, ok := colors[spr]
+			if !ok {
+				c = minimapColor(spr)
+				colors[spr] = c
+			}
+			if c != 0 {
+				p8.Pset(x+tx, y+ty, c)
+			}
+		}
+	}
+
+	cols, rows := g.viewTiles()
+	p8.Rect(x-1, y-1, x+mapWidth, y+mapHeight, g.getUIElementColor())
+	p8.Rect(x+g.mapCameraX, y+g.mapCameraY, x+g.mapCameraX+cols-1, y+g.mapCameraY+rows-1, 8)
+}
+
+// handleMinimapClick centers the viewport on the tile clicked in the minimap
+func (g *myGame) handleMinimapClick(mx, my int) {
+	x, y := 10+mapViewWidth+minimapGap, 10
+	if mx < x || mx >= x+mapWidth || my < y || my >= y+mapHeight || !p8.Btn(p8.ButtonMouseLeft) {
+		return
+	}
+	cols, rows := g.viewTiles()
+	g.mapCameraX, g.mapCameraY = mx-x-cols/2, my-y-rows/2
+	g.clampMapCamera()
 }
 
 func (g *myGame) drawMapTiles(vx, vy int) {
-	cols := mapViewWidth / unit
-	rows := mapViewHeight / unit
+	cols, rows := g.viewTiles()
+	size := g.tilePx()
 
 	for y := 0; y < rows; y++ {
 		for x := 0; x < cols; x++ {
 			tileX, tileY := g.mapCameraX+x, g.mapCameraY+y
 			if tileX >= 0 && tileX < mapWidth && tileY >= 0 && tileY < mapHeight {
 				spr := g.mapData[tileY][tileX] // Use g.mapData directly
-				p8.Spr(spr, float64(vx+x*8), float64(vy+y*8))
+				g.drawMapTile(spr, vx+x*size, vy+y*size)
 			}
 		}
 	}
 }
 
+// drawMapDrag outlines the region of the drag in progress
+func (g *myGame) drawMapDrag(vx, vy int) {
+	x, y, w, h := g.dragRect()
+	size := g.tilePx()
+	left := vx + (x-g.mapCameraX)*size
+	top := vy + (y-g.mapCameraY)*size
+	// Clip the outline to the viewport; the region may extend past the current screen
+	x0, y0 := max(left, vx), max(top, vy)
+	x1 := min(left+w*size-1, vx+mapViewWidth)
+	y1 := min(top+h*size-1, vy+mapViewHeight)
+	if x0 <= x1 && y0 <= y1 {
+		p8.Rect(x0, y0, x1, y1, g.getUIElementColor())
+	}
+}
+
 // drawMapHover draws the hover highlight on the map
 func (g *myGame) drawMapHover(vx, vy, mx, my int) {
-	cols := mapViewWidth / unit
-	rows := mapViewHeight / unit
+	if g.dragMode != dragNone {
+		g.drawMapDrag(vx, vy)
+		return
+	}
+
+	cols, rows := g.viewTiles()
+	size := g.tilePx()
 	// determine multi‐sprite grid
 	w, h := 1, 1
-	if g.gridSize >= 2 {
+	if g.stamp.Width > 0 {
+		w, h = g.stamp.Width, g.stamp.Height
+	} else if g.gridSize >= 2 {
 		w, h = g.gridSize, g.gridSize
 	}
-	gx, gy := (mx-vx)/8, (my-vy)/8
+	if mx < vx || my < vy {
+		return
+	}
+	gx, gy := (mx-vx)/size, (my-vy)/size
 	if gx < 0 || gx >= cols || gy < 0 || gy >= rows {
 		return
 	}
+	// Snap like placement does, which may start the block left of or above the view
+	sx, sy := g.snapCell(g.mapCameraX+gx, g.mapCameraY+gy)
+	gx, gy = sx-g.mapCameraX, sy-g.mapCameraY
+	if g.activeMapSnap() > 1 {
+		g.drawMapGuides(vx, vy, gx, gy)
+	}
 	for dy := 0; dy < h; dy++ {
 		for dx := 0; dx < w; dx++ {
 			x, y := gx+dx, gy+dy
-			if x < cols && y < rows {
+			if x >= 0 && y >= 0 && x < cols && y < rows {
 				p8.Rect(
-					float64(vx+x*8),
-					float64(vy+y*8),
-					float64(vx+(x+1)*8-1),
-					float64(vy+(y+1)*8-1),
+					float64(vx+x*size),
+					float64(vy+y*size),
+					float64(vx+(x+1)*size-1),
+					float64(vy+(y+1)*size-1),
 					g.getUIElementColor(), // Hover color
 				)
 			}
@@ -410,22 +727,45 @@ func (g *myGame) drawMapHover(vx, vy, mx, my int) {
 	}
 }
 
+// drawMapGuides draws dashed ruler lines across the viewport through the
+// top-left corner of the snapped cell (gx, gy), in viewport tiles
+func (g *myGame) drawMapGuides(vx, vy, gx, gy int) {
+	size := g.tilePx()
+	cols, rows := g.viewTiles()
+	guideColor := g.getUIElementColor()
+	if p8.IsDefaultPico8PaletteActive() {
+		guideColor = 12 // PICO-8 blue
+	}
+	dash := p8.WithDash(2, 2)
+	if gx >= 0 {
+		p8.Line(vx+gx*size, vy, vx+gx*size, vy+rows*size-1, guideColor, dash)
+	}
+	if gy >= 0 {
+		p8.Line(vx, vy+gy*size, vx+cols*size-1, vy+gy*size, guideColor, dash)
+	}
+}
+
 // printMapInfo prints the map info
 func (g *myGame) printMapInfo(vx, vy, mx, my int) {
 	// Screen coords
 	p8.Color(1)
-	sx := g.mapCameraX / (mapViewWidth / unit)
-	sy := g.mapCameraY / (mapViewHeight / unit)
+	cols, rows := g.viewTiles()
+	sx := g.mapCameraX / cols
+	sy := g.mapCameraY / rows
 	textY := vy + mapViewHeight + 10
-	p8.Print(fmt.Sprintf("Screen: %d,%d", sx, sy), vx, textY, 1)
+	screenInfo := fmt.Sprintf("Screen: %d,%d x%d", sx, sy, g.tilePx())
+	if g.mapSnap > 1 {
+		screenInfo += fmt.Sprintf(" snap %d", g.mapSnap)
+	}
+	p8.Print(screenInfo, vx, textY, 1)
 
 	// Mouse in map space
 	if mx < vx || mx >= vx+mapViewWidth ||
 		my < vy || my >= vy+mapViewHeight {
 		return
 	}
-	mxMap := g.mapCameraX + (mx-vx)/8
-	myMap := g.mapCameraY + (my-vy)/8
+	mxMap := g.mapCameraX + (mx-vx)/g.tilePx()
+	myMap := g.mapCameraY + (my-vy)/g.tilePx()
 	p8.Print(fmt.Sprintf("Map: %d,%d", mxMap, myMap),
 		vx+90, textY, 1)
 
@@ -478,10 +818,12 @@ func (g *myGame) drawSpritesheetPanel() {
 		for c := 0; c < spriteSheetCols; c++ {
 			baseX := sx + c*spriteCellSize
 			baseY := sy + r*spriteCellSize
+			// Dim the sprites without the filtered flag with a checkerboard
+			dimmed := g.flagFilter != noFlagFilter && !spriteFlags[r][c][g.flagFilter]
 			for py := 0; py < 8; py++ {
 				for px := 0; px < 8; px++ {
 					col := spritesheet[r][c][py][px]
-					if col == 0 {
+					if col == 0 || dimmed && (px+py)%2 == 1 {
 						p8.Pset(baseX+px, baseY+py, 0)
 					} else {
 						p8.Pset(baseX+px, baseY+py, col)
@@ -497,12 +839,30 @@ func (g *myGame) drawSpritesheetPanel() {
 	}
 	p8.Rect(sx-1, sy-1, ex+1, ey+1, g.getUIElementColor())
 
-	sizeText := map[int]string{1: "8x8", 2: "16x16", 4: "32x32"}[g.gridSize]
+	sizeText := fmt.Sprintf("%dx%d", g.safeGridSize()*spriteSize, g.safeGridSize()*spriteSize)
 	p8.Print(
 		fmt.Sprintf("spritesheet - sprite: %d - grid: %s",
 			g.currentSprite, sizeText),
 		sx, ey+4, g.getUIElementColor(),
 	)
+	g.drawAnimationInfo(sx, ey+12)
+}
+
+// drawAnimationInfo shows the animation the A key adds sprites to
+func (g *myGame) drawAnimationInfo(x, y int) {
+	if len(spriteAnimations) == 0 {
+		p8.Print("animation: none - shift+a: new", x, y, g.getUIElementColor())
+		return
+	}
+	anim := spriteAnimations[len(spriteAnimations)-1]
+	frames := make([]string, len(anim.Frames))
+	for i, frame := range anim.Frames {
+		frames[i] = strconv.Itoa(frame)
+	}
+	p8.Print(
+		fmt.Sprintf("animation: %s [%s] - a: add sprite", anim.Name, strings.Join(frames, " ")),
+		x, y, g.getUIElementColor(),
+	)
 }
 
 // drawSelectionBorder highlights the multi‐cell selection in the spritesheet
@@ -552,10 +912,38 @@ var (
 	spritesheetStartX = 120 // Position spritesheet (adjusted 10px to the left)
 )
 
+// mapZoomLevels are the on-screen sizes of a map tile, in pixels
+var mapZoomLevels = []int{4, 8, 16}
+
+// mapSnapSizes are the snap steps the N key cycles through in map mode, in tiles
+var mapSnapSizes = []int{1, 2, 4, 8, 16}
+
+// initialMapSnap is the snap step map mode starts with, set with the -snap flag
+var initialMapSnap = 1
+
 var squareColors [64][64]int      // Up to 64x64 grid to store square colors
 var spritesheet [24][32][8][8]int // 24x32 grid of 8x8 sprites
 var spriteFlags [24][32][8]bool   // Flags for each sprite [row][col][flag0-7]
 
+// spriteAnimations are the named sprite sequences saved in spritesheet.json;
+// the last one is the one the A key adds sprites to
+var spriteAnimations []p8.SpriteAnimation
+
+// flagNames are the sprite flag names saved in spritesheet.json, shown with
+// the flag checkboxes; "" for unnamed flags
+var flagNames [numFlags]string
+
+// savedSpritesheet is spritesheet.json as last loaded or saved, to tell
+// unsaved changes
+var savedSpritesheet []byte
+
+// autoSaveInterval is how often unsaved work is written to recoveryFile, set
+// with the -autosave flag; 0 turns auto-save off
+var autoSaveInterval = defaultAutoSaveInterval
+
+// mapGoPackage is the package of mapGoFile, set with the -gopkg flag
+var mapGoPackage = "main"
+
 func initSquareColors() {
 	for row := range 64 {
 		for col := range 64 {
@@ -587,8 +975,8 @@ func initSpritesheet() {
 func initPico8Spritesheet() error {
 	// Check if spritesheet.json already exists
 	if _, err := os.Stat("spritesheet.json"); err == nil {
-		// File exists, no need to create it
-		return nil
+		// File exists, no need to create it, as long as the editor can edit it
+		return checkSpritesheetTileSize()
 	} else if !os.IsNotExist(err) {
 		// Some other error occurred
 		return fmt.Errorf("error checking spritesheet.json: %w", err)
@@ -609,6 +997,33 @@ func initPico8Spritesheet() error {
 	return nil
 }
 
+// checkSpritesheetTileSize returns an error if spritesheet.json was made for a
+// tile size other than the editor's 8x8 sprites (Settings.TileSize), since
+// opening it would cut its sprites to 8x8 and save them back that way
+func checkSpritesheetTileSize() error {
+	data, err := os.ReadFile("spritesheet.json")
+	if err != nil {
+		return fmt.Errorf("error reading spritesheet.json: %w", err)
+	}
+	var sheet spriteSheetData
+	if err := json.Unmarshal(data, &sheet); err != nil {
+		return fmt.Errorf("error parsing spritesheet.json: %w", err)
+	}
+
+	tileSize := spriteSize
+	if sheet.SpriteSheetColumns > 0 && sheet.SpriteSheetWidth > 0 {
+		tileSize = sheet.SpriteSheetWidth / sheet.SpriteSheetColumns
+	}
+	for _, sprite := range sheet.Sprites {
+		tileSize = max(tileSize, sprite.Width, sprite.Height, len(sprite.Pixels))
+	}
+	if tileSize != spriteSize {
+		return fmt.Errorf("spritesheet.json has %dx%d sprites, but the editor only edits %dx%d sprites (Settings.TileSize %d); "+
+			"it was left unchanged", tileSize, tileSize, spriteSize, spriteSize, spriteSize)
+	}
+	return nil
+}
+
 // createTempSpritesheet creates a temporary spritesheet.json file
 // that PIGO8 can load to initialize its sprite system
 func createTempSpritesheet() {
@@ -747,12 +1162,33 @@ func (g *myGame) drawCheckboxes(x, y int) {
 			p8.Line(checkboxX+checkboxSize-3, checkboxY+2, checkboxX+2, checkboxY+checkboxSize-3, g.getUIElementColor())
 		}
 
-		// Draw flag number
-		p8.Print(strconv.Itoa(i), checkboxX+1, checkboxY+checkboxSize+2, g.getUIElementColor())
+		// Draw the start of the flag name (or its number), marking the filtered flag
+		numberColor := g.getUIElementColor()
+		if i == g.flagFilter {
+			numberColor = filterColor
+		}
+		p8.Print(flagLabel(i), checkboxX+1, checkboxY+checkboxSize+2, numberColor)
 	}
 
-	// Draw label
-	p8.Print("flags", x, y-10, g.getUIElementColor())
+	// Draw label: the name being typed, the hovered flag's name or the filter
+	if g.renamingFlag != noFlag {
+		p8.Print(fmt.Sprintf("flag %d: %s_", g.renamingFlag, p8.GetTextInput()), x, y-10, filterColor)
+		return
+	}
+	mx, my := p8.GetMouseXY()
+	if hovered := flagCheckboxAt(x, y, mx, my); hovered != noFlag {
+		name := flagNames[hovered]
+		if name == "" {
+			name = "shift+click: name"
+		}
+		p8.Print(fmt.Sprintf("flag %d: %s", hovered, name), x, y-10, g.getUIElementColor())
+		return
+	}
+	if g.flagFilter == noFlagFilter {
+		p8.Print("flags", x, y-10, g.getUIElementColor())
+		return
+	}
+	p8.Print(fmt.Sprintf("flags - %d: %d sprites, tab", g.flagFilter, len(spritesWithFlag(g.flagFilter))), x, y-10, filterColor)
 }
 
 // drawPalette draws the color palette below the grid
@@ -851,8 +1287,8 @@ func (g *myGame) convertMapToData() mapData {
 	mapData := mapData{
 		Version:     "1.0",
 		Description: "Map created with PIGO8 editor",
-		Width:       320,
-		Height:      320,
+		Width:       mapWidth,
+		Height:      mapHeight,
 		Name:        "map",
 		Cells:       []mapCell{},
 	}
@@ -875,8 +1311,13 @@ func (g *myGame) convertMapToData() mapData {
 	return mapData
 }
 
-// applyMapData applies the PIGO8 MapData format to the game's map
-func (g *myGame) applyMapData(mapData mapData) {
+// errMapCellsSkipped is returned by saveMapData while map.json has cells the
+// editor's map can't hold
+var errMapCellsSkipped = errors.New("map.json has cells outside the editor's map")
+
+// applyMapData applies the PIGO8 MapData format to the game's map and returns
+// how many cells were outside it and skipped
+func (g *myGame) applyMapData(mapData mapData) int {
 	// Initialize map with zeros
 	for y := range g.mapData {
 		for x := range g.mapData[y] {
@@ -884,23 +1325,57 @@ func (g *myGame) applyMapData(mapData mapData) {
 		}
 	}
 
+	// Keep PIGO8's map the same size as ours, so no tiles are lost when syncing
+	p8.SetMapSize(mapWidth, mapHeight)
+
 	// Load the cells into our map data
+	skipped := 0
 	for _, cell := range mapData.Cells {
 		// Make sure coordinates are within bounds
-		if cell.X >= 0 && cell.X < 320 && cell.Y >= 0 && cell.Y < 320 {
-			g.mapData[cell.Y][cell.X] = cell.Sprite
-			// Also update the PIGO8 map
-			p8.Mset(cell.X, cell.Y, cell.Sprite)
+		if !g.inBounds(cell.X, cell.Y) {
+			skipped++
+			continue
 		}
+		g.mapData[cell.Y][cell.X] = cell.Sprite
+		// Also update the PIGO8 map
+		p8.Mset(cell.X, cell.Y, cell.Sprite)
+	}
+	if skipped > 0 {
+		log.Printf("Warning: %d map cells are outside the editor's %dx%d map and were skipped", skipped, mapWidth, mapHeight)
 	}
 
 	fmt.Printf("Loaded map: %dx%d tiles. View: %dx%d pixels (%dx%d tiles). %d cells\n", mapData.Width, mapData.Height, mapViewWidth, mapViewHeight, mapViewWidth/unit, mapViewHeight/unit, len(mapData.Cells))
+	return skipped
 }
 
 // saveMapData saves the current map to map.json
 func (g *myGame) saveMapData() error {
+	if g.skippedMapCells > 0 {
+		return fmt.Errorf("%w: %d cells outside %dx%d would be lost, so it isn't saved",
+			errMapCellsSkipped, g.skippedMapCells, mapWidth, mapHeight)
+	}
 	mapData := g.convertMapToData()
-	return saveJSONToFile("map.json", mapData)
+	if err := saveJSONToFile("map.json", mapData); err != nil {
+		return err
+	}
+	g.savedMap = mustMarshal(mapData)
+	return nil
+}
+
+// exportMapGo writes the map to mapGoFile as Go source, for games that
+// compile their map in with p8.SetMap instead of loading map.json
+func (g *myGame) exportMapGo() {
+	g.syncMapDataToPigo8()
+	src, err := p8.ExportMapGo(mapGoPackage, mapGoVar)
+	if err != nil {
+		log.Printf("Error exporting the map as Go source: %v", err)
+		return
+	}
+	if err := os.WriteFile(mapGoFile, src, 0644); err != nil {
+		log.Printf("Error writing %s: %v", mapGoFile, err)
+		return
+	}
+	fmt.Printf("Map exported to %s as %s in package %s\n", mapGoFile, mapGoVar, mapGoPackage)
 }
 
 // loadMapData loads the map from map.json if it exists
@@ -910,12 +1385,25 @@ func (g *myGame) loadMapData() error {
 		return err
 	}
 
-	g.applyMapData(mapData)
+	g.skippedMapCells = g.applyMapData(mapData)
+	if g.skippedMapCells > 0 {
+		log.Printf("Warning: map.json won't be saved, so its %d skipped cells are kept; edits stay in %s", g.skippedMapCells, recoveryFile)
+	}
 	return nil
 }
 
 // Refactored Update method with reduced cyclomatic complexity and integrated save-on-toggle logic
 func (g *myGame) Update() {
+	if g.recoveryFound != nil {
+		g.handleRecoveryPrompt()
+		return
+	}
+	defer g.autoSaveIfDue()
+	if g.renamingFlag != noFlag {
+		g.handleFlagRename()
+		return
+	}
+
 	g.toggleMapMode()
 	g.handleUndoRedo() // Handle undo/redo in both modes
 	if g.mapMode {
@@ -947,8 +1435,11 @@ func (g *myGame) toggleMapMode() {
 				// Similar to above, log and continue for now
 			}
 		} else {
-			if err := g.saveMapData(); err != nil {
+			if err := g.saveMapData(); errors.Is(err, errMapCellsSkipped) {
+				log.Printf("Warning: %v", err)
+			} else if err != nil {
 				fmt.Println("Error saving map:", err)
+				g.autoSave() // Keep the work in the recovery file
 				os.Exit(1)
 			}
 		}
@@ -958,24 +1449,138 @@ func (g *myGame) toggleMapMode() {
 // -------------------- Map Mode --------------------
 func (g *myGame) handleMapMode() {
 	g.moveCamera()
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.stamp = p8.MapStamp{} // Drop the stamp and go back to single sprites
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+		g.exportMapGo()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyN) {
+		g.cycleMapSnap()
+	}
+	if g.handleMapDrag() {
+		return
+	}
 	g.placeOrEraseSprites()
 }
 
+// mouseMapCell returns the map cell under the mouse, clamped to the map.
+// The camera offset is included, so a drag keeps its anchor while scrolling.
+func (g *myGame) mouseMapCell(mx, my int) (int, int) {
+	x := g.mapCameraX + (mx-10)/g.tilePx()
+	y := g.mapCameraY + (my-10)/g.tilePx()
+	x = max(0, min(x, len(g.mapData[0])-1))
+	y = max(0, min(y, len(g.mapData)-1))
+	return x, y
+}
+
+// dragRect returns the normalized region covered by the current drag
+func (g *myGame) dragRect() (x, y, w, h int) {
+	x0, x1 := min(g.dragStartX, g.dragEndX), max(g.dragStartX, g.dragEndX)
+	y0, y1 := min(g.dragStartY, g.dragEndY), max(g.dragStartY, g.dragEndY)
+	return x0, y0, x1 - x0 + 1, y1 - y0 + 1
+}
+
+// handleMapDrag starts, tracks and finishes region drags (stamp capture and
+// rectangle fill). It returns true while a drag is consuming the mouse.
+func (g *myGame) handleMapDrag() bool {
+	mx, my := p8.GetMouseXY()
+
+	if g.dragMode == dragNone {
+		if !g.mouseInMap(mx, my) || !p8.Btnp(p8.ButtonMouseLeft) {
+			return false
+		}
+		switch {
+		case ebiten.IsKeyPressed(ebiten.KeyS):
+			g.dragMode = dragCapture
+		case ebiten.IsKeyPressed(ebiten.KeyShift):
+			g.dragMode = dragFill
+		default:
+			return false
+		}
+		g.dragStartX, g.dragStartY = g.mouseMapCell(mx, my)
+		g.dragEndX, g.dragEndY = g.dragStartX, g.dragStartY
+		return true
+	}
+
+	if p8.Btn(p8.ButtonMouseLeft) {
+		g.dragEndX, g.dragEndY = g.mouseMapCell(mx, my)
+		return true
+	}
+
+	// Mouse released: apply the drag
+	x, y, w, h := g.dragRect()
+	switch g.dragMode {
+	case dragCapture:
+		g.stamp = p8.MapCopy(x, y, w, h)
+		log.Printf("Captured %dx%d stamp at (%d,%d)", w, h, x, y)
+	case dragFill:
+		if p8.MapFill(x, y, w, h, g.currentSprite) > 0 {
+			g.syncMapRegionFromPigo8(x, y, w, h)
+			g.recordMapEdit()
+		}
+	}
+	g.dragMode = dragNone
+	return true
+}
+
+// pasteStamp places the current stamp with its top-left corner at (x, y)
+func (g *myGame) pasteStamp(x, y int) {
+	if p8.MapPaste(g.stamp, x, y) > 0 {
+		g.syncMapRegionFromPigo8(x, y, g.stamp.Width, g.stamp.Height)
+		g.recordMapEdit()
+	}
+}
+
+// syncMapRegionFromPigo8 copies a region of PIGO8's map back into g.mapData
+func (g *myGame) syncMapRegionFromPigo8(x, y, w, h int) {
+	region := p8.MapCopy(x, y, w, h)
+	for dy := 0; dy < h; dy++ {
+		for dx := 0; dx < w; dx++ {
+			tx, ty := x+dx, y+dy
+			if ty >= 0 && ty < len(g.mapData) && tx >= 0 && tx < len(g.mapData[ty]) {
+				g.mapData[ty][tx] = region.At(dx, dy)
+			}
+		}
+	}
+}
+
+// recordMapEdit pushes a bulk map edit to the undo history as a single step
+func (g *myGame) recordMapEdit() {
+	if err := g.saveState(); err != nil {
+		log.Printf("Error saving state: %v", err)
+	}
+}
+
 func (g *myGame) moveCamera() {
-	stepX := mapViewWidth / unit
-	stepY := mapViewHeight / unit
-	if p8.Btnp(p8.LEFT) && g.mapCameraX > 0 {
+	stepX, stepY := g.viewTiles()
+	if p8.Btnp(p8.LEFT) {
 		g.mapCameraX -= stepX
 	}
-	if p8.Btnp(p8.RIGHT) && g.mapCameraX < mapWidth-stepX {
+	if p8.Btnp(p8.RIGHT) {
 		g.mapCameraX += stepX
 	}
-	if p8.Btnp(p8.UP) && g.mapCameraY > 0 {
+	if p8.Btnp(p8.UP) {
 		g.mapCameraY -= stepY
 	}
-	if p8.Btnp(p8.DOWN) && g.mapCameraY < mapHeight-stepY {
+	if p8.Btnp(p8.DOWN) {
 		g.mapCameraY += stepY
 	}
+
+	// Zoom with +/- (or the mouse wheel), toggle the grid with G
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) || inpututil.IsKeyJustPressed(ebiten.KeyKPAdd) || p8.Btnp(p8.ButtonMouseWheelUp) {
+		g.setMapZoom(g.mapZoom + 1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) || inpututil.IsKeyJustPressed(ebiten.KeyKPSubtract) || p8.Btnp(p8.ButtonMouseWheelDown) {
+		g.setMapZoom(g.mapZoom - 1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyG) {
+		g.showMapGrid = !g.showMapGrid
+	}
+
+	mx, my := p8.GetMouseXY()
+	g.handleMinimapClick(mx, my)
+	g.clampMapCamera()
 }
 
 func (g *myGame) placeOrEraseSprites() {
@@ -983,13 +1588,21 @@ func (g *myGame) placeOrEraseSprites() {
 	if !g.mouseInMap(mx, my) {
 		return
 	}
-	x := g.mapCameraX + (mx-10)/8
-	y := g.mapCameraY + (my-10)/8
+	x := g.mapCameraX + (mx-10)/g.tilePx()
+	y := g.mapCameraY + (my-10)/g.tilePx()
 
 	if p8.Btn(p8.ButtonMouseRight) {
 		g.eraseAt(x, y)
 		return
 	}
+	// Sprites and stamps go to the snap grid; erasing stays per tile
+	x, y = g.snapCell(x, y)
+	if g.stamp.Width > 0 {
+		if p8.Btnp(p8.ButtonMouseLeft) {
+			g.pasteStamp(x, y)
+		}
+		return
+	}
 	if p8.Btn(p8.ButtonMouseLeft) {
 		g.placeGridSprites(x, y)
 	}
@@ -1018,10 +1631,7 @@ func (g *myGame) inBounds(x, y int) bool {
 }
 
 func (g *myGame) placeGridSprites(x, y int) {
-	w, h := g.gridSize, g.gridSize
-	if w < 1 {
-		w, h = 1, 1
-	}
+	w, h := g.safeGridSize(), g.safeGridSize()
 	base := g.currentSprite
 	changed := false
 
@@ -1055,40 +1665,18 @@ func (g *myGame) placeGridSprites(x, y int) {
 	}
 }
 
-// saveState saves the current state to a temporary file and updates the undo stack
-func (g *myGame) saveState() error {
-	g.stateMutex.Lock()
-	defer g.stateMutex.Unlock()
-
-	// Don't save too frequently
-	if time.Since(g.lastSaveTime) < g.saveCooldown {
-		log.Println("Skipping saveState: too soon since last save")
-		return nil
-	}
-
-	// If we have a redo stack, clear it when making new changes after undo
-	if len(g.redoStack) > 0 {
-		// Clean up old redo states
-		for _, filename := range g.redoStack {
-			if err := g.fs.Remove(filename); err != nil && !os.IsNotExist(err) {
-				log.Printf("Error removing redo state file %s: %v", filename, err)
-			}
-		}
-		g.redoStack = g.redoStack[:0]
-	}
-
-	// Create a unique filename
-	timestamp := time.Now().UnixNano()
-	filename := fmt.Sprintf("state_%d.json", timestamp)
+// editorState is a snapshot of everything undo/redo restores
+type editorState struct {
+	Spritesheet   [24][32][8][8]int
+	SpriteFlags   [24][32][8]bool
+	MapData       [defaultViewportHeight][defaultViewportWidth]int // Use PICO-8 map dimensions
+	CurrentSprite int
+	CurrentColor  int
+}
 
-	// Create state data
-	state := struct {
-		Spritesheet   [24][32][8][8]int
-		SpriteFlags   [24][32][8]bool
-		MapData       [defaultViewportHeight][defaultViewportWidth]int // Use PICO-8 map dimensions
-		CurrentSprite int
-		CurrentColor  int
-	}{
+// saveState pushes the current state onto the undo history
+func (g *myGame) saveState() error {
+	state := editorState{
 		Spritesheet:   spritesheet,
 		SpriteFlags:   spriteFlags,
 		MapData:       g.mapData,
@@ -1096,40 +1684,21 @@ func (g *myGame) saveState() error {
 		CurrentColor:  g.currentColor,
 	}
 
-	// Marshal to JSON
-	data, err := json.Marshal(state)
+	pushed, err := g.history.Push(state)
 	if err != nil {
-		return fmt.Errorf("error marshaling state: %w", err)
-	}
-
-	// Save to virtual filesystem
-	if err := afero.WriteFile(g.fs, filename, data, 0644); err != nil {
-		return fmt.Errorf("error saving state: %w", err)
+		return err
 	}
-
-	// Update undo stack and clear redo stack
-	g.undoStack = append(g.undoStack, filename)
-	g.redoStack = g.redoStack[:0] // Clear redo stack
-
-	// Limit undo stack size (keep last 50 states)
-	if len(g.undoStack) > 50 {
-		// Remove oldest state file
-		oldest := g.undoStack[0]
-		if err := g.fs.Remove(oldest); err != nil && !os.IsNotExist(err) {
-			log.Printf("Error removing old state file %s: %v", oldest, err)
-		}
-		g.undoStack = g.undoStack[1:]
+	if !pushed {
+		log.Println("Skipping saveState: too soon since last save")
 	}
-
-	g.lastSaveTime = time.Now()
 	return nil
 }
 
 // syncMapDataToPigo8 updates PICO-8's internal map memory to match g.mapData
 // using the bulk SetMap operation.
 func (g *myGame) syncMapDataToPigo8() {
-	// g.mapData is now [p8.Pico8MapHeight][p8.Pico8MapWidth]int
-	// p8.SetMap expects a flat []byte slice.
+	// g.mapData is [mapHeight][mapWidth]int, the same size as PIGO8's map
+	// (see applyMapData). p8.SetMap expects a flat []byte slice.
 
 	mapBytes := make([]byte, defaultViewportHeight*defaultViewportWidth)
 	nonZeroTiles := 0
@@ -1158,31 +1727,8 @@ func (g *myGame) syncMapDataToPigo8() {
 	// g.debugPrintMap() // Commented out: ensure it handles new map dimensions if re-enabled
 }
 
-// loadState loads a state from the virtual filesystem
-func (g *myGame) loadState(filename string) error {
-	g.stateMutex.Lock()
-	defer g.stateMutex.Unlock()
-
-	// Read from virtual filesystem
-	data, err := afero.ReadFile(g.fs, filename)
-	if err != nil {
-		return fmt.Errorf("error reading state: %w", err)
-	}
-
-	// Unmarshal state
-	var state struct {
-		Spritesheet   [24][32][8][8]int
-		SpriteFlags   [24][32][8]bool
-		MapData       [defaultViewportHeight][defaultViewportWidth]int // Use PICO-8 map dimensions
-		CurrentSprite int
-		CurrentColor  int
-	}
-
-	if err := json.Unmarshal(data, &state); err != nil {
-		return fmt.Errorf("error unmarshaling state: %w", err)
-	}
-
-	// Apply state
+// applyState restores a snapshot taken by saveState
+func (g *myGame) applyState(state editorState) {
 	spritesheet = state.Spritesheet
 	spriteFlags = state.SpriteFlags
 	g.mapData = state.MapData
@@ -1193,62 +1739,35 @@ func (g *myGame) loadState(filename string) error {
 	g.updateDrawingCanvas()
 	g.syncMapDataToPigo8() // Sync map data to PICO-8's internal map memory
 	updateMapSprites(-1)   // Update all sprites
-
-	return nil
 }
 
 // undo reverts to the previous state
 func (g *myGame) undo() {
-
-	if len(g.undoStack) < 2 {
+	state, ok := g.history.Undo()
+	if !ok {
 		log.Println("Not enough states to undo")
-		return // Need at least 2 states to undo (current + previous)
-	}
-
-	// Pop the current state and move to redo stack
-	current := g.undoStack[len(g.undoStack)-1]
-	g.redoStack = append(g.redoStack, current)
-	g.undoStack = g.undoStack[:len(g.undoStack)-1]
-
-	// Load previous state
-	if len(g.undoStack) > 0 {
-		prevState := g.undoStack[len(g.undoStack)-1]
-		if err := g.loadState(prevState); err != nil {
-			log.Printf("Error undoing: %v", err)
-		}
+		return
 	}
+	g.applyState(state)
 }
 
 // redo re-applies the next state
 func (g *myGame) redo() {
-	log.Printf("Redo called. Stack sizes - undo: %d, redo: %d", len(g.undoStack), len(g.redoStack))
-
-	if len(g.redoStack) == 0 {
+	state, ok := g.history.Redo()
+	if !ok {
 		log.Println("Nothing to redo")
 		return
 	}
+	g.applyState(state)
 
-	// Pop from redo stack and push to undo stack
-	next := g.redoStack[len(g.redoStack)-1]
-	g.redoStack = g.redoStack[:len(g.redoStack)-1]
-	g.undoStack = append(g.undoStack, next)
-
-	log.Printf("Redoing state from %s", next)
-	// Load the state
-	if err := g.loadState(next); err != nil {
-		log.Printf("Error redoing: %v", err)
-	} else {
-		log.Printf("Redo successful. New stack sizes - undo: %d, redo: %d",
-			len(g.undoStack), len(g.redoStack))
-	}
+	undoCount, redoCount := g.history.Len()
+	log.Printf("Redo successful. New stack sizes - undo: %d, redo: %d", undoCount, redoCount)
 }
 
 // saveCurrentStateIfNeeded saves the current state if enough time has passed
 func (g *myGame) saveCurrentStateIfNeeded() {
-	if time.Since(g.lastSaveTime) >= g.saveCooldown {
-		if err := g.saveState(); err != nil {
-			log.Printf("Error saving state: %v", err)
-		}
+	if err := g.saveState(); err != nil {
+		log.Printf("Error saving state: %v", err)
 	}
 }
 
@@ -1274,9 +1793,9 @@ func (g *myGame) handleUndoRedo() {
 	}
 
 	// Check for Cmd+Z (Undo) or Cmd+Shift+Z (Redo)
-	if ebiten.IsKeyPressed(ebiten.KeyMeta) || ebiten.IsKeyPressed(ebiten.KeyControl) {
-		if inpututil.IsKeyJustPressed(ebiten.KeyZ) {
-			if ebiten.IsKeyPressed(ebiten.KeyShift) {
+	if p8.MetaHeld() || p8.CtrlHeld() {
+		if p8.Keyp("Z") {
+			if p8.ShiftHeld() {
 				// Redo with Cmd+Shift+Z
 				if g.canTriggerAction(&g.lastRedoTime) {
 					g.undoInProgress = true
@@ -1304,44 +1823,158 @@ func (g *myGame) handleEditorMode() {
 	g.handlePaletteSelection(mx, my)
 	g.handleWheel()
 	g.handleKeyboardNavigation()
+	g.handleFlagFilterKeys()
 	g.handleCopyPaste()
+	g.handleTransforms()
+	g.handleAnimationKeys()
 
 	// Handle undo/redo with proper debouncing
 	g.handleUndoRedo()
 }
 
 func (g *myGame) toggleSpriteFlags(mx, my int) {
-	// Use the same coordinates and size as in drawCheckboxes
-	const checkboxSize = 8
 	const offset = 15 // Match the offset from drawSelectionAndPalette
 
 	// Get the same base coordinates used in drawCheckboxes
 	baseX := 10
 	baseY := 10 + 8*12 - 2 + offset // Add the offset to match drawCheckboxes position
 
-	for i := 0; i < 8; i++ {
-		checkboxX := baseX + i*checkboxSize*3/2
-		checkboxY := baseY
-		if mx >= checkboxX && mx < checkboxX+checkboxSize && my >= checkboxY && my < checkboxY+checkboxSize && p8.Btnp(p8.ButtonMouseLeft) {
-			g.toggleFlagAtIndex(i)
+	i := flagCheckboxAt(baseX, baseY, mx, my)
+	if i == noFlag {
+		return
+	}
+	switch {
+	case p8.Btnp(p8.ButtonMouseLeft) && p8.ShiftHeld():
+		g.startFlagRename(i)
+	case p8.Btnp(p8.ButtonMouseLeft):
+		g.toggleFlagAtIndex(i)
+	case p8.Btnp(p8.ButtonMouseRight):
+		g.toggleFlagFilter(i)
+	}
+}
+
+// flagCheckboxAt returns the flag whose checkbox is under the mouse, for the
+// checkboxes drawCheckboxes draws at (x, y), or noFlag if none is
+func flagCheckboxAt(x, y, mx, my int) int {
+	const checkboxSize = 8 // Same size and spacing as in drawCheckboxes
+	for i := range numFlags {
+		checkboxX := x + i*checkboxSize*3/2
+		if mx >= checkboxX && mx < checkboxX+checkboxSize && my >= y && my < y+checkboxSize {
+			return i
 		}
 	}
+	return noFlag
 }
 
-func (g *myGame) toggleFlagAtIndex(i int) {
-	g.saveCurrentStateIfNeeded()
+// flagLabel returns what is shown under flag i's checkbox: the first two
+// characters of its name, or its number if it has none
+func flagLabel(i int) string {
+	name := []rune(flagNames[i])
+	if len(name) == 0 {
+		return strconv.Itoa(i)
+	}
+	return string(name[:min(len(name), 2)])
+}
 
-	base := g.currentSprite
-	r, c := base/spriteSheetCols, base%spriteSheetCols
-	cur := spriteFlags[r][c][i]
-	for dr := range g.safeGridSize() {
-		for dc := range g.safeGridSize() {
-			rr, cc := r+dr, c+dc
-			if rr < spriteSheetRows && cc < spriteSheetCols {
-				spriteFlags[rr][cc][i] = !cur
+// startFlagRename starts typing a new name for flag i
+func (g *myGame) startFlagRename(i int) {
+	g.renamingFlag = i
+	p8.StartTextInput(maxFlagName)
+}
+
+// handleFlagRename takes over Update while a flag name is typed: Enter keeps
+// the name (an empty one removes it) and Escape cancels
+func (g *myGame) handleFlagRename() {
+	switch {
+	case p8.Keyp("Enter"):
+		flagNames[g.renamingFlag] = strings.TrimSpace(p8.GetTextInput())
+		log.Printf("Flag %d named %q", g.renamingFlag, flagNames[g.renamingFlag])
+	case p8.Keyp("Escape"):
+	default:
+		return
+	}
+	p8.StopTextInput()
+	g.renamingFlag = noFlag
+}
+
+// setFlagNames replaces the flag names with the ones saved in a spritesheet
+func setFlagNames(names []string) {
+	flagNames = [numFlags]string{}
+	copy(flagNames[:], names)
+}
+
+// flagNamesForSheet returns the flag names to save in spritesheet.json,
+// without the unnamed flags at the end
+func flagNamesForSheet() []string {
+	names := flagNames[:]
+	for len(names) > 0 && names[len(names)-1] == "" {
+		names = names[:len(names)-1]
+	}
+	return append([]string(nil), names...)
+}
+
+// toggleFlagFilter highlights the sprites with flag i on the spritesheet, or
+// shows all sprites again if that flag was already highlighted
+func (g *myGame) toggleFlagFilter(i int) {
+	if g.flagFilter == i {
+		g.flagFilter = noFlagFilter
+		return
+	}
+	g.flagFilter = i
+}
+
+// spritesWithFlag returns the sprites with flag set, in spritesheet order.
+// Sprite 0 is left out, as it can't be selected.
+func spritesWithFlag(flag int) []int {
+	var sprites []int
+	for idx := 1; idx < spriteSheetRows*spriteSheetCols; idx++ {
+		if spriteFlags[idx/spriteSheetCols][idx%spriteSheetCols][flag] {
+			sprites = append(sprites, idx)
+		}
+	}
+	return sprites
+}
+
+// handleFlagFilterKeys selects the next (Tab) or previous (Shift+Tab) sprite
+// with the filtered flag, wrapping around the spritesheet
+func (g *myGame) handleFlagFilterKeys() {
+	if g.flagFilter == noFlagFilter || !p8.Keyp("Tab") {
+		return
+	}
+	sprites := spritesWithFlag(g.flagFilter)
+	if p8.ShiftHeld() {
+		slices.Reverse(sprites)
+	}
+
+	// Start after the current sprite, skipping the sprites whose selection
+	// would start on it again, e.g. near the edge with a larger gridSize
+	after := func(idx int) bool { return idx > g.currentSprite }
+	if p8.ShiftHeld() {
+		after = func(idx int) bool { return idx < g.currentSprite }
+	}
+	for _, wrapped := range []bool{false, true} {
+		for _, idx := range sprites {
+			if (wrapped || after(idx)) && g.clampedSprite(idx) != g.currentSprite {
+				g.currentSprite = g.clampedSprite(idx)
+				g.updateDrawingCanvas()
+				return
 			}
 		}
 	}
+}
+
+func (g *myGame) toggleFlagAtIndex(i int) {
+	g.saveCurrentStateIfNeeded()
+
+	// Same rule as the checkbox display: if every selected sprite has the
+	// flag, clear it; otherwise (none or mixed) set it on all of them
+	allSet := true
+	g.forEachSelectedSprite(func(row, col int) {
+		allSet = allSet && spriteFlags[row][col][i]
+	})
+	g.forEachSelectedSprite(func(row, col int) {
+		spriteFlags[row][col][i] = !allSet
+	})
 	if err := saveSpritesheet(); err != nil {
 		log.Printf("Error saving spritesheet after toggling flag: %v", err)
 	}
@@ -1351,7 +1984,32 @@ func (g *myGame) safeGridSize() int {
 	if g.gridSize < 1 {
 		return 1
 	}
-	return g.gridSize
+	return min(g.gridSize, maxGridSize)
+}
+
+// clampSelection moves the selection so the whole gridSize block stays on the spritesheet
+func (g *myGame) clampSelection() {
+	g.currentSprite = g.clampedSprite(g.currentSprite)
+}
+
+// clampedSprite returns the sprite the selection starts at when selecting idx,
+// moved so the gridSize block fits on the spritesheet
+func (g *myGame) clampedSprite(idx int) int {
+	size := g.safeGridSize()
+	row := max(0, min(idx/spriteSheetCols, spriteSheetRows-size))
+	col := max(0, min(idx%spriteSheetCols, spriteSheetCols-size))
+	return row*spriteSheetCols + col
+}
+
+// selectedSheetCell maps a cell of the drawing canvas to its sprite and pixel.
+// ok is false if the cell falls outside the spritesheet.
+func (g *myGame) selectedSheetCell(row, col int) (r, c, pr, pc int, ok bool) {
+	base := g.currentSprite
+	r = base/spriteSheetCols + row/8
+	c = base%spriteSheetCols + col/8
+	pr, pc = row%8, col%8
+	ok = r >= 0 && r < spriteSheetRows && c >= 0 && c < spriteSheetCols
+	return r, c, pr, pc, ok
 }
 
 func (g *myGame) handleDrawingGrid(mx, my int) {
@@ -1366,7 +2024,17 @@ func (g *myGame) handleDrawingGrid(mx, my int) {
 		return
 	}
 	g.updateHover(row, col)
-	if p8.Btn(p8.ButtonMouseLeft) {
+	if eyedropperActive() {
+		// Eyedropper: hold Alt and click to pick the pixel's color
+		if p8.Btnp(p8.ButtonMouseLeft) && g.hoverX >= 0 {
+			g.pickColor(getSheetPixel(g.hoverX, g.hoverY))
+		}
+	} else if ebiten.IsKeyPressed(ebiten.KeyF) {
+		// Paint bucket: hold F and click
+		if p8.Btnp(p8.ButtonMouseLeft) {
+			g.fillAt(row, col, g.currentColor)
+		}
+	} else if p8.Btn(p8.ButtonMouseLeft) {
 		g.drawAt(row, col, g.currentColor)
 	} else if p8.Btn(p8.ButtonMouseRight) {
 		g.drawAt(row, col, 0)
@@ -1374,18 +2042,19 @@ func (g *myGame) handleDrawingGrid(mx, my int) {
 }
 
 func (g *myGame) updateHover(row, col int) {
-	base := g.currentSprite
-	r := base/spriteSheetCols + row/8
-	c := base%spriteSheetCols + col/8
-	pr, pc := row%8, col%8
+	r, c, pr, pc, ok := g.selectedSheetCell(row, col)
+	if !ok {
+		g.hoverX, g.hoverY = -1, -1
+		return
+	}
 	g.hoverX, g.hoverY = c*8+pc, r*8+pr
 }
 
 func (g *myGame) drawAt(row, col, colorIndex int) {
-	base := g.currentSprite
-	r := base/spriteSheetCols + row/8
-	c := base%spriteSheetCols + col/8
-	pr, pc := row%8, col%8
+	r, c, pr, pc, ok := g.selectedSheetCell(row, col)
+	if !ok {
+		return
+	}
 
 	if spritesheet[r][c][pr][pc] != colorIndex {
 		setSquareColor(row, col, colorIndex)
@@ -1398,13 +2067,66 @@ func (g *myGame) drawAt(row, col, colorIndex int) {
 	}
 }
 
+// getSheetPixel returns the color at spritesheet pixel (px, py)
+func getSheetPixel(px, py int) int {
+	return spritesheet[py/8][px/8][py%8][px%8]
+}
+
+// setSheetPixel sets spritesheet pixel (px, py) in the editor and in PIGO8
+func setSheetPixel(px, py, colorIndex int) {
+	spritesheet[py/8][px/8][py%8][px%8] = colorIndex
+	p8.Sset(px, py, colorIndex)
+}
+
+// fillAt flood-fills the region of the spritesheet under the given canvas cell
+func (g *myGame) fillAt(row, col, colorIndex int) {
+	r, c, pr, pc, ok := g.selectedSheetCell(row, col)
+	if !ok {
+		return
+	}
+	x, y := c*8+pc, r*8+pr
+
+	if p8.FloodFill(spriteSheetCols*8, spriteSheetRows*8, x, y, colorIndex, getSheetPixel, setSheetPixel) == 0 {
+		return
+	}
+	g.saveCurrentStateIfNeeded()
+	updateMapSprites(-1)
+	g.updateDrawingCanvas()
+}
+
+// eyedropperActive reports whether clicks pick colors instead of drawing or selecting
+func eyedropperActive() bool {
+	return ebiten.IsKeyPressed(ebiten.KeyAlt)
+}
+
+// pickColor makes colorIndex the current color; it only reads pixels, never changes them
+func (g *myGame) pickColor(colorIndex int) {
+	if colorIndex < 0 || colorIndex >= p8.GetPaletteSize() {
+		return
+	}
+	g.currentColor = colorIndex
+}
+
 func (g *myGame) handleSpriteSelection(mx, my int) {
+	if mx < spritesheetStartX || my < 10 {
+		return
+	}
 	row := (my - 10) / spriteCellSize
 	col := (mx - spritesheetStartX) / spriteCellSize
+	if row >= 0 && row < spriteSheetRows && col >= 0 && col < spriteSheetCols && eyedropperActive() {
+		// Eyedropper on the spritesheet panel: pick the pixel under the mouse
+		if p8.Btnp(p8.ButtonMouseLeft) {
+			px := col*spriteSize + (mx-spritesheetStartX)%spriteCellSize*spriteSize/spriteCellSize
+			py := row*spriteSize + (my-10)%spriteCellSize*spriteSize/spriteCellSize
+			g.pickColor(getSheetPixel(px, py))
+		}
+		return
+	}
 	if row >= 0 && row < spriteSheetRows && col >= 0 && col < spriteSheetCols && p8.Btnp(p8.ButtonMouseLeft) {
 		idx := row*spriteSheetCols + col
 		if idx > 0 {
 			g.currentSprite = idx
+			g.clampSelection()
 		}
 		g.updateDrawingCanvas()
 	}
@@ -1425,9 +2147,10 @@ func (g *myGame) handleWheel() {
 	if now-g.lastWheelTime <= 150 { // 150ms debounce for wheel and keyboard
 		return
 	}
-	if p8.Btnp(p8.ButtonMouseWheelUp) && g.gridSize < 4 {
-		g.gridSize = min(g.gridSize*2, 8)
+	if p8.Btnp(p8.ButtonMouseWheelUp) && g.gridSize < maxGridSize {
+		g.gridSize = min(g.gridSize*2, maxGridSize)
 		g.lastWheelTime = now
+		g.clampSelection()
 		g.updateDrawingCanvas()
 	} else if p8.Btnp(p8.ButtonMouseWheelDown) && g.gridSize > 1 {
 		g.gridSize = max(1, g.gridSize/2)
@@ -1437,42 +2160,130 @@ func (g *myGame) handleWheel() {
 }
 
 // handleKeyboardNavigation handles keyboard arrow key navigation between sprites
-// copySprite copies the current sprite data to the clipboard
+// copySprite copies the selected gridSize block of sprites to the clipboard,
+// cut short where it runs past the edge of the spritesheet
 func (g *myGame) copySprite() {
-	r, c := g.currentSprite/spriteSheetCols, g.currentSprite%spriteSheetCols
-	for y := 0; y < 8; y++ {
-		for x := 0; x < 8; x++ {
-			g.copiedSprite[y][x] = spritesheet[r][c][y][x]
-		}
+	baseRow := g.currentSprite / spriteSheetCols
+	baseCol := g.currentSprite % spriteSheetCols
+	rows := min(g.safeGridSize(), spriteSheetRows-baseRow)
+	cols := min(g.safeGridSize(), spriteSheetCols-baseCol)
+
+	g.copiedSprites = make([][][8][8]int, rows)
+	for r := range g.copiedSprites {
+		g.copiedSprites[r] = make([][8][8]int, cols)
 	}
+	g.forEachSelectedSprite(func(row, col int) {
+		g.copiedSprites[row-baseRow][col-baseCol] = spritesheet[row][col]
+	})
 }
 
-// pasteSprite pastes the copied sprite data to the current sprite
+// pasteSprite pastes the copied block with its top-left sprite on the current
+// sprite, as a single undo step. Sprites that would land past the edge of the
+// spritesheet are left out.
 func (g *myGame) pasteSprite() {
-	r, c := g.currentSprite/spriteSheetCols, g.currentSprite%spriteSheetCols
-	for y := 0; y < 8; y++ {
-		for x := 0; x < 8; x++ {
-			spritesheet[r][c][y][x] = g.copiedSprite[y][x]
+	if len(g.copiedSprites) == 0 {
+		return
+	}
+	baseRow := g.currentSprite / spriteSheetCols
+	baseCol := g.currentSprite % spriteSheetCols
+
+	clipped := false
+	for r, sprites := range g.copiedSprites {
+		for c, pixels := range sprites {
+			row, col := baseRow+r, baseCol+c
+			if row >= spriteSheetRows || col >= spriteSheetCols {
+				clipped = true
+				continue
+			}
+			for y := range 8 {
+				for x := range 8 {
+					setSheetPixel(col*8+x, row*8+y, pixels[y][x])
+				}
+			}
 		}
 	}
-	updateMapSprites(g.currentSprite)
+	if clipped {
+		log.Printf("Pasted part of the %dx%d block: the rest does not fit on the spritesheet at sprite %d", len(g.copiedSprites[0]), len(g.copiedSprites), g.currentSprite)
+	}
+
+	g.saveCurrentStateIfNeeded()
+	updateMapSprites(-1)
 	g.updateDrawingCanvas()
 }
 
 // handleCopyPaste handles keyboard shortcuts for copy and paste
 func (g *myGame) handleCopyPaste() {
 	// Check for CMD+C (Copy)
-	if inpututil.IsKeyJustPressed(ebiten.KeyC) && (ebiten.IsKeyPressed(ebiten.KeyMeta) || ebiten.IsKeyPressed(ebiten.KeyControl)) {
+	if p8.Keyp("C") && (p8.MetaHeld() || p8.CtrlHeld()) {
 		g.copySprite()
 	}
 
 	// Check for CMD+V (Paste)
-	if inpututil.IsKeyJustPressed(ebiten.KeyV) && (ebiten.IsKeyPressed(ebiten.KeyMeta) || ebiten.IsKeyPressed(ebiten.KeyControl)) {
-		g.saveCurrentStateIfNeeded()
+	if p8.Keyp("V") && (p8.MetaHeld() || p8.CtrlHeld()) {
 		g.pasteSprite()
 	}
 }
 
+// handleTransforms flips (H, V) or rotates (R) the selected sprite block
+func (g *myGame) handleTransforms() {
+	if p8.MetaHeld() || p8.CtrlHeld() {
+		return // Leave Cmd/Ctrl combinations (e.g. Cmd+V) to the other shortcuts
+	}
+
+	switch {
+	case p8.Keyp("H"):
+		g.transformSelection(func(size int, get func(x, y int) int, set func(x, y, color int)) {
+			p8.FlipPixelsH(size, size, get, set)
+		})
+	case p8.Keyp("V"):
+		g.transformSelection(func(size int, get func(x, y int) int, set func(x, y, color int)) {
+			p8.FlipPixelsV(size, size, get, set)
+		})
+	case p8.Keyp("R"):
+		g.transformSelection(p8.RotatePixels90)
+	}
+}
+
+// transformSelection applies a pixel transform to the whole gridSize block,
+// treating it as one image so the sprites inside it move as well
+func (g *myGame) transformSelection(transform func(size int, get func(x, y int) int, set func(x, y, color int))) {
+	size := g.safeGridSize()
+	baseRow := g.currentSprite / spriteSheetCols
+	baseCol := g.currentSprite % spriteSheetCols
+	if baseRow+size > spriteSheetRows || baseCol+size > spriteSheetCols {
+		log.Printf("Cannot transform: a %dx%d block at sprite %d does not fit on the spritesheet", size, size, g.currentSprite)
+		return
+	}
+
+	ox, oy := baseCol*8, baseRow*8
+	transform(size*8,
+		func(x, y int) int { return getSheetPixel(ox+x, oy+y) },
+		func(x, y, c int) { setSheetPixel(ox+x, oy+y, c) },
+	)
+
+	g.saveCurrentStateIfNeeded()
+	updateMapSprites(-1)
+	g.updateDrawingCanvas()
+}
+
+// handleAnimationKeys starts a new animation (Shift+A) or adds the current
+// sprite to the last one (A). Animations are saved with the spritesheet.
+func (g *myGame) handleAnimationKeys() {
+	if !p8.Keyp("A") || p8.MetaHeld() || p8.CtrlHeld() {
+		return
+	}
+	if p8.ShiftHeld() || len(spriteAnimations) == 0 {
+		spriteAnimations = append(spriteAnimations, p8.SpriteAnimation{
+			Name: fmt.Sprintf("anim%d", len(spriteAnimations)),
+		})
+		if p8.ShiftHeld() {
+			return
+		}
+	}
+	last := &spriteAnimations[len(spriteAnimations)-1]
+	last.Frames = append(last.Frames, g.currentSprite)
+}
+
 func (g *myGame) handleKeyboardNavigation() {
 	now := time.Now().UnixNano() / int64(time.Millisecond)
 	if now-g.lastWheelTime <= 150 { // 150ms debounce for keyboard navigation
@@ -1487,13 +2298,13 @@ func (g *myGame) handleKeyboardNavigation() {
 	case p8.Btnp(p8.LEFT) && currentCol > 0:
 		g.currentSprite--
 		moved = true
-	case p8.Btnp(p8.RIGHT) && currentCol < spriteSheetCols-1:
+	case p8.Btnp(p8.RIGHT) && currentCol < spriteSheetCols-g.safeGridSize():
 		g.currentSprite++
 		moved = true
 	case p8.Btnp(p8.UP) && currentRow > 0:
 		g.currentSprite -= spriteSheetCols
 		moved = true
-	case p8.Btnp(p8.DOWN) && currentRow < spriteSheetRows-1:
+	case p8.Btnp(p8.DOWN) && currentRow < spriteSheetRows-g.safeGridSize():
 		g.currentSprite += spriteSheetCols
 		moved = true
 	}
@@ -1506,6 +2317,153 @@ func (g *myGame) handleKeyboardNavigation() {
 
 // getUIElementColor returns the appropriate color for UI elements based on the active palette.
 // It returns 7 (white) if the default PICO-8 palette is active, otherwise defaultColor (1).
+// -------------------- Auto-save --------------------
+
+// recoveryData is the unsaved work written to recoveryFile
+type recoveryData struct {
+	Version     int             `json:"version"`
+	SavedAt     time.Time       `json:"savedAt"`
+	Spritesheet spriteSheetData `json:"spritesheet"`
+	Map         mapData         `json:"map"`
+}
+
+// mustMarshal encodes data to compact JSON, to compare saved contents
+func mustMarshal(data any) []byte {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Error encoding editor data: %v", err)
+	}
+	return encoded
+}
+
+// autoSaveIfDue runs autoSave every autoSaveInterval
+func (g *myGame) autoSaveIfDue() {
+	if autoSaveInterval <= 0 || time.Since(g.lastAutoSave) < autoSaveInterval {
+		return
+	}
+	g.lastAutoSave = time.Now()
+	g.autoSave()
+}
+
+// autoSave writes the spritesheet and map to recoveryFile if either differs
+// from what was last saved, and removes the file once everything is saved.
+// It only reads the editor state, so it never adds undo snapshots.
+func (g *myGame) autoSave() {
+	sheet := convertSpritesheetToData()
+	mapData := g.convertMapToData()
+	sheetJSON, mapJSON := mustMarshal(sheet), mustMarshal(mapData)
+	if string(sheetJSON) == string(savedSpritesheet) && string(mapJSON) == string(g.savedMap) {
+		if g.lastRecovery != nil {
+			removeRecoveryFile()
+			g.lastRecovery = nil
+		}
+		return
+	}
+	contents := append(sheetJSON, mapJSON...)
+	if string(contents) == string(g.lastRecovery) {
+		return // Nothing changed since the last auto-save
+	}
+
+	recovery := recoveryData{Version: recoveryVersion, SavedAt: time.Now(), Spritesheet: sheet, Map: mapData}
+	// Write next to the file and rename, so a crash never leaves half a file
+	if err := saveJSONToFile(recoveryFile+".tmp", recovery); err != nil {
+		log.Printf("Error auto-saving: %v", err)
+		return
+	}
+	if err := os.Rename(recoveryFile+".tmp", recoveryFile); err != nil {
+		log.Printf("Error auto-saving: %v", err)
+		return
+	}
+	g.lastRecovery = contents
+	log.Printf("Auto-saved unsaved work to %s", recoveryFile)
+}
+
+// removeRecoveryFile deletes recoveryFile, if there is one
+func removeRecoveryFile() {
+	if err := os.Remove(recoveryFile); err != nil && !os.IsNotExist(err) {
+		log.Printf("Error removing %s: %v", recoveryFile, err)
+	}
+}
+
+// checkRecovery looks for a recovery file left by a session that didn't save
+// its work, and asks whether to restore it. A file that matches what's on
+// disk is removed without asking.
+func (g *myGame) checkRecovery() {
+	var recovery recoveryData
+	if err := loadJSONFromFile(recoveryFile, &recovery); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Ignoring the recovery file: %v", err)
+		}
+		return
+	}
+	if recovery.Version != recoveryVersion {
+		log.Printf("Ignoring %s with version %d, expected %d", recoveryFile, recovery.Version, recoveryVersion)
+		return
+	}
+	if string(mustMarshal(recovery.Spritesheet)) == string(savedSpritesheet) && string(mustMarshal(recovery.Map)) == string(g.savedMap) {
+		removeRecoveryFile()
+		return
+	}
+	g.recoveryFound = &recovery
+}
+
+// handleRecoveryPrompt waits for Y to restore the recovered work or N to
+// discard it
+func (g *myGame) handleRecoveryPrompt() {
+	switch {
+	case p8.Keyp("Y"):
+		g.restoreRecovery(*g.recoveryFound)
+		g.recoveryFound = nil
+	case p8.Keyp("N"):
+		removeRecoveryFile()
+		g.recoveryFound = nil
+		log.Printf("Discarded the unsaved work in %s", recoveryFile)
+	}
+}
+
+// restoreRecovery loads the recovered spritesheet and map as a single undo
+// step, so undo goes back to the saved files. They are written to disk as
+// usual, e.g. when switching modes.
+func (g *myGame) restoreRecovery(recovery recoveryData) {
+	for _, sprite := range recovery.Spritesheet.Sprites {
+		applySpriteData(sprite)
+	}
+	spriteAnimations = recovery.Spritesheet.Animations
+	setFlagNames(recovery.Spritesheet.FlagNames)
+	g.applyMapData(recovery.Map)
+	g.updateDrawingCanvas()
+	updateMapSprites(-1)
+	if err := g.saveState(); err != nil {
+		log.Printf("Error saving state after restoring: %v", err)
+	}
+	g.lastRecovery = append(mustMarshal(recovery.Spritesheet), mustMarshal(recovery.Map)...)
+	log.Printf("Restored unsaved work from %s", recoveryFile)
+}
+
+// drawRecoveryPrompt asks whether to restore the recovered work
+func (g *myGame) drawRecoveryPrompt() {
+	if g.recoveryFound == nil {
+		return
+	}
+	lines := []string{
+		"unsaved work found from",
+		g.recoveryFound.SavedAt.Local().Format("2006-01-02 15:04"),
+		"y: restore   n: discard",
+	}
+	boxW := 0
+	for _, line := range lines {
+		boxW = max(boxW, len(line)*4+8)
+	}
+	boxH := len(lines)*8 + 8
+	x := (p8.GetScreenWidth() - boxW) / 2
+	y := (p8.GetScreenHeight() - boxH) / 2
+	p8.Rectfill(x, y, x+boxW-1, y+boxH-1, 0)
+	p8.Rect(x, y, x+boxW-1, y+boxH-1, g.getUIElementColor())
+	for i, line := range lines {
+		p8.Print(line, x+4, y+5+i*8, g.getUIElementColor())
+	}
+}
+
 func (g *myGame) getUIElementColor() int {
 	if p8.IsDefaultPico8PaletteActive() {
 		return 7 // PICO-8 white
@@ -1528,6 +2486,9 @@ func main() {
 	// Default values for flags are the initialDefaultMapViewWidth/Height
 	widthFlag := flag.Int("w", initialDefaultMapViewWidthPx, "map viewport width in pixels")
 	heightFlag := flag.Int("h", initialDefaultMapViewHeightPx, "map viewport height in pixels")
+	flag.IntVar(&initialMapSnap, "snap", initialMapSnap, "tiles the map placement cursor snaps to (1 = every tile)")
+	flag.StringVar(&mapGoPackage, "gopkg", mapGoPackage, "package of the Go source the E key exports the map to")
+	flag.DurationVar(&autoSaveInterval, "autosave", defaultAutoSaveInterval, "how often unsaved work is written to "+recoveryFile+" (0 disables)")
 	flag.Parse()
 
 	// Update global map viewport dimensions (pixels) from flags
@@ -1559,6 +2520,7 @@ func main() {
 	settings.ScreenWidth = width * unit
 	settings.ScreenHeight = height * unit
 	settings.ScaleFactor = 3
+	settings.Resizable = true
 	p8.InsertGame(&myGame{})
 	p8.PlayGameWith(settings)
 }
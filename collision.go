@@ -2,6 +2,7 @@ package pigo8
 
 import (
 	"log"
+	"math"
 	"sync"
 )
 
@@ -54,6 +55,56 @@ func ColorCollision[X Number, Y Number](x X, y Y, color int) bool {
 	return pixelColor == color
 }
 
+// ColorCollisionAny reports whether the pixel at (x, y) is any of colors, e.g.
+// every "solid" color of a level, in a single screen read. Like
+// ColorCollision, it reads what was drawn so far this frame, so x and y are
+// screen coordinates: pass a world position through WorldToScreen first when
+// the camera is set. Positions outside the screen never collide.
+//
+// Example:
+//
+//	sx, sy := WorldToScreen(p.x, p.y+8)
+//	if ColorCollisionAny(sx, sy, 3, 4, 5) { // Grass, dirt and stone
+//		p.onGround = true
+//	}
+func ColorCollisionAny[X Number, Y Number](x X, y Y, colors ...int) bool {
+	hit, ok := ColorCollisionInfo(x, y)
+	if !ok {
+		return false
+	}
+	for _, c := range colors {
+		if c == hit {
+			return true
+		}
+	}
+	return false
+}
+
+// ColorCollisionInfo returns the color index of the pixel at (x, y), read with
+// Pget, and false if the position is outside the screen, so a game can react
+// to the material it hit (lava, water, wall) from the drawn pixels alone. x
+// and y are screen coordinates, as for ColorCollision.
+//
+// Example:
+//
+//	sx, sy := WorldToScreen(p.x, p.y)
+//	if c, ok := ColorCollisionInfo(sx, sy); ok {
+//		switch c {
+//		case 8:
+//			p.die() // Lava
+//		case 12:
+//			p.swim()
+//		}
+//	}
+func ColorCollisionInfo[X Number, Y Number](x X, y Y) (int, bool) {
+	xInt := int(math.Floor(float64(x)))
+	yInt := int(math.Floor(float64(y)))
+	if xInt < 0 || xInt >= GetScreenWidth() || yInt < 0 || yInt >= GetScreenHeight() {
+		return 0, false
+	}
+	return Pget(xInt, yInt), true
+}
+
 // Add flag caching for collision detection
 var (
 	flagCache      = make(map[int]map[int]bool) // spriteID -> flag -> isSet
@@ -95,12 +146,18 @@ func ClearFlagCache() {
 // MapCollision checks if a rectangular area, starting at pixel coordinates (x, y) and with a given width and height,
 // overlaps with any map tiles that have the specified flag set.
 //
+// The coordinates are world coordinates: the position the area would be drawn
+// at with Spr, before the camera offset. Screen coordinates, such as the
+// mouse position or a position already offset by the camera, must be
+// converted with ScreenToWorld first, or passed to ScreenMapCollision.
+// WorldMapCollision is the same function under a name that says so.
+//
 // Parameters:
-//   - x: The x-coordinate of the top-left corner of the area to check (pixel units).
-//   - y: The y-coordinate of the top-left corner of the area to check (pixel units).
+//   - x: The world x-coordinate of the top-left corner of the area to check (pixel units).
+//   - y: The world y-coordinate of the top-left corner of the area to check (pixel units).
 //   - flag: The sprite flag number (0-7) to check for on underlying map tiles.
 //   - size: (optional) Variadic integers defining the collision area's dimensions in pixels:
-//   - No argument: defaults to an 8x8 pixel area.
+//   - No argument: defaults to a one-tile area, 8x8 pixels unless changed with SetTileSize.
 //   - One argument `s`: defines an `s`x`s` pixel square area.
 //   - Two arguments `w, h`: defines a `w`x`h` pixel rectangular area.
 //     (Additional arguments are ignored).
@@ -110,7 +167,7 @@ func ClearFlagCache() {
 //
 // Behavior:
 // The function first determines the width and height of the collision area based on the `size` parameters.
-// It then calculates the range of map tiles (which are 8x8 pixels) that this rectangular area overlaps.
+// It then calculates the range of map tiles (GetTileSize pixels square, 8x8 by default) that this rectangular area overlaps.
 // For each map tile within this range, it retrieves the sprite ID using Mget() and then checks
 // if the specified `flag` is set on that sprite using Fget(). If a tile with the
 // target flag is found within the area, the function immediately returns true.
@@ -118,7 +175,7 @@ func ClearFlagCache() {
 //
 // Example:
 //
-//	// Check if the 8x8 area at (player.x, player.y) collides with a tile having Flag0
+//	// Check if the one-tile (8x8) area at (player.x, player.y) collides with a tile having Flag0
 //	if MapCollision(player.x, player.y, Flag0) {
 //	    // Collision detected
 //	}
@@ -135,8 +192,8 @@ func ClearFlagCache() {
 //	    // Collision detected
 //	}
 func MapCollision[X Number, Y Number](x X, y Y, flag int, size ...int) bool {
-	objectWidth := 8  // Default width in pixels
-	objectHeight := 8 // Default height in pixels
+	objectWidth := tileSize  // Default width in pixels
+	objectHeight := tileSize // Default height in pixels
 
 	if len(size) > 0 {
 		if size[0] > 0 {
@@ -151,12 +208,16 @@ func MapCollision[X Number, Y Number](x X, y Y, flag int, size ...int) bool {
 
 	fx := float64(x)
 	fy := float64(y)
+	if collisionDebug {
+		warnFarOutsideMap("MapCollision", fx, fy, objectWidth, objectHeight)
+	}
 
 	// Determine the range of map tiles the object overlaps
-	tileXStart := Flr(fx / 8.0)
-	tileYStart := Flr(fy / 8.0)
-	tileXEnd := Flr((fx + float64(objectWidth) - 1) / 8.0)
-	tileYEnd := Flr((fy + float64(objectHeight) - 1) / 8.0)
+	ts := float64(tileSize)
+	tileXStart := Flr(fx / ts)
+	tileYStart := Flr(fy / ts)
+	tileXEnd := Flr((fx + float64(objectWidth) - 1) / ts)
+	tileYEnd := Flr((fy + float64(objectHeight) - 1) / ts)
 
 	// Check each tile in the overlapping range
 	for ty := tileYStart; ty <= tileYEnd; ty++ {
@@ -178,3 +239,74 @@ func MapCollision[X Number, Y Number](x X, y Y, flag int, size ...int) bool {
 
 	return false // No collision found
 }
+
+// WorldMapCollision is MapCollision under a name that makes clear x and y are
+// world coordinates, the ones Spr and Map draw at before the camera offset.
+func WorldMapCollision[X Number, Y Number](x X, y Y, flag int, size ...int) bool {
+	return MapCollision(x, y, flag, size...)
+}
+
+// ScreenMapCollision is MapCollision for an area given in screen coordinates,
+// e.g. the mouse position or a position already offset by the camera. It
+// converts them with ScreenToWorld using the current camera, so call it with
+// the camera the screen position was seen with.
+//
+// Example:
+//
+//	Camera(camX, camY)
+//	mouseX, mouseY := GetMouseXY()
+//	if ScreenMapCollision(mouseX, mouseY, FlagSolid, 1) {
+//		Print("wall", 0, 0, 8)
+//	}
+func ScreenMapCollision[X Number, Y Number](x X, y Y, flag int, size ...int) bool {
+	wx, wy := ScreenToWorld(float64(x), float64(y))
+	return MapCollision(wx, wy, flag, size...)
+}
+
+// --- Collision debug checks ---
+
+// collisionDebug warns about collision areas far outside the map, see
+// SetCollisionDebug.
+var collisionDebug bool
+
+// SetCollisionDebug turns on a check in MapCollision, WorldMapCollision and
+// ScreenMapCollision that logs a warning when the area tested is more than a
+// screen away from the map. Such positions are almost always screen
+// coordinates, or coordinates offset by the camera twice, passed where world
+// coordinates are expected. Each position is reported once. It can also be
+// set with Settings.CollisionDebug; leave it off in releases.
+//
+// Example:
+//
+//	settings := NewSettings()
+//	settings.CollisionDebug = true
+//	PlayGameWith(settings)
+func SetCollisionDebug(on bool) {
+	collisionDebug = on
+}
+
+// CollisionDebug reports whether the collision debug check is on, see
+// SetCollisionDebug.
+func CollisionDebug() bool {
+	return collisionDebug
+}
+
+// collisionOutsideMap reports whether the w x h area at world position (x, y)
+// is more than a screen away from the map on either axis.
+func collisionOutsideMap(x, y float64, w, h int) bool {
+	mapWidth, mapHeight := GetMapSize()
+	mapRight := float64(mapWidth * tileSize)
+	mapBottom := float64(mapHeight * tileSize)
+	marginX := float64(GetScreenWidth())
+	marginY := float64(GetScreenHeight())
+	return x+float64(w) < -marginX || x > mapRight+marginX ||
+		y+float64(h) < -marginY || y > mapBottom+marginY
+}
+
+// warnFarOutsideMap logs a warning if the area is far outside the map.
+func warnFarOutsideMap(caller string, x, y float64, w, h int) {
+	if collisionOutsideMap(x, y, w, h) {
+		logWarningOnce("Warning: %s(%v, %v) checks an area more than a screen outside the map. "+
+			"Collision functions take world coordinates; convert screen coordinates with ScreenToWorld.", caller, x, y)
+	}
+}
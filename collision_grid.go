@@ -0,0 +1,139 @@
+package pigo8
+
+import (
+	"log"
+	"math"
+	"slices"
+)
+
+// Box is an axis-aligned rectangle in pixels, with its top-left corner at (X, Y).
+type Box struct {
+	X, Y, W, H float64
+}
+
+// Overlaps reports whether two rectangles overlap. Rectangles that only
+// touch at an edge do not overlap.
+//
+// Example:
+//
+//	bullet := Box{X: b.x, Y: b.y, W: 1, H: 4}
+//	if bullet.Overlaps(Box{X: a.x, Y: a.y, W: 8, H: 8}) {
+//	    // hit
+//	}
+func (r Box) Overlaps(other Box) bool {
+	return r.X < other.X+other.W && other.X < r.X+r.W &&
+		r.Y < other.Y+other.H && other.Y < r.Y+r.H
+}
+
+// CollisionGrid is a uniform spatial hash for broad-phase collision checks
+// between many entities. The world is split into square cells; each entity is
+// stored in the cells its rectangle covers, so a query only looks at
+// entities near the queried area instead of all of them.
+//
+// The usual pattern is to Clear the grid and Insert every entity once per
+// frame, then Query with each moving object. Entities are identified by an
+// int of your choosing, e.g. their index in a slice.
+//
+// Example:
+//
+//	grid := NewCollisionGrid(16)
+//
+//	// In Update:
+//	grid.Clear()
+//	for i, a := range aliens {
+//	    grid.Insert(i, Box{X: a.x, Y: a.y, W: 8, H: 8})
+//	}
+//	for _, b := range bullets {
+//	    for _, i := range grid.Query(Box{X: b.x, Y: b.y, W: 1, H: 4}) {
+//	        aliens[i].alive = false
+//	    }
+//	}
+type CollisionGrid struct {
+	cellSize float64
+	cells    map[[2]int][]int // Cell -> indices into entries
+	entries  []gridEntry
+	index    map[int]int // Entity id -> index into entries
+	queryID  int         // Stamp of the running Query, to report each entity once
+}
+
+// gridEntry is an entity stored in a CollisionGrid.
+type gridEntry struct {
+	id      int
+	rect    Box
+	queryID int
+}
+
+// NewCollisionGrid creates an empty grid with square cells of cellSize pixels.
+// A good cell size is about the size of a typical entity; if cellSize is not
+// positive, 8 (one tile) is used.
+func NewCollisionGrid(cellSize float64) *CollisionGrid {
+	if cellSize <= 0 {
+		log.Printf("Warning: NewCollisionGrid() called with cell size %v. Using 8.", cellSize)
+		cellSize = 8
+	}
+	return &CollisionGrid{
+		cellSize: cellSize,
+		cells:    make(map[[2]int][]int),
+		index:    make(map[int]int),
+	}
+}
+
+// Insert adds the entity id with the rectangle r. Inserting an id again
+// replaces its rectangle.
+func (g *CollisionGrid) Insert(id int, r Box) {
+	i, exists := g.index[id]
+	if exists {
+		g.entries[i].rect = r
+	} else {
+		i = len(g.entries)
+		g.entries = append(g.entries, gridEntry{id: id, rect: r, queryID: g.queryID})
+		g.index[id] = i
+	}
+	g.forEachCell(r, func(cell [2]int) {
+		g.cells[cell] = append(g.cells[cell], i)
+	})
+}
+
+// Query returns the ids of all entities whose rectangle overlaps r (see
+// Box.Overlaps), sorted in ascending order. It returns nil if there are none.
+func (g *CollisionGrid) Query(r Box) []int {
+	g.queryID++
+	var ids []int
+	g.forEachCell(r, func(cell [2]int) {
+		for _, i := range g.cells[cell] {
+			e := &g.entries[i]
+			if e.queryID == g.queryID {
+				continue
+			}
+			e.queryID = g.queryID
+			if e.rect.Overlaps(r) {
+				ids = append(ids, e.id)
+			}
+		}
+	})
+	slices.Sort(ids)
+	return ids
+}
+
+// Clear removes all entities. The grid keeps its memory, so clearing and
+// refilling it every frame does not allocate once it has warmed up.
+func (g *CollisionGrid) Clear() {
+	for cell, entries := range g.cells {
+		g.cells[cell] = entries[:0]
+	}
+	g.entries = g.entries[:0]
+	clear(g.index)
+}
+
+// forEachCell calls fn for every cell that r covers.
+func (g *CollisionGrid) forEachCell(r Box, fn func(cell [2]int)) {
+	x0 := int(math.Floor(r.X / g.cellSize))
+	y0 := int(math.Floor(r.Y / g.cellSize))
+	x1 := int(math.Floor((r.X + math.Max(r.W, 0)) / g.cellSize))
+	y1 := int(math.Floor((r.Y + math.Max(r.H, 0)) / g.cellSize))
+	for cy := y0; cy <= y1; cy++ {
+		for cx := x0; cx <= x1; cx++ {
+			fn([2]int{cx, cy})
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package pigo8
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoxOverlaps(t *testing.T) {
+	a := Box{X: 0, Y: 0, W: 8, H: 8}
+	assert.True(t, a.Overlaps(Box{X: 4, Y: 4, W: 8, H: 8}), "partially covered rects should overlap")
+	assert.True(t, a.Overlaps(Box{X: 2, Y: 2, W: 1, H: 1}), "a rect inside another should overlap")
+	assert.False(t, a.Overlaps(Box{X: 8, Y: 0, W: 8, H: 8}), "rects touching at an edge should not overlap")
+	assert.False(t, a.Overlaps(Box{X: -10, Y: 20, W: 5, H: 5}), "distant rects should not overlap")
+}
+
+func TestCollisionGrid(t *testing.T) {
+	grid := NewCollisionGrid(16)
+	grid.Insert(1, Box{X: 0, Y: 0, W: 8, H: 8})
+	grid.Insert(2, Box{X: 30, Y: 30, W: 40, H: 8}) // spans several cells
+	grid.Insert(3, Box{X: -20, Y: -20, W: 8, H: 8})
+
+	assert.Equal(t, []int{1}, grid.Query(Box{X: 4, Y: 4, W: 2, H: 2}))
+	assert.Equal(t, []int{2}, grid.Query(Box{X: 60, Y: 32, W: 1, H: 1}), "entity spanning cells should be found once")
+	assert.Equal(t, []int{3}, grid.Query(Box{X: -16, Y: -16, W: 1, H: 1}), "negative coordinates should work")
+	assert.Equal(t, []int{1, 2}, grid.Query(Box{X: 0, Y: 0, W: 64, H: 64}))
+	assert.Nil(t, grid.Query(Box{X: 10, Y: 0, W: 4, H: 4}), "same cell but no overlap should not match")
+
+	// Inserting again moves the entity
+	grid.Insert(1, Box{X: 100, Y: 100, W: 8, H: 8})
+	assert.Nil(t, grid.Query(Box{X: 4, Y: 4, W: 2, H: 2}))
+	assert.Equal(t, []int{1}, grid.Query(Box{X: 101, Y: 101, W: 1, H: 1}))
+
+	grid.Clear()
+	assert.Nil(t, grid.Query(Box{X: -100, Y: -100, W: 300, H: 300}), "cleared grid should be empty")
+}
+
+func TestCollisionGridMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	rects := randomRects(rng, 300, 256)
+	grid := NewCollisionGrid(8)
+	for i, r := range rects {
+		grid.Insert(i, r)
+	}
+
+	for q := 0; q < 100; q++ {
+		query := Box{X: rng.Float64() * 256, Y: rng.Float64() * 256, W: rng.Float64() * 32, H: rng.Float64() * 32}
+		var want []int
+		for i, r := range rects {
+			if r.Overlaps(query) {
+				want = append(want, i)
+			}
+		}
+		assert.Equal(t, want, grid.Query(query), "query %v", query)
+	}
+}
+
+func TestNewCollisionGridInvalidCellSize(t *testing.T) {
+	grid := NewCollisionGrid(0)
+	assert.Equal(t, 8.0, grid.cellSize, "invalid cell size should fall back to a tile")
+}
+
+// randomRects returns n small rectangles scattered over a size x size world.
+func randomRects(rng *rand.Rand, n int, size float64) []Box {
+	rects := make([]Box, n)
+	for i := range rects {
+		rects[i] = Box{X: rng.Float64() * size, Y: rng.Float64() * size, W: 2 + rng.Float64()*6, H: 2 + rng.Float64()*6}
+	}
+	return rects
+}
+
+// benchmarkEntities is a bullet-hell sized scene: 500 bullets against 500 enemies.
+const benchmarkEntities = 500
+
+// benchmarkHits keeps the compiler from optimizing the collision checks away.
+var benchmarkHits int
+
+func BenchmarkCollisionNaive(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	enemies := randomRects(rng, benchmarkEntities, 512)
+	bullets := randomRects(rng, benchmarkEntities, 512)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		hits := 0
+		for _, bullet := range bullets {
+			for _, enemy := range enemies {
+				if bullet.Overlaps(enemy) {
+					hits++
+				}
+			}
+		}
+		benchmarkHits = hits
+	}
+}
+
+func BenchmarkCollisionGrid(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	enemies := randomRects(rng, benchmarkEntities, 512)
+	bullets := randomRects(rng, benchmarkEntities, 512)
+	grid := NewCollisionGrid(16)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		// Rebuild every frame, as a game with moving entities would
+		grid.Clear()
+		for i, enemy := range enemies {
+			grid.Insert(i, enemy)
+		}
+		hits := 0
+		for _, bullet := range bullets {
+			hits += len(grid.Query(bullet))
+		}
+		benchmarkHits = hits
+	}
+}
@@ -0,0 +1,61 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScreenMapCollision(t *testing.T) {
+	useTestLayerSprites(t)
+	t.Cleanup(func() { Camera() })
+	Mset(20, 2, 4) // Sprite 4 has flag 0, at pixels 160-167, 16-23
+
+	Camera(150, 10)
+	assert.True(t, WorldMapCollision(160, 16, Flag0))
+	assert.False(t, ScreenMapCollision(160, 16, Flag0), "screen (160, 16) is world (310, 26)")
+	assert.True(t, ScreenMapCollision(10, 6, Flag0))
+	assert.False(t, MapCollision(10, 6, Flag0), "MapCollision takes world coordinates")
+}
+
+func TestCollisionOutsideMap(t *testing.T) {
+	useTestLayerSprites(t)
+	t.Cleanup(func() { SetCollisionDebug(false) })
+	assert.False(t, CollisionDebug())
+	SetCollisionDebug(true)
+	assert.True(t, CollisionDebug())
+
+	width, height := GetMapSize()
+	right, bottom := float64(width*tileSize), float64(height*tileSize)
+	screenW, screenH := float64(GetScreenWidth()), float64(GetScreenHeight())
+
+	assert.False(t, collisionOutsideMap(0, 0, 8, 8))
+	assert.False(t, collisionOutsideMap(-screenW, 0, 8, 8), "a screen to the left is plausible, e.g. a wrapping level")
+	assert.False(t, collisionOutsideMap(right+screenW, bottom, 8, 8))
+	assert.True(t, collisionOutsideMap(-screenW-9, 0, 8, 8))
+	assert.True(t, collisionOutsideMap(0, bottom+screenH+1, 8, 8))
+	assert.False(t, MapCollision(-screenW-9, 0, Flag0), "the warning doesn't change the result")
+}
+
+func TestColorCollisionAnyAndInfo(t *testing.T) {
+	setupRowTest(t)
+	t.Cleanup(func() { Camera() })
+
+	// Pixels that weren't flushed yet are read from the pixel buffer
+	Camera(2, 0)
+	Pset(5, 4, 8) // World (5, 4) is screen (3, 4)
+	sx, sy := WorldToScreen(5, 4)
+
+	c, ok := ColorCollisionInfo(sx, sy)
+	assert.True(t, ok)
+	assert.Equal(t, 8, c)
+	assert.True(t, ColorCollisionAny(sx, sy, 3, 8, 11))
+	assert.False(t, ColorCollisionAny(sx, sy, 3, 11))
+	assert.False(t, ColorCollisionAny(sx, sy), "no colors never collide")
+
+	_, ok = ColorCollisionInfo(-1, 4)
+	assert.False(t, ok, "outside the screen")
+	_, ok = ColorCollisionInfo(16, 4)
+	assert.False(t, ok, "the test screen is 16 pixels wide")
+	assert.False(t, ColorCollisionAny(-0.5, 4, 0), "-0.5 is left of the screen")
+}
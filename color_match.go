@@ -0,0 +1,81 @@
+package pigo8
+
+import (
+	"image/color"
+	"math"
+)
+
+// --- Color matching ---
+
+// ColorMetric chooses how NearestColorIndex measures the distance between
+// two colors.
+type ColorMetric int
+
+const (
+	// ColorMetricRGB is the squared Euclidean distance between the red, green
+	// and blue values. It is simple and predictable.
+	ColorMetricRGB ColorMetric = iota
+	// ColorMetricPerceptual is the "redmean" approximation of how different
+	// two colors look to the eye: green differences weigh most, and red and
+	// blue weigh more or less depending on how red the colors are. It picks
+	// more natural matches when converting images to the palette.
+	ColorMetricPerceptual
+)
+
+// NearestColorIndex returns the index of the palette color closest to c,
+// e.g. to convert an image or a color picked from the screen to the palette.
+// Colors that are in the palette return their own index. Alpha is ignored,
+// and on a tie the lowest index wins. The optional metric defaults to
+// ColorMetricRGB. It returns -1 if the palette is empty.
+//
+// GetPaletteColor does the opposite, from index to color.
+//
+// Example:
+//
+//	orange := NearestColorIndex(color.RGBA{250, 160, 10, 255}) // 9
+//	blue := NearestColorIndex(skyColor, ColorMetricPerceptual)
+func NearestColorIndex(c color.Color, metric ...ColorMetric) int {
+	m := ColorMetricRGB
+	if len(metric) > 0 {
+		m = metric[0]
+	}
+
+	target := color.RGBAModel.Convert(c).(color.RGBA)
+	nearest, nearestDistance := -1, math.MaxFloat64
+	for i, paletteColor := range pico8Palette {
+		distance := colorDistance(target, color.RGBAModel.Convert(paletteColor).(color.RGBA), m)
+		if distance < nearestDistance {
+			nearest, nearestDistance = i, distance
+		}
+	}
+	return nearest
+}
+
+// colorDistance returns the distance between two colors with metric m.
+func colorDistance(a, b color.RGBA, m ColorMetric) float64 {
+	dr := float64(a.R) - float64(b.R)
+	dg := float64(a.G) - float64(b.G)
+	db := float64(a.B) - float64(b.B)
+	if m == ColorMetricPerceptual {
+		redMean := (float64(a.R) + float64(b.R)) / 2
+		return (2+redMean/256)*dr*dr + 4*dg*dg + (2+(255-redMean)/256)*db*db
+	}
+	return dr*dr + dg*dg + db*db
+}
+
+// paletteIndexOf returns the first palette index with exactly color c, or 0 and false.
+func paletteIndexOf(c color.Color) (int, bool) {
+	for i, paletteColor := range pico8Palette {
+		if colorEquals(c, paletteColor) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// colorEquals compares two colors for equality
+func colorEquals(c1, c2 color.Color) bool {
+	r1, g1, b1, a1 := c1.RGBA()
+	r2, g2, b2, a2 := c2.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
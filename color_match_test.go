@@ -0,0 +1,47 @@
+package pigo8
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestColorIndex(t *testing.T) {
+	// Every PICO-8 color maps back to its own index, with both metrics
+	for i := range GetPaletteSize() {
+		assert.Equal(t, i, NearestColorIndex(GetPaletteColor(i)), "color %d", i)
+		assert.Equal(t, i, NearestColorIndex(GetPaletteColor(i), ColorMetricPerceptual), "color %d", i)
+	}
+
+	assert.Equal(t, 8, NearestColorIndex(color.RGBA{250, 5, 70, 255}), "almost red")
+	assert.Equal(t, 13, NearestColorIndex(color.RGBA{128, 128, 128, 255}), "mid gray is closest to indigo")
+	assert.Equal(t, 4, NearestColorIndex(color.RGBA{200, 100, 60, 0}), "alpha is ignored")
+
+	// The metrics disagree on pure blue: by RGB distance it is blue (12),
+	// to the eye the dark blue (1) is closer
+	assert.Equal(t, 12, NearestColorIndex(color.RGBA{0, 0, 255, 255}))
+	assert.Equal(t, 12, NearestColorIndex(color.RGBA{0, 0, 255, 255}, ColorMetricRGB))
+	assert.Equal(t, 1, NearestColorIndex(color.RGBA{0, 0, 255, 255}, ColorMetricPerceptual))
+}
+
+func TestNearestColorIndexCustomPalette(t *testing.T) {
+	originalPalette := pico8Palette
+	t.Cleanup(func() { pico8Palette = originalPalette })
+
+	// Duplicate colors: the lowest index wins
+	pico8Palette = []color.Color{color.White, color.Black, color.Black}
+	assert.Equal(t, 1, NearestColorIndex(color.RGBA{10, 10, 10, 255}))
+
+	pico8Palette = nil
+	assert.Equal(t, -1, NearestColorIndex(color.White), "empty palette")
+}
+
+func TestPaletteIndexOf(t *testing.T) {
+	index, ok := paletteIndexOf(color.RGBA{255, 0, 77, 255})
+	assert.True(t, ok)
+	assert.Equal(t, 8, index)
+
+	_, ok = paletteIndexOf(color.RGBA{255, 0, 78, 255})
+	assert.False(t, ok, "only exact matches count")
+}
@@ -0,0 +1,236 @@
+package pigo8
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// --- Command line flags ---
+
+// commandLineFlags holds the values of the flags added by RegisterFlags.
+type commandLineFlags struct {
+	seed   string
+	record string
+	replay string
+}
+
+var (
+	// cliFlags is set once RegisterFlags added the flags to a flag set.
+	cliFlags *commandLineFlags
+	// gameSeed is the seed the game started with, see GameSeed.
+	gameSeed    int64
+	gameSeedSet bool
+)
+
+// RegisterFlags adds pigo8's command line flags to fs, or to flag.CommandLine
+// if fs is nil, for games that parse their own flags:
+//
+//	-seed n        seeds the random number generator with Srand(n) at startup
+//	-record file   records the buttons pressed every frame (and the seed) to file
+//	-replay file   plays back a recording instead of reading the real input
+//
+// The values are used when the game starts, so parse fs before PlayGameWith.
+// Games that don't parse flags themselves can set Settings.CommandLineFlags
+// instead. Either way pigo8 only handles flags when asked to, so games with
+// their own -seed flag are not disrupted.
+//
+// Example:
+//
+//	func main() {
+//		level := flag.Int("level", 1, "level to start at")
+//		p8.RegisterFlags(nil)
+//		flag.Parse()
+//		p8.InsertGame(&Game{level: *level})
+//		p8.Play()
+//	}
+func RegisterFlags(fs *flag.FlagSet) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	f := &commandLineFlags{}
+	fs.StringVar(&f.seed, "seed", "", "seed the random number generator (pigo8)")
+	fs.StringVar(&f.record, "record", "", "record the input to a replay file (pigo8)")
+	fs.StringVar(&f.replay, "replay", "", "play back a replay file instead of the real input (pigo8)")
+	cliFlags = f
+}
+
+// GameSeed returns the seed the game was started with, from the -seed flag or
+// a replay, and false if none was given and the generator is seeded from the
+// clock. Show it in bug reports so a run can be reproduced with -seed.
+func GameSeed() (int64, bool) {
+	return gameSeed, gameSeedSet
+}
+
+// setupCommandLineFlags registers the flags for Settings.CommandLineFlags and
+// parses them, unless the network flags will (see ParseNetworkArgs).
+func setupCommandLineFlags(cfg *Settings, parsedLater bool) {
+	if !cfg.CommandLineFlags || cliFlags != nil {
+		return
+	}
+	RegisterFlags(nil)
+	if !parsedLater && !flag.Parsed() {
+		flag.Parse()
+	}
+}
+
+// applyCommandLineFlags seeds the generator and starts recording or replaying
+// as the flags ask. It runs before the cartridge's Init.
+func applyCommandLineFlags() {
+	if cliFlags == nil {
+		return
+	}
+	if cliFlags.seed != "" {
+		seed, err := strconv.ParseInt(cliFlags.seed, 10, 64)
+		if err != nil {
+			log.Printf("Warning: -seed %q is not a whole number. Ignoring it.", cliFlags.seed)
+		} else {
+			setGameSeed(seed)
+		}
+	}
+	switch {
+	case cliFlags.replay != "":
+		if cliFlags.record != "" {
+			log.Printf("Warning: -record is ignored while -replay plays %s.", cliFlags.replay)
+		}
+		if err := startReplay(cliFlags.replay); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	case cliFlags.record != "":
+		startRecording(cliFlags.record)
+	}
+}
+
+// setGameSeed seeds the generator with seed and remembers it for GameSeed.
+func setGameSeed(seed int64) {
+	Srand(seed)
+	gameSeed, gameSeedSet = seed, true
+	log.Printf("Random seed: %d", seed)
+}
+
+// --- Input recording ---
+
+// inputRecording is the replay file written by -record and read by -replay.
+type inputRecording struct {
+	Version int     `json:"version"`
+	Seed    int64   `json:"seed"`
+	Frames  [][]int `json:"frames"` // Buttons held in each input frame, in order
+}
+
+// inputRecordingVersion is the format of the replay files.
+const inputRecordingVersion = 1
+
+var (
+	// recording is the input being recorded, or nil.
+	recording     *inputRecording
+	recordingPath string
+	// replayRecording is the recording being played back, or nil;
+	// replayFrame is the next frame it plays.
+	replayRecording *inputRecording
+	replayFrame     int
+)
+
+// startRecording records the input of every frame until the game stops, when
+// it is written to path. Without a seed, one is picked so the recording can
+// be replayed.
+func startRecording(path string) {
+	if !gameSeedSet {
+		setGameSeed(time.Now().UnixNano())
+	}
+	recording = &inputRecording{Version: inputRecordingVersion, Seed: gameSeed}
+	recordingPath = path
+	log.Printf("Recording input to %s", path)
+}
+
+// startReplay loads a recording and seeds the generator with its seed.
+func startReplay(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading replay %s: %w", path, err)
+	}
+	var loaded inputRecording
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return fmt.Errorf("error parsing replay %s: %w", path, err)
+	}
+	if loaded.Version != inputRecordingVersion {
+		return fmt.Errorf("replay %s has version %d, expected %d", path, loaded.Version, inputRecordingVersion)
+	}
+	if gameSeedSet && gameSeed != loaded.Seed {
+		log.Printf("Warning: -seed %d is replaced by the seed %d of replay %s.", gameSeed, loaded.Seed, path)
+	}
+	setGameSeed(loaded.Seed)
+	if len(loaded.Frames) == 0 {
+		return nil
+	}
+	replayRecording, replayFrame = &loaded, 0
+	log.Printf("Replaying %d frames from %s", len(loaded.Frames), path)
+	return nil
+}
+
+// updateInputFrame starts the input frame of an Update: the next frame of the
+// replay while one plays, the real input otherwise. Recorded input is
+// appended to the recording.
+func updateInputFrame() {
+	if replayRecording == nil {
+		updateInputCache()
+	} else {
+		inputCacheMutex.Lock()
+		clear(injectedButtons)
+		for _, b := range replayRecording.Frames[replayFrame] {
+			injectedButtons[b] = true
+		}
+		inputCacheMutex.Unlock()
+		refreshInputCache(false)
+
+		replayFrame++
+		if replayFrame == len(replayRecording.Frames) {
+			endReplay()
+		}
+	}
+
+	if recording != nil {
+		var held []int
+		inputCacheMutex.RLock()
+		for b := 0; b <= ButtonJoypadR5; b++ {
+			if buttonStates[b] {
+				held = append(held, b)
+			}
+		}
+		inputCacheMutex.RUnlock()
+		recording.Frames = append(recording.Frames, held)
+	}
+}
+
+// endReplay hands the input back to the player once the replay ran out.
+func endReplay() {
+	log.Printf("Replay finished after %d frames, back to live input", replayFrame)
+	replayRecording = nil
+	inputCacheMutex.Lock()
+	clear(injectedButtons)
+	inputCacheMutex.Unlock()
+}
+
+// saveRecording writes the input recorded so far to its file. The engine
+// calls it when the game stops.
+func saveRecording() {
+	if recording == nil {
+		return
+	}
+	if recording.Frames == nil {
+		recording.Frames = [][]int{}
+	}
+	data, err := json.Marshal(recording)
+	if err != nil {
+		log.Printf("Warning: failed to encode the input recording: %v", err)
+		return
+	}
+	if err := os.WriteFile(recordingPath, data, 0o644); err != nil {
+		log.Printf("Warning: failed to write the input recording: %v", err)
+		return
+	}
+	log.Printf("Recorded %d frames to %s (seed %d)", len(recording.Frames), recordingPath, recording.Seed)
+}
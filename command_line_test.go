@@ -0,0 +1,103 @@
+package pigo8
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetCommandLineState forgets the flags, seed, recording and replay after a test.
+func resetCommandLineState(t *testing.T) {
+	t.Cleanup(func() {
+		cliFlags = nil
+		gameSeed, gameSeedSet = 0, false
+		recording, recordingPath = nil, ""
+		replayRecording, replayFrame = nil, 0
+		inputCacheMutex.Lock()
+		clear(injectedButtons)
+		clear(buttonStates)
+		clear(buttonStatesPrev)
+		inputCacheMutex.Unlock()
+	})
+}
+
+func TestSeedFlag(t *testing.T) {
+	resetCommandLineState(t)
+	_, ok := GameSeed()
+	assert.False(t, ok, "no seed until one is given")
+
+	fs := flag.NewFlagSet("game", flag.ContinueOnError)
+	level := fs.Int("level", 1, "the game's own flag")
+	RegisterFlags(fs)
+	require.NoError(t, fs.Parse([]string{"-level", "3", "-seed", "1234"}))
+	applyCommandLineFlags()
+
+	assert.Equal(t, 3, *level, "the game's flags still work")
+	seed, ok := GameSeed()
+	assert.True(t, ok)
+	assert.Equal(t, int64(1234), seed)
+	first := Rnd(1000)
+	Srand(1234)
+	assert.Equal(t, first, Rnd(1000), "the flag seeds Rnd")
+}
+
+func TestBadSeedFlagIsIgnored(t *testing.T) {
+	resetCommandLineState(t)
+	fs := flag.NewFlagSet("game", flag.ContinueOnError)
+	RegisterFlags(fs)
+	require.NoError(t, fs.Parse([]string{"-seed", "abc"}))
+	applyCommandLineFlags()
+	_, ok := GameSeed()
+	assert.False(t, ok)
+}
+
+func TestCommandLineFlagsAreOptIn(t *testing.T) {
+	resetCommandLineState(t)
+	setupCommandLineFlags(NewSettings(), false)
+	assert.Nil(t, cliFlags, "Settings.CommandLineFlags is off by default")
+}
+
+func TestReplayAndRecord(t *testing.T) {
+	resetCommandLineState(t)
+	dir := t.TempDir()
+	replayPath := filepath.Join(dir, "bug.json")
+	require.NoError(t, os.WriteFile(replayPath, []byte(`{"version": 1, "seed": 99, "frames": [[0], [0, 4], []]}`), 0o644))
+
+	require.NoError(t, startReplay(replayPath))
+	seed, ok := GameSeed()
+	assert.True(t, ok)
+	assert.Equal(t, int64(99), seed, "a replay brings its seed")
+
+	// Record while replaying, to check the recording matches the input seen
+	startRecording(filepath.Join(dir, "copy.json"))
+
+	updateInputFrame()
+	assert.True(t, Btn(LEFT))
+	assert.False(t, Btn(O))
+	updateInputFrame()
+	assert.True(t, Btn(LEFT))
+	assert.True(t, Btnp(O))
+	updateInputFrame()
+	assert.False(t, Btn(LEFT))
+	assert.Nil(t, replayRecording, "the replay ended after its last frame")
+
+	saveRecording()
+	require.NoError(t, startReplay(filepath.Join(dir, "copy.json")))
+	assert.Equal(t, [][]int{{0}, {0, 4}, nil}, replayRecording.Frames)
+	assert.Equal(t, int64(99), replayRecording.Seed)
+}
+
+func TestReplayErrors(t *testing.T) {
+	resetCommandLineState(t)
+	dir := t.TempDir()
+	assert.Error(t, startReplay(filepath.Join(dir, "missing.json")))
+
+	path := filepath.Join(dir, "future.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 2, "seed": 1, "frames": [[0]]}`), 0o644))
+	assert.ErrorContains(t, startReplay(path), "version 2")
+	assert.Nil(t, replayRecording)
+}
@@ -0,0 +1,256 @@
+package pigo8
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// --- Developer console ---
+
+// consoleKey opens and closes the developer console.
+const consoleKey = "Backquote"
+
+// consoleMaxLines is how many lines of output the console keeps.
+const consoleMaxLines = 6
+
+var (
+	// devConsole enables the console key, see SetDevConsole.
+	devConsole bool
+	// consoleOpen is true while the console is shown and captures typing.
+	consoleOpen bool
+	// consoleVars holds the variables registered with RegisterVar.
+	consoleVars = make(map[string]*float64)
+	// consoleLines holds the recent commands and their output, oldest first.
+	consoleLines []string
+)
+
+// consoleCommands are the built-in commands, offered by autocomplete along
+// with the variable names.
+var consoleCommands = []string{"get", "list", "set"}
+
+// SetDevConsole turns the developer console on or off (see also
+// Settings.DevConsole). While it is on, the ` (backquote) key opens a console
+// over the top of the screen to inspect and change the variables registered
+// with RegisterVar while the game runs, e.g. to tune jump physics live:
+//
+//	list              shows every variable and its value
+//	get gravity       shows one variable ("gravity" alone does the same)
+//	set gravity 0.5   changes it ("gravity 0.5" does the same)
+//
+// Tab completes a variable or command name, Enter runs the line, and `
+// closes the console. The game keeps running while it is open, but the
+// keyboard types into the console instead of pressing buttons, as with
+// StartTextInput. Leave it off in releases; it is off by default.
+//
+// Example:
+//
+//	settings := NewSettings()
+//	settings.DevConsole = true
+//	PlayGameWith(settings)
+func SetDevConsole(on bool) {
+	devConsole = on
+	if !on && consoleOpen {
+		closeConsole()
+	}
+}
+
+// DevConsole reports whether the developer console is on.
+func DevConsole() bool {
+	return devConsole
+}
+
+// RegisterVar makes the variable v available in the developer console under
+// name, see SetDevConsole. Names are case-sensitive and can't contain spaces;
+// registering a name again replaces its variable, and a nil v removes it.
+//
+// Example:
+//
+//	var gravity = 0.3
+//
+//	func (g *Game) Init() {
+//		RegisterVar("gravity", &gravity)
+//	}
+func RegisterVar(name string, v *float64) {
+	if v == nil {
+		delete(consoleVars, name)
+		return
+	}
+	consoleVars[name] = v
+}
+
+// updateConsole opens, closes and runs the console. The engine calls it every
+// frame after updateTextInput.
+func updateConsole() {
+	if !devConsole {
+		return
+	}
+	if Keyp(consoleKey) {
+		if consoleOpen {
+			closeConsole()
+		} else {
+			consoleOpen = true
+			StartTextInput(0)
+		}
+		return
+	}
+	if !consoleOpen {
+		return
+	}
+	if Keyp("Tab") {
+		textInput = []rune(completeConsoleLine(GetTextInput()))
+	}
+	if Keyp("Enter") {
+		line := strings.TrimSpace(GetTextInput())
+		StartTextInput(0)
+		if line != "" {
+			consolePrint("> " + line)
+			consolePrint(runConsoleCommand(line))
+		}
+	}
+}
+
+// closeConsole hides the console and stops capturing typing.
+func closeConsole() {
+	consoleOpen = false
+	StopTextInput()
+}
+
+// consolePrint adds a line to the console output.
+func consolePrint(line string) {
+	if line == "" {
+		return
+	}
+	consoleLines = append(consoleLines, line)
+	if len(consoleLines) > consoleMaxLines {
+		consoleLines = consoleLines[len(consoleLines)-consoleMaxLines:]
+	}
+}
+
+// runConsoleCommand runs a console line and returns its output.
+func runConsoleCommand(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	command, args := fields[0], fields[1:]
+	switch command {
+	case "list":
+		names := consoleVarNames()
+		if len(names) == 0 {
+			return "no variables registered"
+		}
+		values := make([]string, len(names))
+		for i, name := range names {
+			values[i] = name + "=" + formatConsoleValue(*consoleVars[name])
+		}
+		return strings.Join(values, " ")
+	case "get":
+		if len(args) != 1 {
+			return "usage: get name"
+		}
+		return getConsoleVar(args[0])
+	case "set":
+		if len(args) != 2 {
+			return "usage: set name value"
+		}
+		return setConsoleVar(args[0], args[1])
+	}
+	switch len(args) {
+	case 0:
+		return getConsoleVar(command)
+	case 1:
+		return setConsoleVar(command, args[0])
+	}
+	return "unknown command: " + command
+}
+
+// getConsoleVar returns "name=value" for a registered variable.
+func getConsoleVar(name string) string {
+	v, ok := consoleVars[name]
+	if !ok {
+		return "unknown variable: " + name
+	}
+	return name + "=" + formatConsoleValue(*v)
+}
+
+// setConsoleVar parses value into a registered variable.
+func setConsoleVar(name, value string) string {
+	v, ok := consoleVars[name]
+	if !ok {
+		return "unknown variable: " + name
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return "not a number: " + value
+	}
+	*v = parsed
+	return name + "=" + formatConsoleValue(parsed)
+}
+
+// formatConsoleValue formats a value as short as it can be read back.
+func formatConsoleValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// consoleVarNames returns the registered variable names in order.
+func consoleVarNames() []string {
+	names := make([]string, 0, len(consoleVars))
+	for name := range consoleVars {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// completeConsoleLine completes the last word of line to the longest prefix
+// shared by the names it starts: commands and variable names (which get or
+// set the variable) for the first word, variable names for the name after get
+// and set, and nothing for the other arguments.
+func completeConsoleLine(line string) string {
+	start := strings.LastIndex(line, " ") + 1
+	word := line[start:]
+	var candidates []string
+	switch fields := strings.Fields(line[:start]); {
+	case len(fields) == 0:
+		candidates = append(slices.Clone(consoleCommands), consoleVarNames()...)
+	case len(fields) == 1 && (fields[0] == "get" || fields[0] == "set"):
+		candidates = consoleVarNames()
+	}
+	var matches []string
+	for _, name := range candidates {
+		if strings.HasPrefix(name, word) {
+			matches = append(matches, name)
+		}
+	}
+	if len(matches) == 0 {
+		return line
+	}
+	completed := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match, completed) {
+			completed = completed[:len(completed)-1]
+		}
+	}
+	if len(matches) == 1 {
+		completed += " "
+	}
+	return line[:start] + completed
+}
+
+// drawConsole draws the open console over the top of the screen.
+func drawConsole() {
+	if !consoleOpen {
+		return
+	}
+	lineHeight := int(defaultFontSize) + 2
+	height := (len(consoleLines)+1)*lineHeight + 3
+	drawOnScreen(func() {
+		Rectfill(0, 0, GetScreenWidth()-1, height, 0)
+		Line(0, height, GetScreenWidth()-1, height, 5)
+		for i, line := range consoleLines {
+			Print(line, 2, 2+i*lineHeight, 6)
+		}
+		Print("> "+GetTextInput()+"_", 2, 2+len(consoleLines)*lineHeight, 7)
+	})
+}
@@ -0,0 +1,64 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// useConsoleVars registers variables for one test.
+func useConsoleVars(t *testing.T, vars map[string]*float64) {
+	t.Helper()
+	t.Cleanup(func() {
+		consoleVars = make(map[string]*float64)
+		consoleLines = nil
+	})
+	for name, v := range vars {
+		RegisterVar(name, v)
+	}
+}
+
+func TestRunConsoleCommand(t *testing.T) {
+	gravity, jump := 0.3, 2.0
+	useConsoleVars(t, map[string]*float64{"gravity": &gravity, "jump": &jump})
+
+	assert.Equal(t, "gravity=0.3 jump=2", runConsoleCommand("list"))
+	assert.Equal(t, "gravity=0.3", runConsoleCommand("get gravity"))
+	assert.Equal(t, "jump=2", runConsoleCommand("jump"))
+
+	assert.Equal(t, "gravity=0.5", runConsoleCommand("set gravity 0.5"))
+	assert.Equal(t, 0.5, gravity)
+	assert.Equal(t, "jump=-1.25", runConsoleCommand("  jump   -1.25 "))
+	assert.Equal(t, -1.25, jump)
+
+	assert.Equal(t, "not a number: high", runConsoleCommand("set jump high"))
+	assert.Equal(t, -1.25, jump, "a bad value leaves the variable alone")
+	assert.Equal(t, "unknown variable: speed", runConsoleCommand("set speed 1"))
+	assert.Equal(t, "usage: set name value", runConsoleCommand("set gravity"))
+	assert.Equal(t, "unknown command: fly", runConsoleCommand("fly to moon"))
+
+	RegisterVar("jump", nil)
+	assert.Equal(t, "gravity=0.5", runConsoleCommand("list"))
+}
+
+func TestCompleteConsoleLine(t *testing.T) {
+	var a, b, c float64
+	useConsoleVars(t, map[string]*float64{"gravity": &a, "groundSpeed": &b, "jump": &c})
+
+	assert.Equal(t, "set gr", completeConsoleLine("set g"), "the shared prefix of gravity and groundSpeed")
+	assert.Equal(t, "set gravity ", completeConsoleLine("set gra"))
+	assert.Equal(t, "jump ", completeConsoleLine("j"))
+	assert.Equal(t, "list ", completeConsoleLine("l"), "commands complete too")
+	assert.Equal(t, "set zz", completeConsoleLine("set zz"), "no match leaves the line alone")
+	assert.Equal(t, "set jump g", completeConsoleLine("set jump g"), "values aren't completed")
+}
+
+func TestConsolePrintKeepsRecentLines(t *testing.T) {
+	useConsoleVars(t, nil)
+	for i := range consoleMaxLines + 2 {
+		consolePrint(string(rune('a' + i)))
+	}
+	consolePrint("")
+	assert.Len(t, consoleLines, consoleMaxLines)
+	assert.Equal(t, "c", consoleLines[0])
+}
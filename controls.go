@@ -347,8 +347,12 @@ func Btn(buttonIndex int, _ ...int) bool {
 // inpututil functions.
 
 // Btnp checks if a specific PICO-8 button was just pressed via gamepad, keyboard (Player 0 only), or mouse.
-// Mimics the PICO-8 btnp() function behavior (without auto-repeat).
-// It returns true only on the single frame the button transitions from up to down.
+// Mimics the PICO-8 btnp() function behavior, including auto-repeat.
+// It returns true on the frame the button transitions from up to down. While
+// one of the six PICO-8 buttons (LEFT, RIGHT, UP, DOWN, O, X) is held, it
+// also returns true again after 15 frames and then every 4 frames, like
+// PICO-8 does; see SetBtnRepeat to change or disable this. Other buttons,
+// including the mouse buttons, never repeat.
 //
 // buttonIndex: The PICO-8 button index (0-15).
 // playerIndex: Optional PICO-8 player index (0-7). Defaults to 0 (player 1) if omitted.
@@ -381,19 +385,132 @@ func Btnp(buttonIndex int, _ ...int) bool {
 	// Check if button is pressed this frame but wasn't pressed last frame
 	current := getCachedButtonState(buttonIndex)
 	previous := getCachedButtonStatePrev(buttonIndex)
-	return current && !previous
+	return current && (!previous || btnRepeats(buttonIndex))
+}
+
+// BtnHoldFrames returns for how many frames in a row a button has been held
+// down, counting the current frame: 1 on the frame Btnp first fires, 2 on
+// the next and so on, and 0 while the button is up. Releasing the button
+// resets the count, even for a single frame. Mouse buttons count too, so
+// click-and-hold tools work the same way.
+//
+// Like Btn, the optional playerIndex is accepted for PICO-8 compatibility,
+// but input from all keyboards, gamepads and mice is combined.
+//
+// Example:
+//
+//	// Charge a shot while O is held, fire it on release
+//	if Btn(O) {
+//		g.charge = min(BtnHoldFrames(O), 60)
+//	} else if g.charge > 0 {
+//		g.fire(g.charge)
+//		g.charge = 0
+//	}
+func BtnHoldFrames(buttonIndex int, _ ...int) int {
+	inputCacheMutex.RLock()
+	defer inputCacheMutex.RUnlock()
+	return buttonHeldFrames[buttonIndex]
+}
+
+// Default Btnp auto-repeat, in frames, the same as PICO-8
+const (
+	defaultBtnRepeatDelay    = 15
+	defaultBtnRepeatInterval = 4
+)
+
+// Btnp auto-repeat settings, in frames (see SetBtnRepeat)
+var (
+	btnRepeatDelay    = defaultBtnRepeatDelay
+	btnRepeatInterval = defaultBtnRepeatInterval
+)
+
+// SetBtnRepeat configures the auto-repeat of Btnp for the six PICO-8
+// buttons: while a button is held, Btnp returns true again after delay
+// frames, and then every interval frames. The default is 15 and 4, as in
+// PICO-8. Counting is per button, in game frames, so it doesn't depend on
+// the frame rate of the machine. A delay or interval below 1 turns repeat
+// off, so Btnp only fires once per press.
+//
+// Example:
+//
+//	// A menu where holding a direction must not skip entries
+//	delay, interval := GetBtnRepeat()
+//	SetBtnRepeat(0, 0)
+//	// ... later, when the menu closes
+//	SetBtnRepeat(delay, interval)
+func SetBtnRepeat(delay, interval int) {
+	inputCacheMutex.Lock()
+	defer inputCacheMutex.Unlock()
+	btnRepeatDelay = delay
+	btnRepeatInterval = interval
+}
+
+// GetBtnRepeat returns the Btnp auto-repeat delay and interval in frames
+// set with SetBtnRepeat.
+func GetBtnRepeat() (delay, interval int) {
+	inputCacheMutex.RLock()
+	defer inputCacheMutex.RUnlock()
+	return btnRepeatDelay, btnRepeatInterval
+}
+
+// btnRepeats reports whether a held button auto-repeats on this frame.
+func btnRepeats(buttonIndex int) bool {
+	if buttonIndex < LEFT || buttonIndex > X {
+		return false
+	}
+	inputCacheMutex.RLock()
+	defer inputCacheMutex.RUnlock()
+	return isBtnRepeatFrame(buttonHeldFrames[buttonIndex], btnRepeatDelay, btnRepeatInterval)
+}
+
+// isBtnRepeatFrame reports whether a button held for heldFrames frames (1 on
+// the frame it was pressed) repeats with the given delay and interval.
+func isBtnRepeatFrame(heldFrames, delay, interval int) bool {
+	if delay < 1 || interval < 1 {
+		return false
+	}
+	sincePress := heldFrames - 1
+	return sincePress >= delay && (sincePress-delay)%interval == 0
 }
 
 // Add input state caching
 var (
 	buttonStates     = make(map[int]bool) // buttonIndex -> isPressed
 	buttonStatesPrev = make(map[int]bool) // previous frame button states
+	injectedButtons  = make(map[int]bool) // buttonIndex -> held down by InjectButton
+	buttonHeldFrames = make(map[int]int)  // buttonIndex -> frames held in a row, for Btnp repeat and BtnHoldFrames
 	inputCacheMutex  sync.RWMutex
 	inputCacheValid  bool
 )
 
+// InjectButton holds a button down (pressed true) or releases it (pressed
+// false) as if a player did it. Injected presses are combined with real input
+// and show up in Btn and Btnp from the next frame on. Use it to drive games
+// from tests (see TestHarness), demos or replays.
+//
+// Example:
+//
+//	InjectButton(RIGHT, true) // walk right...
+//	h.AdvanceFrames(10)
+//	InjectButton(RIGHT, false) // ...for 10 frames
+func InjectButton(buttonIndex int, pressed bool) {
+	inputCacheMutex.Lock()
+	defer inputCacheMutex.Unlock()
+	if pressed {
+		injectedButtons[buttonIndex] = true
+	} else {
+		delete(injectedButtons, buttonIndex)
+	}
+}
+
 // updateInputCache updates the cached button states
 func updateInputCache() {
+	refreshInputCache(true)
+}
+
+// refreshInputCache starts a new input frame. Buttons are pressed if they
+// were injected or, when poll is true, held on a real input device.
+func refreshInputCache(poll bool) {
 	inputCacheMutex.Lock()
 	defer inputCacheMutex.Unlock()
 
@@ -404,7 +521,13 @@ func updateInputCache() {
 
 	// Update current states for all buttons
 	for buttonIndex := 0; buttonIndex <= ButtonJoypadR5; buttonIndex++ {
-		buttonStates[buttonIndex] = checkButtonState(buttonIndex)
+		pressed := injectedButtons[buttonIndex] || (poll && checkButtonState(buttonIndex))
+		buttonStates[buttonIndex] = pressed
+		if pressed {
+			buttonHeldFrames[buttonIndex]++
+		} else {
+			delete(buttonHeldFrames, buttonIndex)
+		}
 	}
 
 	inputCacheValid = true
@@ -417,8 +540,8 @@ func checkButtonState(buttonIndex int) bool {
 		return handleMouseInput(buttonIndex)
 	}
 
-	// Handle keyboard input
-	if handleKeyboardInput(buttonIndex) {
+	// Handle keyboard input, unless the keyboard is typing text
+	if !textInputActive && handleKeyboardInput(buttonIndex) {
 		return true
 	}
 
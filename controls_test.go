@@ -61,3 +61,97 @@ func TestBtnpPlayerIndexValidation(t *testing.T) {
 		assert.False(t, Btnp(button), "Expected false for default player index 0 (no gamepad)")
 	})
 }
+
+func TestIsBtnRepeatFrame(t *testing.T) {
+	var fired []int
+	for held := 1; held <= 30; held++ {
+		if isBtnRepeatFrame(held, 15, 4) {
+			fired = append(fired, held)
+		}
+	}
+	assert.Equal(t, []int{16, 20, 24, 28}, fired, "15 frames after the press, then every 4")
+
+	assert.False(t, isBtnRepeatFrame(16, 0, 4), "zero delay disables repeat")
+	assert.False(t, isBtnRepeatFrame(16, 15, 0), "zero interval disables repeat")
+	assert.True(t, isBtnRepeatFrame(3, 1, 1), "repeats every frame after one frame")
+}
+
+// repeatCartridge counts Btnp presses of X and START.
+type repeatCartridge struct {
+	x, start int
+}
+
+func (c *repeatCartridge) Init() {}
+func (c *repeatCartridge) Update() {
+	if Btnp(X) {
+		c.x++
+	}
+	if Btnp(ButtonStart) {
+		c.start++
+	}
+}
+func (c *repeatCartridge) Draw() {}
+
+func TestBtnpAutoRepeat(t *testing.T) {
+	originalCart := loadedCartridge
+	t.Cleanup(func() {
+		InsertGame(originalCart)
+		resetInputState()
+		SetBtnRepeat(defaultBtnRepeatDelay, defaultBtnRepeatInterval)
+	})
+
+	game := &repeatCartridge{}
+	h := NewTestHarness(game, NewSettings())
+	h.Init()
+
+	delay, interval := GetBtnRepeat()
+	assert.Equal(t, 15, delay)
+	assert.Equal(t, 4, interval)
+
+	h.InjectButton(X, true)
+	h.InjectButton(ButtonStart, true)
+	h.AdvanceFrames(23)
+	assert.Equal(t, 3, game.x, "press on frame 1, repeats on frames 16 and 20")
+	assert.Equal(t, 1, game.start, "only the six PICO-8 buttons repeat")
+
+	// Releasing resets the count
+	h.InjectButton(X, false)
+	h.Step()
+	h.InjectButton(X, true)
+	h.AdvanceFrames(15)
+	assert.Equal(t, 4, game.x)
+
+	SetBtnRepeat(0, 0)
+	h.AdvanceFrames(20)
+	assert.Equal(t, 4, game.x, "repeat turned off")
+}
+
+func TestBtnHoldFrames(t *testing.T) {
+	originalCart := loadedCartridge
+	t.Cleanup(func() {
+		InsertGame(originalCart)
+		resetInputState()
+	})
+
+	h := NewTestHarness(&repeatCartridge{}, NewSettings())
+	h.Init()
+	assert.Equal(t, 0, BtnHoldFrames(O), "up buttons report 0")
+
+	h.InjectButton(O, true)
+	h.InjectButton(ButtonMouseLeft, true)
+	h.Step()
+	assert.Equal(t, 1, BtnHoldFrames(O), "the frame of the press counts")
+	h.AdvanceFrames(9)
+	assert.Equal(t, 10, BtnHoldFrames(O))
+	assert.Equal(t, 10, BtnHoldFrames(ButtonMouseLeft), "mouse buttons are counted too")
+	assert.Equal(t, 10, BtnHoldFrames(O, 1), "input is shared by all players")
+
+	// A single frame released resets the count
+	h.InjectButton(O, false)
+	h.Step()
+	assert.Equal(t, 0, BtnHoldFrames(O))
+	h.InjectButton(O, true)
+	h.Step()
+	assert.Equal(t, 1, BtnHoldFrames(O))
+	assert.Equal(t, 12, BtnHoldFrames(ButtonMouseLeft))
+}
@@ -0,0 +1,184 @@
+package pigo8
+
+import "strconv"
+
+// --- Debug controls ---
+
+// DebugKeys are the keys of the debug controls, as key names for Keyp. An
+// empty name leaves that control unbound.
+type DebugKeys struct {
+	Pause  string // Pauses or resumes the game logic
+	Step   string // Runs one frame of game logic while paused
+	Slower string // Doubles the slow motion, up to maxSlowMotion
+	Faster string // Halves the slow motion, down to normal speed
+	Grid   string // Cycles the debug grid through its modes, see SetDebugGrid
+}
+
+// maxSlowMotion is the slowest speed the Slower key reaches: Update every
+// 16 frames.
+const maxSlowMotion = 16
+
+var (
+	// debugControls enables the debug keys and the pause and slow motion.
+	debugControls bool
+	debugKeys     = DefaultDebugKeys()
+	// simulationPaused stops Update until resumed; stepFrames runs that many
+	// Updates anyway.
+	simulationPaused bool
+	stepFrames       int
+	// slowMotion runs Update every slowMotion frames; slowMotionTick counts
+	// the frames in between.
+	slowMotion     = 1
+	slowMotionTick int
+)
+
+// DefaultDebugKeys returns the default debug keys: F5 pauses, F6 steps one
+// frame, F7 slows down, F8 speeds up and F9 cycles the debug grid.
+func DefaultDebugKeys() DebugKeys {
+	return DebugKeys{Pause: "F5", Step: "F6", Slower: "F7", Faster: "F8", Grid: "F9"}
+}
+
+// SetDebugControls turns the debug controls on or off (see also
+// Settings.DebugControls). While they are on, the debug keys pause the game
+// logic, step it one frame at a time and run it in slow motion, to inspect
+// collisions and animations frame by frame. Drawing goes on as usual, so the
+// frozen frame stays on screen, and a label in the top right corner shows
+// when the game is paused or slowed. Turning them off runs the game at normal
+// speed again.
+//
+// Example:
+//
+//	settings := NewSettings()
+//	settings.DebugControls = true
+//	PlayGameWith(settings)
+func SetDebugControls(on bool) {
+	debugControls = on
+}
+
+// DebugControls reports whether the debug controls are on.
+func DebugControls() bool {
+	return debugControls
+}
+
+// SetDebugKeys changes the keys of the debug controls.
+//
+// Example:
+//
+//	SetDebugKeys(DebugKeys{Pause: "P", Step: "Period", Slower: "Minus", Faster: "Equal"})
+func SetDebugKeys(keys DebugKeys) {
+	debugKeys = keys
+}
+
+// GetDebugKeys returns the keys set with SetDebugKeys.
+func GetDebugKeys() DebugKeys {
+	return debugKeys
+}
+
+// SetSimulationPaused pauses or resumes the game logic like the Pause debug
+// key. It only has an effect while the debug controls are on.
+func SetSimulationPaused(paused bool) {
+	simulationPaused = paused
+	if !paused {
+		stepFrames = 0
+	}
+}
+
+// SimulationPaused reports whether the game logic is paused by the debug
+// controls.
+func SimulationPaused() bool {
+	return simulationPaused
+}
+
+// StepFrame runs one frame of game logic on the next frame while the game
+// logic is paused, like the Step debug key.
+func StepFrame() {
+	if simulationPaused {
+		stepFrames++
+	}
+}
+
+// SetSlowMotion runs Update (and advances T and Frame) only every n frames
+// while drawing every frame, like the Slower and Faster debug keys. 1 is
+// normal speed. It only has an effect while the debug controls are on.
+func SetSlowMotion(n int) {
+	slowMotion = max(n, 1)
+	slowMotionTick = 0
+}
+
+// GetSlowMotion returns the slow motion set with SetSlowMotion.
+func GetSlowMotion() int {
+	return slowMotion
+}
+
+// handleDebugKeys applies the debug keys pressed this frame. The engine
+// calls it every frame before Update.
+func handleDebugKeys() {
+	if !debugControls {
+		return
+	}
+	keys := debugKeys
+	if keys.Pause != "" && Keyp(keys.Pause) {
+		SetSimulationPaused(!simulationPaused)
+	}
+	if keys.Step != "" && Keyp(keys.Step) {
+		StepFrame()
+	}
+	if keys.Slower != "" && Keyp(keys.Slower) {
+		SetSlowMotion(min(slowMotion*2, maxSlowMotion))
+	}
+	if keys.Faster != "" && Keyp(keys.Faster) {
+		SetSlowMotion(slowMotion / 2)
+	}
+	if keys.Grid != "" && Keyp(keys.Grid) {
+		SetDebugGrid((debugGrid + 1) % (DebugGridCoords + 1))
+	}
+}
+
+// simulationFrameDue reports whether the game logic runs this frame, taking
+// the debug pause, steps and slow motion into account.
+func simulationFrameDue() bool {
+	if !debugControls {
+		return true
+	}
+	if simulationPaused {
+		if stepFrames == 0 {
+			return false
+		}
+		stepFrames--
+		return true
+	}
+	slowMotionTick++
+	if slowMotionTick < slowMotion {
+		return false
+	}
+	slowMotionTick = 0
+	return true
+}
+
+// debugControlsLabel returns the label shown while the game is paused or
+// slowed, or "" at normal speed.
+func debugControlsLabel() string {
+	switch {
+	case !debugControls:
+		return ""
+	case simulationPaused:
+		return "paused f" + strconv.Itoa(frameCount)
+	case slowMotion > 1:
+		return "1/" + strconv.Itoa(slowMotion) + " speed"
+	}
+	return ""
+}
+
+// drawDebugControls draws the debug controls label in the top right corner.
+func drawDebugControls() {
+	label := debugControlsLabel()
+	if label == "" {
+		return
+	}
+	width := len(label) * int(CharWidthApproximation)
+	x := GetScreenWidth() - width - 3
+	drawOnScreen(func() {
+		Rectfill(x-2, 1, x+width, int(defaultFontSize)+3, 0)
+		Print(label, x, 3, 10)
+	})
+}
@@ -0,0 +1,85 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// useDebugControls turns the debug controls on for one test.
+func useDebugControls(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		SetDebugControls(false)
+		SetSimulationPaused(false)
+		SetSlowMotion(1)
+		SetDebugKeys(DefaultDebugKeys())
+	})
+	SetDebugControls(true)
+	SetSimulationPaused(false)
+	SetSlowMotion(1)
+}
+
+// dueFrames runs n frames and returns on which of them Update runs.
+func dueFrames(n int) []bool {
+	due := make([]bool, n)
+	for i := range due {
+		due[i] = simulationFrameDue()
+	}
+	return due
+}
+
+func TestDebugPauseAndStep(t *testing.T) {
+	useDebugControls(t)
+	assert.Equal(t, []bool{true, true}, dueFrames(2))
+
+	SetSimulationPaused(true)
+	assert.Equal(t, []bool{false, false}, dueFrames(2))
+
+	StepFrame()
+	StepFrame()
+	assert.Equal(t, []bool{true, true, false}, dueFrames(3), "each step runs one frame")
+
+	StepFrame()
+	SetSimulationPaused(false)
+	SetSimulationPaused(true)
+	assert.Equal(t, []bool{false}, dueFrames(1), "resuming drops pending steps")
+
+	SetSimulationPaused(false)
+	StepFrame()
+	assert.Equal(t, []bool{true, true}, dueFrames(2), "steps only count while paused")
+}
+
+func TestDebugSlowMotion(t *testing.T) {
+	useDebugControls(t)
+
+	SetSlowMotion(3)
+	assert.Equal(t, []bool{false, false, true, false, false, true}, dueFrames(6))
+
+	SetSlowMotion(0)
+	assert.Equal(t, 1, GetSlowMotion(), "below 1 is normal speed")
+	assert.Equal(t, []bool{true, true}, dueFrames(2))
+}
+
+func TestDebugControlsOff(t *testing.T) {
+	useDebugControls(t)
+	SetSimulationPaused(true)
+	SetSlowMotion(4)
+
+	SetDebugControls(false)
+	assert.Equal(t, []bool{true, true}, dueFrames(2), "the game runs at normal speed")
+	assert.Empty(t, debugControlsLabel())
+}
+
+func TestDebugControlsLabel(t *testing.T) {
+	useDebugControls(t)
+	savedFrame := frameCount
+	t.Cleanup(func() { frameCount = savedFrame })
+	frameCount = 42
+
+	assert.Empty(t, debugControlsLabel(), "nothing is shown at normal speed")
+	SetSlowMotion(4)
+	assert.Equal(t, "1/4 speed", debugControlsLabel())
+	SetSimulationPaused(true)
+	assert.Equal(t, "paused f42", debugControlsLabel())
+}
@@ -0,0 +1,234 @@
+package pigo8
+
+import (
+	"math"
+	"strconv"
+)
+
+// --- Debug grid and collision overlay ---
+
+// DebugGridMode is what the debug grid overlay shows, see SetDebugGrid.
+type DebugGridMode int
+
+const (
+	// DebugGridOff hides the debug grid (the default).
+	DebugGridOff DebugGridMode = iota
+	// DebugGridLines draws the map's tile grid over the game.
+	DebugGridLines
+	// DebugGridCoords draws the tile grid, the tile numbers along the top and
+	// left edges, and the coordinates of the tile under the mouse.
+	DebugGridCoords
+)
+
+// debugGridColor is the color of the debug grid lines and labels.
+const debugGridColor = 5
+
+// collisionDebugColors are the colors DrawCollisionDebug outlines tiles with,
+// by flag number.
+var collisionDebugColors = [8]int{8, 11, 12, 10, 14, 9, 13, 15}
+
+var (
+	debugGrid DebugGridMode
+	// debugGridOriginX and debugGridOriginY are the screen position of map
+	// tile (0, 0) as drawn by the last Map call of the frame, which the
+	// overlay aligns to; debugGridOriginSet is false until Map is called.
+	debugGridOriginX, debugGridOriginY float64
+	debugGridOriginSet                 bool
+)
+
+// DrawGrid draws lines every cellSize pixels of the world, following the
+// camera, so with the tile size it lines up with Map() and shows where tiles
+// start. It covers the whole screen and is drawn like any other shape, e.g.
+// before the sprites to show them over it.
+//
+// Example:
+//
+//	Camera(g.camX, g.camY)
+//	Map()
+//	DrawGrid(GetTileSize(), 5)
+//	Spr(1, g.player.x, g.player.y)
+func DrawGrid(cellSize, color int) {
+	if cellSize <= 0 || currentScreen == nil {
+		return
+	}
+	originX, originY := -cameraX, -cameraY
+	drawOnScreen(func() {
+		drawGridAt(originX, originY, cellSize, color)
+	})
+}
+
+// SetDebugGrid shows or hides the debug grid overlay, drawn over the finished
+// frame aligned with the map as drawn by the last Map call of the frame, and
+// with its camera. It can also be set with Settings.DebugGrid, and the Grid
+// debug key cycles through the modes while the debug controls are on. The
+// overlay costs nothing while it's off, so it can stay in releases.
+//
+// Example:
+//
+//	settings := NewSettings()
+//	settings.DebugGrid = DebugGridCoords
+//	PlayGameWith(settings)
+func SetDebugGrid(mode DebugGridMode) {
+	if mode < DebugGridOff || mode > DebugGridCoords {
+		mode = DebugGridOff
+	}
+	debugGrid = mode
+}
+
+// DebugGrid returns the debug grid mode set with SetDebugGrid.
+func DebugGrid() DebugGridMode {
+	return debugGrid
+}
+
+// DrawCollisionDebug outlines the tiles on screen whose sprite has one of the
+// flags (Flag0 if none are given), in a color per flag, to check which tiles
+// MapCollision treats as solid. Like MapCollision it takes the map to start
+// at world (0, 0), so call it after Map() with the game's camera. It only
+// draws while the collision debug is on (see SetCollisionDebug), so the call
+// can stay in releases.
+//
+// Example:
+//
+//	Camera(g.camX, g.camY)
+//	Map()
+//	DrawCollisionDebug(Flag0, Flag1) // Solid and one-way tiles
+func DrawCollisionDebug(flags ...int) {
+	if !collisionDebug || currentScreen == nil {
+		return
+	}
+	if len(flags) == 0 {
+		flags = []int{Flag0}
+	}
+	for _, flag := range flags {
+		if flag < 0 || flag >= len(collisionDebugColors) {
+			continue
+		}
+		color := collisionDebugColors[flag]
+		for _, tile := range collisionDebugTiles(flag) {
+			x, y := tile[0]*tileSize, tile[1]*tileSize
+			Rect(x, y, x+tileSize-1, y+tileSize-1, color)
+		}
+	}
+}
+
+// collisionDebugTiles returns the map tiles on screen, with the camera, whose
+// sprite has flag.
+func collisionDebugTiles(flag int) [][2]int {
+	width, height := GetMapSize()
+	left := max(int(math.Floor(cameraX/float64(tileSize))), 0)
+	top := max(int(math.Floor(cameraY/float64(tileSize))), 0)
+	right := min(int(math.Floor((cameraX+float64(GetScreenWidth()-1))/float64(tileSize))), width-1)
+	bottom := min(int(math.Floor((cameraY+float64(GetScreenHeight()-1))/float64(tileSize))), height-1)
+
+	var tiles [][2]int
+	for ty := top; ty <= bottom; ty++ {
+		for tx := left; tx <= right; tx++ {
+			if sprite := Mget(tx, ty); sprite != 0 && getCachedFlag(sprite, flag) {
+				tiles = append(tiles, [2]int{tx, ty})
+			}
+		}
+	}
+	return tiles
+}
+
+// noteDebugGridMap remembers where Map drew tile (mapX, mapY) on screen, for
+// the debug grid.
+func noteDebugGridMap(mapX, mapY int, screenX, screenY float64) {
+	debugGridOriginX = screenX - float64(mapX*tileSize)
+	debugGridOriginY = screenY - float64(mapY*tileSize)
+	debugGridOriginSet = true
+}
+
+// drawDebugGrid draws the debug grid overlay over the finished frame. Without
+// a Map call this frame, it follows the camera the frame ended with.
+func drawDebugGrid() {
+	if debugGrid == DebugGridOff || currentScreen == nil {
+		return
+	}
+	originX, originY := -cameraX, -cameraY
+	if debugGridOriginSet {
+		originX, originY = debugGridOriginX, debugGridOriginY
+	}
+	debugGridOriginSet = false
+
+	drawOnScreen(func() {
+		drawGridAt(originX, originY, tileSize, debugGridColor)
+		if debugGrid == DebugGridCoords {
+			drawDebugTileCoords(originX, originY)
+		}
+	})
+}
+
+// drawGridAt draws grid lines every cell pixels over the screen, with a line
+// through the screen position (originX, originY).
+func drawGridAt(originX, originY float64, cell, color int) {
+	width, height := GetScreenWidth(), GetScreenHeight()
+	for _, x := range gridLines(originX, cell, width) {
+		Rectfill(x, 0, x, height-1, color)
+	}
+	for _, y := range gridLines(originY, cell, height) {
+		Rectfill(0, y, width-1, y, color)
+	}
+}
+
+// gridLines returns the screen positions in [0, length) of grid lines every
+// cell pixels, one of them at origin.
+func gridLines(origin float64, cell, length int) []int {
+	first := int(math.Floor(origin)) % cell
+	if first < 0 {
+		first += cell
+	}
+	lines := make([]int, 0, length/cell+1)
+	for p := first; p < length; p += cell {
+		lines = append(lines, p)
+	}
+	return lines
+}
+
+// debugGridTileAt returns the map tile at screen position (x, y) for a grid
+// with tile (0, 0) at the screen position (originX, originY).
+func debugGridTileAt(x, y int, originX, originY float64) (int, int) {
+	tx := int(math.Floor((float64(x) - math.Floor(originX)) / float64(tileSize)))
+	ty := int(math.Floor((float64(y) - math.Floor(originY)) / float64(tileSize)))
+	return tx, ty
+}
+
+// drawDebugTileCoords labels the columns along the top edge and the rows
+// along the left edge, every few tiles so the labels don't overlap, and shows
+// the coordinates of the tile under the mouse.
+func drawDebugTileCoords(originX, originY float64) {
+	width, height := GetScreenWidth(), GetScreenHeight()
+	labelHeight := int(defaultFontSize)
+	columnStep := max((3*int(CharWidthApproximation)+1+tileSize-1)/tileSize, 1)
+	rowStep := max((labelHeight+1+tileSize-1)/tileSize, 1)
+
+	for _, x := range gridLines(originX, tileSize, width) {
+		tx, _ := debugGridTileAt(x, 0, originX, originY)
+		if tx%columnStep == 0 {
+			drawDebugLabel(strconv.Itoa(tx), x+1, 1)
+		}
+	}
+	for _, y := range gridLines(originY, tileSize, height) {
+		_, ty := debugGridTileAt(0, y, originX, originY)
+		if ty%rowStep == 0 && y > labelHeight {
+			drawDebugLabel(strconv.Itoa(ty), 1, y+1)
+		}
+	}
+
+	mx, my := GetMouseXY()
+	if mx < 0 || mx >= width || my < 0 || my >= height {
+		return
+	}
+	tx, ty := debugGridTileAt(mx, my, originX, originY)
+	x := int(math.Floor(originX)) + tx*tileSize
+	y := int(math.Floor(originY)) + ty*tileSize
+	Rect(x, y, x+tileSize-1, y+tileSize-1, 7)
+	drawDebugLabel(strconv.Itoa(tx)+","+strconv.Itoa(ty), 1, height-labelHeight-2)
+}
+
+// drawDebugLabel prints text at (x, y) over a black box.
+func drawDebugLabel(text string, x, y int) {
+	width := len(text) * int(CharWidthApproximation)
+	Rectfill(x-1, y-1, x+width-1, y+int(defaultFontSize)-1, 0)
+	Print(text, x, y, 7)
+}
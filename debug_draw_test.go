@@ -0,0 +1,58 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGridLines(t *testing.T) {
+	assert.Equal(t, []int{0, 8, 16}, gridLines(0, 8, 20))
+	assert.Equal(t, []int{3, 11, 19}, gridLines(-13, 8, 20), "a camera at x=13 puts world x=16 at screen x=3")
+	assert.Equal(t, []int{2, 12}, gridLines(22, 10, 20), "lines left of the origin are drawn too")
+	assert.Equal(t, []int{7, 15}, gridLines(-0.5, 8, 20), "fractional origins round down like the map")
+}
+
+func TestDebugGridTileAt(t *testing.T) {
+	tx, ty := debugGridTileAt(0, 0, 0, 0)
+	assert.Equal(t, [2]int{0, 0}, [2]int{tx, ty})
+
+	// Camera(13, 20): tile (0, 0) is at screen (-13, -20)
+	tx, ty = debugGridTileAt(2, 3, -13, -20)
+	assert.Equal(t, [2]int{1, 2}, [2]int{tx, ty})
+	tx, ty = debugGridTileAt(3, 4, -13, -20)
+	assert.Equal(t, [2]int{2, 3}, [2]int{tx, ty})
+
+	// Map(2, 1, 40, 0) draws tile (2, 1) at screen (40, 0)
+	t.Cleanup(func() { debugGridOriginSet = false })
+	noteDebugGridMap(2, 1, 40, 0)
+	tx, ty = debugGridTileAt(40, 0, debugGridOriginX, debugGridOriginY)
+	assert.Equal(t, [2]int{2, 1}, [2]int{tx, ty})
+	tx, ty = debugGridTileAt(39, 0, debugGridOriginX, debugGridOriginY)
+	assert.Equal(t, [2]int{1, 1}, [2]int{tx, ty})
+}
+
+func TestSetDebugGrid(t *testing.T) {
+	t.Cleanup(func() { SetDebugGrid(DebugGridOff) })
+	assert.Equal(t, DebugGridOff, DebugGrid())
+
+	SetDebugGrid(DebugGridCoords)
+	assert.Equal(t, DebugGridCoords, DebugGrid())
+	SetDebugGrid(DebugGridMode(7))
+	assert.Equal(t, DebugGridOff, DebugGrid(), "unknown modes turn the grid off")
+}
+
+func TestCollisionDebugTiles(t *testing.T) {
+	useTestLayerSprites(t)
+	t.Cleanup(func() { Camera() })
+	Mset(1, 1, 4) // Flag 0
+	Mset(2, 1, 1) // Flag 4
+	Mset(3, 1, 3) // Flags 4 and 6
+	Mset(20, 1, 4)
+
+	assert.Equal(t, [][2]int{{1, 1}}, collisionDebugTiles(Flag0), "only the tiles on screen")
+	assert.Equal(t, [][2]int{{2, 1}, {3, 1}}, collisionDebugTiles(Flag4))
+
+	Camera(150, 0)
+	assert.Equal(t, [][2]int{{20, 1}}, collisionDebugTiles(Flag0), "the tiles follow the camera")
+}
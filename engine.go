@@ -2,9 +2,12 @@ package pigo8
 
 import (
 	"fmt"
+	"image/color"
 	"log"
 	"os"
 	"runtime"
+	"sync"
+	"time"
 
 	"github.com/drpaneas/pigo8/network"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -25,29 +28,50 @@ const (
 
 // Settings defines configurable parameters for the PIGO8 console.
 type Settings struct {
-	ScaleFactor  int               // Integer scaling factor for the window (Default: 4).
-	WindowTitle  string            // Title displayed on the window bar (Default: "PIGO-8 Game").
-	TargetFPS    int               // Target ticks per second (Default: 30).
-	ScreenWidth  int               // Custom screen width (Default: 128 for PICO-8 compatibility).
-	ScreenHeight int               // Custom screen height (Default: 128 for PICO-8 compatibility).
-	Multiplayer  bool              // Enable multiplayer networking (Default: false).
-	Fullscreen   bool              // Start the game in fullscreen mode (Default: false).
-	ColorSpace   ebiten.ColorSpace // Color space for rendering (Default: ColorSpaceDefault).
-	DisableHiDPI bool              // Disable HiDPI scaling (Default: false).
+	ScaleFactor      int               // Integer scaling factor for the window (Default: 4).
+	WindowTitle      string            // Title displayed on the window bar (Default: "PIGO-8 Game").
+	TargetFPS        int               // Target ticks per second (Default: 30).
+	ScreenWidth      int               // Custom screen width (Default: 128 for PICO-8 compatibility).
+	ScreenHeight     int               // Custom screen height (Default: 128 for PICO-8 compatibility).
+	Multiplayer      bool              // Enable multiplayer networking (Default: false).
+	Fullscreen       bool              // Start the game in fullscreen mode (Default: false).
+	ColorSpace       ebiten.ColorSpace // Color space for rendering (Default: ColorSpaceDefault).
+	DisableHiDPI     bool              // Disable HiDPI scaling (Default: false).
+	ScaleMode        ScaleMode         // How the screen is scaled to the window (Default: ScaleFit).
+	Resizable        bool              // Let the user resize and maximize the window (Default: false).
+	Headless         bool              // Run Init and Update without a window or drawing, e.g. for servers (Default: false).
+	WatchAssets      bool              // Reload spritesheet.json, map.json and palette.hex when they change on disk (Default: false).
+	MapChunkSize     int               // Width and height in tiles of the chunks of a streamed map, see SetMapChunkProvider (Default: 16).
+	MapChunkMargin   int               // Chunks kept loaded around the screen for a streamed map (Default: 1).
+	StrictAssets     bool              // Exit when a spritesheet needed by Spr and friends fails to load, instead of drawing nothing (Default: false).
+	BorderColor      color.Color       // Color of the bars around the screen when it doesn't fill the window, see SetBorderColor (Default: nil, black).
+	NetworkDebug     bool              // Draw the network debug overlay over the game, see SetNetworkDebug (Default: false).
+	DebugControls    bool              // Enable the debug keys that pause, step and slow down the game logic, see SetDebugControls (Default: false).
+	DebugGrid        DebugGridMode     // Draw the map's tile grid over the game, see SetDebugGrid (Default: DebugGridOff).
+	TileSize         int               // Width and height in pixels of a sprite cell and a map tile, see SetTileSize (Default: 8).
+	SubPixelSprites  bool              // Draw Spr and Sspr at fractional positions instead of rounding them, see SetSubPixelSprites (Default: false).
+	PauseAudio       PauseAudio        // What happens to music and sound effects while the pause menu is open, see SetPauseAudio (Default: PauseAudioKeep).
+	CollisionDebug   bool              // Warn when MapCollision checks areas far outside the map, a sign of screen coordinates, see SetCollisionDebug (Default: false).
+	DevConsole       bool              // Enable the developer console that edits the variables registered with RegisterVar, see SetDevConsole (Default: false).
+	CommandLineFlags bool              // Parse the -seed, -record and -replay flags, see RegisterFlags (Default: false).
 }
 
 // NewSettings creates a new Settings object with default values.
 func NewSettings() *Settings {
 	return &Settings{
-		ScaleFactor:  4,
-		WindowTitle:  "PIGO-8 Game",
-		TargetFPS:    30,
-		ScreenWidth:  defaultViewportWidth,  // Default PICO-8 width
-		ScreenHeight: defaultViewportHeight, // Default PICO-8 height
-		Multiplayer:  false,                 // Networking disabled by default
-		Fullscreen:   false,                 // Windowed mode by default
-		ColorSpace:   ebiten.ColorSpaceDefault,
-		DisableHiDPI: true, // Better performance for retro-style games
+		ScaleFactor:    4,
+		WindowTitle:    "PIGO-8 Game",
+		TargetFPS:      30,
+		ScreenWidth:    defaultViewportWidth,  // Default PICO-8 width
+		ScreenHeight:   defaultViewportHeight, // Default PICO-8 height
+		Multiplayer:    false,                 // Networking disabled by default
+		Fullscreen:     false,                 // Windowed mode by default
+		ColorSpace:     ebiten.ColorSpaceDefault,
+		DisableHiDPI:   true, // Better performance for retro-style games
+		ScaleMode:      ScaleFit,
+		MapChunkSize:   defaultMapChunkSize,
+		MapChunkMargin: defaultMapChunkMargin,
+		TileSize:       defaultTileSize,
 	}
 }
 
@@ -79,6 +103,8 @@ var (
 	currentDrawColor int           // Internal: Current draw color (0-15)
 	elapsedTime      float64       // Internal: Time elapsed since game start (in seconds)
 	timeIncrement    float64       // Internal: Amount to increment time each update
+	frameCount       int           // Internal: Number of Updates run since game start
+	startWallTime    time.Time     // Internal: Wall-clock time the game started, for RealT
 )
 
 // --- Cartridge Definition and Loading ---
@@ -113,10 +139,67 @@ func InsertGame(cartridge Cartridge) {
 
 // CurrentCartridge returns the currently loaded cartridge.
 // This is useful for accessing the game state from network callbacks.
+// CartridgeAs returns it as your own game type instead.
 func CurrentCartridge() Cartridge {
 	return loadedCartridge
 }
 
+// CartridgeAs returns the currently loaded cartridge as type T, usually a
+// pointer to your game struct, and false if the cartridge is of another type.
+//
+// Example:
+//
+//	if game, ok := CartridgeAs[*Game](); ok {
+//		game.score++
+//	}
+func CartridgeAs[T Cartridge]() (T, bool) {
+	game, ok := loadedCartridge.(T)
+	return game, ok
+}
+
+// CartridgeHandler adapts fn to a network game state or player input
+// callback that is called with the loaded cartridge as type T. If the
+// cartridge is of another type, it logs a warning and fn isn't called, so the
+// callbacks don't each need their own type assertion.
+//
+// Example:
+//
+//	p8net.SetOnPlayerInputCallback(CartridgeHandler(func(game *Game, playerID string, data []byte) {
+//		game.applyInput(playerID, data)
+//	}))
+func CartridgeHandler[T Cartridge](fn func(game T, playerID string, data []byte)) func(playerID string, data []byte) {
+	return func(playerID string, data []byte) {
+		if game, ok := cartridgeFor[T](); ok {
+			fn(game, playerID, data)
+		}
+	}
+}
+
+// CartridgePlayerHandler is CartridgeHandler for the network connect and
+// disconnect callbacks.
+//
+// Example:
+//
+//	p8net.SetOnConnectCallback(CartridgePlayerHandler(func(game *Game, playerID string) {
+//		game.remotePlayerID = playerID
+//	}))
+func CartridgePlayerHandler[T Cartridge](fn func(game T, playerID string)) func(playerID string) {
+	return func(playerID string) {
+		if game, ok := cartridgeFor[T](); ok {
+			fn(game, playerID)
+		}
+	}
+}
+
+// cartridgeFor is CartridgeAs with a warning when the type doesn't match.
+func cartridgeFor[T Cartridge]() (T, bool) {
+	game, ok := CartridgeAs[T]()
+	if !ok {
+		log.Printf("Warning: current cartridge is %T, not %T", loadedCartridge, game)
+	}
+	return game, ok
+}
+
 // --- Internal Ebiten Game Implementation ---
 
 // game is the internal struct that satisfies ebiten.Game interface.
@@ -127,10 +210,14 @@ type game struct {
 	// Pause menu state
 	paused        bool
 	pauseSelected int
+
+	initReason RestartReason // Passed to the next Init, see RestartGame
 }
 
 // Layout implements ebiten.Game.
-func (g *game) Layout(_, _ int) (int, int) {
+func (g *game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	applyPendingResolution()
+	trackWindowSize(outsideWidth, outsideHeight)
 	w := screenWidth
 	h := screenHeight
 	if w <= 0 {
@@ -139,31 +226,51 @@ func (g *game) Layout(_, _ int) (int, int) {
 	if h <= 0 {
 		h = defaultViewportHeight // fallback to 128
 	}
+
+	// With ScaleInteger, ScaleStretch or a custom border the engine scales the
+	// screen itself, so it needs a screen image the size of the window
+	if engineScales() && outsideWidth > 0 && outsideHeight > 0 {
+		currentViewport = computeViewport(scaleMode, outsideWidth, outsideHeight, w, h)
+		return outsideWidth, outsideHeight
+	}
 	return w, h
 }
 
-// Restart is a flag that indicates if the game should be restarted
+// Restart is set to true whenever the game is restarted, and is never
+// cleared by the engine.
+//
+// Deprecated: Check GetRestartReason in Init instead, which needs no clearing.
 var Restart bool
 
-// ResetGame fully resets the game state
-func (g *game) ResetGame() {
-	log.Println("Resetting game...")
+// ResetGame fully resets the game state; Init runs again on the next frame
+func (g *game) ResetGame(reason RestartReason) {
 	// Reset all game state
 	g.initialized = false
 	g.firstFrameDrawn = false
 	g.paused = false
 	g.pauseSelected = EngPauseOptionContinue
-	// Reset any other engine state here if needed
-	Restart = true
+	g.initReason = reason
+	syncPauseAudio(false)
+	prepareRestart(reason)
 }
 
 // Update implements ebiten.Game.
 func (g *game) Update() error {
+	if stopRequested {
+		stopRequested = false
+		return ebiten.Termination
+	}
+	if restartRequested {
+		g.ResetGame(RestartByGame)
+	}
+	beginUpdateTiming()
+	defer endUpdateTiming()
+
 	if !g.initialized {
 		log.Println("Cartridge Initializing...")
 		// Log initial memory usage
 		logInitialMemory()
-		loadedCartridge.Init()
+		initCartridge(loadedCartridge, g.initReason)
 		g.initialized = true
 		// Don't call Update on the first frame, wait for Draw to be called first
 		return nil
@@ -171,9 +278,23 @@ func (g *game) Update() error {
 
 	// Only call Update after the first frame has been drawn
 	if g.firstFrameDrawn {
+		notifyResolutionChange()
+		notifyWindowResize()
+		applyAssetReloads()
+		applyPreloads()
 		updateConnectedGamepads()
 		updateMouseState()
-		updateInputCache() // Update input cache for this frame
+		updateTextInput()
+		updateConsole()
+		handleDebugKeys()
+		updateScreenFlash()
+		if !g.paused && (!simulationFrameDue() || consumeHitStopFrame()) {
+			// Paused or slowed by the debug controls, or frozen by HitStop:
+			// Draw keeps showing the frozen frame, and input waits for the
+			// next frame that runs
+			return nil
+		}
+		updateInputFrame() // Update input cache for this frame, from a replay if one plays
 
 		// Check for START button press to toggle pause menu
 		if Btnp(ButtonStart) {
@@ -215,20 +336,22 @@ func (g *game) Update() error {
 					g.paused = false
 				case EngPauseOptionReset:
 					// Reset the game
-					g.ResetGame()
+					g.ResetGame(RestartByPauseMenu)
 					// The next frame will trigger initialization
 				case EngPauseOptionExit:
 					// Exit the game immediately
 					fmt.Println("Exiting application...")
+					saveRecording()
 					os.Exit(0) // This should immediately terminate the program
 				}
 			}
 		} else {
 			// Only update game logic when not paused
 			loadedCartridge.Update()
-			// Update elapsed time
-			elapsedTime += timeIncrement
+			// Update elapsed time and the frame counter
+			advanceClock()
 		}
+		syncPauseAudio(g.paused)
 	}
 
 	return nil
@@ -236,8 +359,10 @@ func (g *game) Update() error {
 
 // Draw implements ebiten.Game.
 func (g *game) Draw(screen *ebiten.Image) {
+	beginDrawTiming()
+
 	// Set the current screen for drawing
-	currentScreen = screen
+	currentScreen = beginScaledFrame(screen)
 
 	// Initialize pixel buffer if needed
 	if pixelBuffer == nil {
@@ -252,12 +377,22 @@ func (g *game) Draw(screen *ebiten.Image) {
 	// Clear the screen
 	// screen.Clear()
 
-	// Call the user's Draw function
+	// Call the user's Draw function, then the draws it queued with DrawAtLayer
 	loadedCartridge.Draw()
+	flushDrawLayers()
+	endUIFrame()
 
 	// Flush all pending pixel operations at the end of the frame
 	flushPixelBuffer()
 	flushSpriteModifications()
+	drawScreenFlash()
+	drawDebugGrid()
+
+	if networkDebug {
+		DrawNetworkDebug()
+	}
+	drawDebugControls()
+	drawConsole()
 
 	// Draw pause menu on top if active
 	if g.paused {
@@ -295,10 +430,15 @@ func (g *game) Draw(screen *ebiten.Image) {
 		flushSpriteModifications()
 	}
 
+	// Scale the finished frame into the window
+	endScaledFrame(screen, currentScreen)
+
 	// Mark that the first frame has been drawn
 	if !g.firstFrameDrawn {
 		g.firstFrameDrawn = true
 	}
+
+	endDrawTiming()
 }
 
 // --- Helper for User Code ---
@@ -320,6 +460,11 @@ func CurrentScreen() *ebiten.Image {
 // Time returns the number of seconds (as a decimal) since the game started.
 // This is calculated by counting the number of times the Update method is called.
 // Multiple calls to Time() in the same frame will return the same value.
+//
+// Like Frame, it only advances when the cartridge's Update runs, so it stops
+// while the pause menu is open or the debug controls pause the game, and
+// timers and animations based on it pause with the game. Use RealT for
+// wall-clock time, and SetPauseAudio to choose whether the sound pauses too.
 func Time() float64 {
 	return elapsedTime
 }
@@ -329,6 +474,53 @@ func T() float64 {
 	return Time()
 }
 
+// Frame returns the number of game frames since the game started: it goes up
+// by exactly one every time the cartridge's Update runs, and never resets
+// while the game runs (it restarts from 0 only when a new game is started).
+// It does not advance while the pause menu is open.
+//
+// Frame is deterministic, so use it for animation and logic that must play
+// out the same way every time, e.g. replays and netcode. Time() is simply
+// Frame() times the fixed time step (1/TargetFPS).
+//
+// Example:
+//
+//	// Swap between two sprites every 5 frames
+//	sprite := 1 + (Frame()/5)%2
+//	Spr(sprite, x, y)
+func Frame() int {
+	return frameCount
+}
+
+// RealT returns the wall-clock seconds since the game started. Unlike T(),
+// it keeps running while the game is paused or when frames are dropped, so
+// use it only for effects that must follow real time, never for game logic.
+func RealT() float64 {
+	if startWallTime.IsZero() {
+		return 0
+	}
+	return time.Since(startWallTime).Seconds()
+}
+
+// advanceClock moves the game clock and the started tweens forward by one Update.
+func advanceClock() {
+	DispatchEvents()
+	elapsedTime += timeIncrement
+	frameCount++
+	updateTweens()
+	updateTimelines()
+	updateRumble()
+	updateCameraFollow()
+	updateMapChunks()
+}
+
+// resetClock starts the game clock from zero.
+func resetClock() {
+	elapsedTime = 0
+	frameCount = 0
+	startWallTime = time.Now()
+}
+
 // --- Play Functions ---
 
 // logInitialMemory logs the initial memory usage of the PIGO-8 console
@@ -348,6 +540,9 @@ func PlayGameWith(settings *Settings) {
 		cfg = NewSettings()
 	}
 
+	// The network flags are parsed with the others when networking starts
+	setupCommandLineFlags(cfg, cfg.Multiplayer && !network.IsNetworkInitialized())
+
 	// Only initialize networking if multiplayer is enabled
 	if cfg.Multiplayer {
 		// Check if network is already initialized
@@ -374,7 +569,9 @@ func PlayGameWith(settings *Settings) {
 	}
 
 	// Reset time tracking variables
-	elapsedTime = 0.0
+	resetClock()
+	applyCommandLineFlags()
+	defer saveRecording()
 
 	// Update logical screen dimensions if custom values are provided
 	width := defaultViewportWidth
@@ -406,8 +603,30 @@ func PlayGameWith(settings *Settings) {
 		winWidth, winHeight = defaultViewportWidth, defaultViewportHeight
 	}
 
+	// Remember the scale so SetResolution can resize the window
+	windowScaleFactor = cfg.ScaleFactor
+	SetScaleMode(cfg.ScaleMode)
+	SetBorderColor(cfg.BorderColor)
+	SetNetworkDebug(cfg.NetworkDebug)
+	SetDebugControls(cfg.DebugControls)
+	SetDebugGrid(cfg.DebugGrid)
+	SetTileSize(cfg.TileSize)
+	SetSubPixelSprites(cfg.SubPixelSprites)
+	SetPauseAudio(cfg.PauseAudio)
+	SetCollisionDebug(cfg.CollisionDebug)
+	SetDevConsole(cfg.DevConsole)
+
 	// Calculate time increment based on target FPS
 	timeIncrement = 1.0 / float64(cfg.TargetFPS)
+	setMapChunkLayout(cfg.MapChunkSize, cfg.MapChunkMargin)
+	strictAssets = cfg.StrictAssets
+
+	// Dedicated servers and tests run the game logic only
+	if cfg.Headless {
+		runHeadless(cfg.TargetFPS)
+		log.Println("PIGO8 console shutdown.")
+		return
+	}
 
 	internalGame := &game{
 		initialized: false,
@@ -418,11 +637,22 @@ func PlayGameWith(settings *Settings) {
 	ebiten.SetWindowSize(winWidth, winHeight)
 	ebiten.SetTPS(cfg.TargetFPS)
 
+	// Let the user resize the window; the screen is rescaled according to ScaleMode
+	if cfg.Resizable {
+		ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	}
+
 	// Set fullscreen mode if enabled
 	if cfg.Fullscreen {
 		ebiten.SetFullscreen(true)
 	}
 
+	// Pick up asset edits, e.g. from the editor, while the game runs
+	if cfg.WatchAssets {
+		startAssetWatcher()
+		defer stopAssetWatcher()
+	}
+
 	// Use RunGameOptions for new v2.8 features
 	opts := &ebiten.RunGameOptions{
 		ColorSpace:   cfg.ColorSpace,
@@ -430,7 +660,9 @@ func PlayGameWith(settings *Settings) {
 	}
 
 	log.Println("Booting PIGO8 console...")
+	gameRunning = true
 	err := ebiten.RunGameWithOptions(internalGame, opts)
+	gameRunning = false
 	if err != nil {
 		log.Panicf("pico8.PlayGameWith: Ebitengine loop failed: %v", err)
 	}
@@ -453,3 +685,101 @@ func GetScreenWidth() int {
 func GetScreenHeight() int {
 	return screenHeight
 }
+
+// --- Resolution ---
+
+var (
+	resolutionMutex    sync.Mutex
+	pendingResolution  [2]int // Requested width and height, zero when nothing is pending
+	resolutionChanged  bool   // The logical size changed and OnResolutionChange has not been told yet
+	onResolutionChange func(width, height int)
+	windowScaleFactor  = 4  // ScaleFactor from the Settings passed to PlayGameWith
+	gameRunning        bool // True while PlayGameWith is running the Ebitengine loop
+)
+
+// SetResolution changes the logical screen size while the game is running,
+// e.g. to switch between 128x128 and a widescreen mode from an options menu.
+//
+// Whatever is being drawn in the current frame finishes at the old size. The
+// new size takes effect at the start of the next frame: the screen, the pixel
+// buffers behind Pset/Pget and the map cache are recreated (so the screen
+// starts out cleared), and the window is resized to keep the ScaleFactor
+// from Settings. Before PlayGameWith, set Settings.ScreenWidth and
+// Settings.ScreenHeight instead.
+//
+// Args:
+//   - width, height: the new logical screen size in pixels (must be positive)
+//
+// Example:
+//
+//	if Btnp(X) {
+//		if GetScreenWidth() == 128 {
+//			SetResolution(240, 136)
+//		} else {
+//			SetResolution(128, 128)
+//		}
+//	}
+func SetResolution(width, height int) {
+	if width <= 0 || height <= 0 {
+		log.Printf("Warning: SetResolution() called with invalid size %dx%d. Ignoring.", width, height)
+		return
+	}
+
+	resolutionMutex.Lock()
+	pendingResolution = [2]int{width, height}
+	resolutionMutex.Unlock()
+
+	if gameRunning && !ebiten.IsFullscreen() && windowScaleFactor > 0 {
+		ebiten.SetWindowSize(width*windowScaleFactor, height*windowScaleFactor)
+	}
+}
+
+// SetOnResolutionChange registers a function that is called after the screen
+// size changed through SetResolution, before the next Update, so games can
+// lay out their UI again. Pass nil to remove it.
+//
+// Example:
+//
+//	SetOnResolutionChange(func(width, height int) {
+//		hudX = width - 40
+//	})
+func SetOnResolutionChange(fn func(width, height int)) {
+	resolutionMutex.Lock()
+	defer resolutionMutex.Unlock()
+	onResolutionChange = fn
+}
+
+// applyPendingResolution switches to the size requested with SetResolution.
+// It runs from Layout, between frames, so no frame is drawn with buffers of
+// the wrong size.
+func applyPendingResolution() {
+	resolutionMutex.Lock()
+	defer resolutionMutex.Unlock()
+
+	width, height := pendingResolution[0], pendingResolution[1]
+	if width <= 0 || height <= 0 {
+		return
+	}
+	pendingResolution = [2]int{}
+	if width == screenWidth && height == screenHeight {
+		return
+	}
+
+	setScreenSize(width, height)
+	mapCacheIsValid = false
+	resolutionChanged = true
+	log.Printf("Resolution changed to %dx%d", width, height)
+}
+
+// notifyResolutionChange calls the OnResolutionChange callback once per change.
+func notifyResolutionChange() {
+	resolutionMutex.Lock()
+	fn := onResolutionChange
+	changed := resolutionChanged
+	resolutionChanged = false
+	resolutionMutex.Unlock()
+
+	if changed && fn != nil {
+		fn(screenWidth, screenHeight)
+	}
+}
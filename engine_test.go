@@ -17,3 +17,99 @@ func TestNewSettings(t *testing.T) {
 
 // --- Add tests for PlayGameWith, InsertGame etc. if needed ---
 // (Though these often require more integration-style testing)
+
+func TestSetResolution(t *testing.T) {
+	originalW, originalH := screenWidth, screenHeight
+	t.Cleanup(func() {
+		setScreenSize(originalW, originalH)
+		SetOnResolutionChange(nil)
+	})
+	setScreenSize(128, 128)
+
+	var calls [][2]int
+	SetOnResolutionChange(func(width, height int) {
+		calls = append(calls, [2]int{width, height})
+	})
+
+	g := &game{}
+	SetResolution(240, 136)
+	assert.Equal(t, 128, GetScreenWidth(), "the size only changes between frames")
+
+	w, h := g.Layout(0, 0)
+	assert.Equal(t, 240, w)
+	assert.Equal(t, 136, h)
+	assert.Equal(t, 240, GetScreenWidth())
+	assert.Equal(t, 136, GetScreenHeight())
+	assert.Len(t, pixelBuffer, 240*136*4, "the pixel buffer follows the new size")
+	width, height, _, _ := GetScreenPixelCacheStats()
+	assert.Equal(t, 240, width)
+	assert.Equal(t, 136, height)
+
+	notifyResolutionChange()
+	notifyResolutionChange()
+	assert.Equal(t, [][2]int{{240, 136}}, calls, "the callback fires once per change")
+
+	SetResolution(0, 10)
+	g.Layout(0, 0)
+	assert.Equal(t, 240, GetScreenWidth(), "invalid sizes are ignored")
+}
+
+func TestFrameCounter(t *testing.T) {
+	originalTime, originalIncrement, originalFrame := elapsedTime, timeIncrement, frameCount
+	t.Cleanup(func() {
+		elapsedTime, timeIncrement, frameCount = originalTime, originalIncrement, originalFrame
+	})
+
+	timeIncrement = 1.0 / 30
+	resetClock()
+	assert.Equal(t, 0, Frame())
+	assert.GreaterOrEqual(t, RealT(), 0.0)
+
+	for i := 0; i < 90; i++ {
+		advanceClock()
+	}
+	assert.Equal(t, 90, Frame(), "every Update should advance the frame counter by one")
+	assert.InDelta(t, 3.0, T(), 1e-9, "T() should be Frame() times the time step")
+}
+
+func TestCartridgeAs(t *testing.T) {
+	original := CurrentCartridge()
+	t.Cleanup(func() { InsertGame(original) })
+
+	cart := &countingCartridge{}
+	InsertGame(cart)
+	got, ok := CartridgeAs[*countingCartridge]()
+	assert.True(t, ok)
+	assert.Same(t, cart, got)
+
+	_, ok = CartridgeAs[*emptyCartridge]()
+	assert.False(t, ok)
+}
+
+func TestCartridgeHandlers(t *testing.T) {
+	original := CurrentCartridge()
+	t.Cleanup(func() { InsertGame(original) })
+
+	var calls []string
+	onInput := CartridgeHandler(func(game *countingCartridge, playerID string, data []byte) {
+		game.updates++
+		calls = append(calls, playerID+":"+string(data))
+	})
+	onConnect := CartridgePlayerHandler(func(game *countingCartridge, playerID string) {
+		game.inits++
+		calls = append(calls, playerID)
+	})
+
+	InsertGame(&emptyCartridge{})
+	onInput("p1", []byte("up"))
+	onConnect("p1")
+	assert.Empty(t, calls, "handlers are skipped for another cartridge type")
+
+	cart := &countingCartridge{}
+	InsertGame(cart)
+	onConnect("p1")
+	onInput("p1", []byte("up"))
+	assert.Equal(t, []string{"p1", "p1:up"}, calls)
+	assert.Equal(t, 1, cart.inits)
+	assert.Equal(t, 1, cart.updates)
+}
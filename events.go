@@ -0,0 +1,135 @@
+package pigo8
+
+import (
+	"log"
+	"slices"
+)
+
+// --- Event bus ---
+
+// maxEventsPerDispatch stops handlers that keep publishing events from
+// hanging the game: events past it are dropped with a warning.
+const maxEventsPerDispatch = 10000
+
+// eventSubscription is a handler subscribed to an event with Subscribe.
+type eventSubscription struct {
+	event   string
+	handler func(payload any)
+	active  bool // false once unsubscribed
+}
+
+// queuedEvent is an event published with Publish, waiting to be dispatched.
+type queuedEvent struct {
+	event   string
+	payload any
+}
+
+var (
+	// eventSubscriptions are the handlers, in subscription order.
+	eventSubscriptions []*eventSubscription
+	// eventQueue holds the events published since the last dispatch.
+	eventQueue []queuedEvent
+)
+
+// Subscribe calls handler with the payload of every event published under
+// the name event, and returns a function that unsubscribes it. Handlers of
+// an event run in the order they subscribed. Subscriptions are dropped when
+// the game restarts, since Init runs again and subscribes anew.
+//
+// Together with Publish it lets systems react to each other without direct
+// references: collision code publishes "enemy_killed", and the score, the
+// sound and the particles each subscribe to it.
+//
+// Example:
+//
+//	func (g *Game) Init() {
+//		Subscribe("enemy_killed", func(payload any) {
+//			g.score += payload.(int)
+//		})
+//	}
+func Subscribe(event string, handler func(payload any)) (unsubscribe func()) {
+	sub := &eventSubscription{event: event, handler: handler, active: true}
+	eventSubscriptions = append(eventSubscriptions, sub)
+	return func() {
+		if !sub.active {
+			return
+		}
+		sub.active = false
+		for i, s := range eventSubscriptions {
+			if s == sub {
+				eventSubscriptions = append(eventSubscriptions[:i], eventSubscriptions[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// SubscribeTo is Subscribe for handlers that take the payload as its own
+// type, so they don't each repeat the type assertion. Payloads of another
+// type are skipped with a warning.
+//
+// Example:
+//
+//	type Hit struct{ X, Y, Points int }
+//
+//	SubscribeTo("hit", func(h Hit) {
+//		g.score += h.Points
+//	})
+//	Publish("hit", Hit{X: 10, Y: 20, Points: 50})
+func SubscribeTo[T any](event string, handler func(payload T)) (unsubscribe func()) {
+	return Subscribe(event, func(payload any) {
+		typed, ok := payload.(T)
+		if !ok {
+			var zero T
+			log.Printf("Warning: event %q has a payload of type %T, but the handler expects %T. Skipped.", event, payload, zero)
+			return
+		}
+		handler(typed)
+	})
+}
+
+// Publish queues an event with a payload (nil if there is none). The engine
+// dispatches the queued events in the order they were published after every
+// Update, before tweens and the camera move, so every handler sees the same
+// frame. Events published by handlers are dispatched in the same pass.
+//
+// Example:
+//
+//	if collide(bullet, enemy) {
+//		Publish("enemy_killed", enemy.points)
+//	}
+func Publish(event string, payload any) {
+	eventQueue = append(eventQueue, queuedEvent{event, payload})
+}
+
+// DispatchEvents runs the handlers of the queued events now, instead of
+// waiting for the end of Update. It is useful in tests and for events
+// published in Draw.
+func DispatchEvents() {
+	// Handlers may publish more events, which are appended to the queue
+	for i := 0; i < len(eventQueue); i++ {
+		if i == maxEventsPerDispatch {
+			log.Printf("Warning: DispatchEvents() dropped %d events after dispatching %d in one frame. Do handlers keep publishing events?", len(eventQueue)-i, maxEventsPerDispatch)
+			break
+		}
+		e := eventQueue[i]
+
+		// Handlers may also subscribe or unsubscribe while the event runs
+		for _, sub := range slices.Clone(eventSubscriptions) {
+			if sub.active && sub.event == e.event {
+				sub.handler(e.payload)
+			}
+		}
+	}
+	clear(eventQueue)
+	eventQueue = eventQueue[:0]
+}
+
+// resetEvents drops every subscription and queued event.
+func resetEvents() {
+	for _, sub := range eventSubscriptions {
+		sub.active = false
+	}
+	eventSubscriptions = nil
+	eventQueue = nil
+}
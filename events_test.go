@@ -0,0 +1,116 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// useTestEvents starts a test with no subscriptions or queued events.
+func useTestEvents(t *testing.T) {
+	t.Helper()
+	t.Cleanup(resetEvents)
+	resetEvents()
+}
+
+func TestPublishRunsHandlersInOrder(t *testing.T) {
+	useTestEvents(t)
+	var calls []string
+	Subscribe("hit", func(p any) { calls = append(calls, "score "+p.(string)) })
+	Subscribe("miss", func(p any) { calls = append(calls, "miss "+p.(string)) })
+	Subscribe("hit", func(p any) { calls = append(calls, "sound "+p.(string)) })
+
+	Publish("hit", "a")
+	Publish("miss", "b")
+	Publish("hit", "c")
+	assert.Empty(t, calls, "events wait for the dispatch")
+
+	DispatchEvents()
+	assert.Equal(t, []string{"score a", "sound a", "miss b", "score c", "sound c"}, calls)
+
+	calls = nil
+	DispatchEvents()
+	assert.Empty(t, calls, "events are dispatched once")
+}
+
+func TestEventsPublishedByHandlers(t *testing.T) {
+	useTestEvents(t)
+	var calls []string
+	Subscribe("killed", func(any) {
+		calls = append(calls, "killed")
+		Publish("score", 10)
+	})
+	Subscribe("score", func(p any) { calls = append(calls, "score") })
+
+	Publish("killed", nil)
+	DispatchEvents()
+	assert.Equal(t, []string{"killed", "score"}, calls, "follow-up events run in the same dispatch")
+}
+
+func TestUnsubscribe(t *testing.T) {
+	useTestEvents(t)
+	count := 0
+	var second func()
+	Subscribe("e", func(any) {
+		count++
+		second() // Unsubscribing during the event skips the later handler
+	})
+	second = Subscribe("e", func(any) { count += 100 })
+
+	Publish("e", nil)
+	DispatchEvents()
+	assert.Equal(t, 1, count)
+
+	second() // Twice is harmless
+	Publish("e", nil)
+	DispatchEvents()
+	assert.Equal(t, 2, count)
+}
+
+func TestSubscribeTo(t *testing.T) {
+	useTestEvents(t)
+	type hit struct{ points int }
+	total := 0
+	SubscribeTo("hit", func(h hit) { total += h.points })
+
+	Publish("hit", hit{points: 5})
+	Publish("hit", "not a hit")
+	DispatchEvents()
+	assert.Equal(t, 5, total, "payloads of another type are skipped")
+}
+
+func TestDispatchEventsStopsRunawayHandlers(t *testing.T) {
+	useTestEvents(t)
+	count := 0
+	Subscribe("loop", func(any) {
+		count++
+		Publish("loop", nil)
+	})
+
+	Publish("loop", nil)
+	DispatchEvents()
+	assert.Equal(t, maxEventsPerDispatch, count)
+	assert.Empty(t, eventQueue, "the rest is dropped")
+}
+
+func TestEventsDispatchAfterUpdate(t *testing.T) {
+	useTestEvents(t)
+	originalCart := loadedCartridge
+	t.Cleanup(func() {
+		InsertGame(originalCart)
+		resetInputState()
+	})
+	h := NewTestHarness(&emptyCartridge{}, nil)
+	h.Init()
+
+	frames := []int{}
+	Subscribe("tick", func(any) { frames = append(frames, Frame()) })
+	Publish("tick", nil)
+	h.Step()
+	assert.Equal(t, []int{0}, frames, "handlers see the frame the event was published in")
+
+	prepareRestart(RestartByGame)
+	Publish("tick", nil)
+	h.Step()
+	assert.Equal(t, []int{0}, frames, "restarting drops subscriptions")
+}
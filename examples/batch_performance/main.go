@@ -15,12 +15,28 @@ type batchPerformanceDemo struct {
 	startTime  time.Time
 	mode       int // 0 = individual pixels, 1 = batch operations
 	readMode   int // 0 = individual reads, 1 = batch reads
+	sprites    [8]*p8.SpriteBatch
 }
 
 func (d *batchPerformanceDemo) Init() {
 	d.startTime = time.Now()
 	d.mode = 1     // Start with batch mode
 	d.readMode = 1 // Start with batch read mode
+	for i := range d.sprites {
+		d.sprites[i] = p8.NewSpriteBatch(i)
+	}
+	d.applyFlushMode()
+}
+
+// applyFlushMode defers pixel uploads in batch mode. Pixels are then uploaded
+// once per frame, or when FlushPixelCache is called, instead of before every
+// Spr and Print call.
+func (d *batchPerformanceDemo) applyFlushMode() {
+	if d.mode == 1 {
+		p8.SetPixelFlushMode(p8.FlushDeferred)
+	} else {
+		p8.SetPixelFlushMode(p8.FlushBeforeDraw)
+	}
 }
 
 func (d *batchPerformanceDemo) Update() {
@@ -32,6 +48,7 @@ func (d *batchPerformanceDemo) Update() {
 		d.readMode = 1 - d.readMode // Toggle read modes
 		d.startTime = time.Now()
 		d.frameCount = 0
+		d.applyFlushMode()
 	}
 }
 
@@ -48,10 +65,9 @@ func (d *batchPerformanceDemo) Draw() {
 	}
 
 	// Test batch reading operations
-	// Both modes currently do the same thing since Pget is optimized
+	// Pget sees the pixels above even though they are not uploaded yet
 	for y := 0; y < 64; y += 4 {
 		for x := 0; x < 64; x += 4 {
-			// Read pixels (optimized in both modes)
 			pixelColor := p8.Pget(x, y)
 			// Use the color for something (just to avoid compiler optimization)
 			if pixelColor > 8 {
@@ -60,11 +76,21 @@ func (d *batchPerformanceDemo) Draw() {
 		}
 	}
 
-	// Draw some animated sprites
+	// Upload the pattern now, so the sprites and text are drawn on top of it
+	p8.FlushPixelCache()
+
+	// Draw some animated sprites, with one batch per sprite in batch mode
 	for i := 0; i < 10; i++ {
 		x := (d.frameCount + i*10) % 120
 		y := 20 + i*8
-		p8.Spr(i%8, x, y)
+		if d.mode == 1 {
+			d.sprites[i%8].Add(float64(x), float64(y))
+		} else {
+			p8.Spr(i%8, x, y)
+		}
+	}
+	for _, batch := range d.sprites {
+		batch.Flush()
 	}
 
 	// Display performance info
@@ -81,7 +107,7 @@ func (d *batchPerformanceDemo) Draw() {
 	p8.Print("Performance Demo", 2, 2, 7)
 	p8.Print(fmt.Sprintf("Mode: %s", modeText), 2, 12, 7)
 	p8.Print(fmt.Sprintf("Reads: %s", readModeText), 2, 22, 7)
-	p8.Print(fmt.Sprintf("Frames: %d", d.frameCount), 2, 32, 7)
+	p8.Print(fmt.Sprintf("FPS: %.0f Frame: %.2fms Draws: %d", p8.GetFPS(), float64(p8.GetFrameTime().Microseconds())/1000, p8.GetDrawCallCount()), 2, 32, 7)
 	p8.Print(fmt.Sprintf("Time: %.1fs", time.Since(d.startTime).Seconds()), 2, 42, 7)
 
 	// Instructions
@@ -0,0 +1,103 @@
+// Package main shows the camera following a player with a dead zone,
+// smoothing and clamping to the map with some sky above it
+package main
+
+import (
+	"fmt"
+
+	p8 "github.com/drpaneas/pigo8"
+)
+
+const (
+	mapW, mapH = 48, 24 // Tiles
+	worldW     = mapW * 8
+	worldH     = mapH * 8
+	speed      = 2
+)
+
+type myGame struct {
+	x, y    float64
+	ellipse bool
+}
+
+func (m *myGame) Init() {
+	p8.SetMapSize(mapW, mapH)
+	m.x, m.y = 40, worldH-40
+	m.setOptions()
+}
+
+func (m *myGame) setOptions() {
+	shape := p8.DeadZoneRect
+	if m.ellipse {
+		shape = p8.DeadZoneEllipse
+	}
+	p8.SetCameraOptions(p8.CameraFollowOptions{
+		DeadZoneWidth:  40,
+		DeadZoneHeight: 30,
+		DeadZoneShape:  shape,
+		Lerp:           0.2,
+		ClampToMap:     true,
+		PaddingTop:     24, // Sky above the level
+	})
+}
+
+func (m *myGame) Update() {
+	if p8.Btn(p8.LEFT) {
+		m.x -= speed
+	}
+	if p8.Btn(p8.RIGHT) {
+		m.x += speed
+	}
+	if p8.Btn(p8.UP) {
+		m.y -= speed
+	}
+	if p8.Btn(p8.DOWN) {
+		m.y += speed
+	}
+	m.x = max(4, min(m.x, worldW-4))
+	m.y = max(4, min(m.y, worldH-4))
+	p8.SetCameraTarget(m.x, m.y)
+
+	if p8.Btnp(p8.O) {
+		m.ellipse = !m.ellipse
+		m.setOptions()
+	}
+	if p8.Btnp(p8.X) {
+		// Teleport to the other side of the level without scrolling there
+		m.x = worldW - m.x
+		p8.SetCameraTarget(m.x, m.y)
+		p8.SnapCamera()
+	}
+}
+
+func (m *myGame) Draw() {
+	p8.Cls(12) // Sky
+
+	// The level: a checkerboard of tiles with a border
+	for ty := 0; ty < mapH; ty++ {
+		for tx := 0; tx < mapW; tx++ {
+			col := 3
+			if (tx+ty)%2 == 0 {
+				col = 11
+			}
+			p8.Rectfill(tx*8, ty*8, tx*8+7, ty*8+7, col)
+		}
+	}
+	p8.Rect(0, 0, worldW-1, worldH-1, 0)
+	p8.Circfill(m.x, m.y, 3, 8)
+
+	p8.CameraPush()
+	p8.Camera()
+	shape := "rect"
+	if m.ellipse {
+		shape = "ellipse"
+	}
+	p8.Print(fmt.Sprintf("dead zone: %s (o)", shape), 2, 2, 7)
+	p8.Print("x: teleport", 2, 120, 7)
+	p8.CameraPop()
+}
+
+func main() {
+	p8.InsertGame(&myGame{})
+	p8.Play()
+}
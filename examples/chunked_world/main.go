@@ -0,0 +1,104 @@
+// Package main chunked world example scrolling through an endless, procedurally generated map
+package main
+
+import (
+	"fmt"
+
+	p8 "github.com/drpaneas/pigo8"
+)
+
+const (
+	chunkSize = 16
+	tileSize  = 8
+	speed     = 2
+	worldSeed = 2024
+)
+
+// Terrain colors, from deep water to mountain tops
+var terrain = []int{1, 12, 15, 11, 3, 5, 6, 7}
+
+type myGame struct {
+	camX, camY float64
+}
+
+// generateChunk is the map chunk provider: it computes the terrain of one
+// chunk from the world coordinates, so the world has no edges.
+func generateChunk(chunkX, chunkY int) []int {
+	tiles := make([]int, chunkSize*chunkSize)
+	for y := range chunkSize {
+		for x := range chunkSize {
+			// Octaves average out towards 0.5, so spread 0.2-0.8 over the terrain
+			h := height(chunkX*chunkSize+x, chunkY*chunkSize+y)
+			level := int((h - 0.2) / 0.6 * float64(len(terrain)))
+			tiles[y*chunkSize+x] = terrain[max(0, min(level, len(terrain)-1))]
+		}
+	}
+	return tiles
+}
+
+// height returns the terrain height of a world tile in [0, 1]: value noise
+// in 4 octaves, each twice as detailed and half as strong as the last. The
+// noise only depends on the tile and the seed, so chunks can be generated in
+// any order and the world is the same every time.
+func height(tx, ty int) float64 {
+	h, amp, freq, total := 0.0, 1.0, 0.04, 0.0
+	for range 4 {
+		h += amp * p8.Noise2D(float64(tx)*freq, float64(ty)*freq)
+		total += amp
+		amp, freq = amp/2, freq*2
+	}
+	return h / total
+}
+
+func (m *myGame) Init() {
+	p8.Srand(worldSeed)
+	p8.SetMapChunkProvider(generateChunk)
+}
+
+func (m *myGame) Update() {
+	if p8.Btn(p8.LEFT) {
+		m.camX -= speed
+	}
+	if p8.Btn(p8.RIGHT) {
+		m.camX += speed
+	}
+	if p8.Btn(p8.UP) {
+		m.camY -= speed
+	}
+	if p8.Btn(p8.DOWN) {
+		m.camY += speed
+	}
+
+	// Dig a hole with O: edits stay even after the chunk is evicted
+	if p8.Btnp(p8.O) {
+		p8.Mset(p8.Flr((m.camX+64)/tileSize), p8.Flr((m.camY+64)/tileSize), 0)
+	}
+}
+
+func (m *myGame) Draw() {
+	p8.Cls(0)
+	p8.Camera(m.camX, m.camY)
+
+	// Draw the visible tiles as colored squares
+	left, top := p8.Flr(m.camX/tileSize), p8.Flr(m.camY/tileSize)
+	for ty := top; ty <= top+p8.GetScreenHeight()/tileSize; ty++ {
+		for tx := left; tx <= left+p8.GetScreenWidth()/tileSize; tx++ {
+			x, y := tx*tileSize, ty*tileSize
+			p8.Rectfill(x, y, x+tileSize-1, y+tileSize-1, p8.Mget(tx, ty))
+		}
+	}
+	p8.Rect(m.camX+60, m.camY+60, m.camX+67, m.camY+67, 8)
+
+	// The HUD ignores the camera, which is restored for the chunk streaming
+	p8.CameraPush()
+	p8.Camera()
+	p8.Rectfill(0, 0, 127, 14, 0)
+	p8.Print(fmt.Sprintf("tile %d,%d", p8.Flr((m.camX+64)/tileSize), p8.Flr((m.camY+64)/tileSize)), 2, 2, 7)
+	p8.Print(fmt.Sprintf("chunks %d", p8.LoadedMapChunks()), 2, 8, 6)
+	p8.CameraPop()
+}
+
+func main() {
+	p8.InsertGame(&myGame{})
+	p8.Play()
+}
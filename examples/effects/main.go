@@ -158,11 +158,10 @@ func (g *Game) Draw() {
 		ghostImg.Set(eyeX+1, 8, color.RGBA{0, 0, 255, 255})
 	}
 
-	// Draw the ghost with transparency
-	ghostOp := &ebiten.DrawImageOptions{}
-	ghostOp.GeoM.Translate(float64(g.ghostX), 40)
-	ghostOp.Blend = ebiten.BlendSourceOver
-	screen.DrawImage(ghostImg, ghostOp)
+	// Draw the ghost with transparency through Sspr's transforms, mirrored
+	// to face the way it drifts
+	facingLeft := math.Cos(float64(g.tick)/20) < 0
+	pigo8.SsprFrom(ghostImg, 0, 0, 16, 20, g.ghostX, 40, 16, 20, facingLeft)
 
 	// ---- 5. Draw water overlay (transparency effect #3) ----
 	// Only in the bottom part of the screen
@@ -0,0 +1,138 @@
+// Package main shows the event bus: the collision code only publishes a
+// "caught" event, and the score and the sparkles subscribe to it without
+// knowing about each other
+package main
+
+import (
+	"fmt"
+
+	p8 "github.com/drpaneas/pigo8"
+)
+
+// caught is the payload of the "caught" event.
+type caught struct {
+	x, y   float64
+	points int
+}
+
+type star struct {
+	x, y, speed float64
+	big         bool
+}
+
+type sparkle struct {
+	x, y, dx, dy float64
+	life         int
+}
+
+// scoreboard keeps the score, only through events.
+type scoreboard struct {
+	score, streak int
+}
+
+func (s *scoreboard) subscribe() {
+	p8.SubscribeTo("caught", func(c caught) {
+		s.streak++
+		s.score += c.points * s.streak
+	})
+	p8.Subscribe("missed", func(any) {
+		s.streak = 0
+	})
+}
+
+// sparkles bursts particles where stars are caught, also through events.
+type sparkles struct {
+	list []sparkle
+}
+
+func (s *sparkles) subscribe() {
+	p8.SubscribeTo("caught", func(c caught) {
+		for i := 0; i < 8; i++ {
+			s.list = append(s.list, sparkle{c.x, c.y, float64(p8.Rnd(5) - 2), float64(-p8.Rnd(3) - 1), 10 + p8.Rnd(10)})
+		}
+	})
+}
+
+func (s *sparkles) update() {
+	alive := s.list[:0]
+	for _, sp := range s.list {
+		sp.x += sp.dx
+		sp.y += sp.dy
+		sp.dy += 0.3
+		if sp.life--; sp.life > 0 {
+			alive = append(alive, sp)
+		}
+	}
+	s.list = alive
+}
+
+type myGame struct {
+	paddleX  float64
+	stars    []star
+	score    scoreboard
+	sparkles sparkles
+}
+
+func (m *myGame) Init() {
+	m.paddleX = 56
+	m.stars = nil
+	m.score = scoreboard{}
+	m.sparkles = sparkles{}
+	m.score.subscribe()
+	m.sparkles.subscribe()
+}
+
+func (m *myGame) Update() {
+	if p8.Btn(p8.LEFT) {
+		m.paddleX -= 3
+	}
+	if p8.Btn(p8.RIGHT) {
+		m.paddleX += 3
+	}
+	m.paddleX = max(0, min(m.paddleX, 112))
+
+	if p8.Frame()%20 == 0 {
+		m.stars = append(m.stars, star{float64(p8.Rnd(120) + 4), 0, 1 + float64(p8.Rnd(3))/2, p8.Rnd(4) == 0})
+	}
+
+	// Collision only reports what happened
+	kept := m.stars[:0]
+	for _, s := range m.stars {
+		s.y += s.speed
+		switch {
+		case s.y >= 116 && s.y < 120 && s.x >= m.paddleX && s.x < m.paddleX+16:
+			points := 1
+			if s.big {
+				points = 5
+			}
+			p8.Publish("caught", caught{s.x, s.y, points})
+		case s.y >= 128:
+			p8.Publish("missed", nil)
+		default:
+			kept = append(kept, s)
+		}
+	}
+	m.stars = kept
+	m.sparkles.update()
+}
+
+func (m *myGame) Draw() {
+	p8.Cls(1)
+	for _, s := range m.stars {
+		if s.big {
+			p8.Circfill(s.x, s.y, 2, 10)
+		} else {
+			p8.Pset(int(s.x), int(s.y), 7)
+		}
+	}
+	for _, sp := range m.sparkles.list {
+		p8.Pset(int(sp.x), int(sp.y), 9+sp.life%2)
+	}
+	p8.Rectfill(m.paddleX, 120, m.paddleX+15, 123, 12)
+	p8.Print(fmt.Sprintf("score %d  streak %d", m.score.score, m.score.streak), 2, 2, 7)
+}
+
+func main() {
+	p8.InsertGame(&myGame{})
+	p8.Play()
+}
@@ -134,8 +134,8 @@ func (g *Game) handleMovement() (isMoving bool) {
 
 // updateAnimation updates the player's sprite based on direction and movement
 func (g *Game) updateAnimation(isMoving bool) {
-	// Animation frame toggle (6 FPS walking animation)
-	anim := (p8.Flr(p8.T()*6) % 2) == 0
+	// Animation frame toggle (6 FPS walking animation at 60 FPS)
+	anim := (p8.Frame()/10)%2 == 0
 
 	// Set default sprite based on direction
 	switch g.dir {
@@ -166,7 +166,10 @@ func (g *Game) Draw() {
 	p8.Cls(2) // Clear screen with color 2
 	p8.Map()  // Draw the map
 	p8.Sspr(g.spritePos.X, g.spritePos.Y, 16, 16, g.pos.X, g.pos.Y, 16, 16, g.flipX, false)
-	p8.Camera() // Reset camera if you want to draw UI fixed to the screen (optional)
+
+	// Draw UI fixed to the screen
+	p8.CameraPush()
+	p8.Camera()
 
 	// DEBUG: Show info on screen
 	p8.Print("position: ", 2, 2, 1)
@@ -174,6 +177,7 @@ func (g *Game) Draw() {
 	p8.Print(int(g.pos.Y), 80, 2, 1)
 	p8.Print("map screen: ", 2, 12, 1)
 	p8.Print(g.screenX, 70, 12, 1)
+	p8.CameraPop() // Back to the world camera
 }
 
 func main() {
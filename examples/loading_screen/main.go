@@ -0,0 +1,52 @@
+// Package main loading screen example using Preload to load assets in the background
+package main
+
+import (
+	"time"
+
+	p8 "github.com/drpaneas/pigo8"
+)
+
+type myGame struct {
+	loading *p8.Preloader
+	levels  []string
+}
+
+func (m *myGame) Init() {
+	m.loading = p8.Preload(
+		p8.PreloadSpritesheet(),
+		p8.PreloadMap(),
+		// A slow custom asset, so the progress bar has something to show
+		p8.PreloadFunc("levels", func() (func(), error) {
+			time.Sleep(2 * time.Second)
+			levels := []string{"forest", "caves", "castle"}
+			return func() { m.levels = levels }, nil
+		}),
+	)
+}
+
+func (m *myGame) Update() {}
+
+func (m *myGame) Draw() {
+	p8.Cls(0)
+	if !m.loading.Done() {
+		// Only draw shapes and text until the assets are in place: Spr or Map
+		// would load them right away and freeze the game
+		p8.Print("loading...", 44, 52, 7)
+		p8.Rect(23, 61, 104, 67, 5)
+		p8.Rectfill(24, 62, 24+int(79*m.loading.Progress()), 66, 11)
+		return
+	}
+	if err := m.loading.Err(); err != nil {
+		p8.Print("some assets failed to load", 8, 8, 8)
+	}
+	p8.Map()
+	for i, level := range m.levels {
+		p8.Print(level, 8, 100+i*8, 7)
+	}
+}
+
+func main() {
+	p8.InsertGame(&myGame{})
+	p8.Play()
+}
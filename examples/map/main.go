@@ -11,7 +11,7 @@ import (
 )
 
 type myGame struct {
-	frame      int
+	toggle     *p8.Timer
 	changed    bool
 	origSprite int
 }
@@ -20,13 +20,14 @@ func (m *myGame) Init() {
 	// Store the original sprite at position (0,5)
 	m.origSprite = p8.Mget(0, 5)
 	log.Printf("Original sprite at (0,5): %d", m.origSprite)
+
+	// Fire every 60 frames (about 2 seconds at 30 FPS)
+	m.toggle = p8.NewTimer(60, true)
 }
 
 func (m *myGame) Update() {
-	// Every 60 frames (about 1 second), toggle the sprite
-	m.frame++
-	if m.frame >= 60 {
-		m.frame = 0
+	// Every 60 frames, toggle the sprite
+	if m.toggle.Update() {
 		m.changed = !m.changed
 
 		// Toggle between original sprite and sprite #67
@@ -47,9 +48,15 @@ func (m *myGame) Draw() {
 	// Display current sprite number
 	currentSprite := p8.Mget(0, 5)
 	p8.Print("Sprite: "+strconv.Itoa(currentSprite), 5, 5, 7)
+	p8.Print("F9: grid", 5, 120, 6)
 }
 
 func main() {
 	p8.InsertGame(&myGame{})
-	p8.Play()
+
+	// F9 shows the tile grid, then the tile coordinates, to check that
+	// Mset(0, 5, ...) changes the tile it should
+	settings := p8.NewSettings()
+	settings.DebugControls = true
+	p8.PlayGameWith(settings)
 }
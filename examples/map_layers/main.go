@@ -5,8 +5,6 @@ package main
 
 import p8 "github.com/drpaneas/pigo8"
 
-// No need to define flags locally anymore as they're provided by the pigo8 package
-
 type myGame struct{}
 
 func (m *myGame) Init() {}
@@ -15,8 +13,8 @@ func (m *myGame) Update() {}
 
 func (m *myGame) Draw() {
 	p8.Cls(1)
-	layers := 4 + 6
-	p8.Map(0, 0, 0, 0, 16, 16, layers)
+	// Tiles with flag 4 or flag 6; the flag numbers are not added up
+	p8.Map(0, 0, 0, 0, 16, 16, p8.LayerMask(p8.Flag4, p8.Flag6))
 }
 
 func main() {
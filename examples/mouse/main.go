@@ -121,13 +121,9 @@ func (g *Game) Draw() {
 	// Clear screen
 	pigo8.Cls(0)
 
-	// Draw the canvas
+	// Draw the canvas one row at a time (color 0 is transparent, so empty cells are skipped)
 	for y := range g.canvas {
-		for x := range g.canvas[y] {
-			if g.canvas[y][x] != 0 {
-				pigo8.Pset(x, y, g.canvas[y][x])
-			}
-		}
+		pigo8.PsetRow(0, y, g.canvas[y])
 	}
 
 	// Draw color palette
@@ -4,6 +4,7 @@ package main
 //go:generate go run github.com/drpaneas/pigo8/cmd/embedgen -dir .
 
 import (
+	"fmt"
 	"log"
 
 	p8 "github.com/drpaneas/pigo8"
@@ -15,6 +16,15 @@ type Game struct{}
 // Init is called once at the start of the game
 func (g *Game) Init() {
 	log.Println("Music example initialized")
+
+	// Tracks and sound effects can also be loaded from WAV files at runtime.
+	// Slots loaded with LoadMusic loop; LoadSfx slots play once per Sfx call.
+	if err := p8.LoadMusic(64, "music10.wav"); err != nil {
+		log.Printf("Could not load custom music: %v", err)
+	}
+	if err := p8.LoadSfx(0, "music0.wav"); err != nil {
+		log.Printf("Could not load sound effect: %v", err)
+	}
 }
 
 // Update is called every frame for game logic
@@ -40,6 +50,16 @@ func (g *Game) Update() {
 		p8.Music(6, true)
 	}
 
+	if p8.Btnp(p8.O) {
+		log.Println("Playing sound effect 0")
+		p8.Sfx(0)
+	}
+
+	if p8.Btnp(p8.X) {
+		log.Println("Looping custom music 64")
+		p8.Music(64)
+	}
+
 	if p8.Btn(p8.UP) && p8.Btn(p8.DOWN) {
 		log.Println("Stopping all music")
 		p8.Music(-1)
@@ -58,7 +78,15 @@ func (g *Game) Draw() {
 	p8.Print("Left to play music 5", 10, 55, 7)
 	p8.Print("Right to play music 6 exclusively", 10, 65, 7)
 	p8.Print("Up+Down to stop all music", 10, 75, 7)
-
+	p8.Print("O to play a sound effect", 10, 85, 7)
+	p8.Print("X to loop a loaded track", 10, 95, 7)
+
+	// Show the position of the most recently started track
+	if track, pos := p8.MusicPosition(); track >= 0 {
+		p8.Print(fmt.Sprintf("Playing music %d", track), 10, 108, 11)
+		p8.Rect(10, 116, 117, 120, 5)
+		p8.Rectfill(11, 117, 11+int(pos*105), 119, 11)
+	}
 }
 
 func main() {
@@ -0,0 +1,50 @@
+// Package main name entry example using text input to type a player name
+package main
+
+import p8 "github.com/drpaneas/pigo8"
+
+const maxNameLength = 12
+
+type myGame struct {
+	name string
+}
+
+func (m *myGame) Init() {
+	p8.StartTextInput(maxNameLength)
+}
+
+func (m *myGame) Update() {
+	if p8.IsTextInputActive() {
+		// The keyboard doesn't press buttons while typing, so confirm with Enter
+		if p8.Keyp("Enter") && p8.GetTextInput() != "" {
+			m.name = p8.GetTextInput()
+			p8.StopTextInput()
+		}
+		return
+	}
+	if p8.Btnp(p8.O) {
+		p8.StartTextInput(maxNameLength)
+	}
+}
+
+func (m *myGame) Draw() {
+	p8.Cls(1)
+	if p8.IsTextInputActive() {
+		p8.Print("enter your name:", 32, 40, 7)
+		p8.Rectfill(30, 56, 97, 64, 0)
+		cursor := ""
+		if int(p8.T()*2)%2 == 0 {
+			cursor = "_"
+		}
+		p8.Print(p8.GetTextInput()+cursor, 32, 58, 11)
+		p8.Print("enter to confirm", 32, 80, 6)
+		return
+	}
+	p8.Print("hello, "+m.name+"!", 32, 56, 10)
+	p8.Print("press o to rename", 30, 80, 6)
+}
+
+func main() {
+	p8.InsertGame(&myGame{})
+	p8.Play()
+}
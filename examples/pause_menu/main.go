@@ -12,6 +12,7 @@ import (
 type Game struct {
 	playerX, playerY int
 	stars            []Star
+	bannerY          *p8.Tween // Slides the instructions in from above the screen
 }
 
 // Star represents a background star
@@ -29,6 +30,9 @@ func (g *Game) Init() {
 	g.playerX = p8.GetScreenWidth() / 2
 	g.playerY = p8.GetScreenHeight() / 2
 
+	// Drop the instructions in over one second with a little bounce
+	g.bannerY = p8.NewTween(-8, 2, 30, p8.EaseOutBounce).Start()
+
 	// Initialize stars
 	g.stars = make([]Star, 50)
 	for i := range g.stars {
@@ -97,7 +101,7 @@ func (g *Game) Draw() {
 	p8.Spr(1, g.playerX, g.playerY)
 
 	// Draw instructions
-	p8.Print("PRESS START TO PAUSE", 10, 2, 7)
+	p8.Print("PRESS START TO PAUSE", 10, int(g.bannerY.Value()), 7)
 }
 
 func main() {
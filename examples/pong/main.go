@@ -51,11 +51,11 @@ func (g *Game) Init() {
 	ballDy := float64(p8.Flr(p8.Rnd(2))) - 0.5
 	g.ball = Ball{x: centerX, y: centerY, size: 2, color: 7, dx: 1.0 * difficulty, dy: ballDy, speed: 1.0 * difficulty, boost: 0.05 * difficulty}
 
-	if p8.Restart {
+	// A restart from the pause menu starts a new match, not just a new round
+	if p8.GetRestartReason() != p8.NotRestarted {
 		g.playerScore = 0
 		g.computerScore = 0
 		g.Scored = ""
-		p8.Restart = false
 	}
 
 	// sound
@@ -145,9 +145,7 @@ func (g *Game) Draw() {
 	p8.Rect(courtLeft, courtTop, courtRight, courtBottom, 5)
 
 	// Center dashed line
-	for y := courtTop; y < courtBottom; y += lineLen * 2 {
-		p8.Line(centerX, float64(y), centerX, float64(y+lineLen), 5)
-	}
+	p8.Line(centerX, courtTop, centerX, courtBottom, 5, p8.WithDash(lineLen, lineLen))
 
 	// Ball and paddles
 	p8.Rectfill(g.ball.x, g.ball.y, g.ball.x+g.ball.size, g.ball.y+g.ball.size, g.ball.color)
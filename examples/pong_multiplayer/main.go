@@ -242,9 +242,7 @@ func (g *Game) Draw() {
 	p8.Rect(courtLeft, courtTop, courtRight, courtBottom, 5)
 
 	// Center dashed line
-	for y := courtTop; y < courtBottom; y += lineLen * 2 {
-		p8.Line(centerX, float64(y), centerX, float64(y+lineLen), 5)
-	}
+	p8.Line(centerX, courtTop, centerX, courtBottom, 5, p8.WithDash(lineLen, lineLen))
 
 	// Ball and paddles
 	p8.Rectfill(g.ball.x, g.ball.y, g.ball.x+g.ball.size, g.ball.y+g.ball.size, g.ball.color)
@@ -331,13 +329,7 @@ func (g *Game) sendPlayerInput() {
 }
 
 // handleGameState processes game state received from the server
-func handleGameState(_ string, data []byte) {
-	game, ok := p8.CurrentCartridge().(*Game)
-	if !ok {
-		log.Printf("Error: current cartridge is not a Game")
-		return
-	}
-
+func handleGameState(game *Game, _ string, data []byte) {
 	var state GameState
 	if err := json.Unmarshal(data, &state); err != nil {
 		log.Printf("Error unmarshaling game state: %v", err)
@@ -381,14 +373,10 @@ func handleGameState(_ string, data []byte) {
 	game.waitingForPlayer = state.WaitingForPlayer
 }
 
-// handlePlayerInput processes player input received from the client
-func handlePlayerInput(playerID string, data []byte) {
-	game, ok := p8.CurrentCartridge().(*Game)
-	if !ok {
-		log.Printf("Error: current cartridge is not a Game")
-		return
-	}
-
+// handlePlayerInput processes player input received from the client. The
+// server applies it on its fixed tick (see main), once per tick with the
+// newest input, however bursty the packets are.
+func handlePlayerInput(game *Game, playerID string, data []byte) {
 	if !game.isServer {
 		return
 	}
@@ -410,19 +398,11 @@ func handlePlayerInput(playerID string, data []byte) {
 	if input.Down && game.rightPaddle.y+game.rightPaddle.height < courtBottom-1 {
 		game.rightPaddle.y += game.rightPaddle.speed
 	}
-
-	// Immediately send updated game state after processing input
-	game.sendGameState()
+	// The new paddle position goes out with the next regular state update
 }
 
 // handlePlayerConnect is called when a player connects
-func handlePlayerConnect(playerID string) {
-	game, ok := p8.CurrentCartridge().(*Game)
-	if !ok {
-		log.Printf("Error: current cartridge is not a Game")
-		return
-	}
-
+func handlePlayerConnect(game *Game, playerID string) {
 	if !game.isServer {
 		return
 	}
@@ -433,13 +413,7 @@ func handlePlayerConnect(playerID string) {
 }
 
 // handlePlayerDisconnect is called when a player disconnects
-func handlePlayerDisconnect(playerID string) {
-	game, ok := p8.CurrentCartridge().(*Game)
-	if !ok {
-		log.Printf("Error: current cartridge is not a Game")
-		return
-	}
-
+func handlePlayerDisconnect(game *Game, playerID string) {
 	if !game.isServer {
 		return
 	}
@@ -467,10 +441,20 @@ func main() {
 
 	// IMPORTANT: Register network callbacks BEFORE initializing the network
 	log.Printf("Registering network callbacks...")
-	p8net.SetOnGameStateCallback(handleGameState)
-	p8net.SetOnPlayerInputCallback(handlePlayerInput)
-	p8net.SetOnConnectCallback(handlePlayerConnect)
-	p8net.SetOnDisconnectCallback(handlePlayerDisconnect)
+	// The handlers get the running *Game, see p8.CartridgeHandler
+	onGameState := p8.CartridgeHandler(handleGameState)
+	onPlayerInput := p8.CartridgeHandler(handlePlayerInput)
+	onConnect := p8.CartridgePlayerHandler(handlePlayerConnect)
+	onDisconnect := p8.CartridgePlayerHandler(handlePlayerDisconnect)
+	p8net.SetOnGameStateCallback(onGameState)
+	p8net.SetOnPlayerInputCallback(onPlayerInput)
+	p8net.SetOnConnectCallback(onConnect)
+	p8net.SetOnDisconnectCallback(onDisconnect)
+
+	// Apply client input on a steady 60 Hz server tick, like the local paddle
+	// moves once per frame, keeping only the newest input of each tick
+	p8net.SetServerTickRate(60)
+	p8net.SetInputCoalescing(p8net.CoalesceLastWins)
 
 	// Configure the game settings
 	settings := p8.NewSettings()
@@ -489,10 +473,10 @@ func main() {
 	// Force register callbacks directly on the network manager as a fallback
 	log.Printf("Force registering callbacks to ensure they're set...")
 	p8net.ForceRegisterCallbacks(
-		handleGameState,
-		handlePlayerInput,
-		handlePlayerConnect,
-		handlePlayerDisconnect,
+		onGameState,
+		onPlayerInput,
+		onConnect,
+		onDisconnect,
 	)
 
 	// Verify callbacks are registered
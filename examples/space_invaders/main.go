@@ -6,7 +6,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"os"
 
 	"github.com/drpaneas/pigo8"
 )
@@ -31,6 +34,8 @@ const (
 	alienPadY   = 10
 	alienStartX = 16
 	alienStartY = 16
+
+	highScoresFile = "highscores.json"
 )
 
 // Game state
@@ -57,10 +62,11 @@ type (
 		aliens []alien
 
 		// Game state
-		score    int
-		gameOver bool
-		paused   bool
-		menuItem int // 0 = resume, 1 = quit
+		score      int
+		highScores *pigo8.HighScoreTable
+		gameOver   bool
+		paused     bool
+		menuItem   int // 0 = resume, 1 = quit
 	}
 )
 
@@ -72,6 +78,11 @@ func NewGame() *Game {
 		lives:   initialLives,
 		score:   0,
 	}
+	g.highScores = pigo8.NewHighScoreTable(5)
+	g.highScores.Y = 44
+	if err := g.highScores.Load(highScoresFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Could not load high scores: %v", err)
+	}
 	g.initAliens()
 	return g
 }
@@ -120,12 +131,30 @@ func (g *Game) processInputs() bool {
 }
 
 func (g *Game) handleGameOverInput() bool {
+	// Typing a name for the high score table, confirmed with Enter
+	if g.highScores.Entering() {
+		if g.highScores.Update() {
+			if err := g.highScores.Save(highScoresFile); err != nil {
+				log.Printf("Could not save high scores: %v", err)
+			}
+		}
+		return false
+	}
 	if pigo8.Btnp(pigo8.O) {
 		g.resetGame()
 	}
 	return false
 }
 
+// endGame ends the game, asking for a name if the score is a high score
+func (g *Game) endGame() {
+	if g.gameOver {
+		return
+	}
+	g.gameOver = true
+	g.highScores.StartEntry(g.score)
+}
+
 func (g *Game) handlePlayerMovement() {
 	if pigo8.Btn(pigo8.LEFT) && g.playerX > 8 {
 		g.playerX -= playerSpeed
@@ -183,7 +212,7 @@ func (g *Game) updateAliensAndBullets() {
 			})
 		}
 		if a.y > playerStartY-8 {
-			g.gameOver = true
+			g.endGame()
 			return
 		}
 	}
@@ -198,8 +227,10 @@ func (g *Game) updateAliensAndBullets() {
 			b.y > g.playerY-8 && b.y < g.playerY+8 {
 			g.lives--
 			pigo8.Music(1)
+			pigo8.FlashScreen(8, 8) // Red flash and a short freeze on a hit
+			pigo8.HitStop(4)
 			if g.lives <= 0 {
-				g.gameOver = true
+				g.endGame()
 			}
 			continue
 		}
@@ -221,6 +252,7 @@ func (g *Game) handleCollisions() {
 				a.alive = false
 				g.score += 10
 				pigo8.Music(0)
+				pigo8.HitStop(1)
 				hit = true
 				break
 			}
@@ -281,12 +313,19 @@ func (g *Game) drawAliens() {
 }
 func (g *Game) drawUI() {
 	pigo8.Print(fmt.Sprintf("score: %d", g.score), 4, 4, 7)
-	pigo8.Print(fmt.Sprintf("lives: %d", g.lives), 80, 4, 7)
+	pigo8.PrintAligned(fmt.Sprintf("lives: %d", g.lives), 124, 4, 7, pigo8.AlignRight)
 }
 
 func (g *Game) drawGameOver() {
-	pigo8.Print("game over", 40, 60, 7)
-	pigo8.Print("press o to restart", 20, 70, 7)
+	pigo8.Rectfill(14, 24, 113, 112, 0)
+	pigo8.Rect(14, 24, 113, 112, 5)
+	pigo8.PrintAligned("game over", 64, 30, 8, pigo8.AlignCenter)
+	g.highScores.Draw()
+	if g.highScores.Entering() {
+		pigo8.PrintAligned("new high score! name:", 64, 102, 7, pigo8.AlignCenter)
+	} else {
+		pigo8.PrintAligned("press o to restart", 64, 102, 7, pigo8.AlignCenter)
+	}
 }
 
 func main() {
@@ -0,0 +1,71 @@
+// Package main shows the UI widgets: a settings panel with a slider, toggles
+// and buttons, drawn over a moving ball
+package main
+
+import (
+	"fmt"
+
+	p8 "github.com/drpaneas/pigo8"
+)
+
+type myGame struct {
+	x, y, dx, dy float64
+	speed        float64
+	trail        bool
+	paused       bool
+	bounces      int
+}
+
+func (m *myGame) Init() {
+	m.x, m.y, m.dx, m.dy = 64, 90, 1, 1
+	m.speed = 0.5
+}
+
+func (m *myGame) Update() {
+	if m.paused {
+		return
+	}
+	step := 0.5 + m.speed*3
+	m.x += m.dx * step
+	m.y += m.dy * step
+	if m.x < 3 || m.x > 124 {
+		m.dx = -m.dx
+		m.bounces++
+	}
+	if m.y < 64 || m.y > 124 {
+		m.dy = -m.dy
+		m.bounces++
+	}
+	m.x = max(3, min(m.x, 124))
+	m.y = max(64, min(m.y, 124))
+}
+
+func (m *myGame) Draw() {
+	if !m.trail {
+		p8.Cls(0)
+	}
+	p8.Rectfill(0, 0, 127, 60, 0)
+	p8.Circfill(m.x, m.y, 3, 8)
+
+	// The widgets handle the mouse and draw themselves in one call
+	p8.Print("speed", 4, 6, 6)
+	m.speed = p8.Slider(30, 5, 60, 7, m.speed, 0, 1)
+	p8.Print(fmt.Sprintf("%.0f%%", m.speed*100), 96, 6, 6)
+
+	m.trail = p8.Toggle(4, 18, "trail", m.trail)
+	m.paused = p8.Toggle(50, 18, "pause", m.paused)
+
+	if p8.Button(4, 32, 40, 11, "reset") {
+		m.Init()
+		m.bounces = 0
+	}
+	if p8.Button(50, 32, 40, 11, "clear") {
+		p8.Cls(0)
+	}
+	p8.Print(fmt.Sprintf("bounces: %d", m.bounces), 4, 50, 6)
+}
+
+func main() {
+	p8.InsertGame(&myGame{})
+	p8.Play()
+}
@@ -0,0 +1,115 @@
+package pigo8
+
+import (
+	"log"
+	"strings"
+)
+
+// --- Sprite flag names ---
+
+// numSpriteFlags is the number of flags each sprite has.
+const numSpriteFlags = 8
+
+var (
+	// sheetFlagNames are the flag names from the "flagNames" list of the
+	// loaded spritesheet.json.
+	sheetFlagNames [numSpriteFlags]string
+	// registeredFlagNames are the names given with RegisterFlagName, which
+	// take precedence over the spritesheet's.
+	registeredFlagNames [numSpriteFlags]string
+)
+
+// RegisterFlagName names sprite flag index (0-7), e.g. "solid", so code can
+// test flags by name with HasNamedFlag and tools can show the name instead of
+// the number. The pigo8 editor saves the names in the "flagNames" list of
+// spritesheet.json, next to the sprites they describe:
+//
+//	"flagNames": ["solid", "one-way", "", "water"]
+//
+// Names registered in code take precedence over the spritesheet's, and an
+// empty name goes back to the spritesheet's name (if any).
+//
+// Example:
+//
+//	func (g *Game) Init() {
+//		RegisterFlagName(Flag0, "solid")
+//		RegisterFlagName(Flag3, "water")
+//	}
+//
+//	func (g *Game) Update() {
+//		if HasNamedFlag(Mget(tx, ty), "solid") {
+//			g.player.vy = 0
+//		}
+//	}
+func RegisterFlagName(index int, name string) {
+	if index < 0 || index >= numSpriteFlags {
+		log.Printf("Warning: RegisterFlagName() called with invalid flag %d. Must be 0-7.", index)
+		return
+	}
+	registeredFlagNames[index] = strings.TrimSpace(name)
+}
+
+// FlagName returns the name of sprite flag index (0-7), registered with
+// RegisterFlagName or loaded from spritesheet.json, or "" if it has none.
+func FlagName(index int) string {
+	if index < 0 || index >= numSpriteFlags {
+		return ""
+	}
+	if name := registeredFlagNames[index]; name != "" {
+		return name
+	}
+	ensureSpritesLoaded("FlagName")
+	return sheetFlagNames[index]
+}
+
+// FlagByName returns the number of the sprite flag called name, and false if
+// no flag has that name.
+func FlagByName(name string) (int, bool) {
+	for index := range numSpriteFlags {
+		if name != "" && FlagName(index) == name {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+// HasNamedFlag reports whether the flag called name is set on sprite
+// spriteNum, like HasFlag with the number FlagByName returns. Unknown names
+// log a warning once and report false.
+func HasNamedFlag(spriteNum int, name string) bool {
+	index, ok := FlagByName(name)
+	if !ok {
+		logWarningOnce("Warning: HasNamedFlag() called with unknown flag name %q. See RegisterFlagName.", name)
+		return false
+	}
+	return HasFlag(spriteNum, index)
+}
+
+// setSheetFlagNames replaces the flag names of the loaded spritesheet, taking
+// at most the first 8.
+func setSheetFlagNames(names []string) {
+	sheetFlagNames = [numSpriteFlags]string{}
+	if len(names) > numSpriteFlags {
+		log.Printf("Warning: Spritesheet has %d flag names, only the first %d are used.", len(names), numSpriteFlags)
+	}
+	for i, name := range names[:min(len(names), numSpriteFlags)] {
+		sheetFlagNames[i] = strings.TrimSpace(name)
+	}
+}
+
+// flagNamesForSheet returns the flag names to save in spritesheet.json, with
+// the trailing unnamed flags left out.
+func flagNamesForSheet() []string {
+	var names []string
+	for index := range numSpriteFlags {
+		name := registeredFlagNames[index]
+		if name == "" {
+			name = sheetFlagNames[index]
+		}
+		names = append(names, name)
+	}
+	for len(names) > 0 && names[len(names)-1] == "" {
+		names = names[:len(names)-1]
+	}
+	return names
+}
@@ -0,0 +1,77 @@
+package pigo8
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useTestFlagNames(t *testing.T) {
+	t.Cleanup(func() {
+		sheetFlagNames = [numSpriteFlags]string{}
+		registeredFlagNames = [numSpriteFlags]string{}
+	})
+}
+
+func TestSpritesheetFlagNames(t *testing.T) {
+	useTestConsoleState(t)
+	useTestFlagNames(t)
+
+	sprites, err := loadSpritesheetFromDataForTest([]byte(`{
+		"sprites": [
+			{"id": 1, "width": 1, "height": 1, "used": true, "pixels": [[7]], "flags": {"bitfield": 1}}
+		],
+		"flagNames": ["solid", "", " water "]
+	}`))
+	require.NoError(t, err)
+	currentSprites = sprites
+
+	assert.Equal(t, "solid", FlagName(Flag0))
+	assert.Equal(t, "", FlagName(Flag1))
+	assert.Equal(t, "water", FlagName(Flag2), "names are trimmed")
+	assert.Equal(t, "", FlagName(8), "invalid flags have no name")
+	assert.True(t, HasNamedFlag(1, "solid"))
+	assert.False(t, HasNamedFlag(1, "water"))
+	assert.False(t, HasNamedFlag(1, "lava"), "unknown names report false")
+}
+
+func TestRegisterFlagName(t *testing.T) {
+	useTestLayerSprites(t)
+	useTestFlagNames(t)
+	setSheetFlagNames([]string{"solid", "ladder"})
+
+	RegisterFlagName(Flag1, "one-way")
+	RegisterFlagName(Flag4, "background")
+	RegisterFlagName(8, "ignored")
+
+	assert.Equal(t, "solid", FlagName(Flag0))
+	assert.Equal(t, "one-way", FlagName(Flag1), "registered names take precedence")
+	flag, ok := FlagByName("background")
+	assert.True(t, ok)
+	assert.Equal(t, Flag4, flag)
+	_, ok = FlagByName("ladder")
+	assert.False(t, ok, "overridden sheet names are gone")
+	_, ok = FlagByName("")
+	assert.False(t, ok, "unnamed flags can't be looked up")
+	assert.True(t, HasNamedFlag(3, "background"))
+	assert.False(t, HasNamedFlag(2, "background"))
+
+	RegisterFlagName(Flag1, "")
+	assert.Equal(t, "ladder", FlagName(Flag1), "an empty name goes back to the sheet's")
+}
+
+func TestSnapshotSpriteSheetFlagNames(t *testing.T) {
+	useTestFlagNames(t)
+	setSheetFlagNames([]string{"solid", "", "water"})
+	RegisterFlagName(Flag1, "one-way")
+
+	assert.Equal(t, []string{"solid", "one-way", "water"}, flagNamesForSheet())
+
+	setSheetFlagNames(nil)
+	registeredFlagNames = [numSpriteFlags]string{}
+	data, err := json.Marshal(spriteSheet{FlagNames: flagNamesForSheet()})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "flagNames", "sheets without names don't save the list")
+}
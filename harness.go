@@ -0,0 +1,193 @@
+package pigo8
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Test harness ---
+
+// TestHarness runs a Cartridge one frame at a time without a window, so
+// games can be unit tested: step the game, inject input and inspect the
+// game state or the screen.
+//
+// Stepping, input and game state work in any `go test` run. Reading pixels
+// back (Pget) needs a graphics context, so tests that do so must run inside
+// RunTestsWithGraphics.
+//
+// Example:
+//
+//	func TestPlayerWalksRight(t *testing.T) {
+//		game := &Game{}
+//		h := pigo8.NewTestHarness(game, nil)
+//		h.Init()
+//
+//		h.InjectButton(pigo8.RIGHT, true)
+//		h.AdvanceFrames(10)
+//
+//		if game.playerX <= 10 {
+//			t.Errorf("player did not move: x = %v", game.playerX)
+//		}
+//	}
+type TestHarness struct {
+	cart   Cartridge
+	screen *ebiten.Image
+	frames int
+}
+
+// NewTestHarness prepares the engine to run cart with the given settings
+// (defaults if nil): screen size, time step and a clean input state.
+// The cartridge becomes the loaded game, as with InsertGame.
+func NewTestHarness(cart Cartridge, settings *Settings) *TestHarness {
+	cfg := settings
+	if cfg == nil {
+		cfg = NewSettings()
+	}
+
+	width, height := cfg.ScreenWidth, cfg.ScreenHeight
+	if width <= 0 {
+		width = defaultViewportWidth
+	}
+	if height <= 0 {
+		height = defaultViewportHeight
+	}
+	setScreenSize(width, height)
+
+	fps := cfg.TargetFPS
+	if fps <= 0 {
+		fps = 30
+	}
+	timeIncrement = 1.0 / float64(fps)
+	resetClock()
+	setMapChunkLayout(cfg.MapChunkSize, cfg.MapChunkMargin)
+	strictAssets = cfg.StrictAssets
+	SetTileSize(cfg.TileSize)
+	SetSubPixelSprites(cfg.SubPixelSprites)
+	SetCollisionDebug(cfg.CollisionDebug)
+
+	InsertGame(cart)
+	resetInputState()
+	resetScreenEffects()
+
+	return &TestHarness{cart: loadedCartridge}
+}
+
+// Init calls the cartridge's Init.
+func (h *TestHarness) Init() {
+	restartRequested = false
+	initCartridge(h.cart, NotRestarted)
+}
+
+// Step runs one frame of game logic: it reads the injected input (so Btnp
+// sees presses made since the last step), calls the cartridge's Update and
+// advances Time() and Frame() by one tick. Real keyboards and gamepads are ignored.
+// If the game called RestartGame, the cartridge is restarted first, like the
+// engine does. During a HitStop, a step only counts the frozen frame down.
+func (h *TestHarness) Step() {
+	if restartRequested {
+		prepareRestart(RestartByGame)
+		initCartridge(h.cart, RestartByGame)
+	}
+	refreshInputCache(false)
+	updateScreenFlash()
+	if !consumeHitStopFrame() {
+		h.cart.Update()
+		advanceClock()
+	}
+	h.frames++
+}
+
+// AdvanceFrames runs n frames of game logic, see Step.
+func (h *TestHarness) AdvanceFrames(n int) {
+	for i := 0; i < n; i++ {
+		h.Step()
+	}
+}
+
+// Draw calls the cartridge's Draw on an offscreen screen image of the
+// configured size and finishes the frame like the engine does.
+func (h *TestHarness) Draw() {
+	width, height := GetScreenWidth(), GetScreenHeight()
+	if h.screen == nil || h.screen.Bounds().Dx() != width || h.screen.Bounds().Dy() != height {
+		h.screen = ebiten.NewImage(width, height)
+	}
+	currentScreen = h.screen
+
+	h.cart.Draw()
+	flushDrawLayers()
+	flushPixelBuffer()
+	flushSpriteModifications()
+	drawScreenFlash()
+	invalidateScreenPixelCache()
+}
+
+// InjectButton holds a button down or releases it for the following steps.
+// It is the same as the package-level InjectButton.
+func (h *TestHarness) InjectButton(buttonIndex int, pressed bool) {
+	InjectButton(buttonIndex, pressed)
+}
+
+// Pget returns the color index at (x, y) on the screen drawn by the last
+// Draw. It needs a graphics context, see RunTestsWithGraphics.
+func (h *TestHarness) Pget(x, y int) int {
+	if h.screen == nil {
+		return 0
+	}
+	currentScreen = h.screen
+	return Pget(x, y)
+}
+
+// Frames returns the number of frames stepped so far.
+func (h *TestHarness) Frames() int {
+	return h.frames
+}
+
+// resetInputState releases every injected button and forgets the input history.
+func resetInputState() {
+	inputCacheMutex.Lock()
+	defer inputCacheMutex.Unlock()
+	buttonStates = make(map[int]bool)
+	buttonStatesPrev = make(map[int]bool)
+	injectedButtons = make(map[int]bool)
+	buttonHeldFrames = make(map[int]int)
+	inputCacheValid = true
+}
+
+// testRunner is an ebiten.Game that runs a test binary's tests inside its
+// first Update, where the GPU can be read from, and then quits.
+type testRunner struct {
+	run  func() int
+	code int
+}
+
+func (r *testRunner) Update() error {
+	r.code = r.run()
+	return ebiten.Termination
+}
+
+func (r *testRunner) Draw(_ *ebiten.Image) {}
+
+func (r *testRunner) Layout(_, _ int) (int, int) {
+	return GetScreenWidth(), GetScreenHeight()
+}
+
+// RunTestsWithGraphics runs a package's tests inside a running Ebitengine
+// loop, which is needed to read pixels back from the GPU (TestHarness.Pget,
+// Pget, Sget). Call it from TestMain. It briefly opens a small window, so it
+// needs a display; on CI use a virtual one such as xvfb.
+//
+// Example:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(pigo8.RunTestsWithGraphics(m))
+//	}
+func RunTestsWithGraphics(m interface{ Run() int }) int {
+	runner := &testRunner{run: m.Run}
+	ebiten.SetWindowTitle("pigo8 tests")
+	ebiten.SetWindowSize(GetScreenWidth(), GetScreenHeight())
+	if err := ebiten.RunGame(runner); err != nil {
+		log.Panicf("pigo8.RunTestsWithGraphics: Ebitengine loop failed: %v", err)
+	}
+	return runner.code
+}
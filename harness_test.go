@@ -0,0 +1,61 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type walkerCartridge struct {
+	x, jumps int
+}
+
+func (c *walkerCartridge) Init() { c.x = 10 }
+func (c *walkerCartridge) Update() {
+	if Btn(RIGHT) {
+		c.x++
+	}
+	if Btnp(X) {
+		c.jumps++
+	}
+}
+func (c *walkerCartridge) Draw() {}
+
+func TestHarnessStepsWithInjectedInput(t *testing.T) {
+	originalCart := loadedCartridge
+	t.Cleanup(func() {
+		InsertGame(originalCart)
+		resetInputState()
+	})
+
+	settings := NewSettings()
+	settings.TargetFPS = 60
+	game := &walkerCartridge{}
+	h := NewTestHarness(game, settings)
+	h.Init()
+	assert.Equal(t, 10, game.x)
+
+	h.AdvanceFrames(3)
+	assert.Equal(t, 10, game.x, "no input, no movement")
+
+	h.InjectButton(RIGHT, true)
+	h.AdvanceFrames(5)
+	assert.Equal(t, 15, game.x)
+
+	h.InjectButton(RIGHT, false)
+	h.Step()
+	assert.Equal(t, 15, game.x)
+
+	// Holding X only counts as a press on the first frame
+	h.InjectButton(X, true)
+	h.AdvanceFrames(4)
+	assert.Equal(t, 1, game.jumps)
+	h.InjectButton(X, false)
+	h.Step()
+	h.InjectButton(X, true)
+	h.Step()
+	assert.Equal(t, 2, game.jumps)
+
+	assert.Equal(t, 15, h.Frames())
+	assert.InDelta(t, 15.0/60.0, Time(), 1e-9)
+}
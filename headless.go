@@ -0,0 +1,100 @@
+package pigo8
+
+import (
+	"log"
+	"time"
+)
+
+// --- Headless mode ---
+
+var (
+	// headless is true while the game runs without a window (Settings.Headless).
+	headless bool
+	// stopRequested asks the main loop to return from PlayGameWith, see StopGame.
+	stopRequested bool
+)
+
+// IsHeadless reports whether the game is running without a window.
+// Games can use it to skip work that only matters on screen.
+//
+// Example:
+//
+//	if !IsHeadless() {
+//		updateParticles()
+//	}
+func IsHeadless() bool {
+	return headless
+}
+
+// StopGame makes PlayGameWith return after the current frame, in both
+// windowed and headless mode. Servers and automated tests use it to shut
+// down cleanly.
+//
+// Example:
+//
+//	if Time() > 60 {
+//		StopGame() // run the simulation for one minute
+//	}
+func StopGame() {
+	stopRequested = true
+}
+
+// warnScreenNotReady logs that a drawing function was called without a screen.
+// In headless mode and during StepSim there is no screen on purpose, so
+// drawing is a silent no-op.
+func warnScreenNotReady(fn string) {
+	if outputMuted() {
+		return
+	}
+	log.Printf("Warning: %s() called before screen was ready.", fn)
+}
+
+// runHeadless runs the cartridge's Init and then its Update at fps ticks per
+// second, without creating a window or calling Draw. It returns after StopGame.
+//
+// There is no vsync to pace the loop, so a ticker does it. If an Update takes
+// longer than a tick, the late ticks are dropped rather than run back to back.
+func runHeadless(fps int) {
+	if fps <= 0 {
+		fps = 30
+	}
+
+	headless = true
+	stopRequested = false
+	restartRequested = false
+	defer func() { headless = false }()
+
+	log.Printf("Running headless at %d ticks per second", fps)
+	initCartridge(loadedCartridge, NotRestarted)
+
+	ticker := time.NewTicker(time.Second / time.Duration(fps))
+	defer ticker.Stop()
+	for !stopRequested {
+		<-ticker.C
+		if restartRequested {
+			prepareRestart(RestartByGame)
+			initCartridge(loadedCartridge, RestartByGame)
+		}
+		stepHeadless()
+	}
+}
+
+// stepHeadless runs one headless tick: the cartridge's Update and the clock.
+// Local input is not polled; there is no window to receive it, but a replay
+// (see RegisterFlags) plays back its input.
+func stepHeadless() {
+	beginUpdateTiming()
+	updateScreenFlash()
+	if !consumeHitStopFrame() {
+		if replayRecording != nil || recording != nil {
+			updateInputFrame()
+		}
+		loadedCartridge.Update()
+		advanceClock()
+	}
+	endUpdateTiming()
+
+	// Nothing is drawn, so the tick is the whole frame
+	drawCalls = 0
+	finishFrameTiming(0)
+}
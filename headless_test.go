@@ -0,0 +1,42 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingCartridge struct {
+	inits, updates, draws int
+	stopAfter             int
+}
+
+func (c *countingCartridge) Init() { c.inits++ }
+func (c *countingCartridge) Update() {
+	c.updates++
+	Spr(1, 10, 10) // drawing is a silent no-op without a screen
+	if c.updates == c.stopAfter {
+		StopGame()
+	}
+}
+func (c *countingCartridge) Draw() { c.draws++ }
+
+func TestRunHeadless(t *testing.T) {
+	originalCart, originalTime, originalIncrement := loadedCartridge, elapsedTime, timeIncrement
+	t.Cleanup(func() {
+		loadedCartridge, elapsedTime, timeIncrement = originalCart, originalTime, originalIncrement
+	})
+
+	cart := &countingCartridge{stopAfter: 5}
+	InsertGame(cart)
+	elapsedTime = 0
+	timeIncrement = 0.5
+
+	runHeadless(1000)
+
+	assert.Equal(t, 1, cart.inits)
+	assert.Equal(t, 5, cart.updates)
+	assert.Equal(t, 0, cart.draws, "Draw is never called in headless mode")
+	assert.InDelta(t, 2.5, Time(), 1e-9)
+	assert.False(t, IsHeadless(), "headless mode ends when the loop returns")
+}
@@ -0,0 +1,257 @@
+package pigo8
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+)
+
+// --- High score table ---
+
+// HighScore is one entry of a HighScoreTable.
+type HighScore struct {
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+}
+
+// HighScoreTable keeps the best N scores of a game, asks for the player's
+// name when a new score makes the table, draws the table, and saves it to a
+// file. Create it with NewHighScoreTable and set the exported fields to style
+// it.
+//
+// Example:
+//
+//	var scores = NewHighScoreTable(5)
+//
+//	func (g *Game) Init() {
+//		_ = scores.Load("scores.json") // No file yet on the first run
+//	}
+//
+//	func (g *Game) Update() {
+//		if g.gameOver {
+//			if scores.Update() { // The name was entered
+//				_ = scores.Save("scores.json")
+//			}
+//			return
+//		}
+//		if g.lives == 0 {
+//			g.gameOver = true
+//			scores.StartEntry(g.score)
+//		}
+//	}
+//
+//	func (g *Game) Draw() {
+//		if g.gameOver {
+//			scores.Draw()
+//		}
+//	}
+type HighScoreTable struct {
+	// Scores holds the entries, best first. Load fills it, and it can be
+	// changed directly.
+	Scores []HighScore
+	// Size is how many scores the table keeps.
+	Size int
+	// Better reports whether a ranks above b. The default puts higher scores
+	// first; use e.g. a.Score < b.Score for times. Equal scores keep the
+	// order they were added in.
+	Better func(a, b HighScore) bool
+	// MaxNameLength caps the names typed in StartEntry.
+	MaxNameLength int
+
+	// Style of Draw
+	X, Y           int
+	Title          string
+	TitleColor     int
+	TextColor      int // Names and scores
+	HighlightColor int // The score added last, and the name being typed
+
+	entering  bool // Between StartEntry and the name being confirmed
+	score     int  // The score waiting for a name
+	lastAdded int  // Index in Scores of the score added last, or -1
+}
+
+// NewHighScoreTable returns an empty table that keeps the best size scores,
+// higher first, drawn with a white title and light gray entries.
+func NewHighScoreTable(size int) *HighScoreTable {
+	return &HighScoreTable{
+		Size:           max(size, 1),
+		MaxNameLength:  10,
+		X:              20,
+		Y:              30,
+		Title:          "high scores",
+		TitleColor:     7,
+		TextColor:      6,
+		HighlightColor: 10,
+		lastAdded:      -1,
+	}
+}
+
+// better reports whether a ranks above b.
+func (t *HighScoreTable) better(a, b HighScore) bool {
+	if t.Better != nil {
+		return t.Better(a, b)
+	}
+	return a.Score > b.Score
+}
+
+// rankOf returns where entry would go in the table, after the entries that
+// rank the same, or -1 if it wouldn't make the table.
+func (t *HighScoreTable) rankOf(entry HighScore) int {
+	rank := len(t.Scores)
+	for i, s := range t.Scores {
+		if t.better(entry, s) {
+			rank = i
+			break
+		}
+	}
+	if rank >= t.Size {
+		return -1
+	}
+	return rank
+}
+
+// Qualifies reports whether score would make the table.
+func (t *HighScoreTable) Qualifies(score int) bool {
+	return t.rankOf(HighScore{Score: score}) >= 0
+}
+
+// Add puts a score in the table and returns its rank, 0 for the best, or -1
+// if it didn't make the table. Scores pushed out of the table are dropped.
+func (t *HighScoreTable) Add(name string, score int) int {
+	entry := HighScore{Name: name, Score: score}
+	rank := t.rankOf(entry)
+	if rank < 0 {
+		return -1
+	}
+	t.Scores = slices.Insert(t.Scores, rank, entry)
+	if len(t.Scores) > t.Size {
+		t.Scores = t.Scores[:t.Size]
+	}
+	t.lastAdded = rank
+	return rank
+}
+
+// StartEntry starts asking for the player's name if score makes the table,
+// with StartTextInput, and returns whether it does. Call Update every frame
+// until the name is confirmed.
+func (t *HighScoreTable) StartEntry(score int) bool {
+	t.lastAdded = -1
+	if !t.Qualifies(score) {
+		return false
+	}
+	t.entering = true
+	t.score = score
+	StartTextInput(t.MaxNameLength)
+	return true
+}
+
+// Entering reports whether the table is waiting for the player's name.
+func (t *HighScoreTable) Entering() bool {
+	return t.entering
+}
+
+// Update handles the name entry started by StartEntry. When Enter is pressed
+// with a name typed, it adds the score, stops the text input and returns true,
+// e.g. to Save the table. Otherwise it returns false.
+func (t *HighScoreTable) Update() bool {
+	if !t.entering || !Keyp("Enter") || GetTextInput() == "" {
+		return false
+	}
+	StopTextInput()
+	t.entering = false
+	t.Add(GetTextInput(), t.score)
+	return true
+}
+
+// Draw draws the title and the table at (X, Y), one numbered entry per line.
+// The score added last is highlighted, and while the name is being typed it
+// is shown at its future rank with a blinking cursor.
+func (t *HighScoreTable) Draw() {
+	lines := t.lines()
+	Print(t.Title, t.X, t.Y, t.TitleColor)
+	for i, line := range lines {
+		col := t.TextColor
+		if line.highlight {
+			col = t.HighlightColor
+		}
+		y := t.Y + (i+2)*(int(defaultFontSize)+2)
+		Print(line.rank, t.X, y, col)
+		Print(line.name, t.X+12, y, col)
+		Print(line.score, t.X+int(float64(t.MaxNameLength+4)*CharWidthApproximation), y, col)
+	}
+}
+
+// highScoreLine is one formatted entry of the table as Draw shows it.
+type highScoreLine struct {
+	rank, name, score string
+	highlight         bool
+}
+
+// lines formats the entries Draw shows, including the one being typed.
+func (t *HighScoreTable) lines() []highScoreLine {
+	scores := t.Scores
+	highlight := t.lastAdded
+	if t.entering {
+		cursor := " "
+		if int(T()*2)%2 == 0 {
+			cursor = "_"
+		}
+		entry := HighScore{Name: GetTextInput() + cursor, Score: t.score}
+		highlight = t.rankOf(entry)
+		if highlight >= 0 {
+			scores = slices.Insert(slices.Clone(scores), highlight, entry)
+			scores = scores[:min(len(scores), t.Size)]
+		}
+	}
+
+	lines := make([]highScoreLine, len(scores))
+	for i, s := range scores {
+		lines[i] = highScoreLine{
+			rank:      strconv.Itoa(i+1) + ".",
+			name:      s.Name,
+			score:     strconv.Itoa(s.Score),
+			highlight: i == highlight,
+		}
+	}
+	return lines
+}
+
+// Save writes the scores to a JSON file.
+func (t *HighScoreTable) Save(path string) error {
+	data, err := json.Marshal(t.Scores)
+	if err != nil {
+		return fmt.Errorf("error encoding high scores: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing high scores %s: %w", path, err)
+	}
+	return nil
+}
+
+// Load replaces the scores with the ones saved in a file by Save, sorted and
+// cut to Size. If the file doesn't exist yet the error wraps os.ErrNotExist,
+// and the table is left as it was.
+func (t *HighScoreTable) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading high scores %s: %w", path, err)
+	}
+	var scores []HighScore
+	if err := json.Unmarshal(data, &scores); err != nil {
+		return fmt.Errorf("error parsing high scores %s: %w", path, err)
+	}
+	slices.SortStableFunc(scores, func(a, b HighScore) int {
+		switch {
+		case t.better(a, b):
+			return -1
+		case t.better(b, a):
+			return 1
+		}
+		return 0
+	})
+	t.Scores = scores[:min(len(scores), t.Size)]
+	t.lastAdded = -1
+	return nil
+}
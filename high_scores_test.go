@@ -0,0 +1,87 @@
+package pigo8
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighScoreTableAdd(t *testing.T) {
+	table := NewHighScoreTable(3)
+	assert.True(t, table.Qualifies(0), "an empty table takes any score")
+
+	assert.Equal(t, 0, table.Add("ann", 100))
+	assert.Equal(t, 1, table.Add("bob", 50))
+	assert.Equal(t, 1, table.Add("cat", 100), "equal scores go after the earlier ones")
+	assert.Equal(t, []HighScore{{"ann", 100}, {"cat", 100}, {"bob", 50}}, table.Scores)
+
+	assert.False(t, table.Qualifies(50), "a full table needs a better score")
+	assert.Equal(t, -1, table.Add("dan", 50))
+	assert.Equal(t, 0, table.Add("eve", 200))
+	assert.Equal(t, []HighScore{{"eve", 200}, {"ann", 100}, {"cat", 100}}, table.Scores, "the worst score drops out")
+}
+
+func TestHighScoreTableBetter(t *testing.T) {
+	table := NewHighScoreTable(2)
+	table.Better = func(a, b HighScore) bool { return a.Score < b.Score } // Fastest time first
+	table.Add("slow", 90)
+	table.Add("fast", 30)
+	table.Add("mid", 60)
+	assert.Equal(t, []HighScore{{"fast", 30}, {"mid", 60}}, table.Scores)
+}
+
+func TestHighScoreTableLines(t *testing.T) {
+	table := NewHighScoreTable(3)
+	table.Add("ann", 100)
+	table.Add("bob", 50)
+	assert.Equal(t, []highScoreLine{
+		{"1.", "ann", "100", false},
+		{"2.", "bob", "50", true},
+	}, table.lines(), "the score added last is highlighted")
+
+	t.Cleanup(StopTextInput)
+	require.True(t, table.StartEntry(70))
+	assert.True(t, table.Entering())
+	lines := table.lines()
+	require.Len(t, lines, 3)
+	assert.Equal(t, "2.", lines[1].rank)
+	assert.Equal(t, "70", lines[1].score)
+	assert.True(t, lines[1].highlight, "the entry being typed is shown at its rank")
+	assert.Len(t, table.Scores, 2, "the score is only added once the name is confirmed")
+
+	StopTextInput()
+	table = NewHighScoreTable(1)
+	table.Add("ann", 100)
+	assert.False(t, table.StartEntry(10))
+	assert.False(t, table.Entering())
+	assert.False(t, IsTextInputActive())
+}
+
+func TestHighScoreTableSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.json")
+	table := NewHighScoreTable(3)
+
+	err := table.Load(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	table.Add("ann", 100)
+	table.Add("bob", 50)
+	require.NoError(t, table.Save(path))
+
+	loaded := NewHighScoreTable(3)
+	require.NoError(t, loaded.Load(path))
+	assert.Equal(t, table.Scores, loaded.Scores)
+
+	// Files edited by hand are sorted and cut to size
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name":"c","score":1},{"name":"a","score":3},{"name":"b","score":2}]`), 0o644))
+	small := NewHighScoreTable(2)
+	require.NoError(t, small.Load(path))
+	assert.Equal(t, []HighScore{{"a", 3}, {"b", 2}}, small.Scores)
+
+	require.NoError(t, os.WriteFile(path, []byte(`not json`), 0o644))
+	assert.Error(t, small.Load(path))
+	assert.Len(t, small.Scores, 2, "a bad file leaves the table as it was")
+}
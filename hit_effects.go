@@ -0,0 +1,101 @@
+package pigo8
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// --- Screen flash and hit-stop ---
+
+// maxHitStopFrames caps the frames HitStop freezes the game for, however
+// many hits add up.
+const maxHitStopFrames = 30
+
+var (
+	flashColor  int // Palette color of the current flash
+	flashFrames int // Length of the current flash, in frames
+	flashLeft   int // Frames left before the current flash is gone
+	// hitStopLeft is the number of frames the game logic stays frozen.
+	hitStopLeft int
+)
+
+// FlashScreen covers the whole screen with a palette color that fades out
+// over frames frames, e.g. white for a hit or red for damage. The flash is
+// drawn over the game (and its DrawAtLayer draws), ignoring the camera, and
+// starts fully opaque on the next drawn frame. A new flash replaces the one
+// showing, so flashes from several hits on one frame don't add up to a
+// longer one. It counts engine frames, so it fades during a HitStop and plays
+// out the same way in replays. FlashScreen(0, 0) cancels it.
+//
+// Example:
+//
+//	if g.player.hit {
+//		FlashScreen(7, 6)
+//		HitStop(4)
+//	}
+func FlashScreen(color, frames int) {
+	if frames <= 0 || color < 0 || color >= len(pico8Palette) {
+		flashLeft = 0
+		return
+	}
+	flashColor, flashFrames, flashLeft = color, frames, frames
+}
+
+// HitStop freezes the game logic for frames frames while drawing goes on, so
+// a hit lands with weight. During a hit-stop the cartridge's Update doesn't
+// run and Frame(), T(), timers and tweens stand still; the input of those
+// frames is ignored, so replays stay in step. Calls add up, e.g. for several
+// hits on one frame, up to 30 frames.
+//
+// Example:
+//
+//	if bullet.Hits(enemy) {
+//		HitStop(3)
+//	}
+func HitStop(frames int) {
+	if frames <= 0 {
+		return
+	}
+	hitStopLeft = min(hitStopLeft+frames, maxHitStopFrames)
+}
+
+// HitStopFrames returns how many more frames the game logic stays frozen.
+func HitStopFrames() int {
+	return hitStopLeft
+}
+
+// consumeHitStopFrame reports whether the game logic is frozen by HitStop on
+// this frame, counting the frame down if so.
+func consumeHitStopFrame() bool {
+	if hitStopLeft == 0 {
+		return false
+	}
+	hitStopLeft--
+	return true
+}
+
+// updateScreenFlash fades the flash by one frame. The engine calls it once
+// per Update, also during hit-stops and pauses.
+func updateScreenFlash() {
+	if flashLeft > 0 {
+		flashLeft--
+	}
+}
+
+// drawScreenFlash draws the flash over the finished game frame.
+func drawScreenFlash() {
+	if flashLeft == 0 || currentScreen == nil || flashColor >= len(pico8Palette) {
+		return
+	}
+	r, g, b, _ := pico8Palette[flashColor].RGBA()
+	alpha := uint8(255 * flashLeft / flashFrames)
+	vector.DrawFilledRect(currentScreen, 0, 0, float32(GetScreenWidth()), float32(GetScreenHeight()),
+		color.NRGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: alpha}, false)
+}
+
+// resetScreenEffects stops the flash and the hit-stop, e.g. on restart.
+func resetScreenEffects() {
+	flashLeft = 0
+	hitStopLeft = 0
+}
@@ -0,0 +1,73 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHitStop(t *testing.T) {
+	originalCart := loadedCartridge
+	t.Cleanup(func() {
+		InsertGame(originalCart)
+		resetInputState()
+		resetScreenEffects()
+		Restart = false
+	})
+
+	game := &walkerCartridge{}
+	h := NewTestHarness(game, nil)
+	h.Init()
+	h.InjectButton(RIGHT, true)
+
+	h.Step()
+	assert.Equal(t, 11, game.x)
+
+	HitStop(2)
+	HitStop(1) // Hits on the same frame add up
+	assert.Equal(t, 3, HitStopFrames())
+	h.AdvanceFrames(3)
+	assert.Equal(t, 11, game.x, "Update doesn't run during a hit-stop")
+	assert.Equal(t, 1, Frame(), "the clock stands still")
+	assert.Equal(t, 0, HitStopFrames())
+
+	h.Step()
+	assert.Equal(t, 12, game.x, "the game goes on after the hit-stop")
+
+	HitStop(20)
+	HitStop(20)
+	assert.Equal(t, maxHitStopFrames, HitStopFrames(), "hit-stops are capped")
+	HitStop(-5)
+	assert.Equal(t, maxHitStopFrames, HitStopFrames())
+
+	RestartGame()
+	h.Step()
+	assert.Equal(t, 0, HitStopFrames(), "restarting ends the hit-stop")
+}
+
+func TestFlashScreen(t *testing.T) {
+	t.Cleanup(resetScreenEffects)
+
+	FlashScreen(7, 3)
+	assert.Equal(t, 3, flashLeft)
+	updateScreenFlash()
+	updateScreenFlash()
+	assert.Equal(t, 1, flashLeft, "the flash fades frame by frame")
+
+	FlashScreen(8, 4)
+	assert.Equal(t, 8, flashColor, "a new flash replaces the current one")
+	assert.Equal(t, 4, flashLeft)
+
+	HitStop(2)
+	h := consumeHitStopFrame()
+	updateScreenFlash()
+	assert.True(t, h)
+	assert.Equal(t, 3, flashLeft, "the flash fades during a hit-stop")
+
+	FlashScreen(7, 0)
+	assert.Equal(t, 0, flashLeft, "FlashScreen(c, 0) cancels it")
+	FlashScreen(99, 5)
+	assert.Equal(t, 0, flashLeft, "invalid colors are ignored")
+	updateScreenFlash()
+	assert.Equal(t, 0, flashLeft)
+}
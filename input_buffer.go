@@ -0,0 +1,158 @@
+package pigo8
+
+// --- Input buffer ---
+
+// defaultInputBufferSize is the number of presses an InputBuffer keeps when
+// created with a size below 1: enough for the longest fighting game motions.
+const defaultInputBufferSize = 32
+
+// InputPress is a button press recorded by an InputBuffer.
+type InputPress struct {
+	Button int
+	Frame  int // The InputBuffer frame the button went down on
+}
+
+// InputBuffer records one player's recent button presses with the frame they
+// happened on, to detect sequences such as fighting game motions (down,
+// down-forward, punch) or double taps.
+//
+// Like Timer, it is not advanced by the engine: call Update once per Update
+// of the cartridge, and frames count those calls, so the buffer stops while
+// the game is paused and behaves the same in tests and StepSim. Use one
+// buffer per player.
+//
+// Only presses are recorded, on the frame a button goes down: holding a
+// button doesn't add presses, auto-repeat is ignored, and releases don't
+// count. Presses on the same frame are simultaneous and can match a sequence
+// in any order. Once the buffer holds its size, the oldest press is dropped
+// for every new one.
+//
+// Example:
+//
+//	type Game struct {
+//		input *InputBuffer
+//	}
+//
+//	func (g *Game) Init() {
+//		g.input = NewInputBuffer(0, 16)
+//	}
+//
+//	func (g *Game) Update() {
+//		g.input.Update()
+//		if g.input.MatchSequence([]int{DOWN, RIGHT, O}, 15) {
+//			g.fireball()
+//		}
+//		if g.input.MatchSequence([]int{RIGHT, RIGHT}, 10) {
+//			g.running = true // Double tap to run
+//		}
+//	}
+type InputBuffer struct {
+	// Player is the player index the buttons are read for, like the
+	// playerIndex of Btn.
+	Player int
+	// Buttons are the buttons recorded, the six PICO-8 buttons by default.
+	Buttons []int
+
+	size    int
+	frame   int
+	presses []InputPress // Oldest first
+}
+
+// NewInputBuffer creates an empty buffer for player that keeps the last size
+// presses of the six PICO-8 buttons (LEFT, RIGHT, UP, DOWN, O and X). A size
+// below 1 keeps 32.
+func NewInputBuffer(player, size int) *InputBuffer {
+	if size < 1 {
+		size = defaultInputBufferSize
+	}
+	return &InputBuffer{
+		Player:  player,
+		Buttons: []int{LEFT, RIGHT, UP, DOWN, O, X},
+		size:    size,
+	}
+}
+
+// Update starts a new frame and records the buttons pressed on it, in the
+// order of Buttons.
+func (b *InputBuffer) Update() {
+	b.frame++
+	for _, button := range b.Buttons {
+		if BtnHoldFrames(button, b.Player) == 1 {
+			b.Press(button)
+		}
+	}
+}
+
+// Press records a press of button on the current frame, as if Update saw it.
+// Use it to feed the buffer from another source, such as the input of a
+// remote player or a replay. A button pressed twice on one frame counts once.
+func (b *InputBuffer) Press(button int) {
+	for i := len(b.presses) - 1; i >= 0 && b.presses[i].Frame == b.frame; i-- {
+		if b.presses[i].Button == button {
+			return
+		}
+	}
+	if len(b.presses) == b.size {
+		copy(b.presses, b.presses[1:])
+		b.presses = b.presses[:b.size-1]
+	}
+	b.presses = append(b.presses, InputPress{Button: button, Frame: b.frame})
+}
+
+// MatchSequence reports whether the buttons of sequence were pressed in order
+// within the last windowFrames frames, counting the current one, with the
+// last button pressed on the current frame. Requiring the last press to be
+// new makes a match fire once, on the frame the sequence is completed, like
+// Btnp.
+//
+// Other presses may come in between (a sloppy DOWN, LEFT, RIGHT, O still
+// matches DOWN, RIGHT, O), and presses on the same frame match in any
+// order. Each press matches one step, so a double tap needs two presses.
+// An empty sequence never matches.
+func (b *InputBuffer) MatchSequence(sequence []int, windowFrames int) bool {
+	if len(sequence) == 0 || windowFrames < 1 {
+		return false
+	}
+	oldest := b.frame - windowFrames + 1
+
+	// Match from the last step back, each step with the latest press of its
+	// button no later than the press of the step after it
+	used := make([]bool, len(b.presses))
+	latest := b.frame
+	for step := len(sequence) - 1; step >= 0; step-- {
+		found := -1
+		for i := len(b.presses) - 1; i >= 0; i-- {
+			p := b.presses[i]
+			if p.Frame < oldest {
+				break
+			}
+			if p.Frame <= latest && p.Button == sequence[step] && !used[i] {
+				found = i
+				break
+			}
+		}
+		if found == -1 || (step == len(sequence)-1 && b.presses[found].Frame != b.frame) {
+			return false
+		}
+		used[found] = true
+		latest = b.presses[found].Frame
+	}
+	return true
+}
+
+// Presses returns a copy of the recorded presses, oldest first.
+func (b *InputBuffer) Presses() []InputPress {
+	return append([]InputPress(nil), b.presses...)
+}
+
+// Frame returns the current frame of the buffer: the number of Update calls.
+func (b *InputBuffer) Frame() int {
+	return b.frame
+}
+
+// Clear forgets every recorded press, e.g. after a special move so its
+// inputs can't start another one.
+func (b *InputBuffer) Clear() {
+	clear(b.presses)
+	b.presses = b.presses[:0]
+}
@@ -0,0 +1,90 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// feed runs one buffer frame per entry, pressing the buttons of the entry.
+func feed(b *InputBuffer, frames ...[]int) {
+	for _, pressed := range frames {
+		b.frame++
+		for _, button := range pressed {
+			b.Press(button)
+		}
+	}
+}
+
+func TestInputBufferMatchSequence(t *testing.T) {
+	fireball := []int{DOWN, RIGHT, O}
+
+	b := NewInputBuffer(0, 0)
+	feed(b, []int{DOWN}, nil, []int{RIGHT}, nil, []int{O})
+	assert.True(t, b.MatchSequence(fireball, 5))
+	assert.False(t, b.MatchSequence(fireball, 4), "DOWN is out of the window")
+
+	feed(b, nil)
+	assert.False(t, b.MatchSequence(fireball, 10), "only on the frame of the last press")
+
+	b = NewInputBuffer(0, 0)
+	feed(b, []int{DOWN}, []int{LEFT}, []int{RIGHT}, []int{O})
+	assert.True(t, b.MatchSequence(fireball, 10), "presses in between are ignored")
+
+	b = NewInputBuffer(0, 0)
+	feed(b, []int{RIGHT}, []int{DOWN}, []int{O})
+	assert.False(t, b.MatchSequence(fireball, 10), "order matters")
+
+	assert.False(t, b.MatchSequence(nil, 10))
+	assert.False(t, b.MatchSequence([]int{O}, 0))
+}
+
+func TestInputBufferSimultaneousPresses(t *testing.T) {
+	b := NewInputBuffer(0, 0)
+	feed(b, []int{DOWN}, []int{O, RIGHT})
+	assert.True(t, b.MatchSequence([]int{DOWN, RIGHT, O}, 5))
+	assert.True(t, b.MatchSequence([]int{DOWN, O, RIGHT}, 5), "same frame, any order")
+
+	b.Press(O)
+	assert.Len(t, b.Presses(), 3, "a button counts once per frame")
+}
+
+func TestInputBufferDoubleTap(t *testing.T) {
+	run := []int{RIGHT, RIGHT}
+
+	b := NewInputBuffer(0, 0)
+	feed(b, []int{RIGHT})
+	assert.False(t, b.MatchSequence(run, 10), "one press is not a double tap")
+	feed(b, nil, nil, []int{RIGHT})
+	assert.True(t, b.MatchSequence(run, 10))
+
+	b.Clear()
+	feed(b, []int{RIGHT})
+	assert.False(t, b.MatchSequence(run, 10), "Clear forgets the presses")
+}
+
+func TestInputBufferSize(t *testing.T) {
+	b := NewInputBuffer(0, 2)
+	feed(b, []int{DOWN}, []int{RIGHT}, []int{O})
+	assert.Equal(t, []InputPress{{RIGHT, 2}, {O, 3}}, b.Presses())
+	assert.False(t, b.MatchSequence([]int{DOWN, RIGHT, O}, 10), "the oldest press was dropped")
+	assert.Equal(t, 3, b.Frame())
+}
+
+func TestInputBufferUpdateRecordsPresses(t *testing.T) {
+	t.Cleanup(resetInputState)
+	resetInputState()
+	b := NewInputBuffer(0, 0)
+
+	InjectButton(DOWN, true)
+	refreshInputCache(false)
+	b.Update()
+	refreshInputCache(false)
+	b.Update()
+	InjectButton(RIGHT, true)
+	refreshInputCache(false)
+	b.Update()
+
+	assert.Equal(t, []InputPress{{DOWN, 1}, {RIGHT, 3}}, b.Presses(), "holding a button is one press")
+	assert.True(t, b.MatchSequence([]int{DOWN, RIGHT}, 3))
+}
@@ -0,0 +1,196 @@
+package pigo8
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// --- Raw keyboard input ---
+
+// keyAliases maps friendlier key names to Ebitengine key names.
+var keyAliases = map[string]string{
+	"ctrl":   "Control",
+	"cmd":    "Meta",
+	"super":  "Meta",
+	"win":    "Meta",
+	"option": "Alt",
+	"esc":    "Escape",
+	"return": "Enter",
+	"del":    "Delete",
+	"left":   "ArrowLeft",
+	"right":  "ArrowRight",
+	"up":     "ArrowUp",
+	"down":   "ArrowDown",
+}
+
+// keyChord is a parsed key name: every part must be held, and a part is
+// held if any of its keys is.
+type keyChord [][]ebiten.Key
+
+var (
+	// keyChords caches parsed key names; nil means the name is invalid.
+	keyChords      = make(map[string]keyChord)
+	keyChordsMutex sync.Mutex
+)
+
+// Key reports whether a keyboard key, or a chord of keys, is held down.
+// Unlike Btn, it reads the keyboard directly, for tools and keyboard
+// shortcuts that PICO-8's buttons don't cover.
+//
+// Key names are case-insensitive Ebitengine key names, such as "A", "Digit1",
+// "Space", "Enter", "Escape", "ArrowUp", "F1", "Shift", "Control", "Alt" and
+// "Meta", plus the shorter "Ctrl", "Cmd", "Esc", "Up", "Down", "Left" and
+// "Right". Join names with "+" for a chord, e.g. "Shift+Tab". "CmdOrCtrl"
+// stands for the platform's shortcut modifier (see CmdOrCtrlHeld).
+//
+// Unknown names log a warning once and are never held.
+//
+// Example:
+//
+//	if Key("Shift") {
+//		speed = 2 // Run while Shift is held
+//	}
+func Key(name string) bool {
+	chord := parseKeyChord(name)
+	if chord == nil {
+		return false
+	}
+	for _, part := range chord {
+		if !anyKeyPressed(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// Keyp reports whether a keyboard key, or a chord of keys, was just pressed.
+// For a chord, it is true on the frame its last key is pressed while the
+// other keys are held, so "CmdOrCtrl+Z" fires once per Z press. Keys don't
+// auto-repeat. Key names are the same as for Key.
+//
+// Example:
+//
+//	if Keyp("CmdOrCtrl+Shift+Z") {
+//		redo()
+//	} else if Keyp("CmdOrCtrl+Z") {
+//		undo()
+//	}
+func Keyp(name string) bool {
+	chord := parseKeyChord(name)
+	if chord == nil {
+		return false
+	}
+	last := len(chord) - 1
+	for _, part := range chord[:last] {
+		if !anyKeyPressed(part) {
+			return false
+		}
+	}
+	for _, key := range chord[last] {
+		if inpututil.IsKeyJustPressed(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// CtrlHeld reports whether either Control key is held.
+func CtrlHeld() bool {
+	return ebiten.IsKeyPressed(ebiten.KeyControl)
+}
+
+// ShiftHeld reports whether either Shift key is held.
+func ShiftHeld() bool {
+	return ebiten.IsKeyPressed(ebiten.KeyShift)
+}
+
+// AltHeld reports whether either Alt (Option on macOS) key is held.
+func AltHeld() bool {
+	return ebiten.IsKeyPressed(ebiten.KeyAlt)
+}
+
+// MetaHeld reports whether either Meta key is held: Cmd on macOS, the
+// Windows key on Windows.
+func MetaHeld() bool {
+	return ebiten.IsKeyPressed(ebiten.KeyMeta)
+}
+
+// CmdOrCtrlHeld reports whether the platform's shortcut modifier is held:
+// Cmd on macOS and Ctrl everywhere else. In a browser, where the operating
+// system isn't known, either one counts. Use it for shortcuts like copy and
+// paste so they feel native on every platform.
+//
+// Example:
+//
+//	if CmdOrCtrlHeld() && Keyp("S") {
+//		save()
+//	}
+func CmdOrCtrlHeld() bool {
+	return anyKeyPressed(cmdOrCtrlKeys(runtime.GOOS))
+}
+
+// cmdOrCtrlKeys returns the keys that are the shortcut modifier on goos.
+func cmdOrCtrlKeys(goos string) []ebiten.Key {
+	switch goos {
+	case "darwin", "ios":
+		return []ebiten.Key{ebiten.KeyMeta}
+	case "js":
+		return []ebiten.Key{ebiten.KeyMeta, ebiten.KeyControl}
+	default:
+		return []ebiten.Key{ebiten.KeyControl}
+	}
+}
+
+// anyKeyPressed reports whether any of keys is held.
+func anyKeyPressed(keys []ebiten.Key) bool {
+	for _, key := range keys {
+		if ebiten.IsKeyPressed(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseKeyChord returns the keys of a key name, or nil (after a one-time
+// warning) if it is invalid. Results are cached, so names can be passed to
+// Key and Keyp every frame.
+func parseKeyChord(name string) keyChord {
+	keyChordsMutex.Lock()
+	defer keyChordsMutex.Unlock()
+
+	if chord, ok := keyChords[name]; ok {
+		return chord
+	}
+	chord, bad := parseKeyChordFor(name, runtime.GOOS)
+	if chord == nil {
+		logWarningOnce("Warning: unknown key name %q in %q", bad, name)
+	}
+	keyChords[name] = chord
+	return chord
+}
+
+// parseKeyChordFor parses a key name for the platform goos. If it is
+// invalid, it returns nil and the part that could not be parsed.
+func parseKeyChordFor(name, goos string) (keyChord, string) {
+	var chord keyChord
+	for _, part := range strings.Split(name, "+") {
+		part = strings.TrimSpace(part)
+		if strings.EqualFold(part, "CmdOrCtrl") {
+			chord = append(chord, cmdOrCtrlKeys(goos))
+			continue
+		}
+		if alias, ok := keyAliases[strings.ToLower(part)]; ok {
+			part = alias
+		}
+		var key ebiten.Key
+		if part == "" || key.UnmarshalText([]byte(part)) != nil {
+			return nil, part
+		}
+		chord = append(chord, []ebiten.Key{key})
+	}
+	return chord, ""
+}
@@ -0,0 +1,46 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeyChordFor(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		want keyChord
+	}{
+		{"a", "linux", keyChord{{ebiten.KeyA}}},
+		{"Space", "linux", keyChord{{ebiten.KeySpace}}},
+		{"esc", "linux", keyChord{{ebiten.KeyEscape}}},
+		{"Up", "linux", keyChord{{ebiten.KeyArrowUp}}},
+		{"Ctrl+Shift+Z", "windows", keyChord{{ebiten.KeyControl}, {ebiten.KeyShift}, {ebiten.KeyZ}}},
+		{"shift + tab", "linux", keyChord{{ebiten.KeyShift}, {ebiten.KeyTab}}},
+		{"CmdOrCtrl+C", "darwin", keyChord{{ebiten.KeyMeta}, {ebiten.KeyC}}},
+		{"CmdOrCtrl+C", "windows", keyChord{{ebiten.KeyControl}, {ebiten.KeyC}}},
+		{"cmdorctrl+v", "js", keyChord{{ebiten.KeyMeta, ebiten.KeyControl}, {ebiten.KeyV}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.goos, func(t *testing.T) {
+			chord, bad := parseKeyChordFor(tt.name, tt.goos)
+			assert.Equal(t, tt.want, chord)
+			assert.Empty(t, bad)
+		})
+	}
+
+	for name, bad := range map[string]string{"Hyper": "Hyper", "Ctrl+": "", "Ctrl+Bogus+A": "Bogus"} {
+		chord, gotBad := parseKeyChordFor(name, "linux")
+		assert.Nil(t, chord, name)
+		assert.Equal(t, bad, gotBad, name)
+	}
+}
+
+func TestKeyWithUnknownName(t *testing.T) {
+	assert.False(t, Key("NoSuchKey"))
+	assert.False(t, Keyp("Ctrl+NoSuchKey"))
+	assert.Contains(t, keyChords, "NoSuchKey", "invalid names are cached too")
+	assert.Nil(t, keyChords["NoSuchKey"])
+}
@@ -0,0 +1,73 @@
+package pigo8
+
+import (
+	"log"
+	"slices"
+)
+
+// --- Draw layers ---
+
+// layeredDraw is a draw function queued by DrawAtLayer.
+type layeredDraw struct {
+	z  int
+	fn func()
+}
+
+var (
+	// layerQueue holds the draws queued by DrawAtLayer during this frame.
+	layerQueue []layeredDraw
+	// layerScratch is reused by flushDrawLayers to run the queue while new draws are queued.
+	layerScratch []layeredDraw
+)
+
+// DrawAtLayer queues fn to be called at the end of Draw, sorted by z: lower
+// layers are drawn first and end up behind higher ones. Draws on the same
+// layer keep the order they were queued in. Use it in Draw to sort entities
+// by depth without restructuring the game, e.g. with y as the layer in a
+// top-down or isometric game.
+//
+// Queued draws run after the cartridge's Draw returns, so they are drawn on
+// top of everything drawn directly. They use the camera and palette state
+// at that time (the state left at the end of Draw), not the state when
+// DrawAtLayer was called; set them inside fn if they differ.
+//
+// Drawing stays immediate unless DrawAtLayer is used. Draws queued from a
+// queued fn run after the current queue, sorted among themselves. In
+// headless mode, where Draw is never called, and during StepSim, nothing is
+// queued.
+//
+// Example:
+//
+//	func (g *Game) Draw() {
+//		Cls(0)
+//		Map()
+//		for _, e := range g.entities {
+//			// Entities lower on screen are drawn in front
+//			DrawAtLayer(int(e.y), func() { Spr(e.sprite, e.x, e.y) })
+//		}
+//	}
+func DrawAtLayer(z int, fn func()) {
+	if fn == nil {
+		log.Println("Warning: DrawAtLayer() called with a nil function. Ignoring.")
+		return
+	}
+	if outputMuted() {
+		return
+	}
+	layerQueue = append(layerQueue, layeredDraw{z: z, fn: fn})
+}
+
+// flushDrawLayers runs the draws queued by DrawAtLayer in layer order and
+// empties the queue. The engine calls it after the cartridge's Draw.
+func flushDrawLayers() {
+	for len(layerQueue) > 0 {
+		layerScratch, layerQueue = layerQueue, layerScratch[:0]
+		slices.SortStableFunc(layerScratch, func(a, b layeredDraw) int {
+			return a.z - b.z
+		})
+		for _, draw := range layerScratch {
+			draw.fn()
+		}
+		clear(layerScratch) // Drop the closures so they can be collected
+	}
+}
@@ -0,0 +1,52 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrawAtLayerOrder(t *testing.T) {
+	t.Cleanup(func() { layerQueue = nil })
+
+	var order []string
+	DrawAtLayer(2, func() { order = append(order, "front") })
+	DrawAtLayer(0, func() { order = append(order, "back") })
+	DrawAtLayer(1, func() { order = append(order, "middle 1") })
+	DrawAtLayer(1, func() { order = append(order, "middle 2") })
+	DrawAtLayer(-5, func() { order = append(order, "background") })
+	assert.Empty(t, order, "queued draws should wait for the flush")
+
+	flushDrawLayers()
+	assert.Equal(t, []string{"background", "back", "middle 1", "middle 2", "front"}, order,
+		"draws should run by layer, keeping the queue order within a layer")
+	assert.Empty(t, layerQueue)
+
+	// The queue starts empty every frame
+	order = nil
+	flushDrawLayers()
+	assert.Empty(t, order)
+}
+
+func TestDrawAtLayerNested(t *testing.T) {
+	t.Cleanup(func() { layerQueue = nil })
+
+	var order []int
+	DrawAtLayer(10, func() {
+		order = append(order, 10)
+		DrawAtLayer(0, func() { order = append(order, 0) })
+	})
+	DrawAtLayer(5, func() { order = append(order, 5) })
+	DrawAtLayer(0, nil) // Ignored
+
+	flushDrawLayers()
+	assert.Equal(t, []int{5, 10, 0}, order, "draws queued while flushing should run after the current queue")
+}
+
+func TestDrawAtLayerHeadless(t *testing.T) {
+	t.Cleanup(func() { headless, layerQueue = false, nil })
+	headless = true
+
+	DrawAtLayer(0, func() {})
+	assert.Empty(t, layerQueue, "nothing should be queued without a Draw to flush it")
+}
@@ -0,0 +1,156 @@
+package pigo8
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// --- Dashed lines ---
+
+// LineDash is a dash pattern for Line, made with WithDash.
+type LineDash struct {
+	// Pattern alternates the lengths in pixels of the dashes and the gaps
+	// between them, starting with a dash. An odd number of lengths is
+	// repeated once, so WithDash(2) is 2 on, 2 off.
+	Pattern []int
+	// Offset shifts the pattern along the line by that many pixels; increase
+	// it every frame for marching ants.
+	Offset int
+}
+
+// WithDash returns a dash pattern for Line: the lengths in pixels of the
+// dashes and the gaps between them, starting with a dash. Lengths are counted
+// in pixels along the longer axis of the line, so a diagonal dash of 4 covers
+// 4 columns or rows, and the pattern starts at (x1, y1). Without lengths, or
+// with lengths that are all 0 or negative, the line is solid.
+//
+// Thick dashes have round caps like solid thick lines, so each dash grows by
+// half the thickness at both ends; use gaps wider than the thickness to keep
+// them apart.
+//
+// Example:
+//
+//	Line(0, 64, 127, 64, 7, WithDash(1, 1))    // Dotted
+//	Line(0, 70, 127, 70, 7, WithDash(6, 2, 1, 2)) // Dash-dot
+//
+//	border := WithDash(3, 3)
+//	border.Offset = Frame() / 2 // Marching ants
+//	Line(10, 10, 100, 10, 10, border)
+func WithDash(pattern ...int) LineDash {
+	return LineDash{Pattern: pattern}
+}
+
+// splitLineDash removes the LineDash options from options and returns the
+// remaining positional options and the last dash pattern (nil if none, or if
+// it draws a solid line).
+func splitLineDash(options []any) ([]any, *LineDash) {
+	found := false
+	for _, opt := range options {
+		if _, ok := opt.(LineDash); ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return options, nil
+	}
+
+	rest := make([]any, 0, len(options))
+	var dash LineDash
+	for _, opt := range options {
+		if d, ok := opt.(LineDash); ok {
+			dash = d
+			continue
+		}
+		rest = append(rest, opt)
+	}
+	if dashPatternLength(dash.Pattern) == 0 {
+		if len(dash.Pattern) > 0 {
+			logWarningOnce("Warning: WithDash(%v) has no positive lengths. Drawing a solid line.", dash.Pattern)
+		}
+		return rest, nil
+	}
+	return rest, &dash
+}
+
+// dashPatternLength returns the length of one repetition of pattern in
+// pixels, with negative lengths counted as 0 and odd patterns doubled.
+func dashPatternLength(pattern []int) int {
+	total := 0
+	for _, length := range pattern {
+		total += max(length, 0)
+	}
+	if len(pattern)%2 == 1 {
+		total *= 2
+	}
+	return total
+}
+
+// dashRuns returns the runs of pixels, as first and last index from 0 to
+// steps, that the dash pattern draws on a line of steps+1 pixels.
+func dashRuns(steps int, dash LineDash) [][2]int {
+	pattern := dash.Pattern
+	if len(pattern)%2 == 1 {
+		pattern = append(append([]int(nil), pattern...), pattern...)
+	}
+	period := dashPatternLength(pattern)
+	if period == 0 {
+		return [][2]int{{0, steps}}
+	}
+
+	var runs [][2]int
+	start := -1
+	for i := 0; i <= steps; i++ {
+		phase := ((i+dash.Offset)%period + period) % period
+		on := false
+		for j, length := range pattern {
+			length = max(length, 0)
+			if phase < length {
+				on = j%2 == 0
+				break
+			}
+			phase -= length
+		}
+		switch {
+		case on && start < 0:
+			start = i
+		case !on && start >= 0:
+			runs = append(runs, [2]int{start, i - 1})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		runs = append(runs, [2]int{start, steps})
+	}
+	return runs
+}
+
+// drawDashedLine draws the dashes of a line between two screen pixels.
+func drawDashedLine(x1, y1, x2, y2 float64, dash LineDash, thickness float32, clr color.Color) {
+	steps := int(math.Max(math.Abs(x2-x1), math.Abs(y2-y1)))
+	point := func(i int) (float32, float32) {
+		if steps == 0 {
+			return float32(x1), float32(y1)
+		}
+		t := float64(i) / float64(steps)
+		return float32(math.Round(Lerp(x1, x2, t))), float32(math.Round(Lerp(y1, y2, t)))
+	}
+
+	for _, run := range dashRuns(steps, dash) {
+		if thickness <= 1 {
+			// One square per pixel, so even 1 pixel dots are drawn
+			for i := run[0]; i <= run[1]; i++ {
+				px, py := point(i)
+				vector.DrawFilledRect(currentScreen, px, py, 1, 1, clr, false)
+			}
+			continue
+		}
+		ax, ay := point(run[0])
+		bx, by := point(run[1])
+		vector.StrokeLine(currentScreen, ax, ay, bx, by, thickness, clr, false)
+		vector.DrawFilledCircle(currentScreen, ax, ay, thickness/2, clr, false)
+		vector.DrawFilledCircle(currentScreen, bx, by, thickness/2, clr, false)
+	}
+}
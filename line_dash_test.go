@@ -0,0 +1,36 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashRuns(t *testing.T) {
+	assert.Equal(t, [][2]int{{0, 1}, {4, 5}, {8, 9}}, dashRuns(9, WithDash(2, 2)))
+	assert.Equal(t, [][2]int{{0, 1}, {4, 5}, {8, 9}}, dashRuns(9, WithDash(2)), "an odd pattern repeats: 2 on, 2 off")
+	assert.Equal(t, [][2]int{{0, 0}, {2, 2}, {4, 4}}, dashRuns(4, WithDash(1, 1)), "dotted")
+	assert.Equal(t, [][2]int{{0, 2}, {4, 4}, {6, 8}}, dashRuns(8, WithDash(3, 1, 1, 1)), "dash-dot")
+	assert.Equal(t, [][2]int{{0, 0}}, dashRuns(0, WithDash(2, 2)), "a one pixel line starts with a dash")
+
+	shifted := WithDash(2, 2)
+	shifted.Offset = 1
+	assert.Equal(t, [][2]int{{0, 0}, {3, 4}, {7, 8}}, dashRuns(8, shifted))
+	shifted.Offset = -1
+	assert.Equal(t, [][2]int{{1, 2}, {5, 6}}, dashRuns(7, shifted), "negative offsets shift the other way")
+}
+
+func TestSplitLineDash(t *testing.T) {
+	options := []any{8, 3}
+	rest, dash := splitLineDash(options)
+	assert.Equal(t, options, rest)
+	assert.Nil(t, dash)
+
+	rest, dash = splitLineDash([]any{8, WithDash(4, 2), 3})
+	assert.Equal(t, []any{8, 3}, rest, "the pattern isn't a color or thickness")
+	assert.Equal(t, &LineDash{Pattern: []int{4, 2}}, dash)
+
+	rest, dash = splitLineDash([]any{WithDash(0, -1)})
+	assert.Empty(t, rest)
+	assert.Nil(t, dash, "a pattern without positive lengths draws a solid line")
+}
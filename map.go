@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
 	"runtime"
 	"sync"
 
@@ -84,16 +83,11 @@ var (
 
 	spriteInfoMap map[int]*spriteInfo // Preserved
 
-	// Map Caching (Preserved)
-	mapCacheImage                *ebiten.Image
-	mapCacheIsValid              bool
-	mapCacheDrawnForWorldTileX   int
-	mapCacheDrawnForWorldTileY   int
-	mapCacheWidthInTiles         int
-	mapCacheHeightInTiles        int
-	mapCacheRenderedLayers       int
-	mapCacheRenderedScreenWidth  int
-	mapCacheRenderedScreenHeight int
+	// Map Caching: one image per region and layers drawn, so drawing several
+	// layers every frame doesn't rebuild the cache on each call. Setting
+	// mapCacheIsValid to false drops them all.
+	mapCaches       = make(map[mapCacheKey]*ebiten.Image)
+	mapCacheIsValid bool
 
 	// Memory monitoring (Preserved)
 	lastMemoryUsage uint64
@@ -159,37 +153,13 @@ func logMemory(label string, forceLog bool) {
 	}
 }
 
-// loadAndParseMapJSON reads and parses a map JSON file.
+// loadAndParseMapJSON reads and parses a map JSON file, from disk or embedded
+// resources as chosen with SetAssetSource.
 func loadAndParseMapJSON(filename string) (*mapDataJSON, error) {
-	data, err := os.ReadFile(filename)
+	// Load from disk or embedded resources, depending on the asset source
+	data, err := readAssetFile(filename, tryLoadEmbeddedMap)
 	if err != nil {
-		commonLocations := []string{
-			filepath.Join("assets", filename),
-			filepath.Join("resources", filename),
-			filepath.Join("data", filename),
-			filepath.Join("static", filename),
-		}
-		found := false
-		for _, location := range commonLocations {
-			data, err = os.ReadFile(location)
-			if err == nil {
-				log.Printf("Loaded map JSON from %s", location)
-				found = true
-				break
-			}
-		}
-		if !found {
-			log.Printf("Map JSON file '%s' not found in common locations, trying embedded resources", filename)
-			// Assuming tryLoadEmbeddedMap() returns []byte, error and is defined elsewhere
-			embeddedData, embErr := tryLoadEmbeddedMap()
-			if embErr != nil {
-				return nil, fmt.Errorf("map file '%s' not found locally and failed to load embedded map: %w", filename, embErr)
-			}
-			data = embeddedData
-			log.Printf("Loaded map JSON from embedded resources.")
-		}
-	} else {
-		log.Printf("Loaded map JSON from %s", filename)
+		return nil, err
 	}
 
 	var jsonData mapDataJSON
@@ -216,8 +186,12 @@ func loadAndParseMapJSON(filename string) (*mapDataJSON, error) {
 // Optional args: [mx, my, sx, sy, w, h, layers]
 //   - mx, my: map tile coordinates in tiles (defaults 0,0)
 //   - sx, sy: screen pixel coordinates to draw at (defaults 0,0)
-//   - w, h: dimensions in tiles (defaults 16x16)
-//   - layers: bitfield to filter sprites by their flags (0 = draw all)
+//   - w, h: dimensions in tiles (defaults to the whole map, see GetMapSize)
+//   - layers: bitfield to filter sprites by their flags (0 = draw all).
+//     A tile is drawn if its sprite has any of the flags, see LayerMask
+//     to build the bitfield and MapLayer to draw one layer at a time.
+//
+// Sprite 0 is never drawn, and each cell is drawn at most once per call.
 func Map(args ...any) {
 	// Default map coordinates
 	mx, my := 0, 0
@@ -256,7 +230,7 @@ func Map(args ...any) {
 //
 // Optional args: [sx, sy, w, h, layers]
 //   - sx, sy: screen pixel coordinates to draw at (defaults 0,0)
-//   - w, h: dimensions in tiles (defaults 16x16)
+//   - w, h: dimensions in tiles (defaults to the whole map, see GetMapSize)
 //   - layers: bitfield to filter sprites by their flags (0 = draw all)
 //
 // Usage:
@@ -289,7 +263,11 @@ func initializeStreamingMapSystem() error {
 	logMemory("before streaming map init", true)
 
 	const mapFilename = "map.json"
-	jsonData, err := loadAndParseMapJSON(mapFilename)
+	jsonData, err := preloadedMap, error(nil)
+	preloadedMap = nil
+	if jsonData == nil {
+		jsonData, err = loadAndParseMapJSON(mapFilename)
+	}
 
 	worldWidth := defaultPico8MapWidth
 	worldHeight := defaultPico8MapHeight
@@ -306,30 +284,13 @@ func initializeStreamingMapSystem() error {
 		log.Printf("Failed to load map '%s' or map data is invalid: %v. Initializing with default world size: %dx%d", mapFilename, err, worldWidth, worldHeight)
 	}
 
-	worldMapMutex.Lock()
-	worldMapStream = &tilemapStream{
-		Data:               make([]int, worldWidth*worldHeight),
-		WorldWidthInTiles:  worldWidth,
-		WorldHeightInTiles: worldHeight,
-	}
-
-	if jsonData != nil && len(jsonData.Cells) > 0 {
-		log.Printf("Populating TilemapStream with %d cells from %s", len(jsonData.Cells), mapFilename)
-		populatedTiles := 0
-		for _, cell := range jsonData.Cells {
-			if cell.X >= 0 && cell.X < worldMapStream.WorldWidthInTiles &&
-				cell.Y >= 0 && cell.Y < worldMapStream.WorldHeightInTiles {
-				worldMapStream.Data[cell.Y*worldMapStream.WorldWidthInTiles+cell.X] = cell.Sprite
-				populatedTiles++
-			} else {
-				log.Printf("Warning: cell data out of bounds in %s: (%d, %d) for sprite %d. World size: %dx%d. Skipping cell.",
-					mapFilename, cell.X, cell.Y, cell.Sprite, worldMapStream.WorldWidthInTiles, worldMapStream.WorldHeightInTiles)
-			}
-		}
-		log.Printf("Finished populating TilemapStream. %d cells processed, %d tiles set.", len(jsonData.Cells), populatedTiles)
-	} else if err == nil {
+	stream := newTilemapStream(worldWidth, worldHeight, jsonData, mapFilename)
+	if jsonData != nil && len(jsonData.Cells) == 0 && err == nil {
 		log.Printf("Map JSON '%s' loaded but contains no cell data (or jsonData is nil after parse attempt). World map will be default (empty).", mapFilename)
 	}
+
+	worldMapMutex.Lock()
+	worldMapStream = stream
 	worldMapMutex.Unlock()
 
 	activeBufferMutex.Lock()
@@ -344,6 +305,75 @@ func initializeStreamingMapSystem() error {
 	return nil
 }
 
+// newTilemapStream creates a world map of the given size filled with the
+// cells of jsonData (if not nil). Cells outside the world are skipped.
+func newTilemapStream(worldWidth, worldHeight int, jsonData *mapDataJSON, source string) *tilemapStream {
+	stream := &tilemapStream{
+		Data:               make([]int, worldWidth*worldHeight),
+		WorldWidthInTiles:  worldWidth,
+		WorldHeightInTiles: worldHeight,
+	}
+	if jsonData == nil || len(jsonData.Cells) == 0 {
+		return stream
+	}
+
+	log.Printf("Populating TilemapStream with %d cells from %s", len(jsonData.Cells), source)
+	populatedTiles := 0
+	for _, cell := range jsonData.Cells {
+		if cell.X >= 0 && cell.X < stream.WorldWidthInTiles &&
+			cell.Y >= 0 && cell.Y < stream.WorldHeightInTiles {
+			stream.Data[cell.Y*stream.WorldWidthInTiles+cell.X] = cell.Sprite
+			populatedTiles++
+		} else {
+			log.Printf("Warning: cell data out of bounds in %s: (%d, %d) for sprite %d. World size: %dx%d. Skipping cell.",
+				source, cell.X, cell.Y, cell.Sprite, stream.WorldWidthInTiles, stream.WorldHeightInTiles)
+		}
+	}
+	log.Printf("Finished populating TilemapStream. %d cells processed, %d tiles set.", len(jsonData.Cells), populatedTiles)
+	return stream
+}
+
+// reloadMapFile replaces the world map with the contents of a map JSON file,
+// e.g. one the editor just saved. The map size comes from the file.
+func reloadMapFile(filename string) error {
+	ensureStreamingSystemInitialized()
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("error reading map file %s: %w", filename, err)
+	}
+	var jsonData mapDataJSON
+	if err := json.Unmarshal(data, &jsonData); err != nil {
+		return fmt.Errorf("failed to parse map JSON from %s: %w", filename, err)
+	}
+	if jsonData.Width <= 0 {
+		jsonData.Width = defaultPico8MapWidth
+	}
+	if jsonData.Height <= 0 {
+		jsonData.Height = defaultPico8MapHeight
+	}
+	setWorldMap(newTilemapStream(jsonData.Width, jsonData.Height, &jsonData, filename))
+	log.Printf("Reloaded map from %s (%dx%d tiles).", filename, jsonData.Width, jsonData.Height)
+	return nil
+}
+
+// setWorldMap installs stream as the world map and invalidates everything
+// that was built from the previous one.
+func setWorldMap(stream *tilemapStream) {
+	worldMapMutex.Lock()
+	worldMapStream = stream
+	worldMapMutex.Unlock()
+
+	activeBufferMutex.Lock()
+	if activeTileBufferInstance != nil {
+		activeTileBufferInstance.IsRegionLoaded = false // World map changed
+	}
+	activeBufferMutex.Unlock()
+
+	mapCacheIsValid = false
+	notifyMapReplaced()
+}
+
 // ensureStreamingSystemInitialized guarantees that the streaming map system is set up.
 // This function is responsible for calling initializeStreamingMapSystem once,
 // loading spritesheets, and setting up map cache parameters.
@@ -387,22 +417,6 @@ func ensureStreamingSystemInitialized() {
 	}
 
 	mapCacheIsValid = false
-	if GetScreenWidth() > 0 && GetScreenHeight() > 0 {
-		mapCacheWidthInTiles = GetScreenWidth() / 8
-		mapCacheHeightInTiles = GetScreenHeight() / 8
-	} else {
-		log.Printf("EnsureStreamingSystemInitialized: ScreenWidth/ScreenHeight not available or zero. Using default map cache dimensions.")
-		mapCacheWidthInTiles = defaultPico8MapWidth / 2
-		mapCacheHeightInTiles = defaultPico8MapHeight / 2
-	}
-	if mapCacheWidthInTiles <= 0 {
-		mapCacheWidthInTiles = 16
-	}
-	if mapCacheHeightInTiles <= 0 {
-		mapCacheHeightInTiles = 16
-	}
-
-	log.Printf("EnsureStreamingSystemInitialized: Map cache parameters set (Width: %d tiles, Height: %d tiles).", mapCacheWidthInTiles, mapCacheHeightInTiles)
 
 	log.Println("EnsureStreamingSystemInitialized: System ready.")
 	streamingSystemInitialized = true
@@ -413,8 +427,7 @@ func ensureStreamingSystemInitialized() {
 func parseMapArgs(args []any) (sx, sy, wTiles, hTiles, layers int) {
 	// Default parameters
 	sx, sy = 0, 0
-	wTiles = defaultPico8MapWidth
-	hTiles = defaultPico8MapHeight
+	wTiles, hTiles = GetMapSize()
 	layers = 0
 
 	// Process optional arguments
@@ -460,92 +473,65 @@ func parseMapArgs(args []any) (sx, sy, wTiles, hTiles, layers int) {
 	return sx, sy, wTiles, hTiles, layers
 }
 
+// mapCacheKey identifies a cached map image.
+type mapCacheKey struct {
+	mapX, mapY, wTiles, hTiles, layers int
+	screenWidth, screenHeight          int
+	transparency                       transparencyKey
+}
+
+// maxMapCaches bounds mapCaches, which is emptied when full.
+const maxMapCaches = 8
+
+// mapTileDraw is a map tile to draw, at (x, y) pixels from the region's corner.
+type mapTileDraw struct {
+	sprite, x, y int
+}
+
 // drawMapRegion draws a region of the map to the screen using a cache
 func drawMapRegion(mapX, mapY, sx, sy, wTiles, hTiles, layers int) {
 	if wTiles <= 0 || hTiles <= 0 {
 		return
 	}
 
-	// Check cache validity
-	// ScreenWidth and ScreenHeight are from the pigo8 package, assumed to be globally accessible updated values.
-	cacheIsCurrentlyValid := mapCacheIsValid &&
-		mapCacheImage != nil &&
-		mapCacheDrawnForWorldTileX == mapX &&
-		mapCacheDrawnForWorldTileY == mapY &&
-		mapCacheWidthInTiles == wTiles &&
-		mapCacheHeightInTiles == hTiles &&
-		mapCacheRenderedLayers == layers &&
-		mapCacheRenderedScreenWidth == GetScreenWidth() &&
-		mapCacheRenderedScreenHeight == GetScreenHeight()
-
-	if !cacheIsCurrentlyValid {
-		// Invalidate and rebuild cache
-		requiredCacheWidth := wTiles * 8
-		requiredCacheHeight := hTiles * 8
-
-		// Ensure mapCacheImage exists and is the correct size
-		if mapCacheImage == nil || mapCacheImage.Bounds().Dx() != requiredCacheWidth || mapCacheImage.Bounds().Dy() != requiredCacheHeight {
-			if mapCacheImage != nil {
-				mapCacheImage.Deallocate() // Dispose old image before creating new
-			}
-			mapCacheImage = ebiten.NewImage(requiredCacheWidth, requiredCacheHeight)
-		} else {
-			mapCacheImage.Clear() // Clear existing image for redraw
+	if !mapCacheIsValid {
+		for _, img := range mapCaches {
+			img.Deallocate()
 		}
+		clear(mapCaches)
+		mapCacheIsValid = true
+	}
 
-		if mapCacheImage == nil { // Still nil after attempt to create
-			log.Println("Error: Failed to create or clear mapCacheImage")
-			return
+	key := mapCacheKey{mapX, mapY, wTiles, hTiles, layers, GetScreenWidth(), GetScreenHeight(), currentTransparencyKey()}
+	cacheImage, cached := mapCaches[key]
+	if !cached {
+		if len(mapCaches) >= maxMapCaches {
+			for _, img := range mapCaches {
+				img.Deallocate()
+			}
+			clear(mapCaches)
 		}
 
-		// Iterate through the tiles that should be visible in the cache
-		for ty := 0; ty < hTiles; ty++ {
-			for tx := 0; tx < wTiles; tx++ {
-				worldTileX := mapX + tx
-				worldTileY := mapY + ty
-
-				spriteID := Mget(worldTileX, worldTileY) // Mget handles map boundaries
-				if spriteID == 0 {                       // Empty tile or out of bounds according to Mget's logic
-					continue
-				}
-
-				// Layer check
-				if layers > 0 {
-					flagBits, _ := Fget(spriteID)
-					if flagBits&layers == 0 {
-						continue
-					}
-				}
-
-				tileImg := getSpriteImage(spriteID) // GetSpriteImage handles nil if sprite not found
-				if tileImg != nil {
-					opts := &ebiten.DrawImageOptions{}
-					opts.Filter = ebiten.FilterNearest
-					opts.GeoM.Translate(float64(tx*8), float64(ty*8))
-					mapCacheImage.DrawImage(tileImg, opts)
-				}
+		cacheImage = ebiten.NewImage(wTiles*tileSize, hTiles*tileSize)
+		for _, tile := range mapRegionTiles(mapX, mapY, wTiles, hTiles, layers) {
+			tileImg := getSpriteImage(tile.sprite) // GetSpriteImage handles nil if sprite not found
+			if tileImg != nil {
+				opts := &ebiten.DrawImageOptions{}
+				opts.Filter = ebiten.FilterNearest
+				opts.GeoM.Translate(float64(tile.x), float64(tile.y))
+				cacheImage.DrawImage(createTransparentSpriteImage(tileImg), opts)
 			}
 		}
-
-		// Update cache state variables
-		mapCacheDrawnForWorldTileX = mapX
-		mapCacheDrawnForWorldTileY = mapY
-		mapCacheWidthInTiles = wTiles
-		mapCacheHeightInTiles = hTiles
-		mapCacheRenderedLayers = layers
-		mapCacheRenderedScreenWidth = GetScreenWidth()
-		mapCacheRenderedScreenHeight = GetScreenHeight()
-		mapCacheIsValid = true
-		// log.Printf("Map cache rebuilt for world (%d,%d) screen (%d,%d) tiles %dx%d layers %d screen_dims %dx%d", mapX, mapY, sx, sy, wTiles, hTiles, layers, ScreenWidth, ScreenHeight)
+		mapCaches[key] = cacheImage
 	}
 
 	// Draw the (now valid) cache to the screen
 	screenToDrawOn := CurrentScreen() // Get the main screen from engine
-	if screenToDrawOn == nil || mapCacheImage == nil {
-		// log.Println("Warning: Cannot draw map cache, screenToDrawOn or mapCacheImage is nil.")
+	if screenToDrawOn == nil {
 		return
 	}
 
+	beforeScreenDraw()
 	drawOpts := &ebiten.DrawImageOptions{}
 	drawOpts.Filter = ebiten.FilterNearest
 	// Apply the global PIGO-8 camera offset. sx and sy are the screen coordinates
@@ -554,7 +540,32 @@ func drawMapRegion(mapX, mapY, sx, sy, wTiles, hTiles, layers int) {
 	finalScreenX := float64(sx) - cameraX
 	finalScreenY := float64(sy) - cameraY
 	drawOpts.GeoM.Translate(finalScreenX, finalScreenY)
-	screenToDrawOn.DrawImage(mapCacheImage, drawOpts)
+	screenToDrawOn.DrawImage(cacheImage, drawOpts)
+	if debugGrid != DebugGridOff {
+		noteDebugGridMap(mapX, mapY, finalScreenX, finalScreenY)
+	}
+}
+
+// mapRegionTiles returns the tiles of a map region that Map draws for the
+// given layers, in row-major order. Every cell appears at most once.
+func mapRegionTiles(mapX, mapY, wTiles, hTiles, layers int) []mapTileDraw {
+	var tiles []mapTileDraw
+	for ty := 0; ty < hTiles; ty++ {
+		for tx := 0; tx < wTiles; tx++ {
+			spriteID := Mget(mapX+tx, mapY+ty) // Mget handles map boundaries
+			if spriteID == 0 {                 // Empty tile or out of bounds according to Mget's logic
+				continue
+			}
+			if layers != 0 {
+				flagBits, _ := Fget(spriteID)
+				if !mapTileInLayers(flagBits, layers) {
+					continue
+				}
+			}
+			tiles = append(tiles, mapTileDraw{spriteID, tx * tileSize, ty * tileSize})
+		}
+	}
+	return tiles
 }
 
 // loadRegionIntoActiveBuffer loads the specified region of the world map into the active tile buffer.
@@ -636,6 +647,10 @@ func Mget[C Number, R Number](column C, row R) int {
 	col := int(column)
 	r := int(row)
 
+	if sprite, streamed := mapChunkTile(col, r); streamed {
+		return sprite
+	}
+
 	worldMapMutex.RLock()
 	if worldMapStream == nil {
 		log.Printf("Mget: worldMapStream is nil. Streaming system not initialized.")
@@ -698,6 +713,7 @@ func Mget[C Number, R Number](column C, row R) int {
 
 // Mset sets the sprite number at the specified map coordinates.
 // This mimics PICO-8's mset(column, row, sprite) function.
+// If the sprite changes, the SetOnMapChange callback is called.
 func Mset[C Number, R Number, S Number](column C, row R, sprite S) {
 	ensureStreamingSystemInitialized()
 
@@ -710,6 +726,12 @@ func Mset[C Number, R Number, S Number](column C, row R, sprite S) {
 		return
 	}
 
+	if oldSprite, ok := setMapChunkTile(col, r, spriteNum); ok {
+		mapCacheIsValid = false
+		notifyMapChange(col, r, oldSprite, spriteNum)
+		return
+	}
+
 	worldMapMutex.Lock()
 	if worldMapStream == nil {
 		log.Printf("Mset: worldMapStream is nil. Streaming system not initialized.")
@@ -724,7 +746,9 @@ func Mset[C Number, R Number, S Number](column C, row R, sprite S) {
 		return
 	}
 
-	worldMapStream.Data[r*worldMapStream.WorldWidthInTiles+col] = spriteNum
+	index := r*worldMapStream.WorldWidthInTiles + col
+	oldSprite := worldMapStream.Data[index]
+	worldMapStream.Data[index] = spriteNum
 	worldMapMutex.Unlock()
 
 	activeBufferMutex.Lock()
@@ -740,46 +764,87 @@ func Mset[C Number, R Number, S Number](column C, row R, sprite S) {
 
 	mapCacheIsValid = false
 	// log.Printf("Mset: Set tile at (%d,%d) to sprite %d. Map cache invalidated.", col, r, spriteNum)
+	notifyMapChange(col, r, oldSprite, spriteNum)
 }
 
 // SetMap directly sets the entire PICO-8 map data from a byte slice.
-// The data slice should contain DefaultPico8MapHeight * DefaultPico8MapWidth bytes,
-// representing sprite IDs in row-major order.
+// The data slice should contain one byte per map cell (width * height of the
+// current map, see GetMapSize), representing sprite IDs in row-major order.
+// Data for the default 128x128 map is also accepted, and resets the map to
+// that size.
 func SetMap(data []byte) {
 	ensureStreamingSystemInitialized()
 
-	expectedLen := defaultPico8MapWidth * defaultPico8MapHeight
-	if len(data) != expectedLen {
-		log.Printf("Warning: SetMap received data of incorrect length. Expected %d, got %d", expectedLen, len(data))
-		return
+	width, height := GetMapSize()
+	if len(data) != width*height {
+		if len(data) != defaultPico8MapWidth*defaultPico8MapHeight {
+			log.Printf("Warning: SetMap received data of incorrect length. Expected %d (%dx%d map), got %d",
+				width*height, width, height, len(data))
+			return
+		}
+		log.Printf("SetMap: Resetting worldMapStream to default dimensions (%dx%d).", defaultPico8MapWidth, defaultPico8MapHeight)
+		width, height = defaultPico8MapWidth, defaultPico8MapHeight
 	}
 
-	worldMapMutex.Lock()
-	// Ensure worldMapStream is initialized with default dimensions if it's nil or has different dimensions
-	if worldMapStream == nil || worldMapStream.WorldWidthInTiles != defaultPico8MapWidth || worldMapStream.WorldHeightInTiles != defaultPico8MapHeight {
-		log.Printf("SetMap: Initializing/resetting worldMapStream to default dimensions (%dx%d).", defaultPico8MapWidth, defaultPico8MapHeight)
-		worldMapStream = &tilemapStream{
-			Data:               make([]int, defaultPico8MapWidth*defaultPico8MapHeight),
-			WorldWidthInTiles:  defaultPico8MapWidth,
-			WorldHeightInTiles: defaultPico8MapHeight,
-		}
-	} else {
-		// If it exists and has correct dimensions, clear existing data by re-making the slice
-		worldMapStream.Data = make([]int, defaultPico8MapWidth*defaultPico8MapHeight)
+	stream := newTilemapStream(width, height, nil, "SetMap")
+	for i := range stream.Data {
+		stream.Data[i] = int(data[i])
 	}
+	setWorldMap(stream)
+	log.Printf("SetMap: World map data updated from byte slice. Active buffer and map cache invalidated.")
+}
 
-	for i := 0; i < expectedLen; i++ {
-		worldMapStream.Data[i] = int(data[i])
+// SetMapSize resizes the map to width x height tiles, e.g. for worlds larger
+// than PICO-8's 128x128. Tiles inside both the old and the new size are kept,
+// new cells are empty (sprite 0) and cells outside the new size are dropped.
+// Mget, Mset, Map, MapCollision and the other map functions all use the new
+// size.
+//
+// Example:
+//
+//	func (g *Game) Init() {
+//		SetMapSize(256, 64) // A long side-scrolling level
+//		for x := 0; x < 256; x++ {
+//			Mset(x, 63, 1) // Ground along the bottom
+//		}
+//	}
+func SetMapSize(width, height int) {
+	if width <= 0 || height <= 0 {
+		log.Printf("Warning: SetMapSize(%d, %d) called with an invalid size. The map size is unchanged.", width, height)
+		return
 	}
-	worldMapMutex.Unlock()
+	ensureStreamingSystemInitialized()
 
-	activeBufferMutex.Lock()
-	if activeTileBufferInstance != nil {
-		activeTileBufferInstance.IsRegionLoaded = false // Invalidate buffer as world map changed
-		// log.Printf("SetMap: Active tile buffer invalidated.")
+	worldMapMutex.RLock()
+	stream := resizeTilemapStream(worldMapStream, width, height)
+	worldMapMutex.RUnlock()
+
+	setWorldMap(stream)
+	log.Printf("SetMapSize: Map resized to %dx%d tiles.", width, height)
+}
+
+// GetMapSize returns the width and height of the map in tiles: 128x128 by
+// default, the size in map.json, or the size set with SetMapSize.
+func GetMapSize() (width, height int) {
+	ensureStreamingSystemInitialized()
+
+	worldMapMutex.RLock()
+	defer worldMapMutex.RUnlock()
+	if worldMapStream == nil {
+		return defaultPico8MapWidth, defaultPico8MapHeight
 	}
-	activeBufferMutex.Unlock()
+	return worldMapStream.WorldWidthInTiles, worldMapStream.WorldHeightInTiles
+}
 
-	mapCacheIsValid = false
-	log.Printf("SetMap: World map data updated from byte slice. Active buffer and map cache invalidated.")
+// resizeTilemapStream returns a width x height copy of old (which may be nil)
+// with the tiles of the area both sizes share.
+func resizeTilemapStream(old *tilemapStream, width, height int) *tilemapStream {
+	stream := newTilemapStream(width, height, nil, "resize")
+	if old == nil {
+		return stream
+	}
+	kept := copyMapTiles(old.Data, old.WorldWidthInTiles, old.WorldHeightInTiles,
+		0, 0, min(width, old.WorldWidthInTiles), min(height, old.WorldHeightInTiles))
+	pasteMapTiles(stream.Data, width, height, kept, 0, 0, false)
+	return stream
 }
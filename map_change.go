@@ -0,0 +1,65 @@
+package pigo8
+
+// --- Map change notifications ---
+
+// AllMapCells is passed as every argument of the SetOnMapChange callback
+// when the whole map was replaced rather than single cells changed.
+const AllMapCells = -1
+
+var (
+	// onMapChange is called for every map cell that changes, see SetOnMapChange.
+	onMapChange func(x, y, oldSprite, newSprite int)
+	// notifyingMapChange is true while onMapChange runs, so map writes made
+	// by the callback don't call it again.
+	notifyingMapChange bool
+)
+
+// SetOnMapChange registers fn to be called after a map cell changes, e.g. to
+// send only the changed tiles to the other players of a networked game, or
+// to mark a region of an editor view as dirty. Pass nil to stop.
+//
+// Mset, MapPaste and MapFill call fn once for every cell whose sprite
+// actually changed, with its coordinates and its old and new sprite numbers;
+// writing the sprite a cell already holds doesn't call it. Operations that
+// replace the whole map (SetMap, SetMapSize, SetMapChunkProvider, LoadState
+// and a reload of map.json) call fn a single time with every argument set to
+// AllMapCells, since the listener has to resend or redraw the whole map
+// anyway.
+//
+// fn runs on the goroutine that changed the map. Map changes made inside fn
+// are applied but don't call fn again, so a callback that rewrites the cell
+// it was told about can't loop forever.
+//
+// Example:
+//
+//	SetOnMapChange(func(x, y, oldSprite, newSprite int) {
+//		if x == AllMapCells {
+//			g.sendFullMap()
+//			return
+//		}
+//		g.pending = append(g.pending, tileDiff{x, y, newSprite})
+//	})
+func SetOnMapChange(fn func(x, y, oldSprite, newSprite int)) {
+	onMapChange = fn
+}
+
+// notifyMapChange calls the SetOnMapChange callback for one changed cell,
+// unless the change was made by the callback itself.
+func notifyMapChange(x, y, oldSprite, newSprite int) {
+	if onMapChange == nil || notifyingMapChange || oldSprite == newSprite {
+		return
+	}
+	notifyingMapChange = true
+	defer func() { notifyingMapChange = false }()
+	onMapChange(x, y, oldSprite, newSprite)
+}
+
+// notifyMapReplaced tells the SetOnMapChange callback that the whole map changed.
+func notifyMapReplaced() {
+	if onMapChange == nil || notifyingMapChange {
+		return
+	}
+	notifyingMapChange = true
+	defer func() { notifyingMapChange = false }()
+	onMapChange(AllMapCells, AllMapCells, AllMapCells, AllMapCells)
+}
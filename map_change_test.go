@@ -0,0 +1,87 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapChangeEvent struct {
+	x, y, oldSprite, newSprite int
+}
+
+// recordMapChanges registers a SetOnMapChange callback that records every
+// event, for the rest of the test.
+func recordMapChanges(t *testing.T) *[]mapChangeEvent {
+	t.Cleanup(func() { SetOnMapChange(nil) })
+	events := &[]mapChangeEvent{}
+	SetOnMapChange(func(x, y, oldSprite, newSprite int) {
+		*events = append(*events, mapChangeEvent{x, y, oldSprite, newSprite})
+	})
+	return events
+}
+
+func TestSetOnMapChange_Mset(t *testing.T) {
+	useTestConsoleState(t)
+	events := recordMapChanges(t)
+
+	Mset(3, 4, 12)
+	Mset(3, 4, 12) // Unchanged, no event
+	Mset(3, 4, 5)
+	Mset(-1, 0, 5) // Out of bounds, no event
+
+	assert.Equal(t, []mapChangeEvent{{3, 4, 0, 12}, {3, 4, 12, 5}}, *events)
+
+	SetOnMapChange(nil)
+	Mset(3, 4, 6)
+	assert.Len(t, *events, 2, "no events after SetOnMapChange(nil)")
+}
+
+func TestSetOnMapChange_Paste(t *testing.T) {
+	useTestConsoleState(t)
+	Mset(1, 1, 7)
+	events := recordMapChanges(t)
+
+	// Cells that already hold the stamp's sprite don't fire
+	MapPaste(MapStamp{Width: 2, Height: 2, Tiles: []int{9, 9, 9, 7}}, 0, 0)
+	assert.ElementsMatch(t, []mapChangeEvent{{0, 0, 0, 9}, {1, 0, 0, 9}, {0, 1, 0, 9}}, *events)
+
+	*events = nil
+	MapFill(126, 126, 4, 4, 2)
+	assert.Len(t, *events, 4, "only the 2x2 cells inside the map change")
+
+	*events = nil
+	MapPaste(MapStamp{Width: 2, Height: 1, Tiles: []int{0, 3}}, 0, 0, true)
+	assert.Equal(t, []mapChangeEvent{{1, 0, 9, 3}}, *events, "skipped empty cells don't fire")
+}
+
+func TestSetOnMapChange_BulkChanges(t *testing.T) {
+	useTestConsoleState(t)
+	events := recordMapChanges(t)
+	replaced := mapChangeEvent{AllMapCells, AllMapCells, AllMapCells, AllMapCells}
+
+	SetMap(make([]byte, defaultPico8MapWidth*defaultPico8MapHeight))
+	assert.Equal(t, []mapChangeEvent{replaced}, *events, "SetMap fires a single event")
+
+	*events = nil
+	SetMapSize(64, 64)
+	assert.Equal(t, []mapChangeEvent{replaced}, *events)
+}
+
+func TestSetOnMapChange_NoRecursion(t *testing.T) {
+	useTestConsoleState(t)
+	t.Cleanup(func() { SetOnMapChange(nil) })
+
+	calls := 0
+	SetOnMapChange(func(x, y, oldSprite, newSprite int) {
+		calls++
+		Mset(x+1, y, newSprite) // Would loop forever if it fired again
+	})
+
+	Mset(0, 0, 4)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 4, Mget(1, 0), "writes made by the callback are applied")
+
+	Mset(5, 5, 8)
+	assert.Equal(t, 2, calls, "the callback fires again once it has returned")
+}
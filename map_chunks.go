@@ -0,0 +1,238 @@
+package pigo8
+
+import (
+	"log"
+	"sync"
+)
+
+// --- Streamed map chunks ---
+
+const (
+	// defaultMapChunkSize is the default width and height of a map chunk in tiles.
+	defaultMapChunkSize = 16
+	// defaultMapChunkMargin is the default number of chunks kept loaded around the screen.
+	defaultMapChunkMargin = 1
+)
+
+// mapChunkKey identifies a chunk by its chunk coordinates.
+type mapChunkKey struct {
+	X, Y int
+}
+
+// mapChunk is a loaded chunk of a streamed map.
+type mapChunk struct {
+	tiles  []int // Row-major sprite numbers, mapChunkSize*mapChunkSize entries
+	edited bool  // Changed with Mset, so it is kept when evicted
+	used   bool  // Read or written since the last updateMapChunks
+}
+
+var (
+	// mapChunkProvider generates chunks of a streamed map; nil means the map
+	// is the regular, fixed-size one.
+	mapChunkProvider func(chunkX, chunkY int) []int
+	mapChunkSize     = defaultMapChunkSize
+	mapChunkMargin   = defaultMapChunkMargin
+	// mapChunks holds the loaded chunks.
+	mapChunks = make(map[mapChunkKey]*mapChunk)
+	// editedMapChunks holds the tiles of edited chunks that were evicted.
+	editedMapChunks = make(map[mapChunkKey][]int)
+	mapChunksMutex  sync.Mutex
+)
+
+// SetMapChunkProvider turns the map into an unbounded, streamed map whose
+// tiles come from provider, e.g. a procedural world generator, instead of
+// map.json. The map is split into square chunks of Settings.MapChunkSize
+// tiles (16 by default); provider(chunkX, chunkY) returns the sprite numbers
+// of one chunk in row-major order, so chunk (1, 0) covers map cells 16-31 on
+// x and 0-15 on y. Chunk coordinates can be negative.
+//
+// Chunks are requested when Mget, Map or MapCollision first need them, and
+// every frame the chunks around the screen, at the camera position the last
+// Draw ended with, are loaded ahead of time (use CameraPush and CameraPop to
+// draw a HUD without moving it). Chunks further than Settings.MapChunkMargin
+// chunks (1 by default) from the screen that weren't used during the frame
+// are evicted, and are requested again when needed.
+//
+// Mset writes to the loaded chunk. An edited chunk isn't requested again: its
+// tiles are kept when it is evicted, so changes to the world persist until
+// SetMapChunkProvider is called again.
+//
+// Pass nil to go back to the regular map. Draw a streamed map with explicit
+// bounds, e.g. Map(mx, my, sx, sy, w, h); MapCopy, MapPaste, FindPath and
+// SaveState only see the regular map. The provider must not call map
+// functions itself.
+//
+// Example:
+//
+//	SetMapChunkProvider(func(chunkX, chunkY int) []int {
+//		tiles := make([]int, 16*16)
+//		for i := range tiles {
+//			if chunkY > 0 {
+//				tiles[i] = 1 // Solid ground below y = 16
+//			}
+//		}
+//		return tiles
+//	})
+func SetMapChunkProvider(provider func(chunkX, chunkY int) []int) {
+	mapChunksMutex.Lock()
+	mapChunkProvider = provider
+	clear(mapChunks)
+	clear(editedMapChunks)
+	mapChunksMutex.Unlock()
+
+	mapCacheIsValid = false
+	notifyMapReplaced()
+}
+
+// LoadedMapChunks returns the number of chunks of a streamed map that are
+// loaded, see SetMapChunkProvider.
+func LoadedMapChunks() int {
+	mapChunksMutex.Lock()
+	defer mapChunksMutex.Unlock()
+	return len(mapChunks)
+}
+
+// setMapChunkLayout sets the chunk size in tiles and the number of chunks
+// kept around the screen, dropping the loaded chunks if the size changes.
+func setMapChunkLayout(size, margin int) {
+	if size <= 0 {
+		size = defaultMapChunkSize
+	}
+	mapChunksMutex.Lock()
+	defer mapChunksMutex.Unlock()
+	if size != mapChunkSize {
+		mapChunkSize = size
+		clear(mapChunks)
+		clear(editedMapChunks)
+	}
+	mapChunkMargin = max(margin, 0)
+}
+
+// mapChunkTile returns the sprite number at (column, row) of a streamed map,
+// and false if the map isn't streamed.
+func mapChunkTile(column, row int) (int, bool) {
+	mapChunksMutex.Lock()
+	defer mapChunksMutex.Unlock()
+	if mapChunkProvider == nil {
+		return 0, false
+	}
+	key, index := mapChunkIndex(column, row)
+	chunk := loadMapChunk(key)
+	chunk.used = true
+	return chunk.tiles[index], true
+}
+
+// setMapChunkTile sets the sprite number at (column, row) of a streamed map
+// and returns the previous one, or false if the map isn't streamed.
+func setMapChunkTile(column, row, sprite int) (int, bool) {
+	mapChunksMutex.Lock()
+	defer mapChunksMutex.Unlock()
+	if mapChunkProvider == nil {
+		return 0, false
+	}
+	key, index := mapChunkIndex(column, row)
+	chunk := loadMapChunk(key)
+	oldSprite := chunk.tiles[index]
+	chunk.tiles[index] = sprite
+	chunk.edited = true
+	chunk.used = true
+	return oldSprite, true
+}
+
+// mapChunkIndex returns the chunk holding map cell (column, row) and the
+// cell's index inside it.
+func mapChunkIndex(column, row int) (mapChunkKey, int) {
+	key := mapChunkKey{floorDiv(column, mapChunkSize), floorDiv(row, mapChunkSize)}
+	localX := column - key.X*mapChunkSize
+	localY := row - key.Y*mapChunkSize
+	return key, localY*mapChunkSize + localX
+}
+
+// loadMapChunk returns the chunk at key, restoring it from editedMapChunks or
+// requesting it from the provider if it isn't loaded. The caller must hold
+// mapChunksMutex.
+func loadMapChunk(key mapChunkKey) *mapChunk {
+	if chunk, ok := mapChunks[key]; ok {
+		return chunk
+	}
+	chunk := &mapChunk{}
+	if tiles, ok := editedMapChunks[key]; ok {
+		chunk.tiles, chunk.edited = tiles, true
+		delete(editedMapChunks, key)
+	} else {
+		chunk.tiles = fitMapChunk(mapChunkProvider(key.X, key.Y), mapChunkSize)
+	}
+	mapChunks[key] = chunk
+	return chunk
+}
+
+// fitMapChunk copies tiles into a new size*size chunk, padding it with 0 or
+// dropping the extra tiles if the provider returned the wrong number.
+func fitMapChunk(tiles []int, size int) []int {
+	if len(tiles) != size*size {
+		logWarningOnce("Warning: map chunk provider returned %d tiles, expected %d (%dx%d). Extra tiles are dropped and missing ones are 0.",
+			len(tiles), size*size, size, size)
+	}
+	chunk := make([]int, size*size)
+	copy(chunk, tiles)
+	return chunk
+}
+
+// updateMapChunks evicts the chunks that are far from the screen and weren't
+// used since the last call, and loads the chunks around the screen. The
+// engine calls it once per frame.
+func updateMapChunks() {
+	mapChunksMutex.Lock()
+	defer mapChunksMutex.Unlock()
+	if mapChunkProvider == nil {
+		return
+	}
+
+	minX, minY, maxX, maxY := screenMapChunks()
+	minX, minY = minX-mapChunkMargin, minY-mapChunkMargin
+	maxX, maxY = maxX+mapChunkMargin, maxY+mapChunkMargin
+
+	evicted := 0
+	for key, chunk := range mapChunks {
+		near := key.X >= minX && key.X <= maxX && key.Y >= minY && key.Y <= maxY
+		if !near && !chunk.used {
+			if chunk.edited {
+				editedMapChunks[key] = chunk.tiles
+			}
+			delete(mapChunks, key)
+			evicted++
+			continue
+		}
+		chunk.used = false
+	}
+	if evicted > 0 {
+		log.Printf("Evicted %d map chunks, %d loaded", evicted, len(mapChunks))
+	}
+
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			loadMapChunk(mapChunkKey{x, y})
+		}
+	}
+}
+
+// screenMapChunks returns the range of chunks the screen shows at the
+// current camera position.
+func screenMapChunks() (minX, minY, maxX, maxY int) {
+	left, top := Flr(cameraX), Flr(cameraY)
+	chunkPixels := mapChunkSize * tileSize
+	minX = floorDiv(left, chunkPixels)
+	minY = floorDiv(top, chunkPixels)
+	maxX = floorDiv(left+max(screenWidth, 1)-1, chunkPixels)
+	maxY = floorDiv(top+max(screenHeight, 1)-1, chunkPixels)
+	return minX, minY, maxX, maxY
+}
+
+// floorDiv divides a by b (> 0), rounding towards negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,117 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// useTestMapChunks streams the map from 4x4 chunks whose tiles hold
+// chunkX*100+chunkY, counts the provider calls and restores the regular map
+// and camera when the test ends.
+func useTestMapChunks(t *testing.T) *int {
+	savedCameraX, savedCameraY := cameraX, cameraY
+	savedWidth, savedHeight := screenWidth, screenHeight
+	t.Cleanup(func() {
+		SetMapChunkProvider(nil)
+		setMapChunkLayout(defaultMapChunkSize, defaultMapChunkMargin)
+		cameraX, cameraY = savedCameraX, savedCameraY
+		screenWidth, screenHeight = savedWidth, savedHeight
+	})
+
+	cameraX, cameraY = 0, 0
+	screenWidth, screenHeight = 32, 32 // One 4x4 chunk of 8x8 tiles
+	setMapChunkLayout(4, 1)
+
+	calls := 0
+	SetMapChunkProvider(func(chunkX, chunkY int) []int {
+		calls++
+		tiles := make([]int, 16)
+		for i := range tiles {
+			tiles[i] = chunkX*100 + chunkY
+		}
+		return tiles
+	})
+	return &calls
+}
+
+func TestMapChunkTile(t *testing.T) {
+	calls := useTestMapChunks(t)
+
+	sprite, streamed := mapChunkTile(5, 9) // Chunk (1, 2)
+	assert.True(t, streamed)
+	assert.Equal(t, 102, sprite)
+
+	sprite, _ = mapChunkTile(-1, -5) // Chunk (-1, -2)
+	assert.Equal(t, -102, sprite)
+
+	mapChunkTile(6, 10)
+	assert.Equal(t, 2, *calls, "loaded chunks are cached")
+
+	SetMapChunkProvider(nil)
+	_, streamed = mapChunkTile(5, 9)
+	assert.False(t, streamed)
+}
+
+func TestUpdateMapChunks_Eviction(t *testing.T) {
+	calls := useTestMapChunks(t)
+
+	updateMapChunks()
+	assert.Equal(t, 9, LoadedMapChunks(), "the screen's chunk and a margin of one chunk")
+
+	// A far chunk used during the frame survives one update
+	mapChunkTile(100, 100)
+	updateMapChunks()
+	assert.Equal(t, 10, LoadedMapChunks())
+	updateMapChunks()
+	assert.Equal(t, 9, LoadedMapChunks())
+
+	// Scrolling one chunk to the right drops the left column and loads a new one
+	cameraX = 32
+	*calls = 0
+	updateMapChunks()
+	assert.Equal(t, 9, LoadedMapChunks())
+	assert.Equal(t, 3, *calls)
+}
+
+func TestSetMapChunkTile_PersistsAfterEviction(t *testing.T) {
+	calls := useTestMapChunks(t)
+
+	_, ok := setMapChunkTile(200, 0, 7)
+	assert.True(t, ok)
+	sprite, _ := mapChunkTile(200, 0)
+	assert.Equal(t, 7, sprite)
+
+	updateMapChunks()
+	updateMapChunks() // The edited chunk is far from the screen and evicted
+	*calls = 0
+	sprite, _ = mapChunkTile(200, 0)
+	assert.Equal(t, 7, sprite, "edits survive eviction")
+	sprite, _ = mapChunkTile(201, 0)
+	assert.Equal(t, 5000, sprite)
+	assert.Equal(t, 0, *calls, "edited chunks aren't requested again")
+}
+
+func TestFitMapChunk(t *testing.T) {
+	assert.Equal(t, []int{1, 2, 0, 0}, fitMapChunk([]int{1, 2}, 2))
+	assert.Equal(t, []int{1, 2, 3, 4}, fitMapChunk([]int{1, 2, 3, 4, 5}, 2))
+	assert.Equal(t, []int{0, 0, 0, 0}, fitMapChunk(nil, 2))
+}
+
+func TestFloorDiv(t *testing.T) {
+	assert.Equal(t, 1, floorDiv(5, 4))
+	assert.Equal(t, 0, floorDiv(0, 4))
+	assert.Equal(t, -1, floorDiv(-1, 4))
+	assert.Equal(t, -1, floorDiv(-4, 4))
+	assert.Equal(t, -2, floorDiv(-5, 4))
+}
+
+func TestMgetAndMset_StreamedMap(t *testing.T) {
+	useTestConsoleState(t)
+	useTestMapChunks(t)
+
+	assert.Equal(t, 300, Mget(12, 0), "cells beyond the regular map come from chunks")
+	Mset(-3, -3, 9)
+	assert.Equal(t, 9, Mget(-3, -3))
+	assert.Equal(t, 149, Mget(-3, 997), "chunk (-1, 249)")
+}
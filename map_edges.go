@@ -0,0 +1,80 @@
+package pigo8
+
+import "math"
+
+// --- Map edges ---
+
+// ClampToMap keeps a point in pixels inside the map (see GetMapSize and
+// GetTileSize): x goes from 0 to the map width in pixels minus 1, and the
+// same for y, so Flr(x/GetTileSize()) is always a column of the map. Use it
+// for things that stop at the level edges; ClampToMapTile does the same in
+// tiles.
+//
+// Example:
+//
+//	g.x, g.y = ClampToMap(g.x+g.dx, g.y+g.dy)
+func ClampToMap(x, y float64) (float64, float64) {
+	w, h := GetMapSize()
+	return clampToEdge(x, float64(w*tileSize-1)), clampToEdge(y, float64(h*tileSize-1))
+}
+
+// WrapMap wraps a point in pixels around the map edges, for things that
+// leave one side of the map and come back on the other: x goes from 0 up to
+// (not including) the map width in pixels, and the same for y. WrapMapTile
+// does the same in tiles.
+//
+// Example:
+//
+//	g.ship.x, g.ship.y = WrapMap(g.ship.x+g.ship.dx, g.ship.y+g.ship.dy)
+func WrapMap(x, y float64) (float64, float64) {
+	w, h := GetMapSize()
+	return wrapFloat(x, float64(w*tileSize)), wrapFloat(y, float64(h*tileSize))
+}
+
+// ClampToMapTile keeps a tile position inside the map: tx from 0 to the map
+// width minus 1, and ty from 0 to the map height minus 1.
+//
+// Example:
+//
+//	tx, ty := ClampToMapTile(g.cursorX, g.cursorY)
+//	Mset(tx, ty, g.brush)
+func ClampToMapTile(tx, ty int) (int, int) {
+	w, h := GetMapSize()
+	return max(0, min(tx, w-1)), max(0, min(ty, h-1))
+}
+
+// WrapMapTile wraps a tile position around the map edges, so -1 is the last
+// column or row.
+func WrapMapTile(tx, ty int) (int, int) {
+	w, h := GetMapSize()
+	return wrapInt(tx, w), wrapInt(ty, h)
+}
+
+// clampToEdge clamps v to [0, hi], or returns 0 for an empty map.
+func clampToEdge(v, hi float64) float64 {
+	return math.Max(0, math.Min(v, hi))
+}
+
+// wrapFloat wraps v into [0, size), or returns 0 for an empty map.
+func wrapFloat(v, size float64) float64 {
+	if size <= 0 {
+		return 0
+	}
+	v = math.Mod(v, size)
+	if v < 0 {
+		v += size
+	}
+	// A tiny negative v rounds up to size
+	if v >= size {
+		v = 0
+	}
+	return v
+}
+
+// wrapInt wraps v into [0, size), or returns 0 for an empty map.
+func wrapInt(v, size int) int {
+	if size <= 0 {
+		return 0
+	}
+	return ((v % size) + size) % size
+}
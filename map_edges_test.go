@@ -0,0 +1,49 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClampToMap(t *testing.T) {
+	useTestConsoleState(t)
+	SetMapSize(16, 8) // 128x64 pixels
+
+	x, y := ClampToMap(-3, 70)
+	assert.Equal(t, []float64{0, 63}, []float64{x, y})
+	x, y = ClampToMap(127.5, 10.25)
+	assert.Equal(t, []float64{127, 10.25}, []float64{x, y}, "points stay on the last pixel")
+	assert.Equal(t, 15, Flr(x/8), "the clamped point is always in a map column")
+
+	tx, ty := ClampToMapTile(-1, 8)
+	assert.Equal(t, []int{0, 7}, []int{tx, ty})
+	tx, ty = ClampToMapTile(15, 3)
+	assert.Equal(t, []int{15, 3}, []int{tx, ty})
+}
+
+func TestWrapMap(t *testing.T) {
+	useTestConsoleState(t)
+	SetMapSize(16, 8)
+
+	x, y := WrapMap(130, -1)
+	assert.Equal(t, []float64{2, 63}, []float64{x, y})
+	x, y = WrapMap(128, 64)
+	assert.Equal(t, []float64{0, 0}, []float64{x, y}, "the far edge wraps to 0")
+	x, _ = WrapMap(-1e-20, 0)
+	assert.Equal(t, 0.0, x, "tiny negative values don't round up to the map width")
+
+	tx, ty := WrapMapTile(-1, 17)
+	assert.Equal(t, []int{15, 1}, []int{tx, ty})
+}
+
+func TestMapEdgesFollowTileSize(t *testing.T) {
+	useTestConsoleState(t)
+	useTileSize(t, 16)
+	SetMapSize(4, 4) // 64x64 pixels
+
+	x, y := ClampToMap(100, -5)
+	assert.Equal(t, []float64{63, 0}, []float64{x, y})
+	x, y = WrapMap(70, 64)
+	assert.Equal(t, []float64{6, 0}, []float64{x, y})
+}
@@ -0,0 +1,85 @@
+package pigo8
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strconv"
+)
+
+// --- Map export as Go source ---
+
+// ExportMapGo returns the map as a Go source file, for games that compile
+// their maps in instead of loading map.json. The file is in package pkg and
+// declares name as a []byte holding one sprite number per tile, row by row,
+// the format SetMap takes, with nameWidth and nameHeight constants for its
+// size:
+//
+//	// Code generated by pigo8 ExportMapGo. DO NOT EDIT.
+//
+//	package levels
+//
+//	// Level1Width and Level1Height are the size of Level1 in tiles.
+//	const (
+//		Level1Width  = 128
+//		Level1Height = 64
+//	)
+//
+//	// Level1 is a map with one sprite number per tile, row by row. Load it with
+//	// SetMapSize(Level1Width, Level1Height) and SetMap(Level1).
+//	var Level1 = []byte{
+//		0, 0, 12, 13, ...
+//	}
+//
+// Save the result next to the game's code, e.g. with os.WriteFile. Since
+// SetMap takes bytes, it fails for maps with sprite numbers above 255, and
+// for names that aren't Go identifiers. The pigo8 editor exports its map the
+// same way.
+//
+// Example:
+//
+//	src, err := ExportMapGo("main", "level1")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if err := os.WriteFile("level1_map.go", src, 0o644); err != nil {
+//		log.Fatal(err)
+//	}
+func ExportMapGo(pkg, name string) ([]byte, error) {
+	width, height := GetMapSize()
+	return formatMapGo(pkg, name, MapCopy(0, 0, width, height))
+}
+
+// formatMapGo returns the Go source of ExportMapGo for the tiles of stamp.
+func formatMapGo(pkg, name string, stamp MapStamp) ([]byte, error) {
+	if !token.IsIdentifier(pkg) || pkg == "_" {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+	if !token.IsIdentifier(name) || name == "_" {
+		return nil, fmt.Errorf("invalid variable name %q", name)
+	}
+	for i, sprite := range stamp.Tiles {
+		if sprite < 0 || sprite > 255 {
+			return nil, fmt.Errorf("sprite %d at map cell (%d, %d) doesn't fit in the byte SetMap takes", sprite, i%stamp.Width, i/stamp.Width)
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by pigo8 ExportMapGo. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	fmt.Fprintf(&src, "// %[1]sWidth and %[1]sHeight are the size of %[1]s in tiles.\n", name)
+	fmt.Fprintf(&src, "const (\n%[1]sWidth = %[2]d\n%[1]sHeight = %[3]d\n)\n\n", name, stamp.Width, stamp.Height)
+	fmt.Fprintf(&src, "// %[1]s is a map with one sprite number per tile, row by row. Load it with\n", name)
+	fmt.Fprintf(&src, "// SetMapSize(%[1]sWidth, %[1]sHeight) and SetMap(%[1]s).\n", name)
+	fmt.Fprintf(&src, "var %s = []byte{\n", name)
+	for y := 0; y < stamp.Height; y++ {
+		for x := 0; x < stamp.Width; x++ {
+			src.WriteString(strconv.Itoa(stamp.Tiles[y*stamp.Width+x]))
+			src.WriteString(", ")
+		}
+		src.WriteString("\n")
+	}
+	src.WriteString("}\n")
+
+	return format.Source(src.Bytes())
+}
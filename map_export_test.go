@@ -0,0 +1,40 @@
+package pigo8
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportMapGo(t *testing.T) {
+	useTestConsoleState(t)
+	SetMapSize(3, 2)
+	Mset(1, 0, 7)
+	Mset(2, 1, 255)
+
+	src, err := ExportMapGo("levels", "Level1")
+	require.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), "level1.go", src, 0)
+	require.NoError(t, err, "the output is valid Go")
+	assert.Contains(t, string(src), "package levels\n")
+	assert.Contains(t, string(src), "Level1Width  = 3\n\tLevel1Height = 2\n")
+	assert.Contains(t, string(src), "var Level1 = []byte{\n\t0, 7, 0,\n\t0, 0, 255,\n}\n")
+
+}
+
+func TestExportMapGoErrors(t *testing.T) {
+	useTestConsoleState(t)
+	SetMapSize(2, 2)
+
+	_, err := ExportMapGo("my-levels", "Level1")
+	assert.Error(t, err, "invalid package name")
+	_, err = ExportMapGo("levels", "1level")
+	assert.Error(t, err, "invalid variable name")
+
+	Mset(1, 1, 256)
+	_, err = ExportMapGo("levels", "Level1")
+	assert.ErrorContains(t, err, "(1, 1)", "sprites above 255 don't fit in a byte")
+}
@@ -0,0 +1,80 @@
+package pigo8
+
+import "log"
+
+// --- Map layers ---
+
+// Sprite flag numbers, for Fget, Fset, MapCollision, MapLayer and LayerMask.
+const (
+	Flag0 = iota
+	Flag1
+	Flag2
+	Flag3
+	Flag4
+	Flag5
+	Flag6
+	Flag7
+)
+
+// LayerMask returns the layers bitfield Map takes for the given flag
+// numbers (0-7). Flag numbers can't simply be added: Map(..., 4+6) asks for
+// flags 1 and 3, while Map(..., LayerMask(Flag4, Flag6)) asks for flags 4
+// and 6.
+//
+// Example:
+//
+//	Map(0, 0, 0, 0, 16, 16, LayerMask(Flag4, Flag6))
+func LayerMask(flags ...int) int {
+	mask := 0
+	for _, flag := range flags {
+		if flag < 0 || flag > 7 {
+			log.Printf("Warning: LayerMask() called with invalid flag number %d. Valid range is 0-7.", flag)
+			continue
+		}
+		mask |= 1 << flag
+	}
+	return mask
+}
+
+// MapLayer draws the map tiles whose sprite has flag set (0-7), skipping
+// all others, so a map can be drawn in layers with sprites in between.
+// Optional args are Map's: [mx, my, sx, sy, w, h].
+//
+// Map and MapLayer draw each cell at most once per call, so a layer is a
+// single pass. Layers drawn by later calls cover the ones drawn before, and
+// transparent pixels (see Palt) show the layers below. A tile whose sprite
+// has several of the flags used as layers is drawn by every call asking for
+// one of them, on top of what was drawn in between; give each tile a single
+// layer flag to avoid that.
+//
+// Example:
+//
+//	func (g *Game) Draw() {
+//		Cls(1)
+//		MapLayer(Flag4) // Background: walls and floor
+//		Spr(1, g.player.x, g.player.y)
+//		MapLayer(Flag6) // Foreground: tree tops the player walks behind
+//	}
+func MapLayer(flag int, args ...any) {
+	if flag < 0 || flag > 7 {
+		log.Printf("Warning: MapLayer() called with invalid flag number %d. Valid range is 0-7.", flag)
+		return
+	}
+	if len(args) > 6 {
+		log.Printf("Warning: MapLayer() called with too many arguments (%d), expected max 6 ([mx,my,sx,sy,w,h]).", len(args))
+		args = args[:6]
+	}
+
+	// Fill in the defaults up to w and h, so the layer lands in Map's layers argument
+	width, height := GetMapSize()
+	full := []any{0, 0, 0, 0, width, height}
+	copy(full, args)
+	Map(append(full, 1<<flag)...)
+}
+
+// mapTileInLayers reports whether a tile whose sprite has the flags bitfield
+// is drawn by Map with the given layers: 0 draws every tile, otherwise the
+// sprite needs at least one of the flags in layers.
+func mapTileInLayers(flags, layers int) bool {
+	return layers == 0 || flags&layers != 0
+}
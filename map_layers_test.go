@@ -0,0 +1,74 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayerMask(t *testing.T) {
+	assert.Equal(t, 0b1010000, LayerMask(Flag4, Flag6))
+	assert.NotEqual(t, 4+6, LayerMask(Flag4, Flag6), "flag numbers are not a bitfield")
+	assert.Equal(t, 1, LayerMask(Flag0, Flag0))
+	assert.Equal(t, 0b10, LayerMask(Flag1, 8, -1), "invalid flags are ignored")
+	assert.Equal(t, 0, LayerMask())
+}
+
+func TestMapTileInLayers(t *testing.T) {
+	assert.True(t, mapTileInLayers(0, 0), "layers 0 draws every tile")
+	assert.True(t, mapTileInLayers(0b101, 0b100))
+	assert.True(t, mapTileInLayers(0b101, 0b110), "one matching flag is enough")
+	assert.False(t, mapTileInLayers(0b001, 0b110))
+	assert.False(t, mapTileInLayers(0, 0b1))
+}
+
+// useTestLayerSprites loads sprites 1 (flag 4, background), 2 (flag 6,
+// foreground), 3 (flags 4 and 6) and 4 (flag 0 only).
+func useTestLayerSprites(t *testing.T) {
+	useTestConsoleState(t)
+	currentSprites = []spriteInfo{
+		{ID: 1, Flags: FlagsData{Bitfield: LayerMask(Flag4)}},
+		{ID: 2, Flags: FlagsData{Bitfield: LayerMask(Flag6)}},
+		{ID: 3, Flags: FlagsData{Bitfield: LayerMask(Flag4, Flag6)}},
+		{ID: 4, Flags: FlagsData{Bitfield: LayerMask(Flag0)}},
+	}
+}
+
+func TestMapRegionTiles(t *testing.T) {
+	useTestLayerSprites(t)
+	Mset(0, 0, 1)
+	Mset(1, 0, 2)
+	Mset(0, 1, 3)
+	Mset(1, 1, 4)
+
+	assert.Equal(t, []mapTileDraw{{1, 0, 0}, {2, 8, 0}, {3, 0, 8}, {4, 8, 8}}, mapRegionTiles(0, 0, 2, 2, 0),
+		"layers 0 draws every non-empty tile in row-major order")
+	assert.Equal(t, []mapTileDraw{{1, 0, 0}, {3, 0, 8}}, mapRegionTiles(0, 0, 2, 2, LayerMask(Flag4)),
+		"non-matching tiles are skipped")
+	assert.Equal(t, []mapTileDraw{{1, 0, 0}, {2, 8, 0}, {3, 0, 8}}, mapRegionTiles(0, 0, 2, 2, LayerMask(Flag4, Flag6)),
+		"a tile matching several layers is drawn once")
+	assert.Empty(t, mapRegionTiles(0, 0, 2, 2, LayerMask(Flag7)))
+}
+
+func TestMapLayersOcclusion(t *testing.T) {
+	useTestLayerSprites(t)
+
+	// Background room at map (0, 0), foreground overlay at map (16, 0), both
+	// drawn at the same screen position
+	Mset(0, 0, 1)
+	Mset(1, 0, 1)
+	Mset(2, 0, 1)
+	Mset(16, 0, 2) // Covers the background at cell 0
+	Mset(17, 0, 4) // Not on the foreground layer, the background stays visible
+
+	screen := map[[2]int]int{}
+	draw := func(tiles []mapTileDraw) {
+		for _, tile := range tiles {
+			screen[[2]int{tile.x, tile.y}] = tile.sprite // Later draws cover earlier ones
+		}
+	}
+	draw(mapRegionTiles(0, 0, 3, 1, LayerMask(Flag4)))
+	draw(mapRegionTiles(16, 0, 3, 1, LayerMask(Flag6)))
+
+	assert.Equal(t, map[[2]int]int{{0, 0}: 2, {8, 0}: 1, {16, 0}: 1}, screen)
+}
@@ -0,0 +1,185 @@
+package pigo8
+
+// --- Bulk map region operations ---
+
+// MapStamp is a rectangular block of map tiles. Capture one with MapCopy and
+// draw it back into the map, anywhere, with MapPaste.
+type MapStamp struct {
+	Width  int
+	Height int
+	Tiles  []int // Row-major sprite numbers, Width*Height entries
+}
+
+// At returns the sprite number at (x, y) inside the stamp, or 0 outside it.
+func (s MapStamp) At(x, y int) int {
+	if x < 0 || y < 0 || x >= s.Width || y >= s.Height {
+		return 0
+	}
+	return s.Tiles[y*s.Width+x]
+}
+
+// MapCopy captures a rectangular region of the map as a stamp.
+// Cells outside the map are captured as 0.
+//
+// Args:
+//   - column, row: top-left map cell of the region
+//   - width, height: size of the region in cells
+//
+// Example:
+//
+//	// Copy the first screen of the map and paste it one screen to the right
+//	room := MapCopy(0, 0, 16, 16)
+//	MapPaste(room, 16, 0)
+func MapCopy(column, row, width, height int) MapStamp {
+	ensureStreamingSystemInitialized()
+
+	worldMapMutex.RLock()
+	defer worldMapMutex.RUnlock()
+	if worldMapStream == nil {
+		return copyMapTiles(nil, 0, 0, column, row, width, height)
+	}
+	return copyMapTiles(worldMapStream.Data, worldMapStream.WorldWidthInTiles, worldMapStream.WorldHeightInTiles,
+		column, row, width, height)
+}
+
+// MapPaste writes a stamp into the map with its top-left corner at (column, row).
+// Cells that would land outside the map are clipped.
+// Returns the number of map cells that changed; the SetOnMapChange callback
+// is called for each of them.
+//
+// Args:
+//   - stamp: the tiles to write, usually from MapCopy
+//   - column, row: map cell for the top-left corner of the stamp
+//   - skipEmpty: (optional) if true, cells holding sprite 0 in the stamp leave the map untouched
+//
+// Example:
+//
+//	tree := MapCopy(40, 0, 2, 3)
+//	MapPaste(tree, 10, 12, true) // keep the ground around the tree
+func MapPaste(stamp MapStamp, column, row int, skipEmpty ...bool) int {
+	skip := len(skipEmpty) > 0 && skipEmpty[0]
+	var before MapStamp
+	changed := updateMapTiles(func(data []int, worldW, worldH int) int {
+		if onMapChange != nil {
+			before = copyMapTiles(data, worldW, worldH, column, row, stamp.Width, stamp.Height)
+		}
+		return pasteMapTiles(data, worldW, worldH, stamp, column, row, skip)
+	})
+	if changed > 0 && len(before.Tiles) > 0 {
+		notifyStampChanges(before, stamp, column, row, skip)
+	}
+	return changed
+}
+
+// notifyStampChanges calls the SetOnMapChange callback for every cell that
+// pasting stamp changed, given the tiles the region held before. Cells
+// outside the map were clipped by the paste and are skipped.
+func notifyStampChanges(before, stamp MapStamp, column, row int, skipEmpty bool) {
+	worldW, worldH := GetMapSize()
+	for y := 0; y < stamp.Height; y++ {
+		for x := 0; x < stamp.Width; x++ {
+			wx, wy := column+x, row+y
+			if wx < 0 || wx >= worldW || wy < 0 || wy >= worldH {
+				continue
+			}
+			i := y*stamp.Width + x
+			tile := stamp.Tiles[i]
+			if (skipEmpty && tile == 0) || tile < 0 {
+				continue
+			}
+			notifyMapChange(wx, wy, before.Tiles[i], tile)
+		}
+	}
+}
+
+// MapFill sets every cell of a rectangular map region to the same sprite.
+// Cells outside the map are ignored. Returns the number of cells that changed.
+//
+// Example:
+//
+//	MapFill(0, 14, 16, 2, 5) // two rows of ground tiles at the bottom of the first screen
+func MapFill(column, row, width, height, sprite int) int {
+	stamp := MapStamp{Width: width, Height: height}
+	if width > 0 && height > 0 {
+		stamp.Tiles = make([]int, width*height)
+		for i := range stamp.Tiles {
+			stamp.Tiles[i] = sprite
+		}
+	}
+	return MapPaste(stamp, column, row)
+}
+
+// updateMapTiles runs fn on the world map and, if anything changed,
+// invalidates the active tile buffer and the map draw cache.
+func updateMapTiles(fn func(data []int, worldW, worldH int) int) int {
+	ensureStreamingSystemInitialized()
+
+	worldMapMutex.Lock()
+	if worldMapStream == nil {
+		worldMapMutex.Unlock()
+		return 0
+	}
+	changed := fn(worldMapStream.Data, worldMapStream.WorldWidthInTiles, worldMapStream.WorldHeightInTiles)
+	worldMapMutex.Unlock()
+
+	if changed > 0 {
+		activeBufferMutex.Lock()
+		if activeTileBufferInstance != nil {
+			activeTileBufferInstance.IsRegionLoaded = false
+		}
+		activeBufferMutex.Unlock()
+		mapCacheIsValid = false
+	}
+	return changed
+}
+
+// copyMapTiles copies a region out of a row-major tile slice.
+func copyMapTiles(data []int, worldW, worldH, column, row, width, height int) MapStamp {
+	if width <= 0 || height <= 0 {
+		return MapStamp{}
+	}
+	stamp := MapStamp{Width: width, Height: height, Tiles: make([]int, width*height)}
+	for y := 0; y < height; y++ {
+		wy := row + y
+		if wy < 0 || wy >= worldH {
+			continue
+		}
+		for x := 0; x < width; x++ {
+			wx := column + x
+			if wx < 0 || wx >= worldW {
+				continue
+			}
+			stamp.Tiles[y*width+x] = data[wy*worldW+wx]
+		}
+	}
+	return stamp
+}
+
+// pasteMapTiles writes a stamp into a row-major tile slice and returns the number of changed cells.
+func pasteMapTiles(data []int, worldW, worldH int, stamp MapStamp, column, row int, skipEmpty bool) int {
+	if stamp.Width <= 0 || stamp.Height <= 0 || len(stamp.Tiles) < stamp.Width*stamp.Height {
+		return 0
+	}
+	changed := 0
+	for y := 0; y < stamp.Height; y++ {
+		wy := row + y
+		if wy < 0 || wy >= worldH {
+			continue
+		}
+		for x := 0; x < stamp.Width; x++ {
+			wx := column + x
+			if wx < 0 || wx >= worldW {
+				continue
+			}
+			tile := stamp.Tiles[y*stamp.Width+x]
+			if (skipEmpty && tile == 0) || tile < 0 {
+				continue
+			}
+			if data[wy*worldW+wx] != tile {
+				data[wy*worldW+wx] = tile
+				changed++
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,48 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyMapTiles(t *testing.T) {
+	// 4x3 world
+	data := []int{
+		1, 2, 3, 4,
+		5, 6, 7, 8,
+		9, 10, 11, 12,
+	}
+
+	stamp := copyMapTiles(data, 4, 3, 1, 1, 2, 2)
+	assert.Equal(t, MapStamp{Width: 2, Height: 2, Tiles: []int{6, 7, 10, 11}}, stamp)
+	assert.Equal(t, 11, stamp.At(1, 1))
+	assert.Equal(t, 0, stamp.At(2, 0))
+
+	// Cells outside the world read as 0
+	stamp = copyMapTiles(data, 4, 3, 3, 2, 2, 2)
+	assert.Equal(t, []int{12, 0, 0, 0}, stamp.Tiles)
+
+	assert.Equal(t, MapStamp{}, copyMapTiles(data, 4, 3, 0, 0, 0, 5))
+}
+
+func TestPasteMapTiles(t *testing.T) {
+	data := make([]int, 4*3)
+	stamp := MapStamp{Width: 2, Height: 2, Tiles: []int{1, 0, 3, 4}}
+
+	changed := pasteMapTiles(data, 4, 3, stamp, 0, 0, false)
+	assert.Equal(t, 3, changed, "writing 0 over 0 is not a change")
+
+	// Clipped at the bottom-right corner
+	changed = pasteMapTiles(data, 4, 3, stamp, 3, 2, false)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, 1, data[2*4+3])
+
+	// skipEmpty keeps what is under the stamp's empty cells
+	data[1] = 9
+	changed = pasteMapTiles(data, 4, 3, stamp, 0, 0, true)
+	assert.Equal(t, 0, changed)
+	assert.Equal(t, 9, data[1])
+
+	assert.Equal(t, 0, pasteMapTiles(data, 4, 3, MapStamp{Width: 2, Height: 2}, 0, 0, false), "malformed stamp is ignored")
+}
@@ -193,3 +193,44 @@ func TestMgetWithMapFile(t *testing.T) {
 	// However, our current initializeStreamingMapSystem prioritizes map.json dimensions for worldMapStream.
 	assert.Equal(t, 0, Mget(20, 20), "Mget(20, 20) should be 0 as it's outside the 16x16 map loaded from file")
 }
+
+func TestSetMapSize(t *testing.T) {
+	useTestConsoleState(t)
+
+	Mset(2, 3, 42)
+	Mset(127, 127, 7)
+
+	SetMapSize(256, 64)
+	width, height := GetMapSize()
+	assert.Equal(t, 256, width)
+	assert.Equal(t, 64, height)
+	assert.Equal(t, 42, Mget(2, 3), "tiles inside both sizes are kept")
+	assert.Equal(t, 0, Mget(127, 127), "out of bounds after shrinking")
+
+	Mset(200, 10, 5)
+	assert.Equal(t, 5, Mget(200, 10), "cells beyond 128 can be set")
+	assert.Equal(t, 0, Mget(256, 10))
+
+	SetMapSize(0, 10)
+	width, height = GetMapSize()
+	assert.Equal(t, 256, width, "invalid sizes are ignored")
+	assert.Equal(t, 64, height)
+
+	// SetMap takes data for the current size
+	data := make([]byte, 256*64)
+	data[10*256+200] = 9
+	SetMap(data)
+	assert.Equal(t, 9, Mget(200, 10))
+	assert.Equal(t, 0, Mget(2, 3))
+
+	// Data for the default size resets the map to it
+	SetMap(make([]byte, defaultPico8MapWidth*defaultPico8MapHeight))
+	width, height = GetMapSize()
+	assert.Equal(t, defaultPico8MapWidth, width)
+	assert.Equal(t, defaultPico8MapHeight, height)
+
+	// Other lengths are rejected
+	SetMap(make([]byte, 10))
+	width, _ = GetMapSize()
+	assert.Equal(t, defaultPico8MapWidth, width)
+}
@@ -1,10 +1,44 @@
 package pigo8
 
 import (
+	"log"
 	"math"
 	"math/rand"
+	"sync"
+	"time"
 )
 
+var (
+	// rng is the random number generator behind Rnd, RndChoice and RndWeighted.
+	rng      = rand.New(rand.NewSource(time.Now().UnixNano()))
+	rngMutex sync.Mutex
+)
+
+// rngFloat64 returns a random float64 in [0, 1) from rng.
+func rngFloat64() float64 {
+	rngMutex.Lock()
+	defer rngMutex.Unlock()
+	return rng.Float64()
+}
+
+// Srand seeds the random number generator used by Rnd, RndChoice and
+// RndWeighted, and picks the pattern of Noise1D and Noise2D. It mimics PICO-8's `srand()` function: after the same seed,
+// the same calls return the same values, which makes levels, replays and
+// tests reproducible. Without Srand the generator is seeded from the clock.
+//
+// Example:
+//
+//	Srand(42)
+//	a := Rnd(100)
+//	Srand(42)
+//	b := Rnd(100) // b == a
+func Srand(seed int64) {
+	rngMutex.Lock()
+	defer rngMutex.Unlock()
+	rng.Seed(seed)
+	noiseSeed = uint64(seed)
+}
+
 // Flr rounds the given number down and returns the nearest integer (whole number).
 // It mimics the behavior of PICO-8's `flr()` function.
 //
@@ -44,9 +78,8 @@ func Flr[T Number](a T) int {
 // If `a` is zero or negative, Rnd returns 0.
 // If `a` is positive, the result is in the range [0, floor(a)).
 //
-// Note: This uses Go's standard `math/rand` package. Unlike PICO-8's default `rnd()`,
-// the sequence is not deterministic across program runs unless the global random
-// source is explicitly seeded using `rand.Seed()`.
+// Note: The sequence is not deterministic across program runs unless the random
+// number generator is explicitly seeded using `Srand()`.
 //
 // Args:
 //   - a: The upper exclusive bound (any Number type) for the random number.
@@ -70,10 +103,76 @@ func Rnd[T Number](a T) int {
 		return 0
 	}
 
-	// rand.Float64() returns a float64 in [0.0, 1.0)
+	// rngFloat64() returns a float64 in [0.0, 1.0)
 	// Multiplying by limit gives a float64 in [0.0, limit)
 	// Applying Floor and converting to int gives an integer in [0, floor(limit))
-	return int(math.Floor(rand.Float64() * limit))
+	return int(math.Floor(rngFloat64() * limit))
+}
+
+// RndChoice returns a random element of items, each with the same chance.
+// It mimics PICO-8's `rnd(table)` and uses the same generator as Rnd, so it
+// is reproducible after Srand.
+//
+// If items is empty, RndChoice returns the zero value of T (it does not panic).
+//
+// Example:
+//
+//	alienSprites := []int{1, 2, 3, 4}
+//	sprite := RndChoice(alienSprites)
+//
+//	name := RndChoice([]string{"ZAP", "POW", "BAM"})
+func RndChoice[T any](items []T) T {
+	var zero T
+	if len(items) == 0 {
+		return zero
+	}
+	return items[Rnd(len(items))]
+}
+
+// RndWeighted returns a random element of items, where items[i] is picked
+// with a chance of weights[i] divided by the sum of all weights. It uses the
+// same generator as Rnd, so it is reproducible after Srand.
+//
+// Negative weights count as 0. RndWeighted returns the zero value of T (it
+// does not panic) if items is empty or no weight is positive. If weights and
+// items have different lengths, a warning is logged and the zero value is
+// returned.
+//
+// Example:
+//
+//	// Common drops 70% of the time, rare 25%, legendary 5%
+//	drop := RndWeighted([]string{"coin", "gem", "crown"}, []float64{70, 25, 5})
+func RndWeighted[T any](items []T, weights []float64) T {
+	var zero T
+	if len(items) != len(weights) {
+		log.Printf("Warning: RndWeighted() called with %d items but %d weights. Returning the zero value.", len(items), len(weights))
+		return zero
+	}
+
+	total := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total <= 0 {
+		return zero
+	}
+
+	pick := rngFloat64() * total
+	last := 0
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		if pick < w {
+			return items[i]
+		}
+		pick -= w
+		last = i
+	}
+	// Rounding can leave pick just above the last weight
+	return items[last]
 }
 
 // Sqrt returns the square root of the given number.
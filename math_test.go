@@ -113,3 +113,49 @@ func TestRnd(t *testing.T) {
 		assert.Less(t, val3, expectedMaxUint)
 	})
 }
+
+func TestSrand(t *testing.T) {
+	Srand(42)
+	first := []int{Rnd(1000), Rnd(1000), Rnd(1000)}
+	Srand(42)
+	second := []int{Rnd(1000), Rnd(1000), Rnd(1000)}
+	assert.Equal(t, first, second, "the same seed should give the same numbers")
+}
+
+func TestRndChoice(t *testing.T) {
+	Srand(1)
+	items := []string{"a", "b", "c", "d"}
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		counts[RndChoice(items)]++
+	}
+	for _, item := range items {
+		assert.InDelta(t, 1000, counts[item], 150, "%q should be picked about a quarter of the time", item)
+	}
+
+	assert.Equal(t, "", RndChoice([]string{}), "an empty slice should give the zero value")
+	assert.Equal(t, 0, RndChoice[int](nil))
+}
+
+func TestRndWeighted(t *testing.T) {
+	Srand(1)
+	items := []string{"common", "rare", "never"}
+	weights := []float64{3, 1, 0}
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		counts[RndWeighted(items, weights)]++
+	}
+	assert.InDelta(t, 3000, counts["common"], 150)
+	assert.InDelta(t, 1000, counts["rare"], 150)
+	assert.Zero(t, counts["never"], "items with weight 0 should never be picked")
+
+	// Deterministic after Srand
+	Srand(7)
+	a := RndWeighted(items, weights)
+	Srand(7)
+	assert.Equal(t, a, RndWeighted(items, weights))
+
+	assert.Equal(t, "", RndWeighted(items, []float64{1, 2}), "mismatched lengths should give the zero value")
+	assert.Equal(t, "", RndWeighted(items, []float64{0, -1, 0}), "no positive weight should give the zero value")
+	assert.Equal(t, "", RndWeighted([]string{}, []float64{}))
+}
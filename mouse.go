@@ -19,8 +19,11 @@ var (
 // updateMouseState updates the internal mouse state.
 // This should be called once per frame in the game's Update method.
 func updateMouseState() {
-	// Update mouse position
+	// Update mouse position, in screen pixels even when the engine letterboxes
 	mouseX, mouseY = ebiten.CursorPosition()
+	if engineScales() {
+		mouseX, mouseY = windowToScreen(currentViewport, mouseX, mouseY)
+	}
 
 	// Update mouse wheel values
 	wheelX, wheelY := ebiten.Wheel()
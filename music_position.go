@@ -0,0 +1,97 @@
+package pigo8
+
+import "time"
+
+// --- Music position ---
+
+// musicPositionCache holds what MusicPosition returned during a frame, so
+// every call in the same frame sees the same position.
+var musicPositionCache struct {
+	valid    bool
+	frame    int
+	track    int
+	position float64
+}
+
+// MusicPosition returns the track that Music most recently started, if it is
+// still playing, and how far it has played as a fraction from 0 (start) to 1
+// (end). A looping track starts again from 0 on every loop. It returns -1
+// and 0 when that track is stopped or nothing was played yet.
+//
+// The position is read from the audio player once per frame, so every call
+// during the same Update and Draw returns the same value and game logic
+// stays consistent within a frame. It is best-effort: it trails what is
+// heard by the audio output latency (typically a few tens of milliseconds)
+// and can move in uneven steps between frames, which is fine for progress
+// bars and beat-matched animation but not for sample-accurate timing.
+//
+// Example:
+//
+//	if track, pos := MusicPosition(); track >= 0 {
+//		Rectfill(10, 120, 10+int(pos*108), 122, 11) // Progress bar
+//	}
+func MusicPosition() (track int, position float64) {
+	if musicPositionCache.valid && musicPositionCache.frame == frameCount {
+		return musicPositionCache.track, musicPositionCache.position
+	}
+
+	track, position = -1, 0
+	ap := getAudioPlayer()
+	ap.mutex.Lock()
+	if player := ap.musicPlayers[ap.lastMusic]; player != nil && player.IsPlaying() {
+		track = ap.lastMusic
+		position = musicFraction(player.Position(), ap.musicLength[track], ap.musicLoop[track])
+	}
+	ap.mutex.Unlock()
+
+	musicPositionCache.valid = true
+	musicPositionCache.frame = frameCount
+	musicPositionCache.track, musicPositionCache.position = track, position
+	return track, position
+}
+
+// MusicIsPlaying reports whether music track n is playing. If n is -1, it
+// reports whether any track is playing.
+//
+// Example:
+//
+//	if !MusicIsPlaying(2) {
+//		Music(2) // Start the boss theme once the intro is over
+//	}
+func MusicIsPlaying(n int) bool {
+	ap := getAudioPlayer()
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	if n != -1 {
+		player := ap.musicPlayers[n]
+		return player != nil && player.IsPlaying()
+	}
+	for _, player := range ap.musicPlayers {
+		if player != nil && player.IsPlaying() {
+			return true
+		}
+	}
+	return false
+}
+
+// musicFraction returns how far elapsed is into a track of the given length,
+// from 0 to 1. A looping track wraps around; other tracks stop at 1.
+func musicFraction(elapsed, length time.Duration, loop bool) float64 {
+	if length <= 0 || elapsed <= 0 {
+		return 0
+	}
+	if loop {
+		elapsed %= length
+	} else if elapsed >= length {
+		return 1
+	}
+	return float64(elapsed) / float64(length)
+}
+
+// pcmDuration returns the play time of size bytes of the 16-bit stereo PCM
+// the audio player plays at sampleRate.
+func pcmDuration(size int64) time.Duration {
+	const bytesPerSample = 4 // 16-bit stereo
+	return time.Duration(size) * time.Second / (bytesPerSample * sampleRate)
+}
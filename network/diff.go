@@ -0,0 +1,334 @@
+package network
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// --- State Diffs ---
+
+// Formats of the payloads made by Diff and FullState, stored in their first byte.
+const (
+	stateFull byte = iota // Every field, in declaration order
+	stateDiff             // The number of changed fields, then each field index and value
+)
+
+// FullState encodes every exported field of state in the compact binary
+// format of Diff. Send it to start a session, and from time to time over UDP,
+// so a client that lost a diff gets back in sync. Patch applies it like a diff.
+//
+// state must be a struct (or a pointer to one) whose exported fields are
+// bools, integers, floats, strings or slices of those. Unexported fields are
+// skipped.
+func FullState[T any](state T) ([]byte, error) {
+	fields, err := encodeStateFields(reflect.ValueOf(state))
+	if err != nil {
+		return nil, err
+	}
+	return fullPayload(fields), nil
+}
+
+// Diff encodes the exported fields of current that changed since previous, for
+// Patch to apply on the other side. Fields are compared by their encoded
+// value, so a float that changes and changes back, or NaN, is never sent by
+// mistake. When the diff isn't smaller than the full state, e.g. because most
+// fields changed, Diff returns the full state instead, so its output is never
+// larger than FullState's.
+//
+// A diff only makes sense applied to the same previous state, so the sender
+// must know what the receiver has: send a FullState when a client connects and
+// periodically, or after a lost packet, and diff against the last state sent.
+// The supported field types are those of FullState.
+//
+// Example:
+//
+//	// Server, every tick
+//	data, err := p8net.Diff(g.lastSent, state)
+//	if err == nil {
+//		p8net.SendGameState(data, "")
+//		g.lastSent = state
+//	}
+//
+//	// Client, in the game state callback
+//	if err := p8net.Patch(&g.state, data); err != nil {
+//		log.Printf("bad state: %v", err)
+//	}
+func Diff[T any](previous, current T) ([]byte, error) {
+	before, err := encodeStateFields(reflect.ValueOf(previous))
+	if err != nil {
+		return nil, err
+	}
+	after, err := encodeStateFields(reflect.ValueOf(current))
+	if err != nil {
+		return nil, err
+	}
+
+	diff := []byte{stateDiff}
+	changed := 0
+	var body []byte
+	for i := range after {
+		if bytes.Equal(before[i], after[i]) {
+			continue
+		}
+		changed++
+		body = binary.AppendUvarint(body, uint64(i))
+		body = append(body, after[i]...)
+	}
+	diff = binary.AppendUvarint(diff, uint64(changed))
+	diff = append(diff, body...)
+
+	if full := fullPayload(after); len(full) <= len(diff) {
+		return full, nil
+	}
+	return diff, nil
+}
+
+// Patch applies a payload made by Diff or FullState to state, which must point
+// to a struct of the same type as the one encoded. Fields not in a diff keep
+// their values. On error, state may be partly updated.
+func Patch[T any](state *T, data []byte) error {
+	if state == nil {
+		return errors.New("patch: nil state")
+	}
+	v := reflect.ValueOf(state).Elem()
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return errors.New("patch: nil state")
+		}
+		v = v.Elem()
+	}
+	fields, err := stateFields(v)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return errors.New("patch: empty payload")
+	}
+
+	r := &stateReader{data: data[1:]}
+	switch data[0] {
+	case stateFull:
+		for _, field := range fields {
+			if err := r.decodeValue(field); err != nil {
+				return err
+			}
+		}
+	case stateDiff:
+		changed := r.uvarint()
+		for n := uint64(0); n < changed && r.err == nil; n++ {
+			index := r.uvarint()
+			if r.err != nil {
+				break
+			}
+			if index >= uint64(len(fields)) {
+				return fmt.Errorf("patch: field %d out of range, the state has %d fields", index, len(fields))
+			}
+			if err := r.decodeValue(fields[index]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("patch: unknown payload format %d", data[0])
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.data) > 0 {
+		return fmt.Errorf("patch: %d unexpected bytes after the state", len(r.data))
+	}
+	return nil
+}
+
+// fullPayload joins encoded fields into a FullState payload.
+func fullPayload(fields [][]byte) []byte {
+	full := []byte{stateFull}
+	for _, field := range fields {
+		full = append(full, field...)
+	}
+	return full
+}
+
+// stateFields returns the exported fields of the struct v, following pointers.
+func stateFields(v reflect.Value) ([]reflect.Value, error) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, errors.New("state is a nil pointer")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("state must be a struct, not %s", v.Kind())
+	}
+	var fields []reflect.Value
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).IsExported() {
+			fields = append(fields, v.Field(i))
+		}
+	}
+	return fields, nil
+}
+
+// encodeStateFields encodes each exported field of the struct v on its own.
+func encodeStateFields(v reflect.Value) ([][]byte, error) {
+	fields, err := stateFields(v)
+	if err != nil {
+		return nil, err
+	}
+	encoded := make([][]byte, len(fields))
+	for i, field := range fields {
+		if encoded[i], err = appendValue(nil, field); err != nil {
+			return nil, fmt.Errorf("exported field %d: %v", i, err)
+		}
+	}
+	return encoded, nil
+}
+
+// appendValue appends the encoding of v: varints for integers and lengths,
+// little-endian IEEE bits for floats.
+func appendValue(b []byte, v reflect.Value) ([]byte, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(b, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(b, v.Uint()), nil
+	case reflect.Float32:
+		return binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(v.Float()))), nil
+	case reflect.Float64:
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(v.Float())), nil
+	case reflect.String:
+		b = binary.AppendUvarint(b, uint64(v.Len()))
+		return append(b, v.String()...), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Slice {
+			return nil, errors.New("nested slices are not supported")
+		}
+		b = binary.AppendUvarint(b, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			var err error
+			if b, err = appendValue(b, v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// stateReader decodes the values written by appendValue. The first error
+// sticks, so a sequence of reads only needs one check.
+type stateReader struct {
+	data []byte
+	err  error
+}
+
+func (r *stateReader) fail(format string, args ...any) {
+	if r.err == nil {
+		r.err = fmt.Errorf("patch: "+format, args...)
+	}
+}
+
+func (r *stateReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail("truncated payload")
+		return 0
+	}
+	r.data = r.data[n:]
+	return value
+}
+
+func (r *stateReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.fail("truncated payload")
+		return 0
+	}
+	r.data = r.data[n:]
+	return value
+}
+
+func (r *stateReader) bytes(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.data)) {
+		r.fail("truncated payload")
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+// decodeValue reads a value into v, which must have the type it was encoded from.
+func (r *stateReader) decodeValue(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		if b := r.bytes(1); b != nil {
+			v.SetBool(b[0] != 0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value := r.varint()
+		if r.err == nil && v.OverflowInt(value) {
+			r.fail("%d overflows %s", value, v.Type())
+		}
+		if r.err == nil {
+			v.SetInt(value)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value := r.uvarint()
+		if r.err == nil && v.OverflowUint(value) {
+			r.fail("%d overflows %s", value, v.Type())
+		}
+		if r.err == nil {
+			v.SetUint(value)
+		}
+	case reflect.Float32:
+		if b := r.bytes(4); b != nil {
+			v.SetFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))))
+		}
+	case reflect.Float64:
+		if b := r.bytes(8); b != nil {
+			v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+		}
+	case reflect.String:
+		if b := r.bytes(r.uvarint()); r.err == nil {
+			v.SetString(string(b))
+		}
+	case reflect.Slice:
+		n := r.uvarint()
+		if r.err == nil && n > uint64(len(r.data)) {
+			// Every element takes at least one byte
+			r.fail("truncated payload")
+		}
+		if r.err != nil {
+			break
+		}
+		slice := reflect.MakeSlice(v.Type(), int(n), int(n))
+		for i := 0; i < int(n) && r.err == nil; i++ {
+			r.decodeValue(slice.Index(i))
+		}
+		if r.err == nil {
+			v.Set(slice)
+		}
+	default:
+		r.fail("unsupported type %s", v.Type())
+	}
+	return r.err
+}
@@ -0,0 +1,82 @@
+package network
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testState struct {
+	BallX, BallY float64
+	Score        int
+	Lives        uint8
+	Started      bool
+	Scored       string
+	Trail        []float32
+	private      int
+}
+
+func TestDiffPatch(t *testing.T) {
+	previous := testState{BallX: 10, BallY: 20, Score: 3, Lives: 2, Scored: "left", Trail: []float32{1, 2}}
+	current := previous
+	current.BallX = 11.5
+	current.Score = -4
+	current.private = 9
+
+	diff, err := Diff(previous, current)
+	require.NoError(t, err)
+	full, err := FullState(current)
+	require.NoError(t, err)
+	assert.Less(t, len(diff), len(full))
+
+	remote := previous
+	require.NoError(t, Patch(&remote, diff))
+	current.private = 0 // Unexported fields aren't sent
+	assert.Equal(t, current, remote)
+
+	var fresh testState
+	require.NoError(t, Patch(&fresh, full))
+	assert.Equal(t, current, fresh)
+}
+
+func TestDiffFallsBackToFullState(t *testing.T) {
+	previous := testState{}
+	current := testState{BallX: 1, BallY: 2, Score: 3, Lives: 4, Started: true, Scored: "r", Trail: []float32{5}}
+
+	diff, err := Diff(previous, current)
+	require.NoError(t, err)
+	full, err := FullState(current)
+	require.NoError(t, err)
+	assert.Equal(t, full, diff, "a diff of every field is sent as the full state")
+
+	unchanged, err := Diff(current, current)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{stateDiff, 0}, unchanged)
+}
+
+func TestDiffNaN(t *testing.T) {
+	state := testState{BallX: math.NaN()}
+	diff, err := Diff(state, state)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{stateDiff, 0}, diff, "NaN compares equal to itself")
+}
+
+func TestPatchErrors(t *testing.T) {
+	var state testState
+	assert.Error(t, Patch(&state, nil))
+	assert.Error(t, Patch(&state, []byte{7}))
+	assert.Error(t, Patch(&state, []byte{stateDiff, 1, 99}), "field out of range")
+	assert.Error(t, Patch(&state, []byte{stateFull, 1}), "truncated")
+
+	_, err := Diff(struct{ M map[int]int }{}, struct{ M map[int]int }{})
+	assert.Error(t, err, "maps are not supported")
+	_, err = FullState(42)
+	assert.Error(t, err)
+
+	var small struct{ N int8 }
+	big, err := FullState(struct{ N int }{1000})
+	require.NoError(t, err)
+	assert.Error(t, Patch(&small, big), "1000 overflows int8")
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -79,6 +80,7 @@ type Manager struct {
 	udpConn    *net.UDPConn            // UDP connection for both server and client
 	serverAddr *net.UDPAddr            // Server address (used by clients)
 	clients    map[string]*net.UDPAddr // Map of connected clients by player ID
+	clientIDs  []string                // Connected player IDs in connection order
 	lastHeard  map[string]time.Time    // Last time we heard from each client
 	// Message handling
 	incomingMsgs chan networkMessage
@@ -97,6 +99,17 @@ type Manager struct {
 	// Heartbeat
 	heartbeatTicker   *time.Ticker
 	heartbeatInterval time.Duration
+	// Stats (see GetNetworkStats)
+	pings      map[string]time.Duration // Last heartbeat round trip by player ID
+	packetsIn  atomic.Uint64
+	packetsOut atomic.Uint64
+	rates      packetRates
+	// Server ticks (see SetServerTickRate)
+	tickRate      int
+	coalescing    InputCoalescing
+	pendingInputs map[string][][]byte // Inputs waiting for the next tick by player ID
+	tickTicker    *time.Ticker
+	tickDone      chan struct{}
 }
 
 var (
@@ -193,6 +206,7 @@ func ShutdownNetwork() {
 	if networkManager.heartbeatTicker != nil {
 		networkManager.heartbeatTicker.Stop()
 	}
+	networkManager.stopServerTicks()
 
 	// Close UDP connection
 	if networkManager.udpConn != nil {
@@ -205,6 +219,7 @@ func ShutdownNetwork() {
 	// But we can clear the maps
 	networkManager.mutex.Lock()
 	networkManager.clients = make(map[string]*net.UDPAddr)
+	networkManager.clientIDs = nil
 	networkManager.lastHeard = make(map[string]time.Time)
 	networkManager.mutex.Unlock()
 
@@ -256,26 +271,30 @@ func (nm *Manager) startServer() error {
 		}
 	}()
 
+	// Start applying buffered inputs on server ticks, if enabled
+	nm.startServerTicks(serverTickRate, inputCoalescing)
+
 	// Start receiving messages in background
 	go nm.receiveMessages()
 	return nil
 }
 
-// sendHeartbeats sends heartbeat messages to all connected clients
+// sendHeartbeats sends heartbeat messages to all connected clients, or to the
+// server on a client
 func (nm *Manager) sendHeartbeats() {
 	nm.mutex.Lock()
 	defer nm.mutex.Unlock()
 
 	// Skip if no clients
-	if len(nm.clients) == 0 {
+	if nm.config.Role == RoleServer && len(nm.clients) == 0 {
 		return
 	}
 
-	// Create heartbeat message
+	// Create heartbeat message, with the time it was sent for measuring the ping
 	heartbeatMsg := networkMessage{
 		Type:     msgPing,
 		PlayerID: nm.config.PlayerID,
-		Data:     []byte{},
+		Data:     pingTimestamp(time.Now()),
 	}
 
 	// Encode the message
@@ -285,13 +304,19 @@ func (nm *Manager) sendHeartbeats() {
 		return
 	}
 
-	// Send to all clients
-	for playerID, addr := range nm.clients {
-		_, err := nm.udpConn.WriteToUDP(data, addr)
+	// A client sends it to the server, the server to all clients
+	if nm.config.Role == RoleClient {
+		if _, err := nm.write(data); err != nil {
+			log.Printf("Error sending heartbeat to server: %v", err)
+		}
+		return
+	}
+	nm.forEachClient(func(playerID string, addr *net.UDPAddr) {
+		_, err := nm.writeToUDP(data, addr)
 		if err != nil {
 			log.Printf("Error sending heartbeat to %s: %v", playerID, err)
 		}
-	}
+	})
 }
 
 // receiveMessages handles incoming UDP messages
@@ -314,6 +339,7 @@ func (nm *Manager) receiveMessages() {
 		}
 
 		// Process the message
+		nm.packetsIn.Add(1)
 		go nm.handleUDPMessage(buffer[:n], addr)
 	}
 }
@@ -350,7 +376,7 @@ func (nm *Manager) handleUDPMessage(data []byte, addr *net.UDPAddr) {
 
 		// If this is a new client, add them to our clients map
 		if _, exists := nm.clients[msg.PlayerID]; !exists && msg.Type == msgConnect {
-			nm.clients[msg.PlayerID] = addr
+			nm.addClient(msg.PlayerID, addr)
 			nm.waitingForPlayers = false
 			log.Printf("New client connected: %s from %s", msg.PlayerID, addr.String())
 
@@ -405,8 +431,17 @@ func (nm *Manager) handleUDPMessage(data []byte, addr *net.UDPAddr) {
 		}
 	case msgPlayerInput:
 		log.Printf("Received player input message from %s, data size: %d bytes", msg.PlayerID, len(msg.Data))
+		// With a server tick rate the input waits for the next tick
+		nm.mutex.Lock()
+		buffered := nm.tickRate > 0
+		if buffered {
+			nm.bufferInput(msg.PlayerID, msg.Data)
+		}
+		nm.mutex.Unlock()
 		// Forward player input to the appropriate handler
-		if onPlayerInput != nil {
+		if buffered {
+			log.Printf("Buffered player input from %s for the next server tick", msg.PlayerID)
+		} else if onPlayerInput != nil {
 			log.Printf("Calling player input handler with data size: %d bytes", len(msg.Data))
 			onPlayerInput(msg.PlayerID, msg.Data)
 		} else {
@@ -415,10 +450,11 @@ func (nm *Manager) handleUDPMessage(data []byte, addr *net.UDPAddr) {
 	case msgPing:
 		// Respond with a pong
 		log.Printf("Received ping from %s, sending pong", msg.PlayerID)
-		nm.sendPong(msg.PlayerID, addr)
+		nm.sendPong(msg.PlayerID, addr, msg.Data)
 	case msgPong:
 		log.Printf("Received pong from %s", msg.PlayerID)
-		// Just update the last heard time (already done above)
+		// The last heard time is already updated above
+		nm.recordPing(msg.PlayerID, msg.Data, time.Now())
 	default:
 		log.Printf("Received unknown message type: %v", msg.Type)
 	}
@@ -437,7 +473,7 @@ func (nm *Manager) handleUDPMessage(data []byte, addr *net.UDPAddr) {
 // handleClientDisconnect handles a client disconnection
 func (nm *Manager) handleClientDisconnect(playerID string) {
 	nm.mutex.Lock()
-	delete(nm.clients, playerID)
+	nm.removeClient(playerID)
 	delete(nm.lastHeard, playerID)
 	if len(nm.clients) == 0 {
 		nm.waitingForPlayers = true
@@ -452,13 +488,49 @@ func (nm *Manager) handleClientDisconnect(playerID string) {
 	log.Printf("Client disconnected: %s", playerID)
 }
 
-// sendPong sends a pong response to a ping
-func (nm *Manager) sendPong(playerID string, addr *net.UDPAddr) {
+// addClient adds a newly connected client after the ones already connected.
+// The caller must hold nm.mutex.
+func (nm *Manager) addClient(playerID string, addr *net.UDPAddr) {
+	if _, exists := nm.clients[playerID]; !exists {
+		nm.clientIDs = append(nm.clientIDs, playerID)
+	}
+	nm.clients[playerID] = addr
+}
+
+// removeClient removes a client, keeping the others in connection order.
+// The caller must hold nm.mutex.
+func (nm *Manager) removeClient(playerID string) {
+	if _, exists := nm.clients[playerID]; !exists {
+		return
+	}
+	delete(nm.clients, playerID)
+	delete(nm.pendingInputs, playerID)
+	delete(nm.pings, playerID)
+	for i, id := range nm.clientIDs {
+		if id == playerID {
+			nm.clientIDs = append(nm.clientIDs[:i], nm.clientIDs[i+1:]...)
+			break
+		}
+	}
+}
+
+// forEachClient calls fn for every connected client in connection order, so
+// broadcasts reach the clients (and log) in the same order every time. Ranging
+// over nm.clients directly would use Go's random map order. The caller must
+// hold nm.mutex.
+func (nm *Manager) forEachClient(fn func(playerID string, addr *net.UDPAddr)) {
+	for _, playerID := range nm.clientIDs {
+		fn(playerID, nm.clients[playerID])
+	}
+}
+
+// sendPong sends a pong response to a ping, echoing its timestamp
+func (nm *Manager) sendPong(playerID string, addr *net.UDPAddr, timestamp []byte) {
 	// Create pong message
 	pongMsg := networkMessage{
 		Type:     msgPong,
 		PlayerID: nm.config.PlayerID,
-		Data:     []byte{},
+		Data:     timestamp,
 	}
 
 	// Encode the message
@@ -471,10 +543,10 @@ func (nm *Manager) sendPong(playerID string, addr *net.UDPAddr) {
 	// Use different send methods depending on role
 	if nm.config.Role == RoleServer {
 		// Server uses WriteToUDP to send to specific client
-		_, err = nm.udpConn.WriteToUDP(data, addr)
+		_, err = nm.writeToUDP(data, addr)
 	} else {
 		// Client uses Write to send to the pre-connected server
-		_, err = nm.udpConn.Write(data)
+		_, err = nm.write(data)
 	}
 
 	if err != nil {
@@ -547,7 +619,7 @@ func (nm *Manager) connectToServer() error {
 	}
 
 	// Send the connect message
-	_, err = nm.udpConn.Write(data)
+	_, err = nm.write(data)
 	if err != nil {
 		if closeErr := nm.udpConn.Close(); closeErr != nil {
 			log.Printf("Error closing UDP connection after send error: %v", closeErr)
@@ -555,6 +627,17 @@ func (nm *Manager) connectToServer() error {
 		return fmt.Errorf("failed to send connect message: %v", err)
 	}
 
+	// Ping the server regularly, so both sides can measure the ping
+	nm.heartbeatTicker = time.NewTicker(nm.heartbeatInterval)
+	go func() {
+		for range nm.heartbeatTicker.C {
+			if !nm.isRunning {
+				return
+			}
+			nm.sendHeartbeats()
+		}
+	}()
+
 	// Start receiving messages
 	go nm.receiveMessages()
 	return nil
@@ -586,16 +669,12 @@ func (nm *Manager) handleIncomingMessage(msg networkMessage) {
 			nm.onGameState(msg.PlayerID, msg.Data)
 		}
 	case msgPlayerInput:
-		if nm.onPlayerInput != nil {
+		nm.mutex.Lock()
+		buffered := nm.tickRate > 0
+		nm.mutex.Unlock()
+		if !buffered && nm.onPlayerInput != nil {
 			nm.onPlayerInput(msg.PlayerID, msg.Data)
 		}
-	case msgPing:
-		// Respond with pong
-		nm.outgoingMsgs <- networkMessage{
-			Type:     msgPong,
-			PlayerID: nm.config.PlayerID,
-			Data:     msg.Data, // Echo the timestamp
-		}
 	}
 }
 
@@ -623,20 +702,20 @@ func (nm *Manager) sendMessage(msg networkMessage) {
 				log.Printf("Broadcasting to %d clients", clientCount)
 			}
 
-			for playerID, addr := range nm.clients {
-				_, err := nm.udpConn.WriteToUDP(data, addr)
+			nm.forEachClient(func(playerID string, addr *net.UDPAddr) {
+				_, err := nm.writeToUDP(data, addr)
 				if err != nil {
 					log.Printf("Error sending message to client %s: %v", playerID, err)
 				} else {
 					log.Printf("Successfully sent message to client %s", playerID)
 				}
-			}
+			})
 			nm.mutex.Unlock()
 		} else {
 			// Send to specific client
 			nm.mutex.Lock()
 			if addr, ok := nm.clients[msg.PlayerID]; ok {
-				_, err := nm.udpConn.WriteToUDP(data, addr)
+				_, err := nm.writeToUDP(data, addr)
 				if err != nil {
 					log.Printf("Error sending message to client %s: %v", msg.PlayerID, err)
 				} else {
@@ -651,7 +730,7 @@ func (nm *Manager) sendMessage(msg networkMessage) {
 		// Client always sends to server using Write (not WriteToUDP)
 		// For client, we already have the server address set as the remote address
 		if nm.udpConn != nil {
-			_, err := nm.udpConn.Write(data)
+			_, err := nm.write(data)
 			if err != nil {
 				log.Printf("Error sending message to server: %v", err)
 			} else {
@@ -744,7 +823,10 @@ func getPlayerID() string {
 	return networkManager.config.PlayerID
 }
 
-// GetConnectedPlayers returns a list of connected player IDs (server only)
+// GetConnectedPlayers returns the connected player IDs in the order they
+// connected (server only), so the first one can be player 1 and so on. The
+// order is the same on every call; a player that disconnects is removed and
+// the others keep their places.
 func GetConnectedPlayers() []string {
 	networkMutex.Lock()
 	defer networkMutex.Unlock()
@@ -756,10 +838,8 @@ func GetConnectedPlayers() []string {
 	networkManager.mutex.Lock()
 	defer networkManager.mutex.Unlock()
 
-	players := make([]string, 0, len(networkManager.clients))
-	for id := range networkManager.clients {
-		players = append(players, id)
-	}
+	players := make([]string, len(networkManager.clientIDs))
+	copy(players, networkManager.clientIDs)
 	return players
 }
 
@@ -1,7 +1,13 @@
 package network
 
 import (
+	"encoding/json"
+	"net"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestNetworkFunctions tests the public network API functions
@@ -36,3 +42,94 @@ func TestNetworkFunctions(t *testing.T) {
 		t.Skip("Skipping ParseMultiplayerArgs test to avoid potential flag redefinition errors")
 	})
 }
+
+// newTestServer returns a server Manager without a connection, installed as
+// the global network manager until the test ends.
+func newTestServer(t *testing.T) *Manager {
+	nm := &Manager{
+		config:    &Config{Role: RoleServer, PlayerID: "server"},
+		clients:   make(map[string]*net.UDPAddr),
+		lastHeard: make(map[string]time.Time),
+	}
+	networkMutex.Lock()
+	original := networkManager
+	networkManager = nm
+	networkMutex.Unlock()
+	t.Cleanup(func() {
+		networkMutex.Lock()
+		networkManager = original
+		networkMutex.Unlock()
+	})
+	return nm
+}
+
+func TestClientsKeepConnectionOrder(t *testing.T) {
+	nm := newTestServer(t)
+	ids := []string{"zed", "alice", "mallory", "bob", "carol", "dave", "eve", "frank"}
+	for i, id := range ids {
+		nm.addClient(id, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9000 + i})
+	}
+
+	broadcastOrder := func() []string {
+		var order []string
+		nm.forEachClient(func(playerID string, _ *net.UDPAddr) {
+			order = append(order, playerID)
+		})
+		return order
+	}
+	for i := 0; i < 20; i++ {
+		require.Equal(t, ids, broadcastOrder(), "broadcast %d", i)
+		require.Equal(t, ids, GetConnectedPlayers(), "call %d", i)
+	}
+
+	nm.addClient("alice", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9100})
+	assert.Equal(t, ids, GetConnectedPlayers(), "a known client keeps its place")
+	assert.Equal(t, 9100, nm.clients["alice"].Port, "but its address is updated")
+
+	nm.handleClientDisconnect("mallory")
+	nm.handleClientDisconnect("nobody")
+	nm.addClient("mallory", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9002})
+	want := []string{"zed", "alice", "bob", "carol", "dave", "eve", "frank", "mallory"}
+	assert.Equal(t, want, broadcastOrder(), "a reconnecting client goes last")
+	assert.Equal(t, want, GetConnectedPlayers())
+
+	players := GetConnectedPlayers()
+	players[0] = "changed"
+	assert.Equal(t, "zed", GetConnectedPlayers()[0], "callers get a copy")
+}
+
+func TestBroadcastReachesEveryClient(t *testing.T) {
+	nm := newTestServer(t)
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skipf("UDP not available: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	nm.udpConn = conn
+
+	// One socket per client; each reads the broadcast in turn
+	var listeners []*net.UDPConn
+	for _, id := range []string{"p2", "p1", "p3"} {
+		l, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = l.Close() })
+		nm.addClient(id, l.LocalAddr().(*net.UDPAddr))
+		listeners = append(listeners, l)
+	}
+
+	for round := 0; round < 3; round++ {
+		nm.sendMessage(networkMessage{Type: msgGameState, PlayerID: "all", Data: []byte{byte(round)}})
+	}
+	buf := make([]byte, 1024)
+	for _, l := range listeners {
+		for round := 0; round < 3; round++ {
+			require.NoError(t, l.SetReadDeadline(time.Now().Add(2*time.Second)))
+			n, err := l.Read(buf)
+			require.NoError(t, err)
+			var msg networkMessage
+			require.NoError(t, json.Unmarshal(buf[:n], &msg))
+			assert.Equal(t, []byte{byte(round)}, msg.Data)
+		}
+	}
+	assert.Equal(t, []string{"p2", "p1", "p3"}, GetConnectedPlayers())
+}
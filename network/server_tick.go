@@ -0,0 +1,165 @@
+package network
+
+import (
+	"log"
+	"net"
+	"time"
+)
+
+// --- Server Ticks ---
+
+// InputCoalescing chooses what the server does with several inputs from the
+// same player that arrive between two server ticks (see SetServerTickRate).
+type InputCoalescing int
+
+const (
+	// CoalesceLastWins delivers only the newest input from each player. It
+	// suits inputs that carry the whole button state, like "up is held", where
+	// older inputs are already out of date.
+	CoalesceLastWins InputCoalescing = iota
+	// CoalesceQueued delivers every input from each player in arrival order,
+	// for inputs that are one-off events like "jump pressed". At most
+	// Config.BufferSize inputs are kept per player; older ones are dropped.
+	CoalesceQueued
+)
+
+var (
+	// Tick settings applied when the server starts, guarded by networkMutex
+	serverTickRate  int
+	inputCoalescing InputCoalescing
+)
+
+// SetServerTickRate makes the server buffer player inputs and hand them to
+// the player input callback hz times per second, instead of as soon as each
+// packet arrives. UDP packets often arrive in bursts, and applying them on a
+// fixed tick keeps the simulation moving at a steady pace. Each tick delivers
+// the buffered inputs one player at a time, in connection order (see
+// GetConnectedPlayers), and SetInputCoalescing chooses what happens to several
+// inputs from one player within a tick.
+//
+// A rate of 0, the default, delivers inputs as they arrive. It can be called
+// before or after InitNetwork and only affects the server.
+//
+// Example:
+//
+//	p8net.SetServerTickRate(60) // Apply inputs once per frame at 60 FPS
+//	p8net.SetOnPlayerInputCallback(func(playerID string, data []byte) {
+//		// Runs on the tick, with the newest input from playerID
+//	})
+func SetServerTickRate(hz int) {
+	networkMutex.Lock()
+	defer networkMutex.Unlock()
+
+	if hz < 0 {
+		hz = 0
+	}
+	serverTickRate = hz
+	if networkManager != nil && networkManager.config.Role == RoleServer {
+		networkManager.startServerTicks(serverTickRate, inputCoalescing)
+	}
+}
+
+// SetInputCoalescing chooses how several inputs from one player between two
+// server ticks are delivered: CoalesceLastWins (the default) or
+// CoalesceQueued. It has no effect without SetServerTickRate.
+func SetInputCoalescing(c InputCoalescing) {
+	networkMutex.Lock()
+	defer networkMutex.Unlock()
+
+	inputCoalescing = c
+	if networkManager != nil {
+		networkManager.mutex.Lock()
+		networkManager.coalescing = c
+		networkManager.mutex.Unlock()
+	}
+}
+
+// startServerTicks (re)starts delivering buffered inputs hz times per second.
+// A rate of 0 stops it and delivers inputs on arrival again.
+func (nm *Manager) startServerTicks(hz int, c InputCoalescing) {
+	nm.stopServerTicks()
+
+	nm.mutex.Lock()
+	nm.tickRate = hz
+	nm.coalescing = c
+	if hz <= 0 {
+		// Inputs waiting for a tick are out of date by the next one anyway
+		clear(nm.pendingInputs)
+	}
+	nm.mutex.Unlock()
+	if hz <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Second / time.Duration(hz))
+	done := make(chan struct{})
+	nm.tickTicker, nm.tickDone = ticker, done
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				nm.applyBufferedInputs()
+			case <-done:
+				return
+			}
+		}
+	}()
+	log.Printf("Server applying player inputs at %d ticks per second", hz)
+}
+
+// stopServerTicks stops the tick goroutine, if running.
+func (nm *Manager) stopServerTicks() {
+	if nm.tickTicker == nil {
+		return
+	}
+	nm.tickTicker.Stop()
+	close(nm.tickDone)
+	nm.tickTicker, nm.tickDone = nil, nil
+}
+
+// bufferInput keeps an input from playerID until the next tick. The caller
+// must hold nm.mutex.
+func (nm *Manager) bufferInput(playerID string, data []byte) {
+	if nm.pendingInputs == nil {
+		nm.pendingInputs = make(map[string][][]byte)
+	}
+	if nm.coalescing != CoalesceQueued {
+		nm.pendingInputs[playerID] = append(nm.pendingInputs[playerID][:0], data)
+		return
+	}
+
+	queue := append(nm.pendingInputs[playerID], data)
+	if limit := nm.config.BufferSize; limit > 0 && len(queue) > limit {
+		log.Printf("Warning: input queue for %s is full, dropping %d old inputs", playerID, len(queue)-limit)
+		queue = queue[len(queue)-limit:]
+	}
+	nm.pendingInputs[playerID] = queue
+}
+
+// applyBufferedInputs runs one server tick: it hands the buffered inputs of
+// each connected player, in connection order, to the player input callback.
+func (nm *Manager) applyBufferedInputs() {
+	type bufferedInput struct {
+		playerID string
+		data     []byte
+	}
+
+	nm.mutex.Lock()
+	var inputs []bufferedInput
+	nm.forEachClient(func(playerID string, _ *net.UDPAddr) {
+		for _, data := range nm.pendingInputs[playerID] {
+			inputs = append(inputs, bufferedInput{playerID, data})
+		}
+	})
+	clear(nm.pendingInputs)
+	onPlayerInput := nm.onPlayerInput
+	nm.mutex.Unlock()
+
+	// Call back without the lock, so the callback can use the network API
+	if onPlayerInput == nil {
+		return
+	}
+	for _, input := range inputs {
+		onPlayerInput(input.playerID, input.data)
+	}
+}
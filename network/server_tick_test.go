@@ -0,0 +1,88 @@
+package network
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordInputs sets a player input callback on nm that records every input
+// as "playerID:data".
+func recordInputs(nm *Manager) func() []string {
+	var mu sync.Mutex
+	var got []string
+	nm.onPlayerInput = func(playerID string, data []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, playerID+":"+string(data))
+	}
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), got...)
+	}
+}
+
+func addTestClients(nm *Manager, ids ...string) {
+	for i, id := range ids {
+		nm.addClient(id, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9000 + i})
+	}
+}
+
+func TestBufferedInputsLastWins(t *testing.T) {
+	nm := newTestServer(t)
+	addTestClients(nm, "p1", "p2")
+	got := recordInputs(nm)
+
+	nm.bufferInput("p2", []byte("a"))
+	nm.bufferInput("p1", []byte("b"))
+	nm.bufferInput("p2", []byte("c"))
+	nm.bufferInput("stranger", []byte("d"))
+	assert.Empty(t, got(), "nothing is delivered before the tick")
+
+	nm.applyBufferedInputs()
+	assert.Equal(t, []string{"p1:b", "p2:c"}, got(), "newest input per player, in connection order")
+
+	nm.applyBufferedInputs()
+	assert.Len(t, got(), 2, "a tick without inputs delivers nothing")
+}
+
+func TestBufferedInputsQueued(t *testing.T) {
+	nm := newTestServer(t)
+	nm.config.BufferSize = 3
+	nm.coalescing = CoalesceQueued
+	addTestClients(nm, "p1", "p2")
+	got := recordInputs(nm)
+
+	for _, data := range []string{"1", "2", "3", "4", "5"} {
+		nm.bufferInput("p2", []byte(data))
+	}
+	nm.bufferInput("p1", []byte("x"))
+
+	nm.applyBufferedInputs()
+	assert.Equal(t, []string{"p1:x", "p2:3", "p2:4", "p2:5"}, got(), "oldest inputs beyond BufferSize are dropped")
+}
+
+func TestServerTickRate(t *testing.T) {
+	nm := newTestServer(t)
+	addTestClients(nm, "p1")
+	got := recordInputs(nm)
+	t.Cleanup(func() {
+		SetServerTickRate(0)
+		SetInputCoalescing(CoalesceLastWins)
+	})
+
+	SetServerTickRate(50)
+	nm.handleUDPMessage([]byte(`{"type":3,"player_id":"p1","data":"dXA="}`), &net.UDPAddr{})
+	assert.Empty(t, got(), "input waits for the tick")
+	require.Eventually(t, func() bool { return len(got()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"p1:up"}, got())
+
+	SetServerTickRate(0)
+	nm.handleUDPMessage([]byte(`{"type":3,"player_id":"p1","data":"ZG93bg=="}`), &net.UDPAddr{})
+	assert.Equal(t, []string{"p1:up", "p1:down"}, got(), "without a tick rate input is delivered on arrival")
+}
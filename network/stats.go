@@ -0,0 +1,173 @@
+package network
+
+import (
+	"net"
+	"strconv"
+	"time"
+)
+
+// --- Network Stats ---
+
+// Connection states reported in Stats.State
+const (
+	StateOffline    = "offline"    // The network isn't initialized
+	StateWaiting    = "waiting"    // The server is waiting for players
+	StateConnecting = "connecting" // The client hasn't heard from the server yet
+	StateConnected  = "connected"
+	StateLost       = "lost"  // The connection was lost
+	StateError      = "error" // See Stats.Error
+)
+
+// PlayerStats describes one peer: a connected player on the server, or the
+// server on a client.
+type PlayerStats struct {
+	PlayerID  string
+	Ping      time.Duration // Round trip time of the last heartbeat, 0 until measured
+	LastHeard time.Time     // When the last message from the peer arrived
+}
+
+// Stats is a snapshot of the network state returned by GetNetworkStats.
+type Stats struct {
+	IsServer         bool
+	State            string // One of the State constants
+	Error            string
+	PacketsInPerSec  float64
+	PacketsOutPerSec float64
+	Players          []PlayerStats // On the server in connection order
+}
+
+// packetRates turns the packet counters into packets per second.
+type packetRates struct {
+	sampledAt time.Time
+	in, out   uint64
+	inPerSec  float64
+	outPerSec float64
+}
+
+// GetNetworkStats returns the current network state, for debug displays like
+// pigo8's DrawNetworkDebug. The packet rates are averaged over about a second.
+//
+// Example:
+//
+//	for _, p := range p8net.GetNetworkStats().Players {
+//		log.Printf("%s: %v", p.PlayerID, p.Ping)
+//	}
+func GetNetworkStats() Stats {
+	networkMutex.Lock()
+	defer networkMutex.Unlock()
+
+	if networkManager == nil {
+		return Stats{State: StateOffline}
+	}
+	nm := networkManager
+	nm.mutex.Lock()
+	defer nm.mutex.Unlock()
+
+	nm.sampleRates(time.Now())
+	stats := Stats{
+		IsServer:         nm.config.Role == RoleServer,
+		State:            nm.connectionState(),
+		Error:            nm.networkError,
+		PacketsInPerSec:  nm.rates.inPerSec,
+		PacketsOutPerSec: nm.rates.outPerSec,
+	}
+	if stats.IsServer {
+		for _, id := range nm.clientIDs {
+			stats.Players = append(stats.Players, nm.playerStats(id))
+		}
+	} else {
+		// The only peer of a client is the server, known once it has sent something
+		for id := range nm.lastHeard {
+			stats.Players = append(stats.Players, nm.playerStats(id))
+		}
+	}
+	return stats
+}
+
+// connectionState returns the Stats.State. The caller must hold nm.mutex.
+func (nm *Manager) connectionState() string {
+	switch {
+	case nm.networkError != "":
+		return StateError
+	case nm.connectionLost:
+		return StateLost
+	case nm.waitingForPlayers:
+		return StateWaiting
+	case nm.config.Role == RoleClient && len(nm.lastHeard) == 0:
+		return StateConnecting
+	}
+	return StateConnected
+}
+
+// playerStats returns the stats of one peer. The caller must hold nm.mutex.
+func (nm *Manager) playerStats(playerID string) PlayerStats {
+	return PlayerStats{
+		PlayerID:  playerID,
+		Ping:      nm.pings[playerID],
+		LastHeard: nm.lastHeard[playerID],
+	}
+}
+
+// sampleRates updates the packet rates once at least a second has passed
+// since the last sample. The caller must hold nm.mutex.
+func (nm *Manager) sampleRates(now time.Time) {
+	in, out := nm.packetsIn.Load(), nm.packetsOut.Load()
+	r := &nm.rates
+	if r.sampledAt.IsZero() {
+		r.sampledAt, r.in, r.out = now, in, out
+		return
+	}
+	elapsed := now.Sub(r.sampledAt).Seconds()
+	if elapsed < 1 {
+		return
+	}
+	r.inPerSec = float64(in-r.in) / elapsed
+	r.outPerSec = float64(out-r.out) / elapsed
+	r.sampledAt, r.in, r.out = now, in, out
+}
+
+// recordPing stores the round trip time of a ping, from the timestamp the pong
+// echoed back. Pongs without a valid timestamp are ignored.
+func (nm *Manager) recordPing(playerID string, timestamp []byte, now time.Time) {
+	sent, err := strconv.ParseInt(string(timestamp), 10, 64)
+	if err != nil {
+		return
+	}
+	rtt := now.Sub(time.Unix(0, sent))
+	if rtt < 0 {
+		return
+	}
+
+	nm.mutex.Lock()
+	defer nm.mutex.Unlock()
+	if _, connected := nm.clients[playerID]; nm.config.Role == RoleServer && !connected {
+		return
+	}
+	if nm.pings == nil {
+		nm.pings = make(map[string]time.Duration)
+	}
+	nm.pings[playerID] = rtt
+}
+
+// pingTimestamp encodes the time a ping is sent as its message data.
+func pingTimestamp(t time.Time) []byte {
+	return strconv.AppendInt(nil, t.UnixNano(), 10)
+}
+
+// writeToUDP sends data to addr, counting the packet.
+func (nm *Manager) writeToUDP(data []byte, addr *net.UDPAddr) (int, error) {
+	n, err := nm.udpConn.WriteToUDP(data, addr)
+	if err == nil {
+		nm.packetsOut.Add(1)
+	}
+	return n, err
+}
+
+// write sends data to the server on a client, counting the packet.
+func (nm *Manager) write(data []byte) (int, error) {
+	n, err := nm.udpConn.Write(data)
+	if err == nil {
+		nm.packetsOut.Add(1)
+	}
+	return n, err
+}
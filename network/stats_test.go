@@ -0,0 +1,78 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNetworkStatsOffline(t *testing.T) {
+	networkMutex.Lock()
+	original := networkManager
+	networkManager = nil
+	networkMutex.Unlock()
+	t.Cleanup(func() {
+		networkMutex.Lock()
+		networkManager = original
+		networkMutex.Unlock()
+	})
+
+	assert.Equal(t, Stats{State: StateOffline}, GetNetworkStats())
+}
+
+func TestGetNetworkStatsServer(t *testing.T) {
+	nm := newTestServer(t)
+	nm.waitingForPlayers = true
+	assert.Equal(t, StateWaiting, GetNetworkStats().State)
+
+	nm.waitingForPlayers = false
+	addTestClients(nm, "p2", "p1")
+	now := time.Now()
+	nm.recordPing("p1", pingTimestamp(now.Add(-30*time.Millisecond)), now)
+	nm.recordPing("p2", []byte("garbage"), now)
+	nm.recordPing("stranger", pingTimestamp(now), now)
+
+	stats := GetNetworkStats()
+	assert.True(t, stats.IsServer)
+	assert.Equal(t, StateConnected, stats.State)
+	if assert.Len(t, stats.Players, 2) {
+		assert.Equal(t, "p2", stats.Players[0].PlayerID, "players are in connection order")
+		assert.Zero(t, stats.Players[0].Ping, "no valid pong yet")
+		assert.Equal(t, 30*time.Millisecond, stats.Players[1].Ping)
+	}
+	assert.NotContains(t, nm.pings, "stranger", "pongs from unknown players are ignored")
+
+	nm.handleClientDisconnect("p1")
+	assert.NotContains(t, nm.pings, "p1")
+
+	nm.networkError = "boom"
+	stats = GetNetworkStats()
+	assert.Equal(t, StateError, stats.State)
+	assert.Equal(t, "boom", stats.Error)
+}
+
+func TestClientConnectionState(t *testing.T) {
+	nm := newTestServer(t)
+	nm.config.Role = RoleClient
+	assert.Equal(t, StateConnecting, nm.connectionState())
+	nm.lastHeard["server"] = time.Now()
+	assert.Equal(t, StateConnected, nm.connectionState())
+	nm.connectionLost = true
+	assert.Equal(t, StateLost, nm.connectionState())
+}
+
+func TestSampleRates(t *testing.T) {
+	nm := newTestServer(t)
+	start := time.Now()
+	nm.sampleRates(start)
+
+	nm.packetsIn.Add(30)
+	nm.packetsOut.Add(10)
+	nm.sampleRates(start.Add(500 * time.Millisecond))
+	assert.Zero(t, nm.rates.inPerSec, "rates wait for a full second")
+
+	nm.sampleRates(start.Add(2 * time.Second))
+	assert.InDelta(t, 15, nm.rates.inPerSec, 1e-9)
+	assert.InDelta(t, 5, nm.rates.outPerSec, 1e-9)
+}
@@ -0,0 +1,128 @@
+package pigo8
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/drpaneas/pigo8/network"
+)
+
+// --- Network debug overlay ---
+
+// networkDebug draws the network debug overlay over every frame.
+var networkDebug bool
+
+// maxNetworkDebugIDLength shortens player IDs so the overlay fits 128 pixels.
+const maxNetworkDebugIDLength = 12
+
+// networkDebugLine is one line of text of the network debug overlay.
+type networkDebugLine struct {
+	text  string
+	color int
+}
+
+// SetNetworkDebug shows or hides the network debug overlay, drawn over the
+// game after every Draw like DrawNetworkDebug. Settings.NetworkDebug shows it
+// from the start. Toggling it with a key helps diagnose multiplayer games in
+// the field:
+//
+//	if Keyp("F3") {
+//		SetNetworkDebug(!NetworkDebug())
+//	}
+func SetNetworkDebug(on bool) {
+	networkDebug = on
+}
+
+// NetworkDebug reports whether the network debug overlay is shown.
+func NetworkDebug() bool {
+	return networkDebug
+}
+
+// DrawNetworkDebug draws an overlay in the top left corner of the screen with
+// the network role and connection state, the packets sent and received per
+// second, and the ping of every connected player (on a client, of the
+// server). Pings are green under 100ms, yellow under 200ms, red above, and
+// "?" until the first heartbeat comes back.
+//
+// It ignores the camera and leaves the camera, cursor and draw color as they
+// were, so it can be called at the end of Draw without affecting the game's
+// drawing. Use SetNetworkDebug to have the engine draw it instead.
+func DrawNetworkDebug() {
+	lines := networkDebugLines(network.GetNetworkStats())
+
+	width := 0
+	for _, line := range lines {
+		width = max(width, int(math.Ceil(float64(len([]rune(line.text)))*CharWidthApproximation)))
+	}
+	lineHeight := int(defaultFontSize) + 1
+	x, y := 1, 1
+
+	drawOnScreen(func() {
+		Rectfill(x, y, x+width+3, y+len(lines)*lineHeight+2, 0)
+		Rect(x, y, x+width+3, y+len(lines)*lineHeight+2, 5)
+		for i, line := range lines {
+			Print(line.text, x+2, y+2+i*lineHeight, line.color)
+		}
+	})
+}
+
+// networkDebugLines returns the text of the debug overlay for stats.
+func networkDebugLines(stats network.Stats) []networkDebugLine {
+	if stats.State == network.StateOffline {
+		return []networkDebugLine{{"net offline", 6}}
+	}
+	role := "client"
+	if stats.IsServer {
+		role = "server"
+	}
+
+	stateColor := 11
+	switch stats.State {
+	case network.StateWaiting, network.StateConnecting:
+		stateColor = 10
+	case network.StateLost, network.StateError:
+		stateColor = 8
+	}
+	lines := []networkDebugLine{
+		{role + " " + stats.State, stateColor},
+		{fmt.Sprintf("in %.0f/s out %.0f/s", stats.PacketsInPerSec, stats.PacketsOutPerSec), 6},
+	}
+	if stats.Error != "" {
+		lines = append(lines, networkDebugLine{shortenDebugText(stats.Error, 2*maxNetworkDebugIDLength), 8})
+	}
+	for _, p := range stats.Players {
+		lines = append(lines, networkDebugLine{shortenDebugText(p.PlayerID, maxNetworkDebugIDLength) + " " + formatPing(p.Ping), pingColor(p.Ping)})
+	}
+	return lines
+}
+
+// formatPing returns ping in whole milliseconds, or "?" if not measured yet.
+func formatPing(ping time.Duration) string {
+	if ping <= 0 {
+		return "?"
+	}
+	return fmt.Sprintf("%dms", ping.Round(time.Millisecond).Milliseconds())
+}
+
+// pingColor returns the palette color for ping: green, yellow or red.
+func pingColor(ping time.Duration) int {
+	switch {
+	case ping <= 0:
+		return 6
+	case ping < 100*time.Millisecond:
+		return 11
+	case ping < 200*time.Millisecond:
+		return 10
+	}
+	return 8
+}
+
+// shortenDebugText cuts s to at most n characters.
+func shortenDebugText(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
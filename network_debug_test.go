@@ -0,0 +1,57 @@
+package pigo8
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drpaneas/pigo8/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkDebugLines(t *testing.T) {
+	assert.Equal(t, []networkDebugLine{{"net offline", 6}}, networkDebugLines(network.Stats{State: network.StateOffline}))
+
+	lines := networkDebugLines(network.Stats{
+		IsServer:         true,
+		State:            network.StateConnected,
+		PacketsInPerSec:  29.6,
+		PacketsOutPerSec: 60,
+		Players: []network.PlayerStats{
+			{PlayerID: "player-1234567890", Ping: 42 * time.Millisecond},
+			{PlayerID: "p2", Ping: 150 * time.Millisecond},
+			{PlayerID: "p3", Ping: 350 * time.Millisecond},
+			{PlayerID: "p4"},
+		},
+	})
+	assert.Equal(t, []networkDebugLine{
+		{"server connected", 11},
+		{"in 30/s out 60/s", 6},
+		{"player-12345 42ms", 11},
+		{"p2 150ms", 10},
+		{"p3 350ms", 8},
+		{"p4 ?", 6},
+	}, lines)
+
+	lines = networkDebugLines(network.Stats{State: network.StateError, Error: "failed to resolve UDP address"})
+	assert.Equal(t, networkDebugLine{"client error", 8}, lines[0])
+	assert.Equal(t, networkDebugLine{"failed to resolve UDP ad", 8}, lines[2])
+}
+
+func TestDrawNetworkDebugKeepsDrawState(t *testing.T) {
+	setupRowTest(t)
+	t.Cleanup(func() {
+		Camera()
+		Cursor()
+		Color(7)
+	})
+	Camera(10, 20)
+	Cursor(5, 6)
+	Color(3)
+
+	DrawNetworkDebug()
+	assert.Equal(t, 10.0, cameraX)
+	assert.Equal(t, 20.0, cameraY)
+	assert.Equal(t, 5, cursorX)
+	assert.Equal(t, 6, cursorY)
+	assert.Equal(t, 3, currentDrawColor)
+}
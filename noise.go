@@ -0,0 +1,81 @@
+package pigo8
+
+import "math"
+
+// --- Noise ---
+
+// noiseSeed picks the noise pattern of Noise1D and Noise2D. Srand sets it;
+// until then it comes from the clock, like the generator of Rnd.
+var noiseSeed = uint64(rng.Int63())
+
+// Noise1D returns smooth pseudo-random noise in [0, 1] at x: nearby x give
+// nearby values, so it suits organic variation such as wind strength, a
+// wobbling flame or the height of a 1D terrain. Noise varies about once per
+// unit of x; scale x to change how fast (Noise1D(x*0.1) is ten times
+// smoother).
+//
+// It is value noise: every integer x gets a random value from a hash of x
+// and the seed, and values in between are blended with a smooth fade curve
+// (6t^5 - 15t^4 + 10t^3), so the noise and its slope are continuous. The
+// output only depends on x and the last Srand seed, never on other random
+// calls, so worlds generated from a seed are the same on every run and
+// machine, in any order of generation.
+//
+// Example:
+//
+//	Srand(levelSeed)
+//	for x := 0; x < 128; x++ {
+//		ground := 64 + int(Noise1D(float64(x)*0.05)*40)
+//		Line(x, ground, x, 127, 3)
+//	}
+func Noise1D(x float64) float64 {
+	x0 := math.Floor(x)
+	t := noiseFade(x - x0)
+	ix := int64(x0)
+	return Lerp(noiseLattice(ix, 0), noiseLattice(ix+1, 0), t)
+}
+
+// Noise2D returns smooth pseudo-random noise in [0, 1] at (x, y), the 2D
+// version of Noise1D for terrain, clouds and caves. Random values at integer
+// points are blended across each unit square, first along x and then y.
+//
+// For more natural detail, add octaves: the same noise at double the
+// frequency and half the strength, a few times.
+//
+// Example:
+//
+//	// Terrain height of tile (tx, ty), with 3 octaves
+//	func height(tx, ty int) float64 {
+//		h, amp, freq, total := 0.0, 1.0, 0.05, 0.0
+//		for range 3 {
+//			h += amp * Noise2D(float64(tx)*freq, float64(ty)*freq)
+//			total += amp
+//			amp, freq = amp/2, freq*2
+//		}
+//		return h / total // Still in [0, 1]
+//	}
+func Noise2D(x, y float64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	tx, ty := noiseFade(x-x0), noiseFade(y-y0)
+	ix, iy := int64(x0), int64(y0)
+	top := Lerp(noiseLattice(ix, iy), noiseLattice(ix+1, iy), tx)
+	bottom := Lerp(noiseLattice(ix, iy+1), noiseLattice(ix+1, iy+1), tx)
+	return Lerp(top, bottom, ty)
+}
+
+// noiseFade eases t in [0, 1] so the noise has no corners at integer points.
+func noiseFade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// noiseLattice returns the random value in [0, 1] of the integer point
+// (x, y), a hash of the point and noiseSeed (the splitmix64 finalizer).
+func noiseLattice(x, y int64) float64 {
+	h := noiseSeed ^ uint64(x)*0x9e3779b97f4a7c15 ^ uint64(y)*0xc2b2ae3d27d4eb4f
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return float64(h>>11) / (1 << 53)
+}
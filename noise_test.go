@@ -0,0 +1,51 @@
+package pigo8
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoiseIsDeterministic(t *testing.T) {
+	Srand(7)
+	a1, a2 := Noise1D(3.7), Noise2D(12.25, -4.5)
+	Rnd(100) // Other random calls don't change the noise
+	assert.Equal(t, a1, Noise1D(3.7))
+
+	Srand(8)
+	assert.NotEqual(t, a2, Noise2D(12.25, -4.5), "another seed, another pattern")
+
+	Srand(7)
+	assert.Equal(t, a1, Noise1D(3.7))
+	assert.Equal(t, a2, Noise2D(12.25, -4.5))
+}
+
+func TestNoiseRange(t *testing.T) {
+	Srand(1)
+	low, high := 1.0, 0.0
+	for i := -500; i < 500; i++ {
+		v1 := Noise1D(float64(i) * 0.37)
+		v2 := Noise2D(float64(i)*0.37, float64(i)*-0.61)
+		for _, v := range []float64{v1, v2} {
+			assert.True(t, v >= 0 && v <= 1, "%v out of [0, 1]", v)
+			low, high = math.Min(low, v), math.Max(high, v)
+		}
+	}
+	assert.Less(t, low, 0.2, "the noise covers the range")
+	assert.Greater(t, high, 0.8)
+}
+
+func TestNoiseIsContinuous(t *testing.T) {
+	Srand(3)
+	const step = 0.001
+	for x := -3.0; x < 3; x += step {
+		assert.InDelta(t, Noise1D(x), Noise1D(x+step), 0.01, "jump at x=%v", x)
+		assert.InDelta(t, Noise2D(x, 1.5-x), Noise2D(x+step, 1.5-x), 0.01, "jump at x=%v", x)
+		assert.InDelta(t, Noise2D(1.5-x, x), Noise2D(1.5-x, x+step), 0.01, "jump at y=%v", x)
+	}
+
+	// At integer points the noise is the lattice value
+	assert.Equal(t, noiseLattice(2, 0), Noise1D(2))
+	assert.Equal(t, noiseLattice(-1, 4), Noise2D(-1, 4))
+}
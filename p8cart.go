@@ -0,0 +1,463 @@
+package pigo8
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+)
+
+// --- PICO-8 .p8 cartridge import ---
+
+// Dimensions of the memory regions stored in a .p8 text cartridge.
+const (
+	p8GfxWidth     = 128 // pixels per __gfx__ line
+	p8GfxHeight    = 128 // __gfx__ lines
+	p8MapWidth     = 128 // tiles per __map__ line
+	p8MapHeight    = 64  // total map rows, including the half shared with gfx
+	p8MapOwnRows   = 32  // rows stored in the __map__ section itself
+	p8SpriteCount  = 256 // sprites described by __gfx__ and __gff__
+	p8SpriteSize   = 8   // sprites are always 8x8 pixels in PICO-8
+	p8SpriteCols   = p8GfxWidth / p8SpriteSize
+	p8SharedGfxRow = p8GfxHeight / 2 // first gfx row that doubles as map memory
+)
+
+// p8Cart holds the memory sections decoded from a .p8 text cartridge.
+type p8Cart struct {
+	Gfx   [p8GfxHeight][p8GfxWidth]uint8 // one colour index (0-15) per pixel
+	Map   [p8MapHeight][p8MapWidth]uint8 // tile ids, row-major
+	Flags [p8SpriteCount]uint8           // sprite flag bitfields
+
+	// Unsupported lists sections that were present in the cart but not imported.
+	Unsupported []string
+}
+
+// p8UnsupportedSections are recognised but ignored by the importer.
+// Lua code has to be ported by hand, and audio needs a PICO-8 synth which
+// pigo8 does not have (music is played from WAV files instead).
+var p8UnsupportedSections = map[string]bool{
+	"__lua__":   true,
+	"__label__": true,
+	"__sfx__":   true,
+	"__music__": true,
+}
+
+// LoadP8Cart imports the sprite sheet, sprite flags and map from a PICO-8
+// text cartridge (.p8) and makes them the active pigo8 resources.
+//
+// The __gfx__, __gff__ and __map__ sections are imported. Like PICO-8, the
+// lower half of the map (rows 32-63) is read from the lower half of the
+// sprite sheet when the cart does not store those rows itself.
+// The __lua__, __label__, __sfx__ and __music__ sections are skipped with a
+// log message; the .p8.png format is not supported.
+//
+// Args:
+//   - path: path to the .p8 file
+//
+// Example:
+//
+//	if err := pigo8.LoadP8Cart("celeste.p8"); err != nil {
+//		log.Fatal(err)
+//	}
+func LoadP8Cart(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading cartridge %s: %w", path, err)
+	}
+
+	cart, err := parseP8Cart(data)
+	if err != nil {
+		return fmt.Errorf("error parsing cartridge %s: %w", path, err)
+	}
+
+	for _, section := range cart.Unsupported {
+		log.Printf("LoadP8Cart: section %s in %s is not supported and was skipped", section, path)
+	}
+
+	applyP8Cart(cart)
+	log.Printf("Successfully imported cartridge %s", path)
+	return nil
+}
+
+// parseP8Cart decodes the data sections of a .p8 text cartridge.
+func parseP8Cart(data []byte) (*p8Cart, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	// __lua__ lines can be long; allow up to 1MB per line.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), "pico-8 cartridge") {
+		return nil, fmt.Errorf("missing 'pico-8 cartridge' header")
+	}
+
+	cart := &p8Cart{}
+	section := ""
+	row := 0
+	mapRows := 0
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if strings.HasPrefix(line, "__") && strings.HasSuffix(line, "__") && len(line) > 4 {
+			section = line
+			row = 0
+			if p8UnsupportedSections[section] {
+				cart.Unsupported = append(cart.Unsupported, section)
+			}
+			continue
+		}
+
+		var err error
+		switch section {
+		case "__gfx__":
+			err = parseP8GfxLine(cart, row, line)
+		case "__gff__":
+			err = parseP8FlagsLine(cart, row, line)
+		case "__map__":
+			err = parseP8MapLine(cart, row, line)
+			if err == nil && line != "" {
+				mapRows = row + 1
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", section, row, err)
+		}
+		row++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning cartridge: %w", err)
+	}
+
+	if mapRows <= p8MapOwnRows {
+		copySharedGfxToMap(cart)
+	}
+
+	return cart, nil
+}
+
+// parseP8GfxLine decodes one __gfx__ line, where each hex digit is one pixel.
+func parseP8GfxLine(cart *p8Cart, row int, line string) error {
+	if line == "" {
+		return nil
+	}
+	if row >= p8GfxHeight {
+		return fmt.Errorf("too many rows (max %d)", p8GfxHeight)
+	}
+	if len(line) > p8GfxWidth {
+		return fmt.Errorf("line has %d pixels, expected at most %d", len(line), p8GfxWidth)
+	}
+	for x := 0; x < len(line); x++ {
+		v, ok := hexNibble(line[x])
+		if !ok {
+			return fmt.Errorf("invalid hex digit %q at column %d", line[x], x)
+		}
+		cart.Gfx[row][x] = v
+	}
+	return nil
+}
+
+// parseP8FlagsLine decodes one __gff__ line, where each byte is one sprite's flags.
+func parseP8FlagsLine(cart *p8Cart, row int, line string) error {
+	const spritesPerLine = p8SpriteCount / 2
+	if line == "" {
+		return nil
+	}
+	values, err := decodeHexBytes(line)
+	if err != nil {
+		return err
+	}
+	if len(values) > spritesPerLine {
+		return fmt.Errorf("line has %d flag bytes, expected at most %d", len(values), spritesPerLine)
+	}
+	start := row * spritesPerLine
+	if start+len(values) > p8SpriteCount {
+		return fmt.Errorf("too many rows (max %d)", p8SpriteCount/spritesPerLine)
+	}
+	copy(cart.Flags[start:], values)
+	return nil
+}
+
+// parseP8MapLine decodes one __map__ line, where each byte is one tile.
+func parseP8MapLine(cart *p8Cart, row int, line string) error {
+	if line == "" {
+		return nil
+	}
+	if row >= p8MapHeight {
+		return fmt.Errorf("too many rows (max %d)", p8MapHeight)
+	}
+	values, err := decodeHexBytes(line)
+	if err != nil {
+		return err
+	}
+	if len(values) > p8MapWidth {
+		return fmt.Errorf("line has %d tiles, expected at most %d", len(values), p8MapWidth)
+	}
+	copy(cart.Map[row][:], values)
+	return nil
+}
+
+// copySharedGfxToMap fills map rows 32-63 from the lower half of the sprite
+// sheet. PICO-8 stores two pixels per byte there, low nibble first.
+func copySharedGfxToMap(cart *p8Cart) {
+	const bytesPerGfxRow = p8GfxWidth / 2
+	for row := p8MapOwnRows; row < p8MapHeight; row++ {
+		for col := 0; col < p8MapWidth; col++ {
+			offset := (row-p8MapOwnRows)*p8MapWidth + col
+			gy := p8SharedGfxRow + offset/bytesPerGfxRow
+			gx := (offset % bytesPerGfxRow) * 2
+			cart.Map[row][col] = cart.Gfx[gy][gx] | cart.Gfx[gy][gx+1]<<4
+		}
+	}
+}
+
+// applyP8Cart replaces the active sprites, flags and map with the cart's contents.
+func applyP8Cart(cart *p8Cart) {
+	sheet := spriteSheet{
+		SpriteSheetColumns: p8SpriteCols,
+		SpriteSheetRows:    p8GfxHeight / p8SpriteSize,
+		SpriteSheetWidth:   p8GfxWidth,
+		SpriteSheetHeight:  p8GfxHeight,
+		Sprites:            p8CartSprites(cart),
+	}
+
+	ClearSpriteCache()
+	clearSpritePixelCache()
+	currentSprites = buildSpritesFromSheet(&sheet, true)
+
+	mapData := make([]byte, defaultPico8MapWidth*defaultPico8MapHeight)
+	for row := 0; row < p8MapHeight; row++ {
+		copy(mapData[row*defaultPico8MapWidth:], cart.Map[row][:])
+	}
+	SetMap(mapData)
+}
+
+// p8CartSprites slices the cart's sprite sheet into 8x8 sprite records.
+// Every sprite is marked as used so that Fset/Fget and Sset work on all 256 ids.
+func p8CartSprites(cart *p8Cart) []spriteData {
+	sprites := make([]spriteData, p8SpriteCount)
+	for id := range sprites {
+		sx := (id % p8SpriteCols) * p8SpriteSize
+		sy := (id / p8SpriteCols) * p8SpriteSize
+
+		pixels := make([][]int, p8SpriteSize)
+		for y := range pixels {
+			pixels[y] = make([]int, p8SpriteSize)
+			for x := range pixels[y] {
+				pixels[y][x] = int(cart.Gfx[sy+y][sx+x])
+			}
+		}
+
+		flags := int(cart.Flags[id])
+		individual := make([]bool, 8)
+		for i := range individual {
+			individual[i] = flags&(1<<i) != 0
+		}
+
+		sprites[id] = spriteData{
+			ID:     id,
+			X:      sx,
+			Y:      sy,
+			Width:  p8SpriteSize,
+			Height: p8SpriteSize,
+			Pixels: pixels,
+			Flags:  FlagsData{Bitfield: flags, Individual: individual},
+			Used:   true,
+		}
+	}
+	return sprites
+}
+
+// decodeHexBytes decodes a string of two-digit hex bytes (high nibble first).
+func decodeHexBytes(line string) ([]uint8, error) {
+	if len(line)%2 != 0 {
+		return nil, fmt.Errorf("odd number of hex digits (%d)", len(line))
+	}
+	values := make([]uint8, len(line)/2)
+	for i := range values {
+		hi, ok1 := hexNibble(line[i*2])
+		lo, ok2 := hexNibble(line[i*2+1])
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("invalid hex byte %q at column %d", line[i*2:i*2+2], i*2)
+		}
+		values[i] = hi<<4 | lo
+	}
+	return values, nil
+}
+
+// hexNibble converts a single hex digit to its value.
+func hexNibble(c byte) (uint8, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// --- PICO-8 .p8 cartridge export ---
+
+// p8CartVersion is the cartridge format version written by SaveP8Cart.
+const p8CartVersion = 41
+
+// SaveP8Cart writes the active sprite sheet, sprite flags and map to a
+// PICO-8 text cartridge (.p8), so assets made with pigo8 can be loaded back
+// into PICO-8. It is the inverse of LoadP8Cart.
+//
+// Only the top-left 128x128 pixels of the sprite sheet and the top-left
+// 128x64 tiles of the map are exported, since that is all PICO-8 can hold.
+// Map rows 32-63 share memory with the lower half of the sprite sheet; they
+// are written there only when that half of the sheet is empty, otherwise they
+// are dropped with a log message. Colour indices above 15 are written as 0.
+//
+// Args:
+//   - path: path of the .p8 file to create (overwritten if it exists)
+//
+// Example:
+//
+//	if err := pigo8.SaveP8Cart("mygame.p8"); err != nil {
+//		log.Printf("export failed: %v", err)
+//	}
+func SaveP8Cart(path string) error {
+	cart := p8CartFromMemory()
+	if !mergeLowerMapIntoGfx(cart) {
+		log.Printf("SaveP8Cart: map rows %d-%d overlap sprites 128-255 and were not exported", p8MapOwnRows, p8MapHeight-1)
+	}
+
+	if err := os.WriteFile(path, encodeP8Cart(cart), 0o644); err != nil {
+		return fmt.Errorf("error writing cartridge %s: %w", path, err)
+	}
+	log.Printf("Successfully exported cartridge %s", path)
+	return nil
+}
+
+// p8CartFromMemory copies the active sprite, flag and map data into a p8Cart.
+func p8CartFromMemory() *p8Cart {
+	cart := &p8Cart{}
+
+	outOfRange := 0
+	for y := 0; y < p8GfxHeight; y++ {
+		for x := 0; x < p8GfxWidth; x++ {
+			c := Sget(x, y)
+			if c < 0 || c > 15 {
+				outOfRange++
+				c = 0
+			}
+			cart.Gfx[y][x] = uint8(c)
+		}
+	}
+	if outOfRange > 0 {
+		log.Printf("SaveP8Cart: %d pixels use colours outside the PICO-8 palette and were written as 0", outOfRange)
+	}
+
+	for _, sprite := range currentSprites {
+		if sprite.ID >= 0 && sprite.ID < p8SpriteCount {
+			cart.Flags[sprite.ID] = uint8(sprite.Flags.Bitfield)
+		}
+	}
+
+	outOfRange = 0
+	for row := 0; row < p8MapHeight; row++ {
+		for col := 0; col < p8MapWidth; col++ {
+			tile := Mget(col, row)
+			if tile < 0 || tile > 255 {
+				outOfRange++
+				tile = 0
+			}
+			cart.Map[row][col] = uint8(tile)
+		}
+	}
+	if outOfRange > 0 {
+		log.Printf("SaveP8Cart: %d map tiles use sprites outside 0-255 and were written as 0", outOfRange)
+	}
+
+	return cart
+}
+
+// mergeLowerMapIntoGfx stores map rows 32-63 in the shared lower half of the
+// sprite sheet. It returns false if the rows could not be stored because both
+// the map rows and the sprites there are in use, unless those sprites already
+// hold the rows, as in a cart that was loaded and saved again.
+func mergeLowerMapIntoGfx(cart *p8Cart) bool {
+	mapUsed, gfxUsed := false, false
+	for row := p8MapOwnRows; row < p8MapHeight; row++ {
+		for _, tile := range cart.Map[row] {
+			mapUsed = mapUsed || tile != 0
+		}
+	}
+	for y := p8SharedGfxRow; y < p8GfxHeight; y++ {
+		for _, c := range cart.Gfx[y] {
+			gfxUsed = gfxUsed || c != 0
+		}
+	}
+	if !mapUsed {
+		return true
+	}
+	if gfxUsed {
+		shared := &p8Cart{Gfx: cart.Gfx}
+		copySharedGfxToMap(shared)
+		return slices.Equal(shared.Map[p8MapOwnRows:], cart.Map[p8MapOwnRows:])
+	}
+
+	const bytesPerGfxRow = p8GfxWidth / 2
+	for row := p8MapOwnRows; row < p8MapHeight; row++ {
+		for col, tile := range cart.Map[row] {
+			offset := (row-p8MapOwnRows)*p8MapWidth + col
+			gy := p8SharedGfxRow + offset/bytesPerGfxRow
+			gx := (offset % bytesPerGfxRow) * 2
+			cart.Gfx[gy][gx] = tile & 0x0f
+			cart.Gfx[gy][gx+1] = tile >> 4
+		}
+	}
+	return true
+}
+
+// encodeP8Cart renders a p8Cart as .p8 text with __gfx__, __gff__ and __map__ sections.
+func encodeP8Cart(cart *p8Cart) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "pico-8 cartridge // http://www.pico-8.com\nversion %d\n", p8CartVersion)
+	buf.WriteString("__lua__\n")
+
+	buf.WriteString("__gfx__\n")
+	for y := 0; y < p8GfxHeight; y++ {
+		for _, c := range cart.Gfx[y] {
+			buf.WriteByte(hexDigits[c&0x0f])
+		}
+		buf.WriteByte('\n')
+	}
+
+	buf.WriteString("__gff__\n")
+	const spritesPerLine = p8SpriteCount / 2
+	for start := 0; start < p8SpriteCount; start += spritesPerLine {
+		buf.WriteString(encodeHexBytes(cart.Flags[start : start+spritesPerLine]))
+		buf.WriteByte('\n')
+	}
+
+	buf.WriteString("__map__\n")
+	for row := 0; row < p8MapOwnRows; row++ {
+		buf.WriteString(encodeHexBytes(cart.Map[row][:]))
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes()
+}
+
+// hexDigits are the lower-case digits PICO-8 uses in .p8 files.
+const hexDigits = "0123456789abcdef"
+
+// encodeHexBytes encodes bytes as two-digit hex (high nibble first).
+func encodeHexBytes(values []uint8) string {
+	var sb strings.Builder
+	sb.Grow(len(values) * 2)
+	for _, v := range values {
+		sb.WriteByte(hexDigits[v>>4])
+		sb.WriteByte(hexDigits[v&0x0f])
+	}
+	return sb.String()
+}
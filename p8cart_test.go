@@ -0,0 +1,143 @@
+package pigo8
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseP8Cart(t *testing.T) {
+	cart := strings.Join([]string{
+		"pico-8 cartridge // http://www.pico-8.com",
+		"version 41",
+		"__lua__",
+		"print('hi')",
+		"__gfx__",
+		"0123456789abcdef",
+		"77",
+		"__gff__",
+		"0001ff",
+		"__map__",
+		"0102",
+		"",
+		"ff",
+		"__sfx__",
+		"000100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+		"",
+	}, "\n")
+
+	parsed, err := parseP8Cart([]byte(cart))
+	require.NoError(t, err)
+
+	for x := 0; x < 16; x++ {
+		assert.Equal(t, uint8(x), parsed.Gfx[0][x], "gfx pixel %d", x)
+	}
+	assert.Equal(t, uint8(7), parsed.Gfx[1][1])
+	assert.Equal(t, uint8(0), parsed.Gfx[1][2])
+
+	assert.Equal(t, uint8(0), parsed.Flags[0])
+	assert.Equal(t, uint8(1), parsed.Flags[1])
+	assert.Equal(t, uint8(255), parsed.Flags[2])
+
+	assert.Equal(t, uint8(1), parsed.Map[0][0])
+	assert.Equal(t, uint8(2), parsed.Map[0][1])
+	assert.Equal(t, uint8(255), parsed.Map[2][0], "blank map lines still advance the row")
+
+	assert.Equal(t, []string{"__lua__", "__sfx__"}, parsed.Unsupported)
+}
+
+func TestParseP8Cart_SharedMapMemory(t *testing.T) {
+	lines := []string{"pico-8 cartridge // http://www.pico-8.com", "version 41", "__gfx__"}
+	for y := 0; y < p8GfxHeight; y++ {
+		lines = append(lines, strings.Repeat("0", p8GfxWidth))
+	}
+	// Row 64 of the sprite sheet holds map row 32; pixels "21" encode tile 0x12.
+	lines[3+p8SharedGfxRow] = "21" + strings.Repeat("0", p8GfxWidth-2)
+
+	parsed, err := parseP8Cart([]byte(strings.Join(lines, "\n")))
+	require.NoError(t, err)
+	assert.Equal(t, uint8(0x12), parsed.Map[p8MapOwnRows][0])
+	assert.Equal(t, uint8(0), parsed.Map[p8MapOwnRows][1])
+}
+
+func TestParseP8Cart_Errors(t *testing.T) {
+	_, err := parseP8Cart([]byte("not a cart"))
+	assert.Error(t, err)
+
+	_, err = parseP8Cart([]byte("pico-8 cartridge\n__gfx__\n0g\n"))
+	assert.Error(t, err, "invalid hex digit")
+
+	_, err = parseP8Cart([]byte("pico-8 cartridge\n__map__\n012\n"))
+	assert.Error(t, err, "odd number of hex digits")
+}
+
+func TestP8CartSprites(t *testing.T) {
+	cart := &p8Cart{}
+	cart.Gfx[8][16] = 9 // top-left pixel of sprite 18
+	cart.Flags[18] = 0x05
+
+	sprites := p8CartSprites(cart)
+	require.Len(t, sprites, p8SpriteCount)
+
+	s := sprites[18]
+	assert.Equal(t, 18, s.ID)
+	assert.Equal(t, 16, s.X)
+	assert.Equal(t, 8, s.Y)
+	assert.True(t, s.Used)
+	assert.Equal(t, 9, s.Pixels[0][0])
+	assert.Equal(t, 5, s.Flags.Bitfield)
+	assert.Equal(t, []bool{true, false, true, false, false, false, false, false}, s.Flags.Individual)
+}
+
+func TestEncodeP8Cart_RoundTrip(t *testing.T) {
+	cart := &p8Cart{}
+	cart.Gfx[0][0] = 8
+	cart.Gfx[127][127] = 15
+	cart.Flags[0] = 0x81
+	cart.Flags[255] = 0x02
+	cart.Map[0][0] = 0x10
+	cart.Map[31][127] = 0xff
+
+	parsed, err := parseP8Cart(encodeP8Cart(cart))
+	require.NoError(t, err)
+	assert.Equal(t, cart.Gfx, parsed.Gfx)
+	assert.Equal(t, cart.Flags, parsed.Flags)
+	assert.Equal(t, cart.Map[:p8MapOwnRows], parsed.Map[:p8MapOwnRows])
+	assert.Equal(t, []string{"__lua__"}, parsed.Unsupported)
+}
+
+func TestMergeLowerMapIntoGfx(t *testing.T) {
+	cart := &p8Cart{}
+	cart.Map[p8MapOwnRows][0] = 0x12
+	cart.Map[p8MapHeight-1][p8MapWidth-1] = 0x34
+	require.True(t, mergeLowerMapIntoGfx(cart))
+
+	parsed, err := parseP8Cart(encodeP8Cart(cart))
+	require.NoError(t, err)
+	assert.Equal(t, uint8(0x12), parsed.Map[p8MapOwnRows][0])
+	assert.Equal(t, uint8(0x34), parsed.Map[p8MapHeight-1][p8MapWidth-1])
+
+	// Lower sprites in use: the lower map half cannot be stored.
+	clash := &p8Cart{}
+	clash.Map[p8MapOwnRows][0] = 1
+	clash.Gfx[p8GfxHeight-1][0] = 7
+	assert.False(t, mergeLowerMapIntoGfx(clash))
+	assert.Equal(t, uint8(7), clash.Gfx[p8GfxHeight-1][0])
+
+	// A loaded cart already holds its lower map half in the shared sprites.
+	reloaded := &p8Cart{Gfx: parsed.Gfx, Map: parsed.Map}
+	assert.True(t, mergeLowerMapIntoGfx(reloaded), "the shared sprites already hold the map rows")
+	assert.Equal(t, parsed.Gfx, reloaded.Gfx)
+}
+
+func TestP8CartFromMemoryTiles(t *testing.T) {
+	useTestConsoleState(t)
+	Mset(0, 0, 42)
+	Mset(1, 0, 300)
+
+	cart := p8CartFromMemory()
+	assert.Equal(t, uint8(42), cart.Map[0][0])
+	assert.Equal(t, uint8(0), cart.Map[0][1], "sprites above 255 don't fit in a cart and are written as 0")
+}
@@ -2,8 +2,10 @@ package pigo8
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"image/color"
+	"image/png"
 	"io/fs"
 	"log"
 	"os"
@@ -13,48 +15,33 @@ import (
 )
 
 // loadPaletteFromHexFile attempts to load a palette from a palette.hex file
-// in the current directory, common locations, or embedded resources.
+// in the current directory, common locations, or embedded resources, as
+// chosen with SetAssetSource.
 // Returns true if a palette was successfully loaded, false otherwise.
 func loadPaletteFromHexFile() bool {
 	const paletteFilename = "palette.hex"
 
-	// First try to load from the current directory
-	data, err := os.ReadFile(paletteFilename)
+	// Load from disk or embedded resources, depending on the asset source
+	data, err := readAssetFile(paletteFilename, tryLoadEmbeddedPalette)
 	if err != nil {
-		// Check common alternative locations
-		commonLocations := []string{
-			filepath.Join("assets", paletteFilename),
-			filepath.Join("resources", paletteFilename),
-			filepath.Join("data", paletteFilename),
-			filepath.Join("static", paletteFilename),
-		}
-
-		for _, location := range commonLocations {
-			data, err = os.ReadFile(location)
-			if err == nil {
-				log.Printf("Loaded palette from %s", location)
-				break
-			}
-		}
+		// No palette.hex; keep the current palette
+		return false
+	}
 
-		// If still not found, try embedded resources
-		if err != nil {
-			embeddedData, embErr := tryLoadEmbeddedPalette()
-			if embErr != nil {
-				// No embedded palette found
-				return false
-			}
-			data = embeddedData
-		}
-	} else {
-		log.Printf("Using palette file from current directory: %s", paletteFilename)
+	if err := applyHexPalette(data); err != nil {
+		log.Printf("Error parsing palette file: %v", err)
+		return false
 	}
+	return true
+}
 
+// applyHexPalette parses palette.hex data and makes it the active palette,
+// with transparent black at index 0 and white at index 1 before the file's colors.
+func applyHexPalette(data []byte) error {
 	// Process the palette data
 	colors, err := parseHexPalette(data)
 	if err != nil {
-		log.Printf("Error parsing palette file: %v", err)
-		return false
+		return err
 	}
 
 	// Create a new palette with a transparent color at index 0 and white at index 1
@@ -78,7 +65,7 @@ func loadPaletteFromHexFile() bool {
 	// Log when palette is loaded
 	log.Printf("Custom palette loaded: %d colors (index 0 transparent, index 1 white)", len(newPalette))
 
-	return true
+	return nil
 }
 
 // tryLoadEmbeddedPalette attempts to load a palette from embedded resources
@@ -165,3 +152,179 @@ func hexToColor(hex string) (color.Color, error) {
 		A: 255, // Full opacity
 	}, nil
 }
+
+// maxPaletteColors is the largest palette LoadPalette accepts.
+const maxPaletteColors = 256
+
+// LoadPalette loads a palette file and makes it the active palette with
+// SetPalette. The first color in the file becomes index 0, the second index 1,
+// and so on; the palette has exactly as many colors as the file (1 to 256).
+// Color 0 stays transparent by default, see SetTransparentColor.
+//
+// Supported formats, detected by file extension or, failing that, by content:
+//   - .hex: one RRGGBB hex color per line, as exported by Lospec (lines
+//     starting with "#" are comments)
+//   - .pal: JASC-PAL (Paint Shop Pro, Aseprite), "R G B" per line after the header
+//   - .gpl: GIMP palette (GIMP, Inkscape, Aseprite), "R G B name" per line
+//   - .png: an image whose distinct opaque colors, read left to right and top
+//     to bottom, form the palette (e.g. a strip of color swatches)
+//
+// Unlike palette.hex loaded at startup, no transparent or white colors are
+// added in front of the file's colors.
+//
+// Example:
+//
+//	if err := LoadPalette("assets/endesga-32.gpl"); err != nil {
+//		log.Fatal(err)
+//	}
+func LoadPalette(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading palette file %s: %w", path, err)
+	}
+	colors, err := parsePaletteData(data, filepath.Ext(path))
+	if err != nil {
+		return fmt.Errorf("error parsing palette file %s: %w", path, err)
+	}
+	SetPalette(colors)
+	log.Printf("Loaded palette from %s: %d colors", path, len(colors))
+	return nil
+}
+
+// parsePaletteData parses palette data in the format given by the file
+// extension ext, or detected from the content if the extension is unknown.
+func parsePaletteData(data []byte, ext string) ([]color.Color, error) {
+	var colors []color.Color
+	var err error
+	switch {
+	case strings.EqualFold(ext, ".png") || bytes.HasPrefix(data, []byte("\x89PNG")):
+		colors, err = parsePNGPalette(data)
+	case strings.EqualFold(ext, ".pal") || bytes.HasPrefix(data, []byte("JASC-PAL")):
+		colors, err = parseJASCPalette(data)
+	case strings.EqualFold(ext, ".gpl") || bytes.HasPrefix(data, []byte("GIMP Palette")):
+		colors, err = parseGIMPPalette(data)
+	default:
+		colors, err = parseHexPalette(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(colors) == 0 {
+		return nil, fmt.Errorf("palette has no colors")
+	}
+	if len(colors) > maxPaletteColors {
+		return nil, fmt.Errorf("palette has %d colors, the maximum is %d", len(colors), maxPaletteColors)
+	}
+	return colors, nil
+}
+
+// parseJASCPalette parses a JASC-PAL file: the header "JASC-PAL", a version
+// line, the color count and then one "R G B" line per color.
+func parseJASCPalette(data []byte) ([]color.Color, error) {
+	lines := paletteLines(data)
+	if len(lines) < 3 || lines[0] != "JASC-PAL" {
+		return nil, fmt.Errorf("missing JASC-PAL header")
+	}
+	count, err := strconv.Atoi(lines[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid color count '%s': %v", lines[2], err)
+	}
+	if count != len(lines)-3 {
+		return nil, fmt.Errorf("header says %d colors but the file has %d", count, len(lines)-3)
+	}
+
+	colors := make([]color.Color, 0, count)
+	for _, line := range lines[3:] {
+		c, err := parseRGBFields(strings.Fields(line))
+		if err != nil {
+			return nil, err
+		}
+		colors = append(colors, c)
+	}
+	return colors, nil
+}
+
+// parseGIMPPalette parses a GIMP .gpl file: the header "GIMP Palette",
+// optional "Name:"/"Columns:" lines and "#" comments, then one
+// "R G B [name]" line per color.
+func parseGIMPPalette(data []byte) ([]color.Color, error) {
+	lines := paletteLines(data)
+	if len(lines) == 0 || lines[0] != "GIMP Palette" {
+		return nil, fmt.Errorf("missing GIMP Palette header")
+	}
+
+	var colors []color.Color
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "Name:") || strings.HasPrefix(line, "Columns:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("invalid color line '%s'", line)
+		}
+		c, err := parseRGBFields(fields[:3]) // The rest of the line is the color name
+		if err != nil {
+			return nil, err
+		}
+		colors = append(colors, c)
+	}
+	return colors, nil
+}
+
+// parsePNGPalette returns the distinct opaque colors of a PNG image in
+// reading order. Transparent pixels are skipped.
+func parsePNGPalette(data []byte) ([]color.Color, error) {
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PNG: %v", err)
+	}
+
+	var colors []color.Color
+	seen := make(map[color.RGBA]bool)
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			if c.A == 0 || seen[c] {
+				continue
+			}
+			if c.A != 255 {
+				return nil, fmt.Errorf("semi-transparent pixel at (%d, %d)", x, y)
+			}
+			seen[c] = true
+			colors = append(colors, c)
+			if len(colors) > maxPaletteColors {
+				return colors, nil // Too many; reported by the caller
+			}
+		}
+	}
+	return colors, nil
+}
+
+// paletteLines returns the trimmed, non-empty lines of a text palette file.
+func paletteLines(data []byte) []string {
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// parseRGBFields parses three decimal color components (0-255).
+func parseRGBFields(fields []string) (color.Color, error) {
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("expected 3 color components, got %d in '%s'", len(fields), strings.Join(fields, " "))
+	}
+	var rgb [3]uint8
+	for i, field := range fields {
+		v, err := strconv.ParseUint(field, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color component '%s': must be 0-255", field)
+		}
+		rgb[i] = uint8(v)
+	}
+	return color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 255}, nil
+}
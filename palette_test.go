@@ -1,7 +1,13 @@
 package pigo8
 
 import (
+	"image/color"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestPaletteAPI tests the public palette API functions
@@ -15,3 +21,69 @@ func TestPaletteAPI(t *testing.T) {
 	// - Palt(args ...interface{})
 	// These would need to be tested in a more comprehensive way
 }
+
+// samplePaletteColors are the colors stored in every testdata/palettes/sample.* file.
+var samplePaletteColors = []color.Color{
+	color.RGBA{0, 0, 0, 255},
+	color.RGBA{29, 43, 83, 255},
+	color.RGBA{126, 37, 83, 255},
+	color.RGBA{0, 135, 81, 255},
+}
+
+func TestParsePaletteFormats(t *testing.T) {
+	for _, name := range []string{"sample.hex", "sample.pal", "sample.gpl", "sample.png"} {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "palettes", name))
+			require.NoError(t, err)
+
+			colors, err := parsePaletteData(data, filepath.Ext(name))
+			require.NoError(t, err)
+			assert.Equal(t, samplePaletteColors, colors)
+
+			// The format is also recognized without the extension
+			colors, err = parsePaletteData(data, "")
+			require.NoError(t, err)
+			assert.Equal(t, samplePaletteColors, colors)
+		})
+	}
+}
+
+func TestParsePaletteMalformed(t *testing.T) {
+	tests := map[string]struct {
+		data string
+		ext  string
+	}{
+		"bad hex":               {"00000\n", ".hex"},
+		"empty hex":             {"# only a comment\n", ".hex"},
+		"JASC count mismatch":   {"JASC-PAL\n0100\n3\n0 0 0\n", ".pal"},
+		"JASC bad component":    {"JASC-PAL\n0100\n1\n0 0 256\n", ".pal"},
+		"JASC missing header":   {"0 0 0\n", ".pal"},
+		"GIMP short line":       {"GIMP Palette\n0 0\n", ".gpl"},
+		"GIMP negative":         {"GIMP Palette\n-1 0 0 black\n", ".gpl"},
+		"GIMP missing header":   {"0 0 0 black\n", ".gpl"},
+		"PNG that is not a PNG": {"not an image", ".png"},
+	}
+	for name, tt := range tests {
+		_, err := parsePaletteData([]byte(tt.data), tt.ext)
+		assert.Error(t, err, name)
+	}
+
+	var tooMany string
+	for i := 0; i <= maxPaletteColors; i++ {
+		tooMany += "000000\n"
+	}
+	_, err := parsePaletteData([]byte(tooMany), ".hex")
+	assert.Error(t, err, "palettes over the size limit should be rejected")
+}
+
+func TestLoadPalette(t *testing.T) {
+	original := GetPalette()
+	t.Cleanup(func() { SetPalette(original) })
+
+	require.NoError(t, LoadPalette(filepath.Join("testdata", "palettes", "sample.gpl")))
+	assert.Equal(t, samplePaletteColors, GetPalette())
+	assert.True(t, IsColorTransparent(0), "color 0 should stay transparent")
+
+	assert.Error(t, LoadPalette(filepath.Join("testdata", "palettes", "missing.gpl")))
+	assert.Equal(t, samplePaletteColors, GetPalette(), "a failed load should keep the palette")
+}
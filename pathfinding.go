@@ -0,0 +1,190 @@
+package pigo8
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+)
+
+// --- Pathfinding ---
+
+// PathOptions tunes FindPath.
+type PathOptions struct {
+	// Diagonal allows 8-directional movement. Diagonal steps never cut the
+	// corner of a blocked tile.
+	Diagonal bool
+	// MaxIterations caps how many tiles the search may expand before giving
+	// up, to bound the cost of a search in a large map. 0 means no cap.
+	MaxIterations int
+}
+
+// FindPath finds the shortest path over the map from tile (startX, startY)
+// to tile (goalX, goalY) with A*. isWalkable reports whether a map sprite can
+// be walked on; PathAvoidFlag builds one from a sprite flag.
+//
+// The path is returned as the pixel centers of the tiles to walk through,
+// from the first step after the start to the goal, ready to be followed by
+// an entity. It is nil if there is no path (or the search hit
+// MaxIterations), and empty but not nil if start and goal are the same tile.
+// Only the goal has to be walkable; the start tile is not checked.
+//
+// Example:
+//
+//	// Enemies walk around tiles with flag 0 set, diagonals allowed
+//	path := FindPath(ex/8, ey/8, px/8, py/8, PathAvoidFlag(0), PathOptions{Diagonal: true, MaxIterations: 500})
+//	if len(path) > 0 {
+//		dir := path[0].Sub(NewVector2D(enemy.x+4, enemy.y+4)).Normalize()
+//		enemy.x += dir.X
+//		enemy.y += dir.Y
+//	}
+func FindPath(startX, startY, goalX, goalY int, isWalkable func(sprite int) bool, options ...PathOptions) []Vector2D {
+	var opts PathOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	// Search a snapshot of the map, so the map lock isn't held while
+	// isWalkable runs and tiles aren't streamed in one by one
+	ensureStreamingSystemInitialized()
+	worldMapMutex.RLock()
+	if worldMapStream == nil {
+		worldMapMutex.RUnlock()
+		return nil
+	}
+	tiles := slices.Clone(worldMapStream.Data)
+	width, height := worldMapStream.WorldWidthInTiles, worldMapStream.WorldHeightInTiles
+	worldMapMutex.RUnlock()
+
+	walkable := func(x, y int) bool { return isWalkable(tiles[y*width+x]) }
+	cells := findPathOnGrid(width, height, walkable, [2]int{startX, startY}, [2]int{goalX, goalY}, opts)
+	if cells == nil {
+		return nil
+	}
+
+	path := make([]Vector2D, len(cells))
+	for i, c := range cells {
+		path[i] = Vector2D{X: float64(c[0]*tileSize + tileSize/2), Y: float64(c[1]*tileSize + tileSize/2)}
+	}
+	return path
+}
+
+// PathAvoidFlag returns an isWalkable function for FindPath that blocks the
+// sprites with the given flag set (e.g. the flag used for walls with
+// MapCollision). Empty tiles (sprite 0) are walkable.
+func PathAvoidFlag(flag int) func(sprite int) bool {
+	return func(sprite int) bool {
+		return sprite <= 0 || !getCachedFlag(sprite, flag)
+	}
+}
+
+// pathNode is a tile in the A* open set.
+type pathNode struct {
+	cell  [2]int
+	f, h  float64 // Estimated total cost, and estimated cost left
+	order int     // Insertion order, so equal nodes come out in a fixed order
+}
+
+// pathQueue is a min-heap of pathNodes by f, then h, then insertion order.
+type pathQueue []pathNode
+
+func (q pathQueue) Len() int { return len(q) }
+func (q pathQueue) Less(i, j int) bool {
+	if q[i].f != q[j].f {
+		return q[i].f < q[j].f
+	}
+	if q[i].h != q[j].h {
+		return q[i].h < q[j].h
+	}
+	return q[i].order < q[j].order
+}
+func (q pathQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x any)   { *q = append(*q, x.(pathNode)) }
+func (q *pathQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+// findPathOnGrid runs A* on a width by height grid and returns the cells from
+// the first step after start up to goal, nil if there is no path, or an empty
+// slice if start is goal.
+func findPathOnGrid(width, height int, walkable func(x, y int) bool, start, goal [2]int, opts PathOptions) [][2]int {
+	inside := func(c [2]int) bool { return c[0] >= 0 && c[1] >= 0 && c[0] < width && c[1] < height }
+	if !inside(start) || !inside(goal) || !walkable(goal[0], goal[1]) {
+		return nil
+	}
+	if start == goal {
+		return [][2]int{}
+	}
+
+	// Octile distance with diagonals, Manhattan distance without
+	heuristic := func(c [2]int) float64 {
+		dx := math.Abs(float64(c[0] - goal[0]))
+		dy := math.Abs(float64(c[1] - goal[1]))
+		if opts.Diagonal {
+			return math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)
+		}
+		return dx + dy
+	}
+
+	directions := [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+	if opts.Diagonal {
+		directions = append(directions, [2]int{1, -1}, [2]int{1, 1}, [2]int{-1, 1}, [2]int{-1, -1})
+	}
+
+	index := func(c [2]int) int { return c[1]*width + c[0] }
+	cost := map[int]float64{index(start): 0}
+	cameFrom := map[int][2]int{}
+	closed := map[int]bool{}
+
+	queue := &pathQueue{}
+	order := 0
+	heap.Push(queue, pathNode{cell: start, f: heuristic(start), h: heuristic(start)})
+
+	for iterations := 0; queue.Len() > 0; iterations++ {
+		if opts.MaxIterations > 0 && iterations >= opts.MaxIterations {
+			return nil
+		}
+
+		current := heap.Pop(queue).(pathNode).cell
+		if current == goal {
+			var path [][2]int
+			for c := goal; c != start; c = cameFrom[index(c)] {
+				path = append(path, c)
+			}
+			slices.Reverse(path)
+			return path
+		}
+		if closed[index(current)] {
+			continue // A stale entry for a tile that was reached more cheaply
+		}
+		closed[index(current)] = true
+
+		for _, d := range directions {
+			next := [2]int{current[0] + d[0], current[1] + d[1]}
+			if !inside(next) || closed[index(next)] || !walkable(next[0], next[1]) {
+				continue
+			}
+			step := 1.0
+			if d[0] != 0 && d[1] != 0 {
+				// Don't squeeze diagonally between two blocked tiles or around a corner
+				if !walkable(current[0]+d[0], current[1]) || !walkable(current[0], current[1]+d[1]) {
+					continue
+				}
+				step = math.Sqrt2
+			}
+
+			newCost := cost[index(current)] + step
+			if old, seen := cost[index(next)]; seen && newCost >= old {
+				continue
+			}
+			cost[index(next)] = newCost
+			cameFrom[index(next)] = current
+			order++
+			h := heuristic(next)
+			heap.Push(queue, pathNode{cell: next, f: newCost + h, h: h, order: order})
+		}
+	}
+	return nil
+}
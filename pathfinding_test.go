@@ -0,0 +1,82 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gridWalkable builds a walkable check from rows of tiles, where '#' is a wall.
+func gridWalkable(rows []string) (width, height int, walkable func(x, y int) bool) {
+	return len(rows[0]), len(rows), func(x, y int) bool { return rows[y][x] != '#' }
+}
+
+func TestFindPathOnGrid(t *testing.T) {
+	w, h, walkable := gridWalkable([]string{
+		"S..#....",
+		".#.#.##.",
+		".#...#G.",
+		".####.#.",
+		"........",
+	})
+
+	path := findPathOnGrid(w, h, walkable, [2]int{0, 0}, [2]int{6, 2}, PathOptions{})
+	assert.Equal(t, [][2]int{
+		{1, 0}, {2, 0}, {2, 1}, {2, 2}, {3, 2}, {4, 2}, {4, 1}, {4, 0},
+		{5, 0}, {6, 0}, {7, 0}, {7, 1}, {7, 2}, {6, 2},
+	}, path, "the path should go around the walls")
+
+	// Every step is to an edge neighbor
+	prev := [2]int{0, 0}
+	for _, c := range path {
+		dx, dy := c[0]-prev[0], c[1]-prev[1]
+		assert.Equal(t, 1, dx*dx+dy*dy, "4-directional paths only move to edge neighbors")
+		prev = c
+	}
+}
+
+func TestFindPathDiagonal(t *testing.T) {
+	w, h, walkable := gridWalkable([]string{
+		"....",
+		"....",
+		"....",
+		"....",
+	})
+	path := findPathOnGrid(w, h, walkable, [2]int{0, 0}, [2]int{3, 3}, PathOptions{Diagonal: true})
+	assert.Equal(t, [][2]int{{1, 1}, {2, 2}, {3, 3}}, path)
+
+	path = findPathOnGrid(w, h, walkable, [2]int{0, 0}, [2]int{3, 3}, PathOptions{})
+	assert.Len(t, path, 6, "without diagonals the path is 6 steps")
+
+	// No cutting corners between walls
+	w, h, walkable = gridWalkable([]string{
+		".#",
+		"#.",
+	})
+	assert.Nil(t, findPathOnGrid(w, h, walkable, [2]int{0, 0}, [2]int{1, 1}, PathOptions{Diagonal: true}))
+}
+
+func TestFindPathNoPath(t *testing.T) {
+	w, h, walkable := gridWalkable([]string{
+		"..#..",
+		"..#..",
+		"..#..",
+	})
+	assert.Nil(t, findPathOnGrid(w, h, walkable, [2]int{0, 0}, [2]int{4, 2}, PathOptions{}), "the wall splits the map")
+	assert.Nil(t, findPathOnGrid(w, h, walkable, [2]int{0, 0}, [2]int{2, 1}, PathOptions{}), "the goal is a wall")
+	assert.Nil(t, findPathOnGrid(w, h, walkable, [2]int{0, 0}, [2]int{9, 0}, PathOptions{}), "the goal is off the map")
+
+	path := findPathOnGrid(w, h, walkable, [2]int{1, 1}, [2]int{1, 1}, PathOptions{})
+	assert.NotNil(t, path)
+	assert.Empty(t, path, "start and goal on the same tile give an empty path")
+}
+
+func TestFindPathMaxIterations(t *testing.T) {
+	w, h, walkable := gridWalkable([]string{
+		"..........",
+		"..........",
+		"..........",
+	})
+	assert.Nil(t, findPathOnGrid(w, h, walkable, [2]int{0, 0}, [2]int{9, 2}, PathOptions{MaxIterations: 3}))
+	assert.NotNil(t, findPathOnGrid(w, h, walkable, [2]int{0, 0}, [2]int{9, 2}, PathOptions{MaxIterations: 100}))
+}
@@ -0,0 +1,132 @@
+package pigo8
+
+import (
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// --- Audio while paused ---
+
+// PauseAudio says what happens to the sound while the pause menu is open,
+// see SetPauseAudio.
+type PauseAudio int
+
+const (
+	// PauseAudioKeep keeps music and sound effects playing (the default).
+	PauseAudioKeep PauseAudio = iota
+	// PauseAudioSfx pauses the sound effects and keeps the music playing.
+	PauseAudioSfx
+	// PauseAudioAll pauses the music and the sound effects.
+	PauseAudioAll
+)
+
+var (
+	pauseAudio PauseAudio
+	// audioPausedByMenu is true while the players in the audio players'
+	// menuPaused lists were paused by the pause menu.
+	audioPausedByMenu bool
+)
+
+// SetPauseAudio sets what happens to the sound while the pause menu is open:
+// it keeps playing (PauseAudioKeep, the default), the sound effects pause
+// (PauseAudioSfx), or everything pauses (PauseAudioAll). Paused sounds
+// resume where they were when the menu closes. It can also be set with
+// Settings.PauseAudio; a change takes effect the next time the menu opens.
+//
+// The game clock pauses with the menu whatever the policy: T(), Frame(),
+// timers and tweens don't advance while it is open, and RealT() does.
+//
+// Example:
+//
+//	settings := NewSettings()
+//	settings.PauseAudio = PauseAudioSfx // Keep the music, freeze the explosions
+//	PlayGameWith(settings)
+func SetPauseAudio(policy PauseAudio) {
+	pauseAudio = policy
+}
+
+// GetPauseAudio returns the policy set with SetPauseAudio.
+func GetPauseAudio() PauseAudio {
+	return pauseAudio
+}
+
+// syncPauseAudio pauses or resumes the sound according to the pause audio
+// policy when the pause menu opens or closes. The engine calls it every
+// frame with whether the menu is open.
+func syncPauseAudio(menuOpen bool) {
+	paused := menuOpen && (audioPausedByMenu || pauseAudio != PauseAudioKeep)
+	if paused == audioPausedByMenu {
+		return
+	}
+	audioPausedByMenu = paused
+	musicPositionCache.valid = false
+	if paused {
+		pauseAudioPlayers(pauseAudio == PauseAudioAll)
+	} else {
+		resumeAudioPlayers()
+	}
+}
+
+// pauseAudioPlayers pauses the sound effects that are playing, and the music
+// too if music is true, remembering them for resumeAudioPlayers. Audio that
+// was never used stays uninitialized.
+func pauseAudioPlayers(music bool) {
+	if ap := audioPlayerInstance; ap != nil {
+		ap.mutex.Lock()
+		ap.menuPaused = pausePlaying(ap.menuPaused, ap.sfxPlayers)
+		if music {
+			for _, player := range ap.musicPlayers {
+				ap.menuPaused = pausePlaying(ap.menuPaused, []*audio.Player{player})
+			}
+		}
+		ap.mutex.Unlock()
+	}
+	if ap := audioPlayerF32Instance; ap != nil && music {
+		ap.mutex.Lock()
+		for _, player := range ap.musicPlayers {
+			ap.menuPaused = pausePlaying(ap.menuPaused, []*audio.Player{player})
+		}
+		ap.mutex.Unlock()
+	}
+}
+
+// pausePlaying pauses the players that are playing and appends them to paused.
+func pausePlaying(paused, players []*audio.Player) []*audio.Player {
+	for _, player := range players {
+		if player != nil && player.IsPlaying() {
+			player.Pause()
+			paused = append(paused, player)
+		}
+	}
+	return paused
+}
+
+// resumeAudioPlayers plays the players paused by pauseAudioPlayers again.
+func resumeAudioPlayers() {
+	if ap := audioPlayerInstance; ap != nil {
+		ap.mutex.Lock()
+		ap.menuPaused = resumePaused(ap.menuPaused)
+		ap.mutex.Unlock()
+	}
+	if ap := audioPlayerF32Instance; ap != nil {
+		ap.mutex.Lock()
+		ap.menuPaused = resumePaused(ap.menuPaused)
+		ap.mutex.Unlock()
+	}
+}
+
+// resumePaused plays the players in paused and returns the emptied list.
+func resumePaused(paused []*audio.Player) []*audio.Player {
+	for _, player := range paused {
+		player.Play()
+	}
+	clear(paused)
+	return paused[:0]
+}
+
+// forgetMenuPaused drops player from paused, so a track stopped while the
+// pause menu is open doesn't start again when it closes.
+func forgetMenuPaused(paused []*audio.Player, player *audio.Player) []*audio.Player {
+	return slices.DeleteFunc(paused, func(p *audio.Player) bool { return p == player })
+}
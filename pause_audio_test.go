@@ -0,0 +1,43 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// usePauseAudio sets the pause audio policy for one test.
+func usePauseAudio(t *testing.T, policy PauseAudio) {
+	t.Helper()
+	t.Cleanup(func() {
+		syncPauseAudio(false)
+		SetPauseAudio(PauseAudioKeep)
+	})
+	SetPauseAudio(policy)
+}
+
+func TestSyncPauseAudio(t *testing.T) {
+	usePauseAudio(t, PauseAudioKeep)
+	assert.Equal(t, PauseAudioKeep, NewSettings().PauseAudio, "sound keeps playing by default")
+
+	syncPauseAudio(true)
+	assert.False(t, audioPausedByMenu, "PauseAudioKeep doesn't pause")
+	syncPauseAudio(false)
+
+	SetPauseAudio(PauseAudioAll)
+	assert.Equal(t, PauseAudioAll, GetPauseAudio())
+	syncPauseAudio(true)
+	assert.True(t, audioPausedByMenu)
+
+	SetPauseAudio(PauseAudioKeep)
+	syncPauseAudio(true)
+	assert.True(t, audioPausedByMenu, "a policy change waits for the next pause")
+
+	syncPauseAudio(false)
+	assert.False(t, audioPausedByMenu, "closing the menu resumes")
+}
+
+func TestResumePaused(t *testing.T) {
+	assert.Empty(t, resumePaused(nil))
+	assert.Empty(t, forgetMenuPaused(nil, nil))
+}
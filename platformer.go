@@ -0,0 +1,165 @@
+package pigo8
+
+import "math"
+
+// --- Platformer physics ---
+
+// PlatformerBody is an optional helper with the physics of a typical
+// platformer character: gravity, jumping with variable height, and movement
+// that stops at solid map tiles. The game supplies the input by setting
+// Vel.X and calling Jump and ReleaseJump; Step does the rest.
+//
+// Movement is resolved one pixel at a time, so fast bodies can't tunnel
+// through thin walls, and Pos always ends up flush against what it hit.
+// Fractions of a pixel are carried over to the next Step, so slow speeds
+// like 0.25 pixels per frame still move smoothly.
+//
+// Example:
+//
+//	func (g *Game) Init() {
+//		g.player = NewPlatformerBody(16, 16, 8, 8)
+//	}
+//
+//	func (g *Game) Update() {
+//		g.player.Vel.X = 0
+//		if Btn(LEFT) {
+//			g.player.Vel.X = -1.5
+//		}
+//		if Btn(RIGHT) {
+//			g.player.Vel.X = 1.5
+//		}
+//		if Btnp(O) {
+//			g.player.Jump()
+//		}
+//		if !Btn(O) {
+//			g.player.ReleaseJump() // Short hop when the button is let go early
+//		}
+//		g.player.Step(0) // Tiles with flag 0 are solid
+//	}
+//
+//	func (g *Game) Draw() {
+//		Spr(1, g.player.Pos.X, g.player.Pos.Y)
+//	}
+type PlatformerBody struct {
+	Pos  Vector2D // Top-left corner of the hitbox, in pixels
+	Vel  Vector2D // Pixels per frame; positive Y is down
+	W, H int      // Hitbox size in pixels
+
+	Gravity    float64 // Added to Vel.Y every Step
+	MaxFall    float64 // Largest downward speed; 0 means no limit
+	JumpSpeed  float64 // Upward speed at the start of a jump
+	JumpCutoff float64 // Vel.Y is multiplied by this when a jump is released early (0-1)
+
+	// Set by Step
+	OnGround  bool // Standing on a solid tile
+	OnCeiling bool // Bumped into a solid tile above during the last Step
+	OnWall    bool // Bumped into a solid tile to the side during the last Step
+
+	jumping    bool // Rising from a jump that was not released yet
+	remX, remY float64
+}
+
+// NewPlatformerBody creates a body with a w by h pixel hitbox at (x, y) and
+// defaults that suit 8x8 sprites at 30 FPS: a jump of about three tiles,
+// or one tile for a quick tap.
+func NewPlatformerBody(x, y float64, w, h int) *PlatformerBody {
+	return &PlatformerBody{
+		Pos:        Vector2D{X: x, Y: y},
+		W:          w,
+		H:          h,
+		Gravity:    0.35,
+		MaxFall:    4,
+		JumpSpeed:  4.5,
+		JumpCutoff: 0.4,
+	}
+}
+
+// Jump starts a jump if the body is standing on the ground and reports
+// whether it did.
+func (b *PlatformerBody) Jump() bool {
+	if !b.OnGround {
+		return false
+	}
+	b.Vel.Y = -b.JumpSpeed
+	b.remY = 0
+	b.OnGround = false
+	b.jumping = true
+	return true
+}
+
+// ReleaseJump ends a jump early: if the body is still rising from a jump,
+// its upward speed is cut by JumpCutoff. Call it when the jump button is not
+// held, so a tap gives a short hop and holding the button a full jump.
+func (b *PlatformerBody) ReleaseJump() {
+	if b.jumping && b.Vel.Y < 0 {
+		b.Vel.Y *= b.JumpCutoff
+	}
+	b.jumping = false
+}
+
+// Step advances the body by one frame: it applies gravity, moves by Vel
+// while stopping at map tiles that have solidFlag set (see MapCollision),
+// and updates OnGround, OnCeiling and OnWall. Call it once per Update.
+func (b *PlatformerBody) Step(solidFlag int) {
+	b.step(func(x, y float64) bool {
+		return MapCollision(x, y, solidFlag, b.W, b.H)
+	})
+}
+
+// step is Step with the collision check supplied, so it can be tested without a map.
+func (b *PlatformerBody) step(solid func(x, y float64) bool) {
+	b.OnCeiling, b.OnWall = false, false
+
+	b.Vel.Y += b.Gravity
+	if b.MaxFall > 0 && b.Vel.Y > b.MaxFall {
+		b.Vel.Y = b.MaxFall
+	}
+	if b.Vel.Y >= 0 {
+		b.jumping = false
+	}
+
+	// Horizontal first, so walking into a wall doesn't stop a fall
+	if moveAxis(&b.Pos.X, &b.remX, b.Vel.X, func(x float64) bool { return solid(x, b.Pos.Y) }) {
+		b.Vel.X = 0
+		b.OnWall = true
+	}
+
+	if moveAxis(&b.Pos.Y, &b.remY, b.Vel.Y, func(y float64) bool { return solid(b.Pos.X, y) }) {
+		if b.Vel.Y < 0 {
+			b.OnCeiling = true
+			b.jumping = false
+		}
+		b.Vel.Y = 0
+	}
+
+	// Standing still on the ground moves less than a pixel per frame, so
+	// look for the ground directly instead of waiting to hit it
+	b.OnGround = b.Vel.Y >= 0 && solid(b.Pos.X, b.Pos.Y+1)
+	if b.OnGround {
+		b.Vel.Y = 0
+		b.remY = 0
+	}
+}
+
+// moveAxis moves *pos by speed plus the carried over fraction *rem, one
+// pixel at a time, until blocked reports a solid position. It reports
+// whether the body was blocked.
+func moveAxis(pos, rem *float64, speed float64, blocked func(p float64) bool) bool {
+	*rem += speed
+	move := math.Round(*rem)
+	*rem -= move
+
+	step := 1.0
+	if move < 0 {
+		step = -1
+	}
+	for move != 0 {
+		if blocked(*pos + step) {
+			*rem = 0
+			return true
+		}
+		*pos += step
+		move -= step
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package pigo8
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testLevel builds a collision check from rows of tiles, where '#' is solid,
+// like MapCollision does for a w by h pixel body.
+func testLevel(rows []string, w, h int) func(x, y float64) bool {
+	return func(x, y float64) bool {
+		for ty := Flr(y / 8); ty <= Flr((y+float64(h)-1)/8); ty++ {
+			for tx := Flr(x / 8); tx <= Flr((x+float64(w)-1)/8); tx++ {
+				if ty >= 0 && ty < len(rows) && tx >= 0 && tx < len(rows[ty]) && rows[ty][tx] == '#' {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+var testPlatformerRows = []string{
+	"##########",
+	"#........#",
+	"#........#",
+	"#...#....#",
+	"#........#",
+	"#........#",
+	"#........#",
+	"##########",
+}
+
+func TestPlatformerBodyLanding(t *testing.T) {
+	solid := testLevel(testPlatformerRows, 8, 8)
+	b := NewPlatformerBody(8, 8, 8, 8)
+
+	for i := 0; i < 60; i++ {
+		b.step(solid)
+	}
+	assert.True(t, b.OnGround)
+	assert.Equal(t, 48.0, b.Pos.Y, "the body should rest flush on the floor at y=56")
+	assert.Equal(t, 0.0, b.Vel.Y)
+
+	// Standing still keeps it on the ground without sinking
+	for i := 0; i < 10; i++ {
+		b.step(solid)
+		assert.True(t, b.OnGround)
+		assert.Equal(t, 48.0, b.Pos.Y)
+	}
+}
+
+func TestPlatformerBodyCeiling(t *testing.T) {
+	solid := testLevel(testPlatformerRows, 8, 8)
+	// Standing under the block at tile (4, 3)
+	b := NewPlatformerBody(32, 48, 8, 8)
+	b.step(solid)
+	assert.True(t, b.OnGround)
+
+	assert.True(t, b.Jump())
+	assert.False(t, b.Jump(), "no double jumps in the air")
+	hitCeiling := false
+	for i := 0; i < 10 && !hitCeiling; i++ {
+		b.step(solid)
+		hitCeiling = b.OnCeiling
+	}
+	assert.True(t, hitCeiling)
+	assert.Equal(t, 32.0, b.Pos.Y, "the body should stop flush under the block at y=24..31")
+	assert.GreaterOrEqual(t, b.Vel.Y, 0.0, "the body should stop rising")
+}
+
+func TestPlatformerBodyWall(t *testing.T) {
+	solid := testLevel(testPlatformerRows, 8, 8)
+	b := NewPlatformerBody(50, 48, 8, 8)
+	b.step(solid)
+
+	for i := 0; i < 20; i++ {
+		b.Vel.X = 3.7 // Fast enough to skip past the edge in one frame without sweeping
+		b.step(solid)
+	}
+	assert.True(t, b.OnWall)
+	assert.Equal(t, 64.0, b.Pos.X, "the body should stop flush against the wall at x=72")
+	assert.True(t, b.OnGround, "walking into a wall keeps the body on the ground")
+
+	b.Vel.X = -1
+	b.step(solid)
+	assert.False(t, b.OnWall)
+	assert.Equal(t, 63.0, b.Pos.X)
+}
+
+func TestPlatformerBodyVariableJump(t *testing.T) {
+	solid := testLevel([]string{
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"##########",
+	}, 8, 8)
+
+	apex := func(releaseAfter int) float64 {
+		b := NewPlatformerBody(8, 48, 8, 8)
+		b.step(solid)
+		b.Jump()
+		highest := b.Pos.Y
+		for frame := 0; frame < 40; frame++ {
+			if frame >= releaseAfter {
+				b.ReleaseJump()
+			}
+			b.step(solid)
+			highest = math.Min(highest, b.Pos.Y)
+		}
+		assert.True(t, b.OnGround, "the body should land again")
+		return 48 - highest
+	}
+
+	full, hop := apex(100), apex(1)
+	assert.Greater(t, full, 16.0, "holding jump should clear two tiles")
+	assert.Less(t, hop, full/2, "releasing early should give a much lower jump")
+}
+
+func TestPlatformerBodySubPixel(t *testing.T) {
+	solid := testLevel(testPlatformerRows, 8, 8)
+	b := NewPlatformerBody(16, 48, 8, 8)
+	for i := 0; i < 8; i++ {
+		b.Vel.X = 0.25
+		b.step(solid)
+	}
+	assert.Equal(t, 18.0, b.Pos.X, "fractions of a pixel should add up across steps")
+}
@@ -0,0 +1,71 @@
+package pigo8
+
+// Pool keeps released objects so they can be reused instead of allocated
+// again. Recycling short-lived objects such as bullets, particles and enemies
+// avoids garbage collection pauses in spawn-heavy games.
+//
+// T is usually a pointer type (e.g. *Bullet), so Get hands out the same
+// object that was Put back.
+//
+// A Pool is not safe for concurrent use. Use it from the game loop (Init,
+// Update and Draw all run on the same goroutine), or guard it with a mutex
+// if you share it with other goroutines.
+//
+// Example:
+//
+//	type Bullet struct{ x, y, dy float64 }
+//
+//	bullets := NewPool(
+//	    func() *Bullet { return &Bullet{} },
+//	    func(b *Bullet) { *b = Bullet{} }, // clear old state on Put
+//	)
+//
+//	// Spawn
+//	b := bullets.Get()
+//	b.x, b.y, b.dy = playerX, playerY, -2
+//	active = append(active, b)
+//
+//	// Despawn
+//	bullets.Put(b)
+type Pool[T any] struct {
+	free  []T
+	newFn func() T
+	reset func(T)
+}
+
+// NewPool creates an empty pool. newFn creates an object when the pool is
+// empty; if nil, Get returns the zero value of T. reset, if not nil, is
+// called on every object passed to Put, to clear its state before reuse.
+func NewPool[T any](newFn func() T, reset func(T)) *Pool[T] {
+	return &Pool[T]{newFn: newFn, reset: reset}
+}
+
+// Get returns a recycled object, or a new one if the pool is empty.
+func (p *Pool[T]) Get() T {
+	if n := len(p.free); n > 0 {
+		item := p.free[n-1]
+		var zero T
+		p.free[n-1] = zero // Let the pool's slot be collected if it is never refilled
+		p.free = p.free[:n-1]
+		return item
+	}
+	if p.newFn != nil {
+		return p.newFn()
+	}
+	var zero T
+	return zero
+}
+
+// Put returns an object to the pool for a later Get. The caller must not
+// use the object after putting it back.
+func (p *Pool[T]) Put(item T) {
+	if p.reset != nil {
+		p.reset(item)
+	}
+	p.free = append(p.free, item)
+}
+
+// Len returns the number of objects waiting in the pool.
+func (p *Pool[T]) Len() int {
+	return len(p.free)
+}
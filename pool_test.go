@@ -0,0 +1,82 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pooledBullet struct {
+	x, y, dy float64
+	trail    [16]float64
+}
+
+func TestPool(t *testing.T) {
+	created := 0
+	pool := NewPool(
+		func() *pooledBullet { created++; return &pooledBullet{} },
+		func(b *pooledBullet) { *b = pooledBullet{} },
+	)
+
+	b := pool.Get()
+	assert.Equal(t, 1, created, "empty pool should create a new object")
+	b.x, b.dy = 10, -2
+
+	pool.Put(b)
+	assert.Equal(t, 1, pool.Len())
+	assert.Equal(t, pooledBullet{}, *b, "Put should run the reset hook")
+
+	again := pool.Get()
+	assert.Same(t, b, again, "Get should return the recycled object")
+	assert.Equal(t, 1, created, "recycling should not create objects")
+	assert.Equal(t, 0, pool.Len())
+}
+
+func TestPoolWithoutHooks(t *testing.T) {
+	pool := NewPool[int](nil, nil)
+	assert.Equal(t, 0, pool.Get(), "Get without newFn should return the zero value")
+
+	pool.Put(7)
+	pool.Put(9)
+	assert.Equal(t, 9, pool.Get(), "pool should reuse the last object put back")
+	assert.Equal(t, 7, pool.Get())
+}
+
+// benchmarkSpawns is how many bullets are spawned and despawned per frame.
+const benchmarkSpawns = 200
+
+// benchmarkActive keeps the spawned bullets reachable, like a game's live list.
+var benchmarkActive []*pooledBullet
+
+func BenchmarkSpawnWithoutPool(b *testing.B) {
+	b.ReportAllocs()
+	active := make([]*pooledBullet, 0, benchmarkSpawns)
+	for n := 0; n < b.N; n++ {
+		active = active[:0]
+		for i := 0; i < benchmarkSpawns; i++ {
+			active = append(active, &pooledBullet{y: float64(i), dy: -2})
+		}
+		benchmarkActive = active
+	}
+}
+
+func BenchmarkSpawnWithPool(b *testing.B) {
+	b.ReportAllocs()
+	pool := NewPool(
+		func() *pooledBullet { return &pooledBullet{} },
+		func(b *pooledBullet) { *b = pooledBullet{} },
+	)
+	active := make([]*pooledBullet, 0, benchmarkSpawns)
+	for n := 0; n < b.N; n++ {
+		for _, bullet := range active {
+			pool.Put(bullet)
+		}
+		active = active[:0]
+		for i := 0; i < benchmarkSpawns; i++ {
+			bullet := pool.Get()
+			bullet.y, bullet.dy = float64(i), -2
+			active = append(active, bullet)
+		}
+		benchmarkActive = active
+	}
+}
@@ -0,0 +1,261 @@
+package pigo8
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+)
+
+// --- Asset preloading ---
+
+// PreloadAsset is an asset Preload and PreloadSync load ahead of time, made
+// with PreloadSpritesheet, PreloadMap, PreloadMusic, PreloadSfx or PreloadFunc.
+type PreloadAsset struct {
+	name string
+	// load reads and decodes the asset without touching the engine state, and
+	// returns the function that swaps it in on the game loop.
+	load func() (apply func(), err error)
+}
+
+// Preloader tracks the assets loaded by Preload. Its methods are safe to call
+// from any goroutine, but it is meant to be polled from Update or Draw.
+type Preloader struct {
+	mu      sync.Mutex
+	total   int
+	applied int
+	ready   []func() // Loaded assets waiting to be swapped in on the game loop
+	errs    []error
+}
+
+var (
+	preloadMutex  sync.Mutex
+	activePreload []*Preloader // Preloaders with assets still to swap in
+	// preloadedMap is a map.json parsed by PreloadMap before the map system
+	// started, used by initializeStreamingMapSystem instead of reading it again.
+	preloadedMap *mapDataJSON
+)
+
+// PreloadSpritesheet loads spritesheet.json, from disk or embedded resources
+// as chosen with SetAssetSource, replacing the loaded sprites.
+func PreloadSpritesheet() PreloadAsset {
+	return PreloadAsset{name: "spritesheet.json", load: func() (func(), error) {
+		data, err := readAssetFile("spritesheet.json", tryLoadEmbeddedSpritesheet)
+		if err != nil {
+			return nil, err
+		}
+		sheet, err := parseSpritesheetData(data)
+		if err != nil {
+			return nil, err
+		}
+		return func() {
+			// The sprite images are created here, as building them changes the
+			// spritesheet size and the pixel caches the game loop reads
+			currentSprites = buildSpritesFromSheet(sheet, true)
+			ClearSpriteCache()
+			ClearFlagCache()
+			mapCacheIsValid = false
+		}, nil
+	}}
+}
+
+// PreloadMap loads map.json, from disk or embedded resources as chosen with
+// SetAssetSource, replacing the map.
+func PreloadMap() PreloadAsset {
+	return PreloadAsset{name: "map.json", load: func() (func(), error) {
+		jsonData, err := loadAndParseMapJSON("map.json")
+		if err != nil {
+			return nil, err
+		}
+		return func() {
+			if !streamingSystemInitialized {
+				preloadedMap = jsonData
+				ensureStreamingSystemInitialized()
+				return
+			}
+			setWorldMap(newTilemapStream(jsonData.Width, jsonData.Height, jsonData, "map.json"))
+		}, nil
+	}}
+}
+
+// PreloadMusic loads a WAV file into music slot n, like LoadMusic.
+func PreloadMusic(n int, path string) PreloadAsset {
+	return PreloadAsset{name: path, load: func() (func(), error) {
+		if n < 0 {
+			return nil, fmt.Errorf("invalid music slot %d", n)
+		}
+		data, err := readMusicFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return func() { setMusicData(n, data, path) }, nil
+	}}
+}
+
+// PreloadSfx loads and decodes a WAV file into sound effect slot n, like LoadSfx.
+func PreloadSfx(n int, path string) PreloadAsset {
+	return PreloadAsset{name: path, load: func() (func(), error) {
+		if n < 0 {
+			return nil, fmt.Errorf("invalid sfx slot %d", n)
+		}
+		pcm, err := readSfxFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return func() { setSfxData(n, pcm, path) }, nil
+	}}
+}
+
+// PreloadFunc makes a custom asset, e.g. a font or level data. load runs in
+// the background with Preload, so it must only read and decode, without
+// changing anything the game loop uses; the function it returns (which may be
+// nil) runs on the game loop to put the asset in place.
+//
+// Example:
+//
+//	levels := PreloadFunc("levels.json", func() (func(), error) {
+//		data, err := os.ReadFile("levels.json")
+//		if err != nil {
+//			return nil, err
+//		}
+//		var parsed []Level
+//		if err := json.Unmarshal(data, &parsed); err != nil {
+//			return nil, err
+//		}
+//		return func() { g.levels = parsed }, nil
+//	})
+func PreloadFunc(name string, load func() (apply func(), err error)) PreloadAsset {
+	return PreloadAsset{name: name, load: load}
+}
+
+// Preload loads assets in the background, one after the other, so the game
+// keeps running (e.g. to draw a loading screen) instead of freezing while the
+// first Spr or Map loads them lazily. The files are read and decoded on
+// another goroutine, and each asset is swapped in on the game loop before an
+// Update, so Update and Draw never see a half-loaded asset. Until
+// Preloader.Done, draw the loading screen without the assets being loaded:
+// calling Spr before the spritesheet is in place loads it right away, as
+// without Preload.
+//
+// Example:
+//
+//	func (g *Game) Init() {
+//		g.loading = Preload(PreloadSpritesheet(), PreloadMap(), PreloadMusic(0, "theme.wav"))
+//	}
+//
+//	func (g *Game) Draw() {
+//		if !g.loading.Done() {
+//			Cls(0)
+//			Rectfill(24, 60, 24+int(80*g.loading.Progress()), 66, 11)
+//			return
+//		}
+//		Map()
+//	}
+func Preload(assets ...PreloadAsset) *Preloader {
+	p := &Preloader{total: len(assets)}
+	if len(assets) == 0 {
+		return p
+	}
+	preloadMutex.Lock()
+	activePreload = append(activePreload, p)
+	preloadMutex.Unlock()
+
+	go func() {
+		for _, asset := range assets {
+			apply, err := asset.load()
+			p.mu.Lock()
+			if err != nil {
+				p.errs = append(p.errs, fmt.Errorf("error preloading %s: %w", asset.name, err))
+				apply = nil
+			}
+			if apply == nil {
+				apply = func() {}
+			}
+			p.ready = append(p.ready, apply)
+			p.mu.Unlock()
+		}
+	}()
+	return p
+}
+
+// PreloadSync loads assets right away, one after the other, and returns the
+// errors of the ones that failed (the others are still loaded). It blocks, so
+// call it from Init or before PlayGame, where freezing doesn't matter.
+//
+// Example:
+//
+//	if err := PreloadSync(PreloadSpritesheet(), PreloadSfx(0, "jump.wav")); err != nil {
+//		log.Printf("missing assets: %v", err)
+//	}
+func PreloadSync(assets ...PreloadAsset) error {
+	var errs []error
+	for _, asset := range assets {
+		apply, err := asset.load()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error preloading %s: %w", asset.name, err))
+			continue
+		}
+		if apply != nil {
+			apply()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Progress returns how much of the assets are in place, from 0 to 1.
+func (p *Preloader) Progress() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.total == 0 {
+		return 1
+	}
+	return float64(p.applied) / float64(p.total)
+}
+
+// Done reports whether every asset was loaded (or failed to) and is in place.
+func (p *Preloader) Done() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.applied == p.total
+}
+
+// Err returns the errors of the assets that failed to load so far, or nil.
+func (p *Preloader) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return errors.Join(p.errs...)
+}
+
+// applyPreloads swaps in the assets loaded in the background. The engine
+// calls it before every Update, next to applyAssetReloads.
+func applyPreloads() {
+	preloadMutex.Lock()
+	preloaders := slices.Clone(activePreload)
+	preloadMutex.Unlock()
+
+	for _, p := range preloaders {
+		p.mu.Lock()
+		ready := p.ready
+		p.ready = nil
+		p.mu.Unlock()
+
+		// Run outside the lock, so the apply functions can call Progress
+		for _, apply := range ready {
+			apply()
+		}
+
+		p.mu.Lock()
+		p.applied += len(ready)
+		done := p.applied == p.total
+		p.mu.Unlock()
+		if done {
+			preloadMutex.Lock()
+			activePreload = slices.DeleteFunc(activePreload, func(active *Preloader) bool { return active == p })
+			preloadMutex.Unlock()
+			if err := p.Err(); err != nil {
+				log.Printf("Warning: Preload: %v", err)
+			}
+		}
+	}
+}
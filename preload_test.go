@@ -0,0 +1,70 @@
+package pigo8
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreload(t *testing.T) {
+	t.Cleanup(func() { activePreload = nil })
+
+	release := make(chan struct{})
+	var order []string
+	p := Preload(
+		PreloadFunc("first", func() (func(), error) {
+			return func() { order = append(order, "first") }, nil
+		}),
+		PreloadFunc("slow", func() (func(), error) {
+			<-release
+			return func() { order = append(order, "slow") }, nil
+		}),
+		PreloadFunc("broken", func() (func(), error) {
+			return nil, errors.New("missing file")
+		}),
+	)
+	assert.False(t, p.Done())
+	assert.Empty(t, order, "assets are only swapped in by the game loop")
+
+	require.Eventually(t, func() bool {
+		applyPreloads()
+		return p.Progress() > 0
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"first"}, order)
+	assert.InDelta(t, 1.0/3, p.Progress(), 1e-9)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		applyPreloads()
+		return p.Done()
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"first", "slow"}, order)
+	assert.Equal(t, 1.0, p.Progress())
+	assert.ErrorContains(t, p.Err(), "broken")
+	assert.Empty(t, activePreload, "finished preloaders are dropped")
+}
+
+func TestPreloadNothing(t *testing.T) {
+	p := Preload()
+	assert.True(t, p.Done())
+	assert.Equal(t, 1.0, p.Progress())
+	assert.NoError(t, p.Err())
+}
+
+func TestPreloadSync(t *testing.T) {
+	loaded := false
+	err := PreloadSync(
+		PreloadFunc("level", func() (func(), error) {
+			return func() { loaded = true }, nil
+		}),
+		PreloadFunc("nothing to apply", func() (func(), error) { return nil, nil }),
+		PreloadMusic(-1, "theme.wav"),
+		PreloadSfx(0, "does-not-exist.wav"),
+	)
+	assert.True(t, loaded, "assets after a failure still load")
+	assert.ErrorContains(t, err, "invalid music slot -1")
+	assert.ErrorContains(t, err, "does-not-exist.wav")
+}
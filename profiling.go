@@ -0,0 +1,94 @@
+package pigo8
+
+import "time"
+
+// --- Frame profiling ---
+
+var (
+	updateStart    time.Time     // When the current Update started
+	updateDuration time.Duration // How long the last Update took
+	drawStart      time.Time     // When the current Draw started
+	drawCalls      int           // Draw operations issued so far this frame
+	lastFrameTime  time.Duration // Update + Draw duration of the last finished frame
+	lastDrawCalls  int           // Draw operations of the last finished frame
+
+	fpsWindowStart time.Time // Start of the current one-second FPS window
+	fpsFrames      int       // Frames finished in the current FPS window
+	measuredFPS    float64   // Frames per second over the last full window
+)
+
+// GetFPS returns the number of frames the engine finished per second,
+// measured over the last second. In headless mode every tick counts as a
+// frame. It is 0 during the first second.
+//
+// Example:
+//
+//	Print(fmt.Sprintf("FPS: %.0f", GetFPS()), 0, 0, 7)
+func GetFPS() float64 {
+	return measuredFPS
+}
+
+// GetFrameTime returns how long the last frame took to run: the time spent
+// in Update plus the time spent in Draw (only Update in headless mode). It
+// measures the game's own work, not the time waited for vsync, so it shows
+// how much of the frame budget (33ms at 30 FPS) is used.
+//
+// Example:
+//
+//	if GetFrameTime() > 16*time.Millisecond {
+//	    particles = particles[:len(particles)/2] // too slow for 60 FPS
+//	}
+func GetFrameTime() time.Duration {
+	return lastFrameTime
+}
+
+// GetDrawCallCount returns the number of draw operations of the last frame:
+// every Spr, Sspr, Map, Print and shape call, plus each upload of pending
+// Pset pixels. Fewer draw calls usually means a faster frame. It is 0 in
+// headless mode.
+func GetDrawCallCount() int {
+	return lastDrawCalls
+}
+
+// beginUpdateTiming starts timing the game logic of a frame.
+func beginUpdateTiming() {
+	updateStart = time.Now()
+}
+
+// endUpdateTiming stops timing the game logic of a frame.
+func endUpdateTiming() {
+	updateDuration = time.Since(updateStart)
+}
+
+// beginDrawTiming starts timing the drawing of a frame and resets the draw call count.
+func beginDrawTiming() {
+	drawStart = time.Now()
+	drawCalls = 0
+}
+
+// endDrawTiming stops timing the drawing of a frame and finishes the frame.
+func endDrawTiming() {
+	finishFrameTiming(time.Since(drawStart))
+}
+
+// countDrawCall records one draw operation for GetDrawCallCount.
+func countDrawCall() {
+	drawCalls++
+}
+
+// finishFrameTiming publishes the stats of a finished frame and updates the FPS.
+func finishFrameTiming(drawDuration time.Duration) {
+	lastFrameTime = updateDuration + drawDuration
+	lastDrawCalls = drawCalls
+
+	now := time.Now()
+	if fpsWindowStart.IsZero() {
+		fpsWindowStart = now
+	}
+	fpsFrames++
+	if elapsed := now.Sub(fpsWindowStart); elapsed >= time.Second {
+		measuredFPS = float64(fpsFrames) / elapsed.Seconds()
+		fpsFrames = 0
+		fpsWindowStart = now
+	}
+}
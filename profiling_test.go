@@ -0,0 +1,44 @@
+package pigo8
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameTiming(t *testing.T) {
+	t.Cleanup(func() {
+		updateDuration, drawCalls, lastFrameTime, lastDrawCalls = 0, 0, 0, 0
+		fpsWindowStart, fpsFrames, measuredFPS = time.Time{}, 0, 0
+	})
+
+	updateDuration = 3 * time.Millisecond
+	drawCalls = 0
+	countDrawCall()
+	countDrawCall()
+	finishFrameTiming(2 * time.Millisecond)
+
+	assert.Equal(t, 5*time.Millisecond, GetFrameTime(), "frame time should be Update plus Draw")
+	assert.Equal(t, 2, GetDrawCallCount())
+	assert.Equal(t, 0.0, GetFPS(), "FPS is not known before a full second")
+
+	// 59 frames so far and this one ends a two second window
+	fpsWindowStart = time.Now().Add(-2 * time.Second)
+	fpsFrames = 59
+	finishFrameTiming(0)
+	assert.InDelta(t, 30, GetFPS(), 0.5)
+	assert.Equal(t, 0, fpsFrames, "a new FPS window should start")
+}
+
+func TestFrameTimingHeadless(t *testing.T) {
+	originalCart, originalTime := loadedCartridge, elapsedTime
+	t.Cleanup(func() { loadedCartridge, elapsedTime = originalCart, originalTime })
+
+	InsertGame(&countingCartridge{})
+	drawCalls = 7
+	stepHeadless()
+
+	assert.Equal(t, 0, GetDrawCallCount(), "nothing is drawn in headless mode")
+	assert.GreaterOrEqual(t, GetFrameTime(), time.Duration(0))
+}
@@ -0,0 +1,99 @@
+package pigo8
+
+import "log"
+
+// --- Restarting ---
+
+// RestartReason tells a cartridge's Init why the engine is calling it.
+type RestartReason int
+
+const (
+	// NotRestarted means Init runs because the game just started, or that
+	// the game called its own Init.
+	NotRestarted RestartReason = iota
+	// RestartByGame means the game called RestartGame.
+	RestartByGame
+	// RestartByPauseMenu means the player chose Reset in the pause menu.
+	RestartByPauseMenu
+)
+
+// String returns the name of the restart reason.
+func (r RestartReason) String() string {
+	switch r {
+	case NotRestarted:
+		return "NotRestarted"
+	case RestartByGame:
+		return "RestartByGame"
+	case RestartByPauseMenu:
+		return "RestartByPauseMenu"
+	default:
+		return "Unknown"
+	}
+}
+
+var (
+	// restartRequested asks the main loop to restart the cartridge, see RestartGame.
+	restartRequested bool
+	// restartReason is the reason of the Init call the engine is making.
+	restartReason RestartReason
+)
+
+// RestartGame restarts the running cartridge at the start of the next frame:
+// the engine resets T() and Frame(), releases all buttons, resets the camera
+// and calls the cartridge's Init again, where GetRestartReason returns
+// RestartByGame. The pause menu's Reset option does the same with
+// RestartByPauseMenu.
+//
+// Console memory is kept: sprites, the map and the palette are not reloaded,
+// so changes made with Sset, Mset or Pal stay. Init is expected to put the
+// game's own state back to its starting values. To run a different
+// cartridge, call InsertGame first; it only swaps the cartridge, and
+// RestartGame then starts it.
+//
+// Example:
+//
+//	func (g *Game) Update() {
+//		if g.gameOver && Btnp(X) {
+//			RestartGame()
+//		}
+//	}
+func RestartGame() {
+	restartRequested = true
+}
+
+// GetRestartReason returns why the engine is calling the cartridge's Init.
+// It is only meaningful inside Init; elsewhere, and when the game calls its
+// own Init, it returns NotRestarted.
+//
+// Example:
+//
+//	func (g *Game) Init() {
+//		g.ball = newBall()
+//		if GetRestartReason() != NotRestarted {
+//			g.score = 0 // A new match, not just a new round
+//		}
+//	}
+func GetRestartReason() RestartReason {
+	return restartReason
+}
+
+// prepareRestart resets the engine state a restarted cartridge should not
+// inherit. The caller then runs initCartridge with the same reason.
+func prepareRestart(reason RestartReason) {
+	log.Printf("Restarting game (%s)...", reason)
+	restartRequested = false
+	Restart = true
+	resetClock()
+	resetInputState()
+	resetEvents()
+	resetScreenEffects()
+	Camera()
+}
+
+// initCartridge calls cart's Init, with GetRestartReason returning reason
+// during the call.
+func initCartridge(cart Cartridge, reason RestartReason) {
+	restartReason = reason
+	defer func() { restartReason = NotRestarted }()
+	cart.Init()
+}
@@ -0,0 +1,62 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// restartCartridge records why Init ran and restarts when asked to.
+type restartCartridge struct {
+	reasons    []RestartReason
+	restartNow bool
+	rounds     int
+}
+
+func (c *restartCartridge) Init() {
+	c.reasons = append(c.reasons, GetRestartReason())
+}
+
+func (c *restartCartridge) Update() {
+	if c.restartNow {
+		c.restartNow = false
+		RestartGame()
+	}
+	if Btnp(O) {
+		c.rounds++
+		c.Init() // Like pong, which calls its own Init for a new round
+	}
+}
+
+func (c *restartCartridge) Draw() {}
+
+func TestRestartGame(t *testing.T) {
+	cart := &restartCartridge{}
+	h := NewTestHarness(cart, nil)
+	t.Cleanup(func() { Restart = false })
+	h.Init()
+	h.AdvanceFrames(5)
+	assert.Equal(t, 5, Frame())
+
+	cart.restartNow = true
+	h.Step() // RestartGame is called during this Update
+	assert.Equal(t, []RestartReason{NotRestarted}, cart.reasons, "the restart waits for the next frame")
+
+	h.Step()
+	assert.Equal(t, []RestartReason{NotRestarted, RestartByGame}, cart.reasons)
+	assert.Equal(t, 1, Frame(), "the clock restarts")
+	assert.Equal(t, NotRestarted, GetRestartReason(), "only set during Init")
+	assert.True(t, Restart, "the deprecated flag is still set")
+
+	h.InjectButton(O, true)
+	h.Step()
+	h.InjectButton(O, false)
+	assert.Equal(t, 1, cart.rounds)
+	assert.Equal(t, []RestartReason{NotRestarted, RestartByGame, NotRestarted}, cart.reasons,
+		"the game's own Init call is not a restart")
+}
+
+func TestRestartReasonString(t *testing.T) {
+	assert.Equal(t, "RestartByPauseMenu", RestartByPauseMenu.String())
+	assert.Equal(t, "Unknown", RestartReason(9).String())
+}
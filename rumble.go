@@ -0,0 +1,114 @@
+package pigo8
+
+import (
+	"log"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Gamepad rumble ---
+
+// rumbleEffect is a running rumble of one player's gamepad.
+type rumbleEffect struct {
+	strength   float64
+	framesLeft int
+}
+
+// activeRumbles holds the running rumble per player index.
+var activeRumbles = make(map[int]rumbleEffect)
+
+// Rumble vibrates the gamepad of a player for a while, e.g. on landing or
+// taking damage. Player 0 is the first connected gamepad (by gamepad ID),
+// player 1 the second, and so on.
+//
+// The duration is turned into a number of frames at the target FPS and
+// counts down with the game clock, so a rumble stops while the game is
+// paused and lasts the same number of frames on every machine.
+//
+// A new rumble on a gamepad that is already rumbling doesn't queue or cut
+// the old one: the stronger of the two wins, and it lasts until the later
+// of the two would end.
+//
+// Vibration is only supported where Ebitengine supports it (browsers and
+// Nintendo Switch at the moment). Elsewhere, without a gamepad, or in
+// headless mode, Rumble does nothing, so games can call it unconditionally.
+//
+// Args:
+//   - player: player index (0-7)
+//   - strength: 0 to 1, clamped
+//   - durationMs: how long to rumble, in milliseconds
+//
+// Example:
+//
+//	if g.player.OnGround && !g.wasOnGround {
+//		Rumble(0, 0.3, 100) // Light bump on landing
+//	}
+//	if g.player.hit {
+//		Rumble(0, 1, 400)
+//	}
+func Rumble(player int, strength float64, durationMs int) {
+	if player < 0 {
+		log.Printf("Warning: Rumble() called with invalid player %d. Ignoring.", player)
+		return
+	}
+	strength = math.Max(0, math.Min(1, strength))
+	frames := int(math.Ceil(float64(durationMs) / 1000 / frameDuration()))
+	if strength == 0 || frames <= 0 {
+		return
+	}
+	addRumble(player, strength, frames)
+}
+
+// frameDuration returns the length of a frame in seconds.
+func frameDuration() float64 {
+	if timeIncrement > 0 {
+		return timeIncrement
+	}
+	return defaultTimeStep
+}
+
+// addRumble combines a new rumble with the one running for player, if any.
+func addRumble(player int, strength float64, frames int) {
+	effect := activeRumbles[player]
+	effect.strength = math.Max(effect.strength, strength)
+	effect.framesLeft = max(effect.framesLeft, frames)
+	activeRumbles[player] = effect
+}
+
+// updateRumble vibrates the gamepads with a running rumble for one more
+// frame and counts the rumbles down. The engine calls it every game frame.
+func updateRumble() {
+	if len(activeRumbles) == 0 {
+		return
+	}
+
+	var gamepads []ebiten.GamepadID
+	if !outputMuted() {
+		for id := range connectedGamepadIDs {
+			gamepads = append(gamepads, id)
+		}
+		slices.Sort(gamepads)
+	}
+
+	// Each call covers a little more than a frame, so the vibration doesn't
+	// stutter between frames and stops soon after the last one
+	duration := time.Duration(2 * frameDuration() * float64(time.Second))
+	for player, effect := range activeRumbles {
+		if player < len(gamepads) {
+			ebiten.VibrateGamepad(gamepads[player], &ebiten.VibrateGamepadOptions{
+				Duration:        duration,
+				StrongMagnitude: effect.strength,
+				WeakMagnitude:   effect.strength,
+			})
+		}
+		effect.framesLeft--
+		if effect.framesLeft <= 0 {
+			delete(activeRumbles, player)
+		} else {
+			activeRumbles[player] = effect
+		}
+	}
+}
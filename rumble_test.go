@@ -0,0 +1,41 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRumble(t *testing.T) {
+	t.Cleanup(func() {
+		clear(activeRumbles)
+		timeIncrement = 0
+	})
+	timeIncrement = 1.0 / 30
+
+	Rumble(0, 0.5, 100) // 3 frames at 30 FPS
+	assert.Equal(t, rumbleEffect{strength: 0.5, framesLeft: 3}, activeRumbles[0])
+
+	Rumble(0, 0.2, 200) // Weaker but longer: keeps the strength, extends the time
+	assert.Equal(t, rumbleEffect{strength: 0.5, framesLeft: 6}, activeRumbles[0])
+
+	Rumble(0, 2, 10) // Stronger but shorter: clamped strength wins, time stays
+	assert.Equal(t, rumbleEffect{strength: 1, framesLeft: 6}, activeRumbles[0])
+
+	Rumble(1, 0.3, 1)
+	assert.Equal(t, 1, activeRumbles[1].framesLeft, "rounds up to a whole frame")
+
+	Rumble(2, 0, 500)
+	Rumble(2, 1, 0)
+	Rumble(-1, 1, 500)
+	assert.Len(t, activeRumbles, 2, "silent, empty and invalid rumbles are ignored")
+
+	// Counting down works without any gamepad connected
+	updateRumble()
+	assert.Equal(t, 5, activeRumbles[0].framesLeft)
+	assert.NotContains(t, activeRumbles, 1, "finished rumbles are dropped")
+	for i := 0; i < 5; i++ {
+		updateRumble()
+	}
+	assert.Empty(t, activeRumbles)
+}
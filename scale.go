@@ -0,0 +1,218 @@
+package pigo8
+
+import (
+	"log"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Window scaling ---
+
+// ScaleMode controls how the logical screen is scaled to fill the window
+// or the monitor in fullscreen.
+type ScaleMode int
+
+const (
+	// ScaleFit scales the screen as large as it fits while keeping its aspect
+	// ratio, with letterboxing or pillarboxing. The scale may be fractional.
+	// This is the default.
+	ScaleFit ScaleMode = iota
+	// ScaleInteger uses the largest whole-number scale that fits, so every
+	// pixel is drawn as the same size square. The rest of the window is filled
+	// with bars, black unless changed with SetBorderColor. If the window is smaller than the screen, it falls back
+	// to a fractional scale.
+	ScaleInteger
+	// ScaleStretch fills the whole window, ignoring the aspect ratio.
+	ScaleStretch
+)
+
+// String returns the name of the scale mode.
+func (m ScaleMode) String() string {
+	switch m {
+	case ScaleFit:
+		return "Fit"
+	case ScaleInteger:
+		return "Integer"
+	case ScaleStretch:
+		return "Stretch"
+	default:
+		return "Unknown"
+	}
+}
+
+// viewport describes where the logical screen is drawn inside the window.
+type viewport struct {
+	scaleX, scaleY   float64
+	offsetX, offsetY float64
+}
+
+var (
+	// scaleMode is the active ScaleMode.
+	scaleMode = ScaleFit
+	// currentViewport maps the logical screen into the window when the engine
+	// scales it (see engineScales). It is recomputed by Layout every frame, so it follows
+	// window resizes and fullscreen switches.
+	currentViewport = viewport{scaleX: 1, scaleY: 1}
+	// logicalScreen is the offscreen image games draw to when the engine does the scaling itself.
+	logicalScreen *ebiten.Image
+)
+
+// SetScaleMode changes how the screen is scaled to the window at runtime.
+// It takes effect on the next frame.
+//
+// Example:
+//
+//	SetScaleMode(ScaleInteger) // crisp, evenly sized pixels with black bars
+func SetScaleMode(mode ScaleMode) {
+	if mode < ScaleFit || mode > ScaleStretch {
+		log.Printf("Warning: SetScaleMode() called with unknown mode %d. Ignoring.", mode)
+		return
+	}
+	scaleMode = mode
+}
+
+// GetScaleMode returns the active scale mode.
+func GetScaleMode() ScaleMode {
+	return scaleMode
+}
+
+// engineScales reports whether the engine scales the screen itself (and Layout
+// must return the window size) rather than leaving it to Ebitengine. ScaleFit
+// is left to Ebitengine, unless the bars around the screen must be drawn in
+// another color than black (see SetBorderColor).
+func engineScales() bool {
+	return scaleMode != ScaleFit || hasCustomBorder()
+}
+
+// computeViewport returns the scale and offset that draw a width x height
+// screen into an outW x outH window using the given mode.
+func computeViewport(mode ScaleMode, outW, outH, width, height int) viewport {
+	if outW <= 0 || outH <= 0 || width <= 0 || height <= 0 {
+		return viewport{scaleX: 1, scaleY: 1}
+	}
+
+	sx := float64(outW) / float64(width)
+	sy := float64(outH) / float64(height)
+
+	var scale float64
+	switch mode {
+	case ScaleStretch:
+		return viewport{scaleX: sx, scaleY: sy}
+	case ScaleInteger:
+		scale = math.Floor(math.Min(sx, sy))
+		if scale < 1 {
+			scale = math.Min(sx, sy)
+		}
+	default:
+		scale = math.Min(sx, sy)
+	}
+
+	return viewport{
+		scaleX:  scale,
+		scaleY:  scale,
+		offsetX: math.Floor((float64(outW) - float64(width)*scale) / 2),
+		offsetY: math.Floor((float64(outH) - float64(height)*scale) / 2),
+	}
+}
+
+// windowToScreen converts a position in the window to logical screen
+// coordinates, undoing the current viewport's scale and letterboxing.
+func windowToScreen(v viewport, x, y int) (int, int) {
+	if v.scaleX <= 0 || v.scaleY <= 0 {
+		return x, y
+	}
+	sx := math.Floor((float64(x) - v.offsetX) / v.scaleX)
+	sy := math.Floor((float64(y) - v.offsetY) / v.scaleY)
+	return int(sx), int(sy)
+}
+
+// beginScaledFrame returns the image the game should draw to this frame.
+// When the engine does the scaling, this is an offscreen image of the logical
+// size, cleared like Ebitengine clears the real screen.
+func beginScaledFrame(screen *ebiten.Image) *ebiten.Image {
+	if !engineScales() {
+		return screen
+	}
+
+	w, h := GetScreenWidth(), GetScreenHeight()
+	if logicalScreen == nil || logicalScreen.Bounds().Dx() != w || logicalScreen.Bounds().Dy() != h {
+		if logicalScreen != nil {
+			logicalScreen.Deallocate()
+		}
+		logicalScreen = ebiten.NewImage(w, h)
+	}
+	logicalScreen.Clear()
+	return logicalScreen
+}
+
+// endScaledFrame draws the finished logical screen into the window.
+func endScaledFrame(screen, drawn *ebiten.Image) {
+	if drawn == screen {
+		return
+	}
+
+	drawBorder(screen)
+	op := &ebiten.DrawImageOptions{}
+	op.Filter = ebiten.FilterNearest
+	op.GeoM.Scale(currentViewport.scaleX, currentViewport.scaleY)
+	op.GeoM.Translate(currentViewport.offsetX, currentViewport.offsetY)
+	screen.DrawImage(drawn, op)
+}
+
+// --- Window resizing ---
+
+var (
+	windowMutex    sync.Mutex
+	windowWidth    int  // Window width last passed to Layout, 0 before the first frame
+	windowHeight   int  // Window height last passed to Layout, 0 before the first frame
+	windowResized  bool // The window size changed and OnWindowResize has not been told yet
+	onWindowResize func(width, height int)
+)
+
+// SetOnWindowResize registers a function that is called, before the next
+// Update, whenever the window changes size (resized by the user, maximized
+// or switched to fullscreen). It receives the new window size in
+// device-independent pixels, not the logical screen size, which only
+// changes through SetResolution. Pass nil to remove it.
+//
+// Example:
+//
+//	SetOnWindowResize(func(width, height int) {
+//		log.Printf("window is now %dx%d", width, height)
+//	})
+func SetOnWindowResize(fn func(width, height int)) {
+	windowMutex.Lock()
+	defer windowMutex.Unlock()
+	onWindowResize = fn
+}
+
+// trackWindowSize records the window size passed to Layout.
+// The first size seen is the initial one and is not reported as a resize.
+func trackWindowSize(width, height int) {
+	windowMutex.Lock()
+	defer windowMutex.Unlock()
+
+	if width == windowWidth && height == windowHeight {
+		return
+	}
+	if windowWidth != 0 || windowHeight != 0 {
+		windowResized = true
+	}
+	windowWidth, windowHeight = width, height
+}
+
+// notifyWindowResize calls the OnWindowResize callback once per change.
+func notifyWindowResize() {
+	windowMutex.Lock()
+	fn := onWindowResize
+	changed := windowResized
+	w, h := windowWidth, windowHeight
+	windowResized = false
+	windowMutex.Unlock()
+
+	if changed && fn != nil {
+		fn(w, h)
+	}
+}
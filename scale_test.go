@@ -0,0 +1,106 @@
+package pigo8
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeViewport(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       ScaleMode
+		outW, outH int
+		want       viewport
+	}{
+		{"fit pillarbox", ScaleFit, 700, 512, viewport{scaleX: 4, scaleY: 4, offsetX: 94, offsetY: 0}},
+		{"fit fractional", ScaleFit, 600, 600, viewport{scaleX: 4.6875, scaleY: 4.6875}},
+		{"integer letterbox", ScaleInteger, 600, 600, viewport{scaleX: 4, scaleY: 4, offsetX: 44, offsetY: 44}},
+		{"integer wide window", ScaleInteger, 1920, 1080, viewport{scaleX: 8, scaleY: 8, offsetX: 448, offsetY: 28}},
+		{"integer smaller than screen", ScaleInteger, 64, 100, viewport{scaleX: 0.5, scaleY: 0.5, offsetX: 0, offsetY: 18}},
+		{"stretch", ScaleStretch, 256, 512, viewport{scaleX: 2, scaleY: 4}},
+		{"empty window", ScaleInteger, 0, 0, viewport{scaleX: 1, scaleY: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, computeViewport(tt.mode, tt.outW, tt.outH, 128, 128))
+		})
+	}
+}
+
+func TestWindowToScreen(t *testing.T) {
+	v := computeViewport(ScaleInteger, 600, 600, 128, 128) // scale 4, 44px bars
+
+	x, y := windowToScreen(v, 44, 44)
+	assert.Equal(t, 0, x)
+	assert.Equal(t, 0, y)
+
+	x, y = windowToScreen(v, 555, 555)
+	assert.Equal(t, 127, x)
+	assert.Equal(t, 127, y)
+
+	// Positions on the black bars land outside the screen
+	x, y = windowToScreen(v, 10, 300)
+	assert.Equal(t, -9, x)
+	assert.Equal(t, 64, y)
+}
+
+func TestSetScaleMode(t *testing.T) {
+	original := GetScaleMode()
+	t.Cleanup(func() { SetScaleMode(original) })
+
+	SetScaleMode(ScaleInteger)
+	assert.Equal(t, ScaleInteger, GetScaleMode())
+	assert.Equal(t, "Integer", GetScaleMode().String())
+
+	SetScaleMode(ScaleMode(42))
+	assert.Equal(t, ScaleInteger, GetScaleMode(), "unknown modes are ignored")
+}
+
+func TestBorderColor(t *testing.T) {
+	originalMode := GetScaleMode()
+	t.Cleanup(func() {
+		SetScaleMode(originalMode)
+		SetBorderColor(nil)
+	})
+	SetScaleMode(ScaleFit)
+
+	assert.Equal(t, color.Black, GetBorderColor())
+	assert.False(t, engineScales(), "black bars are left to Ebitengine")
+
+	SetBorderColor(color.RGBA{0, 0, 0, 255})
+	assert.False(t, engineScales(), "any opaque black is the default")
+
+	SetBorderColor(GetPaletteColor(1))
+	assert.Equal(t, GetPaletteColor(1), GetBorderColor())
+	assert.True(t, engineScales(), "a colored border is drawn by the engine")
+
+	SetBorderColor(nil)
+	assert.Equal(t, color.Black, GetBorderColor())
+	assert.False(t, engineScales())
+}
+
+func TestWindowResizeCallback(t *testing.T) {
+	t.Cleanup(func() {
+		SetOnWindowResize(nil)
+		windowWidth, windowHeight, windowResized = 0, 0, false
+	})
+	windowWidth, windowHeight, windowResized = 0, 0, false
+
+	var sizes [][2]int
+	SetOnWindowResize(func(width, height int) {
+		sizes = append(sizes, [2]int{width, height})
+	})
+
+	trackWindowSize(512, 512) // initial size, not a resize
+	notifyWindowResize()
+	assert.Empty(t, sizes)
+
+	trackWindowSize(800, 600)
+	trackWindowSize(800, 600)
+	notifyWindowResize()
+	notifyWindowResize()
+	assert.Equal(t, [][2]int{{800, 600}}, sizes)
+}
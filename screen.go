@@ -72,6 +72,10 @@ var (
 	// By default, only color 0 (black) is transparent
 	paletteTransparency = []bool{true, false, false, false, false, false, false, false, false, false, false, false, false, false, false, false}
 
+	// transparentColor is the palette index that is transparent by default.
+	// Palt() resets to it and SetPalette keeps it transparent. See SetTransparentColor.
+	transparentColor = 0
+
 	// pico8FaceSource is the loaded source for the PICO-8 TTF font.
 	pico8FaceSource *text.GoTextFaceSource
 
@@ -91,6 +95,8 @@ var (
 	pixelBufferHeight int
 	bufferDirty       bool
 	pixelBufferMutex  sync.Mutex
+	// pendingPixelsImage uploads pixelBuffer so it can be drawn over the screen
+	pendingPixelsImage *ebiten.Image
 
 	// Sprite modification batching
 	spriteModifications = make(map[*ebiten.Image][]pixelMod)
@@ -138,7 +144,7 @@ func buildColorToIndexMap() {
 // If no colorIndex is provided, it defaults to 0 (Black).
 func Cls(colorIndex ...int) {
 	if currentScreen == nil {
-		log.Println("Warning: Cls() called before screen was ready.")
+		warnScreenNotReady("Cls")
 		return
 	}
 	idx := 0 // Default to black (index 0)
@@ -171,7 +177,7 @@ func Cls(colorIndex ...int) {
 //	ClsRGBA(color.RGBA{}) // Clear with transparent black (all zeros)
 func ClsRGBA(clr color.RGBA) {
 	if currentScreen == nil {
-		log.Println("Warning: ClsRGBA() called before screen was ready.")
+		warnScreenNotReady("ClsRGBA")
 		return
 	}
 
@@ -209,7 +215,7 @@ func ClsRGBA(clr color.RGBA) {
 //	}
 func Pget(x, y int) int {
 	if currentScreen == nil {
-		log.Println("Warning: Pget() called before screen was ready.")
+		warnScreenNotReady("Pget")
 		return 0
 	}
 	bounds := currentScreen.Bounds()
@@ -218,7 +224,20 @@ func Pget(x, y int) int {
 		return 0 // PICO-8 pget returns 0 for out-of-bounds
 	}
 
-	// Try to get pixel from cache first (batch reading optimization)
+	// Pixels set with Pset this frame may not be on the screen yet
+	if index, ok := pendingPixelIndex(x, y); ok {
+		return index
+	}
+
+	// Read the whole screen back once after it changed (batch reading optimization)
+	screenCacheMutex.RLock()
+	stale := !screenCacheValid
+	screenCacheMutex.RUnlock()
+	if stale {
+		updateScreenPixelCache()
+	}
+
+	// Try to get pixel from cache first
 	screenCacheMutex.RLock()
 	if screenCacheValid && screenPixelCache != nil &&
 		x < screenPixelCacheWidth && y < screenPixelCacheHeight {
@@ -273,7 +292,7 @@ func Pget(x, y int) int {
 func Pset(x, y int, colorIndex ...int) {
 	// Check if screen is ready
 	if currentScreen == nil {
-		log.Println("Warning: Pset() called before screen was ready.")
+		warnScreenNotReady("Pset")
 		return
 	}
 
@@ -344,6 +363,189 @@ func Pset(x, y int, colorIndex ...int) {
 	setPixelInBuffer(x, y, pixelColor)
 }
 
+// pendingPixelIndex returns the color index of a pixel that was set but not
+// flushed to the screen yet.
+func pendingPixelIndex(x, y int) (int, bool) {
+	pixelBufferMutex.Lock()
+	defer pixelBufferMutex.Unlock()
+
+	if !bufferDirty || x < 0 || y < 0 || x >= pixelBufferWidth || y >= pixelBufferHeight {
+		return 0, false
+	}
+	offset := (y*pixelBufferWidth + x) * 4
+	if pixelBuffer[offset+3] == 0 {
+		return 0, false
+	}
+	index, ok := colorToIndexMap[color.RGBA{pixelBuffer[offset], pixelBuffer[offset+1], pixelBuffer[offset+2], pixelBuffer[offset+3]}]
+	return index, ok
+}
+
+// PsetRow draws a horizontal run of pixels starting at (x, y), one pixel
+// per entry in colors, in a single pass over the screen buffer. It behaves
+// like calling Pset for each pixel: the camera offset and draw palette (Pal)
+// are applied, transparent colors (Palt) are skipped and pixels outside the
+// screen are clipped. Invalid color indices are skipped with one warning.
+//
+// Example:
+//
+//	// Draw a 16-pixel gradient
+//	PsetRow(10, 20, []int{1, 1, 2, 2, 8, 8, 14, 14, 15, 15, 7, 7, 7, 7, 7, 7})
+func PsetRow(x, y int, colors []int) {
+	psetRun("PsetRow", x, y, len(colors), 1, colors)
+}
+
+// PsetRect draws a width x height block of pixels with its top-left corner at
+// (x, y). colors holds the color indices row by row (width*height entries);
+// missing entries are left undrawn. It applies the camera, draw palette,
+// transparency and clipping exactly like Pset.
+//
+// Example:
+//
+//	// Blit a software-rendered 64x64 canvas
+//	PsetRect(32, 32, 64, 64, canvas)
+func PsetRect(x, y, width, height int, colors []int) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	psetRun("PsetRect", x, y, width, height, colors)
+}
+
+// psetRun writes a width x height block of color indices to the pixel buffer.
+func psetRun(fn string, x, y, width, height int, colors []int) {
+	if currentScreen == nil {
+		warnScreenNotReady(fn)
+		return
+	}
+	if pixelBuffer == nil {
+		initPixelBuffer(GetScreenWidth(), GetScreenHeight())
+	}
+
+	// Resolve every palette entry once instead of once per pixel
+	mappedRGBA, drawable := drawColorTable()
+
+	fx, fy := applyCameraOffset(float64(x), float64(y))
+	x, y = int(fx), int(fy)
+
+	pixelBufferMutex.Lock()
+	defer pixelBufferMutex.Unlock()
+
+	invalid := -1
+	for row := 0; row < height; row++ {
+		py := y + row
+		if py < 0 || py >= pixelBufferHeight {
+			continue
+		}
+		for col := 0; col < width; col++ {
+			i := row*width + col
+			if i >= len(colors) {
+				break
+			}
+			px := x + col
+			if px < 0 || px >= pixelBufferWidth {
+				continue
+			}
+			c := colors[i]
+			if c < 0 || c >= len(drawable) {
+				invalid = c
+				continue
+			}
+			if !drawable[c] {
+				continue
+			}
+			offset := (py*pixelBufferWidth + px) * 4
+			copy(pixelBuffer[offset:offset+4], mappedRGBA[c][:])
+			bufferDirty = true
+		}
+	}
+
+	if invalid != -1 {
+		log.Printf("Warning: %s() called with invalid color index %d. Draw palette map has %d entries. Skipped.", fn, invalid, len(drawPaletteMap))
+	}
+}
+
+// drawColorTable returns, for every color index, the RGBA bytes Pset would
+// write after the draw palette and whether the color is drawn at all
+// (false for transparent or unmapped colors).
+func drawColorTable() ([][4]byte, []bool) {
+	rgba := make([][4]byte, len(drawPaletteMap))
+	drawable := make([]bool, len(drawPaletteMap))
+	for c, mapped := range drawPaletteMap {
+		if mapped < 0 || mapped >= len(pico8Palette) {
+			continue
+		}
+		if mapped < len(paletteTransparency) && paletteTransparency[mapped] {
+			continue
+		}
+		r, g, b, a := pico8Palette[mapped].RGBA()
+		rgba[c] = [4]byte{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+		drawable[c] = true
+	}
+	return rgba, drawable
+}
+
+// PgetRow returns the color indices of length pixels starting at (x, y),
+// read in one pass from the screen pixel cache. Like Pget, pixels outside
+// the screen read as 0.
+//
+// Example:
+//
+//	// Copy a line of the screen one pixel down
+//	PsetRow(0, 11, PgetRow(0, 10, 128))
+func PgetRow(x, y, length int) []int {
+	if length <= 0 {
+		return nil
+	}
+	result := make([]int, length)
+	if currentScreen == nil {
+		warnScreenNotReady("PgetRow")
+		return result
+	}
+
+	screenCacheMutex.RLock()
+	stale := !screenCacheValid
+	screenCacheMutex.RUnlock()
+	if stale {
+		updateScreenPixelCache()
+	}
+
+	screenCacheMutex.RLock()
+	readPixelRow(screenPixelCache, screenPixelCacheWidth, screenPixelCacheHeight, x, y, result, false)
+	screenCacheMutex.RUnlock()
+
+	// Pixels set with Pset this frame may not be on the screen yet
+	pixelBufferMutex.Lock()
+	if bufferDirty {
+		readPixelRow(pixelBuffer, pixelBufferWidth, pixelBufferHeight, x, y, result, true)
+	}
+	pixelBufferMutex.Unlock()
+	return result
+}
+
+// readPixelRow fills out with the palette indices of a row of RGBA pixels.
+// With onlySet, fully transparent pixels leave out unchanged.
+func readPixelRow(pixels []byte, width, height, x, y int, out []int, onlySet bool) {
+	if y < 0 || y >= height {
+		return
+	}
+	for i := range out {
+		px := x + i
+		if px < 0 || px >= width {
+			continue
+		}
+		offset := (y*width + px) * 4
+		if offset+3 >= len(pixels) {
+			return
+		}
+		if onlySet && pixels[offset+3] == 0 {
+			continue
+		}
+		pixelColor := color.RGBA{pixels[offset], pixels[offset+1], pixels[offset+2], pixels[offset+3]}
+		if index, ok := colorToIndexMap[pixelColor]; ok {
+			out[i] = index
+		}
+	}
+}
+
 const (
 	// CharWidthApproximation approximates character width for PICO-8 font for measurement.
 	CharWidthApproximation = 4.0
@@ -423,7 +625,7 @@ func Print(s any, args ...int) (int, int) {
 
 	// Check if screen is ready
 	if currentScreen == nil {
-		log.Println("Warning: Print() called before screen was ready.")
+		warnScreenNotReady("Print")
 
 		// Calculate return values based on arguments without changing cursor state
 		posX, posY := cursorX, cursorY
@@ -482,6 +684,7 @@ func Print(s any, args ...int) (int, int) {
 	endY := posY + int(defaultFontSize)
 
 	// --- Draw ---
+	beforeScreenDraw()
 	text.Draw(currentScreen, str, face, op)
 
 	// --- Update Cursor Position ---
@@ -606,7 +809,8 @@ func Pal(args ...interface{}) {
 
 // Palt sets the transparency for a specific color in the palette.
 // When called with no arguments, it resets all colors to default transparency
-// (only black is transparent).
+// (only the default transparent color, black unless changed with
+// SetTransparentColor, is transparent).
 //
 // Args:
 //   - color: A color number from the PICO-8 palette (0-15).
@@ -621,10 +825,7 @@ func Pal(args ...interface{}) {
 func Palt(args ...interface{}) {
 	// If called with no arguments, reset to default transparency settings
 	if len(args) == 0 {
-		// Default: only black (color 0) is transparent
-		for i := range paletteTransparency {
-			paletteTransparency[i] = (i == 0)
-		}
+		resetPaletteTransparency()
 		return
 	}
 
@@ -662,11 +863,77 @@ func Palt(args ...interface{}) {
 	paletteTransparency[colorIndex] = transparent
 }
 
+// SetTransparentColor changes which palette index is transparent by default
+// (0 unless changed). Only that color is made transparent right away, Palt()
+// resets to it and SetPalette keeps it transparent. Use it with palettes whose
+// index 0 is a real, opaque color.
+//
+// Args:
+//   - colorIndex: palette index to treat as transparent.
+//
+// Example:
+//
+//	SetPalette(myPalette)   // index 0 is dark blue, index 3 is the backdrop
+//	SetTransparentColor(3)  // sprites now draw index 0 and hide index 3
+//	Palt(5, true)           // temporarily hide color 5 as well
+//	Palt()                  // back to only color 3 being transparent
+func SetTransparentColor(colorIndex int) {
+	if colorIndex < 0 || colorIndex >= len(paletteTransparency) {
+		log.Printf("Warning: SetTransparentColor() called with out-of-range color index: %d", colorIndex)
+		return
+	}
+	transparentColor = colorIndex
+	resetPaletteTransparency()
+}
+
+// GetTransparentColor returns the palette index that is transparent by default.
+//
+// Example:
+//
+//	Palt(GetTransparentColor(), false) // draw the next sprite fully opaque
+//	Spr(1, 10, 10)
+//	Palt()
+func GetTransparentColor() int {
+	return transparentColor
+}
+
+// IsColorTransparent reports whether a palette color is currently transparent
+// (see Palt). Out-of-range colors report false.
+//
+// Example:
+//
+//	wasTransparent := IsColorTransparent(4)
+//	Palt(4, true)
+//	Spr(1, 10, 10)
+//	Palt(4, wasTransparent) // restore only what we changed
+func IsColorTransparent(colorIndex int) bool {
+	if colorIndex < 0 || colorIndex >= len(paletteTransparency) {
+		return false
+	}
+	return paletteTransparency[colorIndex]
+}
+
+// resetPaletteTransparency makes only the default transparent color transparent.
+func resetPaletteTransparency() {
+	for i := range paletteTransparency {
+		paletteTransparency[i] = (i == transparentColor)
+	}
+}
+
+// paletteColorsChanged drops every image that was built with the old
+// palette colors, so the next Spr or Map call rebuilds it. Transparency
+// changes (see Palt) need no call: the caches are keyed by it.
+func paletteColorsChanged() {
+	ClearSpriteCache()
+	mapCacheIsValid = false
+}
+
 // --- Palette Management Functions ---
 
 // SetPalette replaces the current color palette with a new one.
 // This also resizes the transparency array to match the new palette size,
-// setting only the first color (index 0) as transparent by default.
+// keeping the default transparent color (index 0 unless changed with
+// SetTransparentColor) transparent.
 //
 // newPalette: Slice of color.Color values to use as the new palette.
 //
@@ -698,9 +965,14 @@ func SetPalette(newPalette []color.Color) {
 		paletteTransparency[i] = oldTransparency[i]
 	}
 
-	// Ensure at least the first color is transparent if we have any colors
+	// Keep the default transparent color transparent, falling back to index 0
+	// if the new palette is too small to hold it
 	if len(paletteTransparency) > 0 {
-		paletteTransparency[0] = true
+		if transparentColor >= len(paletteTransparency) {
+			transparentColor = 0
+		}
+		paletteTransparency[transparentColor] = true
+		paletteColorsChanged()
 
 		// Resize and reset draw palette map as well
 		drawPaletteMap = make([]int, len(newPalette))
@@ -750,12 +1022,80 @@ func GetPaletteColor(colorIndex int) color.Color {
 func SetPaletteColor(colorIndex int, newColor color.Color) {
 	if colorIndex >= 0 && colorIndex < len(pico8Palette) {
 		pico8Palette[colorIndex] = newColor
+		paletteColorsChanged()
 	} else {
 		log.Printf("Warning: Attempted to set color at out-of-range index %d. Palette has %d colors.",
 			colorIndex, len(pico8Palette))
 	}
 }
 
+// GetPalette returns a copy of the current palette. Changing the returned
+// slice does not change the palette; pass it to SetPalette for that.
+//
+// Example:
+//
+//	saved := GetPalette()
+//	SetPalette(grayscale)
+//	// ... later
+//	SetPalette(saved)
+func GetPalette() []color.Color {
+	return append([]color.Color(nil), pico8Palette...)
+}
+
+// paletteSnapshot is a saved palette and its transparency state, see PushPalette.
+type paletteSnapshot struct {
+	palette          []color.Color
+	transparency     []bool
+	transparentColor int
+}
+
+// paletteStack holds the snapshots saved by PushPalette.
+var paletteStack []paletteSnapshot
+
+// PushPalette saves the current palette and transparency settings (Palt and
+// SetTransparentColor) so PopPalette can restore them. Pushes nest, which
+// lets layered effects each undo only their own changes.
+//
+// Example:
+//
+//	// Flash everything white for one frame
+//	PushPalette()
+//	for i := 0; i < GetPaletteSize(); i++ {
+//		SetPaletteColor(i, color.RGBA{255, 255, 255, 255})
+//	}
+//	Spr(1, x, y)
+//	PopPalette()
+func PushPalette() {
+	paletteStack = append(paletteStack, paletteSnapshot{
+		palette:          append([]color.Color(nil), pico8Palette...),
+		transparency:     append([]bool(nil), paletteTransparency...),
+		transparentColor: transparentColor,
+	})
+}
+
+// PopPalette restores the palette and transparency settings saved by the
+// last PushPalette. Draw palette mappings set with Pal() are kept unless the
+// palette size changed. Calling it without a matching PushPalette logs a
+// warning and does nothing.
+func PopPalette() {
+	if len(paletteStack) == 0 {
+		log.Printf("Warning: PopPalette() called without a matching PushPalette()")
+		return
+	}
+	snapshot := paletteStack[len(paletteStack)-1]
+	paletteStack = paletteStack[:len(paletteStack)-1]
+
+	sizeChanged := len(snapshot.palette) != len(pico8Palette)
+	pico8Palette = snapshot.palette
+	paletteTransparency = snapshot.transparency
+	transparentColor = snapshot.transparentColor
+	if sizeChanged {
+		drawPaletteMap = make([]int, len(pico8Palette))
+		resetDrawPaletteMapInternal()
+	}
+	paletteColorsChanged()
+}
+
 // --- Transparency Functions ---
 
 // No alpha transparency functions needed - using only binary transparency
@@ -795,18 +1135,89 @@ func initPixelBuffer(width, height int) {
 	initScreenPixelCache(width, height)
 }
 
-// flushPixelBuffer uploads all pending pixel changes to the GPU
+// flushPixelBuffer uploads all pending pixel changes to the GPU.
+// Pending pixels are drawn over the screen, so everything drawn with other
+// functions stays where no pixel was set, and the buffer starts empty again.
 func flushPixelBuffer() {
 	pixelBufferMutex.Lock()
 	defer pixelBufferMutex.Unlock()
 
 	if bufferDirty && currentScreen != nil && len(pixelBuffer) > 0 {
-		currentScreen.WritePixels(pixelBuffer)
+		if pendingPixelsImage == nil || pendingPixelsImage.Bounds().Dx() != pixelBufferWidth || pendingPixelsImage.Bounds().Dy() != pixelBufferHeight {
+			pendingPixelsImage = ebiten.NewImage(pixelBufferWidth, pixelBufferHeight)
+		}
+		pendingPixelsImage.WritePixels(pixelBuffer)
+		currentScreen.DrawImage(pendingPixelsImage, nil)
+		countDrawCall()
+		clear(pixelBuffer)
 		bufferDirty = false
 
-		// Update screen pixel cache after flushing
-		updateScreenPixelCache()
+		// The screen changed; Pget reads it back lazily
+		invalidateScreenPixelCache()
+	}
+}
+
+// PixelFlushMode controls when pixels written with Pset, PsetRow and
+// PsetRect are uploaded to the screen. Until then they wait in a CPU-side
+// buffer, which is what makes thousands of Pset calls per frame cheap.
+type PixelFlushMode int
+
+const (
+	// FlushBeforeDraw uploads pending pixels before anything else is drawn
+	// (Spr, Sspr, Map, Print and the shape functions) and at the end of the
+	// frame, so the drawing order of the frame is always kept. This is the default.
+	FlushBeforeDraw PixelFlushMode = iota
+	// FlushDeferred uploads pending pixels only at the end of the frame or when
+	// FlushPixelCache is called. It avoids an upload per draw call when Pset
+	// and other drawing are interleaved, but pixels that are still pending
+	// end up on top of whatever was drawn after them.
+	FlushDeferred
+)
+
+// pixelFlushMode is the active PixelFlushMode.
+var pixelFlushMode = FlushBeforeDraw
+
+// SetPixelFlushMode changes when pending Pset pixels are uploaded to the
+// screen, see PixelFlushMode.
+//
+// With FlushDeferred, call FlushPixelCache wherever the drawing order
+// matters, e.g. before drawing sprites on top of a software-rendered
+// background:
+//
+//	SetPixelFlushMode(FlushDeferred)
+//	// ... thousands of Pset calls for the background ...
+//	FlushPixelCache()
+//	Spr(1, playerX, playerY) // drawn on top of the background
+func SetPixelFlushMode(mode PixelFlushMode) {
+	if mode < FlushBeforeDraw || mode > FlushDeferred {
+		log.Printf("Warning: SetPixelFlushMode() called with unknown mode %d. Ignoring.", mode)
+		return
+	}
+	pixelFlushMode = mode
+}
+
+// GetPixelFlushMode returns the active pixel flush mode.
+func GetPixelFlushMode() PixelFlushMode {
+	return pixelFlushMode
+}
+
+// FlushPixelCache uploads all pending Pset/PsetRow/PsetRect pixels to the
+// screen now. Pget always sees pending pixels, so a flush is only needed to
+// control drawing order in FlushDeferred mode, or before reading the screen
+// directly through CurrentScreen.
+func FlushPixelCache() {
+	flushPixelBuffer()
+}
+
+// beforeScreenDraw must be called by every function that draws on the
+// screen on the GPU. It keeps the drawing order (in FlushBeforeDraw mode),
+// marks the screen pixel cache as stale and counts the draw call.
+func beforeScreenDraw() {
+	if pixelFlushMode == FlushBeforeDraw {
+		flushPixelBuffer()
 	}
+	invalidateScreenPixelCache()
+	countDrawCall()
 }
 
 // setPixelInBuffer sets a pixel in the buffer without immediate GPU upload
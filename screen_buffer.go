@@ -0,0 +1,151 @@
+package pigo8
+
+import "log"
+
+// --- Whole screen readback ---
+
+// ScreenBuffer holds the color indices of the whole screen, filled by
+// SnapshotScreen and drawn back with WriteScreen. The pixel at (x, y) is
+// Pix[y*Width+x].
+type ScreenBuffer struct {
+	Width, Height int
+	Pix           []int
+}
+
+// At returns the color index at (x, y), or 0 outside the buffer like Pget.
+func (b *ScreenBuffer) At(x, y int) int {
+	if x < 0 || y < 0 || x >= b.Width || y >= b.Height {
+		return 0
+	}
+	return b.Pix[y*b.Width+x]
+}
+
+// Set changes the color index at (x, y). Pixels outside the buffer are
+// ignored.
+func (b *ScreenBuffer) Set(x, y, colorIndex int) {
+	if x < 0 || y < 0 || x >= b.Width || y >= b.Height {
+		return
+	}
+	b.Pix[y*b.Width+x] = colorIndex
+}
+
+// SnapshotScreen reads the whole screen drawn so far this frame into buf and
+// returns it, for full-screen effects in Go such as dissolves, water ripples
+// or distortions. Pass the buffer of the previous call to reuse its memory,
+// or nil for a new one; it is resized if the screen size changed. Like Pget,
+// colors outside the palette read as 0.
+//
+// It reads the screen back from the GPU in one operation, through the same
+// cache as Pget, which is relatively expensive: call it at most once per
+// frame, usually at the end of Draw followed by WriteScreen.
+//
+// Example:
+//
+//	var buf *ScreenBuffer
+//
+//	func (g *Game) Draw() {
+//		// ... draw the scene
+//		buf = SnapshotScreen(buf)
+//		for y := 0; y < buf.Height; y++ {
+//			shift := int(2 * math.Sin(float64(y)/8+T()*4))
+//			copy(g.row, buf.Pix[y*buf.Width:(y+1)*buf.Width])
+//			for x := range g.row {
+//				buf.Set(x, y, g.row[(x+shift+buf.Width)%buf.Width])
+//			}
+//		}
+//		WriteScreen(buf)
+//	}
+func SnapshotScreen(buf *ScreenBuffer) *ScreenBuffer {
+	width, height := GetScreenWidth(), GetScreenHeight()
+	if buf == nil {
+		buf = &ScreenBuffer{}
+	}
+	if buf.Width != width || buf.Height != height || len(buf.Pix) != width*height {
+		buf.Width, buf.Height = width, height
+		buf.Pix = make([]int, width*height)
+	} else {
+		clear(buf.Pix)
+	}
+	if currentScreen == nil {
+		warnScreenNotReady("SnapshotScreen")
+		return buf
+	}
+	if pixelBuffer == nil {
+		initPixelBuffer(width, height)
+	}
+
+	screenCacheMutex.RLock()
+	stale := !screenCacheValid
+	screenCacheMutex.RUnlock()
+	if stale {
+		updateScreenPixelCache()
+	}
+
+	screenCacheMutex.RLock()
+	for y := 0; y < height; y++ {
+		readPixelRow(screenPixelCache, screenPixelCacheWidth, screenPixelCacheHeight, 0, y, buf.Pix[y*width:(y+1)*width], false)
+	}
+	screenCacheMutex.RUnlock()
+
+	// Pixels set with Pset this frame may not be on the screen yet
+	pixelBufferMutex.Lock()
+	if bufferDirty {
+		for y := 0; y < height; y++ {
+			readPixelRow(pixelBuffer, pixelBufferWidth, pixelBufferHeight, 0, y, buf.Pix[y*width:(y+1)*width], true)
+		}
+	}
+	pixelBufferMutex.Unlock()
+	return buf
+}
+
+// WriteScreen draws a buffer from SnapshotScreen back to the screen, with its
+// top-left corner at (0, 0). Unlike PsetRect it writes the colors as they
+// are: the camera, the draw palette (Pal) and transparency (Palt) are
+// ignored, so a snapshot written back unchanged leaves the screen as it was.
+// Invalid color indices are skipped with one warning. Like Pset, the pixels
+// reach the screen when the frame ends, over anything drawn in between.
+func WriteScreen(buf *ScreenBuffer) {
+	if buf == nil {
+		return
+	}
+	if currentScreen == nil {
+		warnScreenNotReady("WriteScreen")
+		return
+	}
+	if pixelBuffer == nil {
+		initPixelBuffer(GetScreenWidth(), GetScreenHeight())
+	}
+
+	// Resolve every palette color once instead of once per pixel
+	rgba := make([][4]byte, len(pico8Palette))
+	for i, c := range pico8Palette {
+		r, g, b, a := c.RGBA()
+		rgba[i] = [4]byte{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+	}
+
+	pixelBufferMutex.Lock()
+	defer pixelBufferMutex.Unlock()
+
+	invalid := -1
+	width, height := min(buf.Width, pixelBufferWidth), min(buf.Height, pixelBufferHeight)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*buf.Width + x
+			if i >= len(buf.Pix) {
+				break
+			}
+			c := buf.Pix[i]
+			if c < 0 || c >= len(rgba) {
+				invalid = c
+				continue
+			}
+			offset := (y*pixelBufferWidth + x) * 4
+			copy(pixelBuffer[offset:offset+4], rgba[c][:])
+			bufferDirty = true
+		}
+	}
+
+	if invalid != -1 {
+		log.Printf("Warning: WriteScreen() called with invalid color index %d. Palette has %d colors. Skipped.", invalid, len(rgba))
+	}
+}
@@ -0,0 +1,77 @@
+package pigo8
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useFakeScreenCache fills the screen pixel cache with color 1 and paints
+// (3, 4) with color 12, as if the screen had been read back from the GPU.
+func useFakeScreenCache(t *testing.T) {
+	t.Helper()
+	setupRowTest(t)
+	savedCache, savedW, savedH := screenPixelCache, screenPixelCacheWidth, screenPixelCacheHeight
+	t.Cleanup(func() {
+		screenPixelCache, screenPixelCacheWidth, screenPixelCacheHeight = savedCache, savedW, savedH
+		clearPixelBuffer()
+	})
+
+	initScreenPixelCache(16, 16)
+	paint := func(x, y int, c color.Color) {
+		r, g, b, a := c.RGBA()
+		copy(screenPixelCache[(y*16+x)*4:], []byte{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)})
+	}
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			paint(x, y, pico8Palette[1])
+		}
+	}
+	paint(3, 4, pico8Palette[12])
+	screenCacheMutex.Lock()
+	screenCacheValid = true
+	screenCacheMutex.Unlock()
+}
+
+func TestSnapshotScreen(t *testing.T) {
+	useFakeScreenCache(t)
+	Pset(5, 5, 8) // Not on the screen until the frame ends
+
+	buf := SnapshotScreen(nil)
+	require.Equal(t, 16, buf.Width)
+	require.Equal(t, 16, buf.Height)
+	require.Len(t, buf.Pix, 16*16)
+	assert.Equal(t, 12, buf.At(3, 4))
+	assert.Equal(t, 8, buf.At(5, 5), "pending Pset pixels are included")
+	assert.Equal(t, 1, buf.At(0, 0))
+	assert.Equal(t, 0, buf.At(-1, 0), "outside the buffer reads as 0")
+
+	pix := buf.Pix
+	buf.Set(0, 0, 9)
+	buf.Set(99, 0, 9)
+	again := SnapshotScreen(buf)
+	assert.Same(t, buf, again, "the buffer is reused")
+	assert.Equal(t, &pix[0], &again.Pix[0])
+	assert.Equal(t, 1, again.At(0, 0), "every pixel is read again")
+}
+
+func TestWriteScreen(t *testing.T) {
+	useFakeScreenCache(t)
+	Camera(4, 4)
+	Pal(7, 8)
+	t.Cleanup(func() { Pal() })
+
+	buf := SnapshotScreen(nil)
+	buf.Set(0, 0, 7)
+	buf.Set(1, 0, 0)
+	buf.Set(2, 0, 99)
+	WriteScreen(buf)
+
+	assert.Equal(t, 7, bufferColorAt(0, 0), "the camera and Pal are ignored")
+	assert.Equal(t, 0, bufferColorAt(1, 0), "transparent colors are written too")
+	assert.Equal(t, -1, bufferColorAt(2, 0), "invalid colors are skipped")
+	assert.Equal(t, 12, bufferColorAt(3, 4))
+	assert.Equal(t, 1, bufferColorAt(15, 15))
+}
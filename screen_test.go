@@ -1,6 +1,7 @@
 package pigo8
 
 import (
+	"image/color"
 	"math"
 	"testing"
 
@@ -479,3 +480,96 @@ func TestPalt(t *testing.T) {
 		assert.True(t, paletteTransparency[8], "Color 8 should be transparent (from float 8.7)")
 	})
 }
+
+func TestIsColorTransparent(t *testing.T) {
+	originalTransparency := append([]bool(nil), paletteTransparency...)
+	t.Cleanup(func() {
+		paletteTransparency = originalTransparency
+	})
+
+	Palt()
+	assert.True(t, IsColorTransparent(0), "Color 0 should be transparent by default")
+	assert.False(t, IsColorTransparent(4), "Color 4 should be opaque by default")
+
+	Palt(4, true)
+	assert.True(t, IsColorTransparent(4), "Color 4 should be transparent after Palt(4, true)")
+
+	// Palt() with no arguments restores the PICO-8 default
+	Palt()
+	assert.False(t, IsColorTransparent(4), "Color 4 should be opaque after reset")
+	assert.True(t, IsColorTransparent(0), "Color 0 should be transparent after reset")
+
+	assert.False(t, IsColorTransparent(-1))
+	assert.False(t, IsColorTransparent(99))
+}
+
+func TestSetTransparentColor(t *testing.T) {
+	originalTransparency := append([]bool(nil), paletteTransparency...)
+	originalColor := transparentColor
+	t.Cleanup(func() {
+		paletteTransparency = originalTransparency
+		transparentColor = originalColor
+	})
+
+	SetTransparentColor(3)
+	assert.Equal(t, 3, GetTransparentColor())
+	for i := range paletteTransparency {
+		assert.Equal(t, i == 3, paletteTransparency[i], "only color 3 should be transparent (color %d)", i)
+	}
+
+	// Palt() resets to the configured color, not to 0
+	Palt(0, true)
+	Palt()
+	assert.False(t, paletteTransparency[0], "Color 0 should be opaque after reset")
+	assert.True(t, paletteTransparency[3], "Color 3 should be transparent after reset")
+
+	// Out-of-range colors are ignored
+	SetTransparentColor(99)
+	assert.Equal(t, 3, GetTransparentColor())
+}
+
+func TestPushPopPalette(t *testing.T) {
+	originalPalette := pico8Palette
+	originalTransparency := append([]bool(nil), paletteTransparency...)
+	originalColor := transparentColor
+	originalDrawMap := append([]int(nil), drawPaletteMap...)
+	t.Cleanup(func() {
+		pico8Palette = originalPalette
+		paletteTransparency = originalTransparency
+		transparentColor = originalColor
+		drawPaletteMap = originalDrawMap
+		paletteStack = nil
+	})
+	pico8Palette = append([]color.Color(nil), originalPico8Palette...)
+
+	// GetPalette returns a copy
+	palette := GetPalette()
+	palette[7] = color.RGBA{1, 2, 3, 255}
+	assert.Equal(t, originalPico8Palette[7], GetPaletteColor(7), "changing the copy must not change the palette")
+
+	PushPalette()
+	SetPaletteColor(7, color.RGBA{1, 2, 3, 255})
+	Palt(4, true)
+	SetTransparentColor(2)
+
+	// Nested push of a smaller palette
+	PushPalette()
+	SetPalette([]color.Color{color.Black, color.White})
+	assert.Equal(t, 2, GetPaletteSize())
+
+	PopPalette()
+	assert.Equal(t, 16, GetPaletteSize())
+	assert.Equal(t, color.RGBA{1, 2, 3, 255}, GetPaletteColor(7))
+	assert.Equal(t, 2, GetTransparentColor())
+	assert.Len(t, drawPaletteMap, 16, "draw palette map follows the palette size")
+
+	PopPalette()
+	assert.Equal(t, originalPico8Palette[7], GetPaletteColor(7))
+	assert.False(t, IsColorTransparent(4))
+	assert.True(t, IsColorTransparent(originalColor))
+	assert.Equal(t, originalColor, GetTransparentColor())
+
+	// Unbalanced pops are ignored
+	PopPalette()
+	assert.Equal(t, 16, GetPaletteSize())
+}
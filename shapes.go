@@ -91,9 +91,10 @@ func parseRectArgs(x1, y1, x2, y2 float64, options []interface{}) (float32, floa
 //     uses the current drawing color (defaults to 7 - white currently).
 func Rect[X1 Number, Y1 Number, X2 Number, Y2 Number](x1 X1, y1 Y1, x2 X2, y2 Y2, options ...interface{}) {
 	if currentScreen == nil {
-		log.Println("Warning: Rect() called before screen was ready.")
+		warnScreenNotReady("Rect")
 		return
 	}
+	beforeScreenDraw()
 
 	fx1, fy1, fx2, fy2 := float64(x1), float64(y1), float64(x2), float64(y2)
 
@@ -160,9 +161,10 @@ func Rect[X1 Number, Y1 Number, X2 Number, Y2 Number](x1 X1, y1 Y1, x2 X2, y2 Y2
 //     uses the current drawing color (defaults to 7 - white currently).
 func Rectfill[X1 Number, Y1 Number, X2 Number, Y2 Number](x1 X1, y1 Y1, x2 X2, y2 Y2, options ...interface{}) {
 	if currentScreen == nil {
-		log.Println("Warning: Rectfill() called before screen was ready.")
+		warnScreenNotReady("Rectfill")
 		return
 	}
+	beforeScreenDraw()
 
 	fx1, fy1, fx2, fy2 := float64(x1), float64(y1), float64(x2), float64(y2)
 
@@ -217,8 +219,9 @@ func Rectfill[X1 Number, Y1 Number, X2 Number, Y2 Number](x1 X1, y1 Y1, x2 X2, y
 }
 
 // parseLineArgs parses common arguments for Line function.
-// It returns the PICO-8 color index to use and whether parsing was successful.
-func parseLineArgs(options []interface{}) (int, bool) {
+// It returns the PICO-8 color index to use, the line thickness and whether
+// parsing was successful.
+func parseLineArgs(options []interface{}) (int, float32, bool) {
 	// Determine drawing color
 	drawColorIndex := currentDrawColor // Use the global current draw color set by Color()
 	if len(options) >= 1 {
@@ -257,11 +260,37 @@ func parseLineArgs(options []interface{}) (int, bool) {
 			logWarningOnce("Warning: Line optional color argument expected numeric type, got %T. Using current color %d.", options[0], drawColorIndex)
 		}
 	}
-	if len(options) > 1 {
-		logWarningOnce("Warning: Line called with too many arguments (%d), expected max 5.", len(options)+4)
+	thickness := float32(1)
+	if len(options) >= 2 {
+		thickness = parseThickness("Line", options[1])
+	}
+	if len(options) > 2 {
+		logWarningOnce("Warning: Line called with too many arguments (%d), expected max 6.", len(options)+4)
 	}
 
-	return drawColorIndex, true
+	return drawColorIndex, thickness, true
+}
+
+// parseThickness parses the optional thickness argument of Line and Circ.
+// Thicknesses below 1 (or not numeric) fall back to 1 with a warning.
+func parseThickness(fn string, v interface{}) float32 {
+	var thickness float64
+	switch t := v.(type) {
+	case int:
+		thickness = float64(t)
+	case float64:
+		thickness = t
+	case float32:
+		thickness = float64(t)
+	default:
+		logWarningOnce("Warning: %s optional thickness argument expected numeric type, got %T. Using 1.", fn, v)
+		return 1
+	}
+	if thickness < 1 {
+		logWarningOnce("Warning: %s thickness %v is less than 1. Using 1.", fn, thickness)
+		return 1
+	}
+	return float32(math.Round(thickness))
 }
 
 // Line draws a line between two points.
@@ -272,23 +301,41 @@ func parseLineArgs(options []interface{}) (int, bool) {
 // options...:
 //   - color (int): Optional PICO-8 color index (0-15). If omitted or invalid,
 //     uses the current drawing color (defaults to 7 - white currently).
+//   - thickness (int): Optional width of the line in pixels (default 1). Lines
+//     thicker than 1 pixel are centered on the path and have round caps, so
+//     lines joined end to end form a solid outline without gaps.
+//
+// A dash pattern made with WithDash can be passed anywhere after y2 to draw a
+// dashed or dotted line; it is not counted as color or thickness.
+//
+// Example:
+//
+//	Line(10, 10, 100, 40, 8)    // 1 pixel wide red line
+//	Line(10, 50, 100, 80, 8, 3) // The same line, 3 pixels wide
+//	Line(64, 0, 64, 127, 5, WithDash(4, 4)) // Dashed center line
 func Line[X1 Number, Y1 Number, X2 Number, Y2 Number](x1 X1, y1 Y1, x2 X2, y2 Y2, options ...interface{}) {
 	if currentScreen == nil {
-		log.Println("Warning: Line() called before screen was ready.")
+		warnScreenNotReady("Line")
 		return
 	}
+	beforeScreenDraw()
 
 	// Convert to float64 for calculations
 	fx1, fy1, fx2, fy2 := float64(x1), float64(y1), float64(x2), float64(y2)
 
+	// Apply camera offset
+	fx1, fy1 = applyCameraOffset(fx1, fy1)
+	fx2, fy2 = applyCameraOffset(fx2, fy2)
+
 	// Round to nearest integer for pixel-perfect rendering
 	fx1 = math.Round(fx1)
 	fy1 = math.Round(fy1)
 	fx2 = math.Round(fx2)
 	fy2 = math.Round(fy2)
 
-	// Parse optional color argument
-	drawColorIndex, ok := parseLineArgs(options)
+	// Parse optional color and thickness arguments
+	options, dash := splitLineDash(options)
+	drawColorIndex, thickness, ok := parseLineArgs(options)
 	if !ok {
 		return // Argument parsing logged an issue
 	}
@@ -302,6 +349,11 @@ func Line[X1 Number, Y1 Number, X2 Number, Y2 Number](x1 X1, y1 Y1, x2 X2, y2 Y2
 		log.Printf("Error: Invalid effective drawing color index %d for Line(). Defaulting to black.", drawColorIndex)
 	}
 
+	if dash != nil {
+		drawDashedLine(fx1, fy1, fx2, fy2, *dash, thickness, actualColor)
+		return
+	}
+
 	// Draw the line using Ebitengine's vector package
 	vector.StrokeLine(
 		currentScreen,
@@ -309,16 +361,22 @@ func Line[X1 Number, Y1 Number, X2 Number, Y2 Number](x1 X1, y1 Y1, x2 X2, y2 Y2
 		float32(fy1),
 		float32(fx2),
 		float32(fy2),
-		1.0, // Line width of 1 pixel to match PICO-8
+		thickness, // 1 pixel by default to match PICO-8
 		actualColor,
 		false, // No anti-aliasing to match PICO-8's pixel-perfect style
 	)
+
+	// Round the caps of thick lines
+	if thickness > 1 {
+		vector.DrawFilledCircle(currentScreen, float32(fx1), float32(fy1), thickness/2, actualColor, false)
+		vector.DrawFilledCircle(currentScreen, float32(fx2), float32(fy2), thickness/2, actualColor, false)
+	}
 }
 
 // parseCircArgs parses common arguments for Circ and Circfill.
 // It returns the center coordinates (x, y), radius, the PICO-8 color index to use,
-// and whether parsing was successful.
-func parseCircArgs(x, y, radius float64, options []interface{}) (float32, float32, float32, int, bool) {
+// the outline thickness and whether parsing was successful.
+func parseCircArgs(x, y, radius float64, options []interface{}) (float32, float32, float32, int, float32, bool) {
 	// Determine drawing color
 	drawColorIndex := currentDrawColor // Use the global current draw color set by Color()
 	if len(options) >= 1 {
@@ -360,11 +418,15 @@ func parseCircArgs(x, y, radius float64, options []interface{}) (float32, float3
 			logWarningOnce("Warning: Circ/Circfill optional color argument expected numeric type, got %T. Using current color %d.", options[0], drawColorIndex)
 		}
 	}
-	if len(options) > 1 {
-		logWarningOnce("Warning: Circ/Circfill called with too many arguments (%d), expected max 4.", len(options)+3)
+	thickness := float32(1)
+	if len(options) >= 2 {
+		thickness = parseThickness("Circ", options[1])
+	}
+	if len(options) > 2 {
+		logWarningOnce("Warning: Circ/Circfill called with too many arguments (%d), expected max 5.", len(options)+3)
 	}
 
-	return float32(x), float32(y), float32(radius), drawColorIndex, true
+	return float32(x), float32(y), float32(radius), drawColorIndex, thickness, true
 }
 
 // Circ draws an outline circle.
@@ -375,11 +437,20 @@ func parseCircArgs(x, y, radius float64, options []interface{}) (float32, float3
 // options...:
 //   - color (int): Optional PICO-8 color index (0-15). If omitted or invalid,
 //     uses the current drawing color (defaults to 7 - white currently).
+//   - thickness (int): Optional width of the outline in pixels (default 1).
+//     The outline is centered on the radius, so it grows both inwards and
+//     outwards.
+//
+// Example:
+//
+//	Circ(64, 64, 20, 12)    // 1 pixel wide blue ring
+//	Circ(64, 64, 20, 12, 4) // The same ring, 4 pixels wide
 func Circ[X Number, Y Number, R Number](x X, y Y, radius R, options ...interface{}) {
 	if currentScreen == nil {
-		log.Println("Warning: Circ() called before screen was ready.")
+		warnScreenNotReady("Circ")
 		return
 	}
+	beforeScreenDraw()
 
 	fx, fy, fr := float64(x), float64(y), float64(radius)
 
@@ -390,7 +461,7 @@ func Circ[X Number, Y Number, R Number](x X, y Y, radius R, options ...interface
 	fx = math.Round(fx)
 	fy = math.Round(fy)
 
-	circX, circY, circR, drawColorIndex, ok := parseCircArgs(fx, fy, fr, options)
+	circX, circY, circR, drawColorIndex, thickness, ok := parseCircArgs(fx, fy, fr, options)
 	if !ok {
 		return // Argument parsing logged an issue
 	}
@@ -410,7 +481,7 @@ func Circ[X Number, Y Number, R Number](x X, y Y, radius R, options ...interface
 		circX,
 		circY,
 		circR,
-		1.0, // Stroke width of 1 pixel to match PICO-8's style
+		thickness, // 1 pixel by default to match PICO-8's style
 		actualColor,
 		false, // No anti-aliasing to match PICO-8's pixel-perfect style
 	)
@@ -426,9 +497,10 @@ func Circ[X Number, Y Number, R Number](x X, y Y, radius R, options ...interface
 //     uses the current drawing color (defaults to 7 - white currently).
 func Circfill[X Number, Y Number, R Number](x X, y Y, radius R, options ...interface{}) {
 	if currentScreen == nil {
-		log.Println("Warning: Circfill() called before screen was ready.")
+		warnScreenNotReady("Circfill")
 		return
 	}
+	beforeScreenDraw()
 
 	fx, fy, fr := float64(x), float64(y), float64(radius)
 
@@ -439,10 +511,13 @@ func Circfill[X Number, Y Number, R Number](x X, y Y, radius R, options ...inter
 	fx = math.Round(fx)
 	fy = math.Round(fy)
 
-	circX, circY, circR, drawColorIndex, ok := parseCircArgs(fx, fy, fr, options)
+	circX, circY, circR, drawColorIndex, _, ok := parseCircArgs(fx, fy, fr, options)
 	if !ok {
 		return // Argument parsing logged an issue
 	}
+	if len(options) > 1 {
+		logWarningOnce("Warning: Circfill ignores the thickness argument, a filled circle has no outline.")
+	}
 
 	// Get the actual color from the palette
 	var actualColor color.Color
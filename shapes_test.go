@@ -0,0 +1,39 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseThickness(t *testing.T) {
+	assert.Equal(t, float32(3), parseThickness("Line", 3))
+	assert.Equal(t, float32(2), parseThickness("Line", 2.4), "thickness should round to whole pixels")
+	assert.Equal(t, float32(4), parseThickness("Circ", float32(4)))
+
+	// Invalid thicknesses fall back to the default 1 pixel
+	assert.Equal(t, float32(1), parseThickness("Line", 0))
+	assert.Equal(t, float32(1), parseThickness("Line", -2.0))
+	assert.Equal(t, float32(1), parseThickness("Circ", "thick"))
+}
+
+func TestParseLineAndCircThickness(t *testing.T) {
+	originalColor, originalCursor := currentDrawColor, cursorColor
+	t.Cleanup(func() { currentDrawColor, cursorColor = originalColor, originalCursor })
+
+	colorIndex, thickness, ok := parseLineArgs([]interface{}{8})
+	assert.True(t, ok)
+	assert.Equal(t, 8, colorIndex)
+	assert.Equal(t, float32(1), thickness, "lines should stay 1 pixel wide by default")
+
+	_, thickness, _ = parseLineArgs([]interface{}{8, 3})
+	assert.Equal(t, float32(3), thickness)
+
+	_, _, _, colorIndex, thickness, ok = parseCircArgs(64, 64, 10, []interface{}{12, 4})
+	assert.True(t, ok)
+	assert.Equal(t, 12, colorIndex)
+	assert.Equal(t, float32(4), thickness)
+
+	_, _, _, _, thickness, _ = parseCircArgs(64, 64, 10, nil)
+	assert.Equal(t, float32(1), thickness, "circles should stay 1 pixel wide by default")
+}
@@ -0,0 +1,79 @@
+package pigo8
+
+// --- Simulation steps ---
+
+// simOnly is true while StepSim runs the game logic: drawing, sound and
+// rumble do nothing, like in headless mode.
+var simOnly bool
+
+// StepSim runs one frame of game logic as a pure simulation step: the
+// buttons in held are down and every other button is up, real keyboards,
+// gamepads and mice are ignored, and the cartridge's Update runs followed by
+// the engine's own frame logic (events, timers, tweens, the camera) and the
+// clock. Draw is not called.
+//
+// It is the building block for replays, lockstep netcode and tests: starting
+// from the same state and Srand seed, the same sequence of inputs gives the
+// same results, on any machine and with or without a window.
+//
+// The contract a game opts into is that everything Update does depends only
+// on its own state, the input and the random number generator, and that
+// effects meant for the player live in Draw. To make sure of it, during a
+// simulation step drawing functions (Spr, Print, Rectfill, ...), DrawAtLayer,
+// sound (Sfx, Music) and rumble do nothing, and IsSimOnly reports true, so
+// such calls left in Update are harmless. Reading the screen (Pget) is not
+// part of the simulation and returns 0.
+//
+// Held buttons replace the ones injected with InjectButton, and stay held
+// until the next StepSim or InjectButton call.
+//
+// Like the engine's frames, a step during a HitStop only counts the frozen
+// frame down, without running Update.
+//
+// Example:
+//
+//	// Replay recorded inputs from a known start
+//	Srand(seed)
+//	game.Init()
+//	for _, buttons := range recording {
+//		StepSim(buttons...)
+//	}
+//	// game is now in the same state as when it was recorded
+func StepSim(held ...int) {
+	inputCacheMutex.Lock()
+	clear(injectedButtons)
+	for _, b := range held {
+		injectedButtons[b] = true
+	}
+	inputCacheMutex.Unlock()
+	refreshInputCache(false)
+
+	updateScreenFlash()
+	if consumeHitStopFrame() {
+		return
+	}
+
+	savedScreen := currentScreen
+	currentScreen = nil
+	simOnly = true
+	defer func() {
+		simOnly = false
+		currentScreen = savedScreen
+	}()
+
+	loadedCartridge.Update()
+	advanceClock()
+}
+
+// IsSimOnly reports whether the game logic is running in a StepSim, where
+// drawing, sound and rumble do nothing. Games can use it to skip work that
+// only matters to the player, like IsHeadless.
+func IsSimOnly() bool {
+	return simOnly
+}
+
+// outputMuted reports whether drawing, sound and rumble should do nothing:
+// in headless mode and during StepSim.
+func outputMuted() bool {
+	return headless || simOnly
+}
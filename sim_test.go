@@ -0,0 +1,107 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// simCartridge is a game whose Update rolls dice, reads input and, against
+// the StepSim contract, also draws and plays sounds.
+type simCartridge struct {
+	x, rolls   int
+	sawSimOnly bool
+}
+
+func (c *simCartridge) Init() {}
+func (c *simCartridge) Update() {
+	if Btn(RIGHT) {
+		c.x++
+	}
+	if Btnp(X) {
+		c.rolls += Rnd(100)
+	}
+	c.sawSimOnly = IsSimOnly()
+	Print("update", 0, 0, 7)
+	DrawAtLayer(1, func() {})
+	Sfx(0)
+}
+func (c *simCartridge) Draw() {}
+
+// useSimCartridge loads game for one test.
+func useSimCartridge(t *testing.T, game Cartridge) {
+	t.Helper()
+	originalCart := loadedCartridge
+	t.Cleanup(func() {
+		InsertGame(originalCart)
+		resetInputState()
+		resetClock()
+	})
+	InsertGame(game)
+	resetInputState()
+	resetClock()
+}
+
+// replay runs a recording of held buttons from seed and returns the game.
+func replay(t *testing.T, seed int64, recording [][]int) *simCartridge {
+	t.Helper()
+	game := &simCartridge{}
+	useSimCartridge(t, game)
+	Srand(seed)
+	for _, held := range recording {
+		StepSim(held...)
+	}
+	return game
+}
+
+func TestStepSimIsDeterministic(t *testing.T) {
+	recording := [][]int{{RIGHT}, {RIGHT, X}, {}, {X}, {X}, {}, {X, RIGHT}}
+
+	first := replay(t, 42, recording)
+	second := replay(t, 42, recording)
+	assert.Equal(t, 3, first.x)
+	assert.Equal(t, first.x, second.x)
+	assert.Equal(t, first.rolls, second.rolls, "same seed and inputs, same rolls")
+	assert.Equal(t, len(recording), Frame())
+}
+
+func TestStepSimHeldButtons(t *testing.T) {
+	game := &simCartridge{}
+	useSimCartridge(t, game)
+
+	InjectButton(LEFT, true)
+	StepSim(RIGHT)
+	assert.True(t, Btn(RIGHT))
+	assert.False(t, Btn(LEFT), "held buttons replace injected ones")
+	assert.Equal(t, 1, game.x)
+
+	StepSim()
+	assert.False(t, Btn(RIGHT))
+	assert.Equal(t, 1, game.x)
+}
+
+func TestStepSimMutesOutput(t *testing.T) {
+	setupRowTest(t)
+	game := &simCartridge{}
+	useSimCartridge(t, game)
+	screen := currentScreen
+	t.Cleanup(func() { layerQueue = nil })
+
+	StepSim()
+	assert.True(t, game.sawSimOnly)
+	assert.False(t, IsSimOnly(), "only during StepSim")
+	assert.Same(t, screen, currentScreen, "the screen is restored")
+	assert.Empty(t, layerQueue, "DrawAtLayer queues nothing")
+	assert.Equal(t, -1, bufferColorAt(0, 0), "Print draws nothing")
+}
+
+func TestStepSimDispatchesEvents(t *testing.T) {
+	useTestEvents(t)
+	useSimCartridge(t, &simCartridge{})
+
+	var got []any
+	Subscribe("tick", func(payload any) { got = append(got, payload) })
+	Publish("tick", 1)
+	StepSim()
+	assert.Equal(t, []any{1}, got)
+}
@@ -0,0 +1,89 @@
+package pigo8
+
+// --- Sprite anchors ---
+
+// SpriteAnchor is a Spr and Sspr option that chooses which point of the
+// drawn sprite lands on (x, y), as fractions of its drawn width and height:
+// {0, 0} is the top-left corner (the default), {0.5, 0.5} the center and
+// {0.5, 1} the middle of the bottom edge. Create it with WithAnchor or
+// WithOrigin.
+//
+// The anchor is a point of the drawn rectangle, after the w and h scale, so a
+// sprite anchored at its center stays centered on (x, y) at any size.
+// Flipping mirrors the sprite inside that rectangle without moving it, as
+// without an anchor.
+type SpriteAnchor struct {
+	X, Y float64
+}
+
+// Common anchors for WithAnchor.
+var (
+	AnchorTopLeft     = SpriteAnchor{0, 0} // The default
+	AnchorTop         = SpriteAnchor{0.5, 0}
+	AnchorTopRight    = SpriteAnchor{1, 0}
+	AnchorCenterLeft  = SpriteAnchor{0, 0.5}
+	AnchorCenter      = SpriteAnchor{0.5, 0.5}
+	AnchorCenterRight = SpriteAnchor{1, 0.5}
+	AnchorBottomLeft  = SpriteAnchor{0, 1}
+	AnchorBottom      = SpriteAnchor{0.5, 1} // Where a character's feet are
+	AnchorBottomRight = SpriteAnchor{1, 1}
+)
+
+// WithAnchor returns a Spr and Sspr option that draws the sprite with anchor
+// on (x, y) instead of its top-left corner, e.g. to center it on a position
+// without subtracting half its size.
+//
+// Example:
+//
+//	// Centered on the player, at any scale and facing
+//	Spr(1, p.x, p.y, 2, 2, p.facingLeft, WithAnchor(AnchorCenter))
+//
+//	// Standing on the ground line
+//	Sspr(0, 8, 16, 16, p.x, groundY, WithAnchor(AnchorBottom))
+func WithAnchor(anchor SpriteAnchor) SpriteAnchor {
+	return anchor
+}
+
+// WithOrigin returns a Spr and Sspr option that draws the sprite with the
+// point (fx, fy) of it on (x, y), in fractions of its drawn size (0 is the
+// left or top edge, 1 the right or bottom edge).
+//
+// Example:
+//
+//	Spr(5, handX, handY, WithOrigin(0.25, 0.75)) // The sword's hilt
+func WithOrigin(fx, fy float64) SpriteAnchor {
+	return SpriteAnchor{X: fx, Y: fy}
+}
+
+// splitSpriteAnchor removes SpriteAnchor options from options and returns the
+// remaining options and the anchor (the last one wins, the top-left corner if
+// none).
+func splitSpriteAnchor(options []any) ([]any, SpriteAnchor) {
+	found := false
+	for _, opt := range options {
+		if _, ok := opt.(SpriteAnchor); ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return options, SpriteAnchor{}
+	}
+
+	rest := make([]any, 0, len(options))
+	var anchor SpriteAnchor
+	for _, opt := range options {
+		if a, ok := opt.(SpriteAnchor); ok {
+			anchor = a
+			continue
+		}
+		rest = append(rest, opt)
+	}
+	return rest, anchor
+}
+
+// offset returns how far the top-left corner of a sprite drawn
+// width x height pixels big is from its anchor.
+func (a SpriteAnchor) offset(width, height float64) (float64, float64) {
+	return -a.X * width, -a.Y * height
+}
@@ -0,0 +1,35 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitSpriteAnchor(t *testing.T) {
+	options, anchor := splitSpriteAnchor([]any{2, 2, true})
+	assert.Equal(t, []any{2, 2, true}, options)
+	assert.Equal(t, AnchorTopLeft, anchor, "the top-left corner by default")
+
+	options, anchor = splitSpriteAnchor([]any{2, WithAnchor(AnchorCenter), 2, true})
+	assert.Equal(t, []any{2, 2, true}, options, "anchors are not positional options")
+	assert.Equal(t, AnchorCenter, anchor)
+
+	_, anchor = splitSpriteAnchor([]any{WithAnchor(AnchorCenter), WithOrigin(0.25, 1)})
+	assert.Equal(t, SpriteAnchor{0.25, 1}, anchor, "the last anchor wins")
+}
+
+func TestSpriteAnchorOffset(t *testing.T) {
+	x, y := AnchorTopLeft.offset(16, 8)
+	assert.Equal(t, [2]float64{0, 0}, [2]float64{x, y})
+
+	x, y = AnchorCenter.offset(16, 8)
+	assert.Equal(t, [2]float64{-8, -4}, [2]float64{x, y})
+
+	// The offset follows the drawn size, so scaled sprites stay anchored
+	x, y = AnchorBottom.offset(8*2, 8*3)
+	assert.Equal(t, [2]float64{-8, -24}, [2]float64{x, y})
+
+	x, y = WithOrigin(0.25, 0.75).offset(8, 8)
+	assert.Equal(t, [2]float64{-2, -6}, [2]float64{x, y})
+}
@@ -0,0 +1,117 @@
+package pigo8
+
+import (
+	"log"
+	"math"
+)
+
+// --- Sprite animations ---
+
+// defaultSpriteAnimFPS is the speed of an animation without fps.
+const defaultSpriteAnimFPS = 8
+
+// SpriteAnimation is a named sequence of sprites, defined in the
+// "animations" list of spritesheet.json (and editable in the pigo8 editor):
+//
+//	"animations": [
+//		{"name": "walk", "frames": [16, 17, 18, 17], "fps": 8},
+//		{"name": "blink", "frames": [3, 4]}
+//	]
+//
+// The list is optional, so older spritesheets load unchanged.
+type SpriteAnimation struct {
+	Name   string  `json:"name"`
+	Frames []int   `json:"frames"`        // Sprite numbers, in order
+	FPS    float64 `json:"fps,omitempty"` // Sprites shown per second of game time (Default: 8)
+}
+
+// spriteAnimations holds the animations of the loaded spritesheet, in file order.
+var spriteAnimations []SpriteAnimation
+
+// setSpriteAnimations replaces the loaded animations, skipping the ones
+// without a name or frames.
+func setSpriteAnimations(animations []SpriteAnimation) {
+	spriteAnimations = nil
+	for _, anim := range animations {
+		if anim.Name == "" || len(anim.Frames) == 0 {
+			log.Printf("Warning: Skipping spritesheet animation %q without a name or frames.", anim.Name)
+			continue
+		}
+		spriteAnimations = append(spriteAnimations, anim)
+	}
+}
+
+// SpriteAnim returns the animation called name from the loaded spritesheet,
+// and false if there is none.
+func SpriteAnim(name string) (SpriteAnimation, bool) {
+	ensureSpritesLoaded("SpriteAnim")
+	for _, anim := range spriteAnimations {
+		if anim.Name == name {
+			return anim, true
+		}
+	}
+	return SpriteAnimation{}, false
+}
+
+// SpriteAnims returns the names of the loaded animations, in file order.
+func SpriteAnims() []string {
+	ensureSpritesLoaded("SpriteAnims")
+	names := make([]string, len(spriteAnimations))
+	for i, anim := range spriteAnimations {
+		names[i] = anim.Name
+	}
+	return names
+}
+
+// SpriteAnimFrame returns the sprite number the animation called name shows
+// frame game frames after it started, looping forever. Pass Frame() for an
+// animation that runs with the game, or the frames since an object spawned
+// so each object starts its animation from the first sprite. Unknown
+// animations log a warning once and return 0.
+//
+// Example:
+//
+//	Spr(SpriteAnimFrame("explode", Frame()-e.spawnFrame), e.x, e.y)
+func SpriteAnimFrame(name string, frame int) int {
+	anim, ok := SpriteAnim(name)
+	if !ok {
+		logWarningOnce("Warning: SpriteAnimFrame() called with unknown animation %q.", name)
+		return 0
+	}
+	return anim.frameAt(frame)
+}
+
+// PlaySpriteAnim draws the current sprite of the animation called name at
+// (x, y), like Spr with the sprite SpriteAnimFrame(name, Frame()) returns.
+// The options are those of Spr. Since it follows the game clock, it pauses
+// with the game and plays the same way in replays.
+//
+// Example:
+//
+//	if p.moving {
+//		PlaySpriteAnim("walk", p.x, p.y, 1, 1, p.facingLeft)
+//	} else {
+//		Spr(16, p.x, p.y, 1, 1, p.facingLeft)
+//	}
+func PlaySpriteAnim[X Number, Y Number](name string, x X, y Y, options ...any) {
+	if _, ok := SpriteAnim(name); !ok {
+		logWarningOnce("Warning: PlaySpriteAnim() called with unknown animation %q.", name)
+		return
+	}
+	Spr(SpriteAnimFrame(name, frameCount), x, y, options...)
+}
+
+// frameAt returns the sprite shown frame game frames into the animation.
+func (anim SpriteAnimation) frameAt(frame int) int {
+	fps := anim.FPS
+	if fps <= 0 {
+		fps = defaultSpriteAnimFPS
+	}
+	step := timeIncrement
+	if step <= 0 {
+		step = defaultTimeStep
+	}
+	index := int(math.Floor(float64(frame)*step*fps + timerEpsilon))
+	n := len(anim.Frames)
+	return anim.Frames[(index%n+n)%n]
+}
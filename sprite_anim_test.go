@@ -0,0 +1,58 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpritesheetAnimations(t *testing.T) {
+	useTestConsoleState(t)
+	t.Cleanup(func() { spriteAnimations = nil })
+
+	sprites, err := loadSpritesheetFromDataForTest([]byte(`{
+		"sprites": [
+			{"id": 1, "width": 1, "height": 1, "used": true, "pixels": [[7]]}
+		],
+		"animations": [
+			{"name": "walk", "frames": [16, 17, 18], "fps": 10},
+			{"name": "blink", "frames": [3, 4]},
+			{"name": "broken", "frames": []}
+		]
+	}`))
+	require.NoError(t, err)
+	currentSprites = sprites
+
+	assert.Equal(t, []string{"walk", "blink"}, SpriteAnims(), "animations without frames are skipped")
+	walk, ok := SpriteAnim("walk")
+	assert.True(t, ok)
+	assert.Equal(t, SpriteAnimation{Name: "walk", Frames: []int{16, 17, 18}, FPS: 10}, walk)
+	_, ok = SpriteAnim("run")
+	assert.False(t, ok)
+
+	sprites, err = loadSpritesheetFromDataForTest([]byte(`{
+		"sprites": [{"id": 1, "width": 1, "height": 1, "used": true, "pixels": [[7]]}]
+	}`))
+	require.NoError(t, err)
+	assert.Empty(t, SpriteAnims(), "spritesheets without animations still load")
+	assert.Len(t, sprites, 1)
+}
+
+func TestSpriteAnimFrame(t *testing.T) {
+	originalIncrement := timeIncrement
+	t.Cleanup(func() { timeIncrement = originalIncrement })
+	timeIncrement = 1.0 / 30
+
+	walk := SpriteAnimation{Name: "walk", Frames: []int{16, 17, 18}, FPS: 10}
+	assert.Equal(t, 16, walk.frameAt(0))
+	assert.Equal(t, 16, walk.frameAt(2))
+	assert.Equal(t, 17, walk.frameAt(3), "10 fps at 30 FPS is a sprite every 3 frames")
+	assert.Equal(t, 18, walk.frameAt(6))
+	assert.Equal(t, 16, walk.frameAt(9), "animations loop")
+	assert.Equal(t, 18, walk.frameAt(-1), "negative frames wrap around")
+
+	blink := SpriteAnimation{Name: "blink", Frames: []int{3, 4}}
+	assert.Equal(t, 3, blink.frameAt(3))
+	assert.Equal(t, 4, blink.frameAt(4), "8 fps by default")
+}
@@ -0,0 +1,113 @@
+package pigo8
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Sprite batches ---
+
+// spriteInstance is one copy of a SpriteBatch's sprite.
+type spriteInstance struct {
+	x, y         float64
+	flipX, flipY bool
+}
+
+// SpriteBatch draws many copies of one sprite, e.g. a swarm of enemies, a
+// particle effect or a field of repeated tiles. Add queues a copy and Flush
+// draws all of them, looking the sprite and its transparent image up once
+// instead of once per copy like Spr does. Create it with NewSpriteBatch.
+//
+// A batch can be kept and reused every frame: Flush empties it but keeps its
+// memory. Copies are drawn in the order they were added, with the camera and
+// transparency (see Palt) in effect when Flush is called.
+type SpriteBatch struct {
+	id        int
+	instances []spriteInstance
+	opts      ebiten.DrawImageOptions // Reused for every copy
+}
+
+// NewSpriteBatch returns an empty batch of sprite id, which is looked up like
+// in Spr.
+//
+// Example:
+//
+//	var aliens = NewSpriteBatch(3)
+//
+//	func (g *Game) Draw() {
+//		Cls(0)
+//		for _, a := range g.aliens {
+//			aliens.Add(float64(a.x), float64(a.y), a.facingLeft)
+//		}
+//		aliens.Flush()
+//	}
+func NewSpriteBatch(id int) *SpriteBatch {
+	return &SpriteBatch{id: id}
+}
+
+// Add queues a copy of the sprite at (x, y). The optional flip arguments are
+// flipX and flipY, as in Spr.
+func (b *SpriteBatch) Add(x, y float64, flip ...bool) {
+	inst := spriteInstance{x: x, y: y}
+	if len(flip) > 0 {
+		inst.flipX = flip[0]
+	}
+	if len(flip) > 1 {
+		inst.flipY = flip[1]
+	}
+	b.instances = append(b.instances, inst)
+}
+
+// Len returns the number of copies waiting for Flush.
+func (b *SpriteBatch) Len() int {
+	return len(b.instances)
+}
+
+// Flush draws the queued copies to the screen and empties the batch. Nothing
+// is drawn if the sprite doesn't exist.
+func (b *SpriteBatch) Flush() {
+	defer func() { b.instances = b.instances[:0] }()
+	if len(b.instances) == 0 {
+		return
+	}
+	if currentScreen == nil {
+		warnScreenNotReady("SpriteBatch.Flush")
+		return
+	}
+	if !ensureSpritesLoaded("SpriteBatch.Flush") {
+		return
+	}
+	spriteInfo := findSpriteByID(b.id)
+	if spriteInfo == nil {
+		return
+	}
+	beforeScreenDraw()
+
+	// Resolve the sprite once; consecutive draws of the same image are
+	// batched by ebiten into a single draw call
+	tempImage := createTransparentSpriteImage(spriteInfo.Image)
+	width := float64(tempImage.Bounds().Dx())
+	height := float64(tempImage.Bounds().Dy())
+	for _, inst := range b.instances {
+		spriteInstanceGeoM(&b.opts.GeoM, inst, width, height)
+		b.opts.Filter = ebiten.FilterNearest
+		currentScreen.DrawImage(tempImage, &b.opts)
+	}
+}
+
+// spriteInstanceGeoM sets geoM to place a width x height sprite copy on the
+// screen exactly like Spr would, including the camera offset.
+func spriteInstanceGeoM(geoM *ebiten.GeoM, inst spriteInstance, width, height float64) {
+	screenX, screenY := applyCameraOffset(inst.x, inst.y)
+	geoM.Reset()
+	if inst.flipX {
+		geoM.Scale(-1, 1)
+		geoM.Translate(width, 0)
+	}
+	if inst.flipY {
+		geoM.Scale(1, -1)
+		geoM.Translate(0, height)
+	}
+	geoM.Translate(math.Round(screenX), math.Round(screenY))
+}
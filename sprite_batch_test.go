@@ -0,0 +1,108 @@
+package pigo8
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpriteBatchAddAndFlush(t *testing.T) {
+	setupRowTest(t)
+	originalSprites := currentSprites
+	t.Cleanup(func() { currentSprites = originalSprites })
+	currentSprites = []spriteInfo{}
+
+	batch := NewSpriteBatch(1)
+	batch.Add(1, 2)
+	batch.Add(3, 4, true)
+	batch.Add(5, 6, false, true)
+	assert.Equal(t, 3, batch.Len())
+	assert.Equal(t, []spriteInstance{{1, 2, false, false}, {3, 4, true, false}, {5, 6, false, true}}, batch.instances)
+
+	// The sprite doesn't exist, but the batch is emptied all the same
+	batch.Flush()
+	assert.Equal(t, 0, batch.Len())
+}
+
+func TestSpriteInstanceGeoMMatchesSpr(t *testing.T) {
+	t.Cleanup(func() { cameraX, cameraY = 0, 0 })
+	cameraX, cameraY = 10, -4
+
+	for _, inst := range []spriteInstance{
+		{20, 30, false, false},
+		{20.6, 30.2, true, false},
+		{-5, 7, false, true},
+		{0, 0, true, true},
+	} {
+		var geoM ebiten.GeoM
+		spriteInstanceGeoM(&geoM, inst, 8, 16)
+
+		// Spr's own placement of the same copy
+		x, y := applyCameraOffset(inst.x, inst.y)
+		want := setupDrawOptions(math.Round(x), math.Round(y), 8, 16, 1, 1, inst.flipX, inst.flipY)
+		assert.Equal(t, want.GeoM, geoM, "instance %+v", inst)
+	}
+}
+
+// useBenchSprite loads sprite 1 with its transparent image already cached,
+// so the benchmarks measure drawing rather than the one-time pixel read.
+func useBenchSprite(b *testing.B) {
+	setupRowTest(b)
+	originalSprites := currentSprites
+	b.Cleanup(func() {
+		currentSprites = originalSprites
+		ClearSpriteCache()
+	})
+	sprite := ebiten.NewImage(8, 8)
+	currentSprites = []spriteInfo{{ID: 1, Image: sprite}}
+	spriteCacheMutex.Lock()
+	spriteCache[spriteCacheKey{sprite, currentTransparencyKey()}] = ebiten.NewImage(8, 8)
+	spriteCacheMutex.Unlock()
+}
+
+func BenchmarkSprRepeated(b *testing.B) {
+	useBenchSprite(b)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < 500; i++ {
+			Spr(1, i%16, i/16)
+		}
+	}
+}
+
+func BenchmarkSpriteBatch(b *testing.B) {
+	useBenchSprite(b)
+	batch := NewSpriteBatch(1)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < 500; i++ {
+			batch.Add(float64(i%16), float64(i/16))
+		}
+		batch.Flush()
+	}
+}
+
+func BenchmarkSprFrame100(b *testing.B) {
+	setupRowTest(b)
+	originalSprites := currentSprites
+	b.Cleanup(func() {
+		currentSprites = originalSprites
+		ClearSpriteCache()
+	})
+	// Every frame after the first finds the transparent images in the cache
+	currentSprites = make([]spriteInfo, 100)
+	key := currentTransparencyKey()
+	for i := range currentSprites {
+		currentSprites[i] = spriteInfo{ID: i, Image: ebiten.NewImage(8, 8)}
+		spriteCache[spriteCacheKey{currentSprites[i].Image, key}] = ebiten.NewImage(8, 8)
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < 100; i++ {
+			Spr(i, i%16*8, i/16*8)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package pigo8
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 // TestSpriteFlagsAPI tests the sprite flags API functions
@@ -15,3 +17,36 @@ func TestSpriteFlagsAPI(t *testing.T) {
 	// - Fset(n int, f interface{}, v ...bool)
 	// These would need to be tested in a more comprehensive way
 }
+
+func TestFlagHelpers(t *testing.T) {
+	originalSprites := currentSprites
+	t.Cleanup(func() { currentSprites = originalSprites })
+
+	// Sprite 3 has no individual flags, like sprites built in code
+	currentSprites = []spriteInfo{
+		{ID: 7, Flags: FlagsData{Bitfield: 0b101, Individual: []bool{true, false, true, false, false, false, false, false}}},
+		{ID: 3},
+	}
+
+	assert.True(t, HasFlag(7, 0))
+	assert.False(t, HasFlag(7, 1))
+	assert.True(t, HasFlag(7, 2))
+	assert.False(t, HasFlag(7, 8), "invalid flags are never set")
+	assert.False(t, HasFlag(1, 0), "index 1 is not a sprite number")
+
+	SetFlag(3, 4)
+	assert.True(t, HasFlag(3, 4))
+	assert.Equal(t, []bool{false, false, false, false, true, false, false, false}, currentSprites[1].Flags.Individual)
+
+	ClearFlag(7, 0)
+	bitfield, _ := Fget(7)
+	assert.Equal(t, 0b100, bitfield)
+	assert.False(t, currentSprites[0].Flags.Individual[0])
+
+	assert.True(t, ToggleFlag(7, 1))
+	assert.False(t, ToggleFlag(7, 1))
+	assert.False(t, ToggleFlag(7, -1))
+
+	assert.Equal(t, []bool{false, false, true, false, false, false, false, false}, FlagsOf(7))
+	assert.Nil(t, FlagsOf(99))
+}
@@ -0,0 +1,160 @@
+package pigo8
+
+import (
+	"image/color"
+	"slices"
+)
+
+// --- Sprite metadata ---
+
+// SpriteMetadata describes a loaded sprite. It is a copy, so changing it
+// doesn't change the sprite; use Fset and Sset for that.
+type SpriteMetadata struct {
+	ID     int // Sprite number, as used by Spr and Fget
+	Width  int // Width in pixels
+	Height int // Height in pixels
+	Flags  int // Flag bitfield (0-255), as returned by Fget
+}
+
+// SpriteCount returns the number of loaded sprites. Sprites marked unused in
+// spritesheet.json aren't loaded, so the count can be lower than the highest
+// sprite number plus one.
+//
+// Example:
+//
+//	Print(fmt.Sprintf("%d sprites", SpriteCount()), 0, 0, 7)
+func SpriteCount() int {
+	if !ensureSpritesLoaded("SpriteCount") {
+		return 0
+	}
+	return len(currentSprites)
+}
+
+// SpriteExists reports whether a sprite with number id is loaded.
+//
+// Only sprite numbers count. Spr falls back to treating a number that no
+// sprite has as a position in the list of loaded sprites; SpriteExists and
+// SpriteMeta don't, so SpriteExists(id) is false for every id that isn't a
+// loaded sprite's number, and exactly SpriteCount() ids exist.
+//
+// Example:
+//
+//	if !SpriteExists(g.skin) {
+//		g.skin = 1 // Fall back to the default skin
+//	}
+func SpriteExists(id int) bool {
+	_, ok := SpriteMeta(id)
+	return ok
+}
+
+// SpriteMeta returns the size and flags of the sprite with number id, and
+// false if no such sprite is loaded (see SpriteExists).
+//
+// Example:
+//
+//	if meta, ok := SpriteMeta(12); ok {
+//		Spr(12, x-meta.Width/2, y-meta.Height/2) // Draw centered on x, y
+//	}
+func SpriteMeta(id int) (SpriteMetadata, bool) {
+	if !ensureSpritesLoaded("SpriteMeta") {
+		return SpriteMetadata{}, false
+	}
+	for i := range currentSprites {
+		if currentSprites[i].ID == id {
+			return spriteMetadataOf(&currentSprites[i]), true
+		}
+	}
+	return SpriteMetadata{}, false
+}
+
+// spriteMetadataOf copies the metadata of a loaded sprite.
+func spriteMetadataOf(sprite *spriteInfo) SpriteMetadata {
+	meta := SpriteMetadata{ID: sprite.ID, Flags: sprite.Flags.Bitfield}
+	if sprite.Image != nil {
+		bounds := sprite.Image.Bounds()
+		meta.Width, meta.Height = bounds.Dx(), bounds.Dy()
+	}
+	return meta
+}
+
+// IsSpriteBlank reports whether every pixel of the sprite with number id is
+// transparent: a palette color marked transparent with Palt (color 0 by
+// default) or a pixel with no alpha. It is false if no such sprite is loaded,
+// and always false when running headless, where sprite pixels can't be read
+// back from the GPU.
+//
+// Example:
+//
+//	if !IsSpriteBlank(g.frame) {
+//		Spr(g.frame, x, y)
+//	}
+func IsSpriteBlank(id int) bool {
+	if headless || !ensureSpritesLoaded("IsSpriteBlank") {
+		return false
+	}
+	flushSpriteModifications()
+	for i := range currentSprites {
+		if currentSprites[i].ID == id {
+			return spriteImageBlank(&currentSprites[i])
+		}
+	}
+	return false
+}
+
+// ForEachUsedSprite calls fn with the number of every used sprite, in
+// increasing order. A sprite is used if it is loaded and either has a pixel
+// that isn't transparent (see IsSpriteBlank) or has a flag set, since games
+// use blank flagged sprites as invisible markers and triggers. Sprites marked
+// unused in spritesheet.json aren't loaded, so they are never used. Headless,
+// every loaded sprite counts as used.
+//
+// Example:
+//
+//	// List the sprites worth exporting
+//	ForEachUsedSprite(func(id int) {
+//		ids = append(ids, id)
+//	})
+func ForEachUsedSprite(fn func(id int)) {
+	if !ensureSpritesLoaded("ForEachUsedSprite") {
+		return
+	}
+	flushSpriteModifications()
+	var used []int
+	for i := range currentSprites {
+		sprite := &currentSprites[i]
+		if headless || sprite.Flags.Bitfield != 0 || !spriteImageBlank(sprite) {
+			used = append(used, sprite.ID)
+		}
+	}
+	slices.Sort(used)
+	for _, id := range used {
+		fn(id)
+	}
+}
+
+// spriteImageBlank reads a sprite's pixels back and reports whether they are
+// all transparent. A sprite without an image is blank.
+func spriteImageBlank(sprite *spriteInfo) bool {
+	if sprite.Image == nil {
+		return true
+	}
+	bounds := sprite.Image.Bounds()
+	pixels := make([]byte, bounds.Dx()*bounds.Dy()*4)
+	sprite.Image.ReadPixels(pixels)
+	return pixelsTransparent(pixels)
+}
+
+// pixelsTransparent reports whether every RGBA pixel in pixels has no alpha
+// or is a palette color marked transparent.
+func pixelsTransparent(pixels []byte) bool {
+	for offset := 0; offset+3 < len(pixels); offset += 4 {
+		if pixels[offset+3] == 0 {
+			continue
+		}
+		index, ok := paletteIndexOf(color.RGBA{pixels[offset], pixels[offset+1], pixels[offset+2], pixels[offset+3]})
+		if !ok || index >= len(paletteTransparency) || !paletteTransparency[index] {
+			return false
+		}
+	}
+	return true
+}
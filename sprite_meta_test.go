@@ -0,0 +1,68 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpriteMeta(t *testing.T) {
+	originalSprites := currentSprites
+	t.Cleanup(func() { currentSprites = originalSprites })
+
+	// IDs out of order with the slice index, like a sheet with unused sprites
+	currentSprites = []spriteInfo{
+		{ID: 5, Image: ebiten.NewImage(8, 8), Flags: FlagsData{Bitfield: 0b11}},
+		{ID: 0, Image: ebiten.NewImage(8, 16)},
+		{ID: 10, Image: ebiten.NewImage(16, 8), Flags: FlagsData{Bitfield: 128}},
+	}
+
+	assert.Equal(t, 3, SpriteCount())
+
+	meta, ok := SpriteMeta(10)
+	assert.True(t, ok)
+	assert.Equal(t, SpriteMetadata{ID: 10, Width: 16, Height: 8, Flags: 128}, meta)
+
+	meta, ok = SpriteMeta(0)
+	assert.True(t, ok)
+	assert.Equal(t, SpriteMetadata{ID: 0, Width: 8, Height: 16}, meta)
+
+	assert.True(t, SpriteExists(5))
+	assert.False(t, SpriteExists(1), "index 1 is not a sprite number")
+	assert.False(t, SpriteExists(-1))
+
+	meta, ok = SpriteMeta(2)
+	assert.False(t, ok)
+	assert.Equal(t, SpriteMetadata{}, meta)
+}
+
+func TestPixelsTransparent(t *testing.T) {
+	useTestConsoleState(t)
+	rgba := func(indexes ...int) []byte {
+		var pixels []byte
+		for _, index := range indexes {
+			r, g, b, a := pico8Palette[index].RGBA()
+			pixels = append(pixels, byte(r>>8), byte(g>>8), byte(b>>8), byte(a>>8))
+		}
+		return pixels
+	}
+
+	assert.True(t, pixelsTransparent(rgba(0, 0, 0)), "color 0 is transparent by default")
+	assert.False(t, pixelsTransparent(rgba(0, 7, 0)))
+	assert.True(t, pixelsTransparent([]byte{255, 0, 0, 0}), "no alpha is transparent whatever the color")
+	assert.False(t, pixelsTransparent([]byte{1, 2, 3, 255}), "colors outside the palette are drawn")
+
+	Palt(7, true)
+	assert.True(t, pixelsTransparent(rgba(0, 7, 0)))
+}
+
+func TestForEachUsedSpriteHeadless(t *testing.T) {
+	useTestConsoleState(t)
+	currentSprites = []spriteInfo{{ID: 9}, {ID: 2}, {ID: 4}}
+
+	var ids []int
+	ForEachUsedSprite(func(id int) { ids = append(ids, id) })
+	assert.Equal(t, []int{2, 4, 9}, ids, "sprite pixels can't be read headless, so every sprite counts")
+	assert.False(t, IsSpriteBlank(2))
+}
@@ -0,0 +1,186 @@
+package pigo8
+
+import "log"
+
+// --- Spritesheet editing helpers ---
+
+// FloodFill replaces the contiguous region of same-coloured pixels around
+// (x, y) with newColor, using 4-way connectivity. It works on any pixel grid
+// of the given size through the get and set callbacks, so tools that keep
+// their own copy of the pixels (like the pigo8 editor) can share it.
+//
+// Pixels are visited from an explicit queue, so large regions cannot overflow
+// the stack, and each pixel is read at most once (set may be deferred).
+// Returns the number of pixels changed; 0 if (x, y) is out of bounds or
+// already has newColor.
+//
+// Args:
+//   - width, height: size of the pixel grid
+//   - x, y: start pixel
+//   - newColor: colour to fill with
+//   - get: returns the colour at a pixel
+//   - set: changes the colour at a pixel
+func FloodFill(width, height, x, y, newColor int, get func(x, y int) int, set func(x, y, color int)) int {
+	if x < 0 || y < 0 || x >= width || y >= height {
+		return 0
+	}
+	target := get(x, y)
+	if target == newColor {
+		return 0
+	}
+
+	visited := make([]bool, width*height)
+	queue := []int{y*width + x}
+	visited[y*width+x] = true
+	filled := 0
+
+	for len(queue) > 0 {
+		idx := queue[0]
+		queue = queue[1:]
+		px, py := idx%width, idx/width
+
+		set(px, py, newColor)
+		filled++
+
+		neighbours := [4][2]int{{px - 1, py}, {px + 1, py}, {px, py - 1}, {px, py + 1}}
+		for _, n := range neighbours {
+			nx, ny := n[0], n[1]
+			if nx < 0 || ny < 0 || nx >= width || ny >= height {
+				continue
+			}
+			nIdx := ny*width + nx
+			if visited[nIdx] || get(nx, ny) != target {
+				continue
+			}
+			visited[nIdx] = true
+			queue = append(queue, nIdx)
+		}
+	}
+
+	return filled
+}
+
+// FloodFillSprite is a paint bucket for the spritesheet: it replaces the
+// contiguous region of the colour at (x, y) with newColor, reading with Sget
+// and writing with Sset. Returns the number of pixels changed.
+//
+// Args:
+//   - x: the distance from the left side of the spritesheet (in pixels).
+//   - y: the distance from the top side of the spritesheet (in pixels).
+//   - newColor: the colour index to fill with.
+//
+// Example:
+//
+//	// Fill the background of sprite 1 (which starts at 8,0) with red
+//	FloodFillSprite(8, 0, 8)
+func FloodFillSprite[X Number, Y Number](x X, y Y, newColor int) int {
+	return FloodFill(spritesheetWidth, spritesheetHeight, int(x), int(y), newColor,
+		func(px, py int) int { return Sget(px, py) },
+		func(px, py, c int) { Sset(px, py, c) },
+	)
+}
+
+// FlipPixelsH mirrors a w x h pixel grid left-to-right through the get and
+// set callbacks. All pixels are read before any are written.
+func FlipPixelsH(w, h int, get func(x, y int) int, set func(x, y, color int)) {
+	transformPixels(w, h, get, set, func(x, y int) (int, int) { return w - 1 - x, y })
+}
+
+// FlipPixelsV mirrors a w x h pixel grid top-to-bottom through the get and
+// set callbacks. All pixels are read before any are written.
+func FlipPixelsV(w, h int, get func(x, y int) int, set func(x, y, color int)) {
+	transformPixels(w, h, get, set, func(x, y int) (int, int) { return x, h - 1 - y })
+}
+
+// RotatePixels90 rotates a square size x size pixel grid 90 degrees clockwise
+// through the get and set callbacks. All pixels are read before any are written.
+func RotatePixels90(size int, get func(x, y int) int, set func(x, y, color int)) {
+	transformPixels(size, size, get, set, func(x, y int) (int, int) { return y, size - 1 - x })
+}
+
+// transformPixels sets every pixel (x, y) to the old colour at source(x, y).
+func transformPixels(w, h int, get func(x, y int) int, set func(x, y, color int), source func(x, y int) (int, int)) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	old := make([]int, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			old[y*w+x] = get(x, y)
+		}
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			sx, sy := source(x, y)
+			set(x, y, old[sy*w+sx])
+		}
+	}
+}
+
+// spriteBlock returns the spritesheet pixel rectangle covered by a square
+// block of sprites whose top-left sprite is spriteNum.
+func spriteBlock(spriteNum int, blockSize []int) (x, y, size int, ok bool) {
+	n := 1
+	if len(blockSize) > 0 && blockSize[0] > 1 {
+		n = blockSize[0]
+	}
+	if spriteNum < 0 || spritesheetColumns <= 0 {
+		return 0, 0, 0, false
+	}
+	x = (spriteNum % spritesheetColumns) * tileSize
+	y = (spriteNum / spritesheetColumns) * tileSize
+	size = n * tileSize
+	return x, y, size, validateSpriteSheetBounds(x, y, size, size)
+}
+
+// transformSpriteBlock applies fn to a sprite block using Sget/Sset.
+func transformSpriteBlock(name string, spriteNum int, blockSize []int, fn func(size int, get func(x, y int) int, set func(x, y, color int))) {
+	bx, by, size, ok := spriteBlock(spriteNum, blockSize)
+	if !ok {
+		log.Printf("Warning: %s() called with sprite %d and block size %v outside the spritesheet", name, spriteNum, blockSize)
+		return
+	}
+	fn(size,
+		func(x, y int) int { return Sget(bx+x, by+y) },
+		func(x, y, c int) { Sset(bx+x, by+y, c) },
+	)
+}
+
+// FlipSpriteH mirrors a sprite left-to-right in the spritesheet.
+//
+// Args:
+//   - spriteNum: the sprite to flip
+//   - blockSize: (optional) flip an n x n block of sprites starting at
+//     spriteNum as one image, so the sprites in it swap places as well
+//
+// Example:
+//
+//	FlipSpriteH(1)    // flip sprite 1
+//	FlipSpriteH(1, 2) // flip the 16x16 image made of sprites 1, 2, 17 and 18
+func FlipSpriteH(spriteNum int, blockSize ...int) {
+	transformSpriteBlock("FlipSpriteH", spriteNum, blockSize, func(size int, get func(x, y int) int, set func(x, y, color int)) {
+		FlipPixelsH(size, size, get, set)
+	})
+}
+
+// FlipSpriteV mirrors a sprite top-to-bottom in the spritesheet.
+// The optional blockSize works like in FlipSpriteH.
+//
+// Example:
+//
+//	FlipSpriteV(1)
+func FlipSpriteV(spriteNum int, blockSize ...int) {
+	transformSpriteBlock("FlipSpriteV", spriteNum, blockSize, func(size int, get func(x, y int) int, set func(x, y, color int)) {
+		FlipPixelsV(size, size, get, set)
+	})
+}
+
+// RotateSprite90 rotates a sprite 90 degrees clockwise in the spritesheet.
+// The optional blockSize works like in FlipSpriteH.
+//
+// Example:
+//
+//	RotateSprite90(1)
+func RotateSprite90(spriteNum int, blockSize ...int) {
+	transformSpriteBlock("RotateSprite90", spriteNum, blockSize, RotatePixels90)
+}
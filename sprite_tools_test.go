@@ -0,0 +1,102 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testGrid is a small pixel grid used to exercise the sprite editing helpers.
+type testGrid struct {
+	w, h   int
+	pixels []int
+}
+
+func newTestGrid(rows ...string) *testGrid {
+	g := &testGrid{w: len(rows[0]), h: len(rows)}
+	for _, row := range rows {
+		for _, c := range row {
+			g.pixels = append(g.pixels, int(c-'0'))
+		}
+	}
+	return g
+}
+
+func (g *testGrid) get(x, y int) int { return g.pixels[y*g.w+x] }
+func (g *testGrid) set(x, y, c int)  { g.pixels[y*g.w+x] = c }
+
+func TestFloodFill(t *testing.T) {
+	g := newTestGrid(
+		"0010",
+		"0110",
+		"1000",
+		"0001",
+	)
+	n := FloodFill(g.w, g.h, 0, 0, 5, g.get, g.set)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, newTestGrid(
+		"5510",
+		"5110",
+		"1000",
+		"0001",
+	).pixels, g.pixels, "diagonal neighbours are not part of the region")
+}
+
+func TestFloodFill_NoOpAndBounds(t *testing.T) {
+	g := newTestGrid("11", "11")
+	assert.Equal(t, 0, FloodFill(g.w, g.h, 0, 0, 1, g.get, g.set), "same colour is a no-op")
+	assert.Equal(t, 0, FloodFill(g.w, g.h, -1, 0, 2, g.get, g.set))
+	assert.Equal(t, 0, FloodFill(g.w, g.h, 0, 2, 2, g.get, g.set))
+}
+
+func TestFloodFill_LargeRegion(t *testing.T) {
+	const size = 512
+	pixels := make([]int, size*size)
+	get := func(x, y int) int { return pixels[y*size+x] }
+	set := func(x, y, c int) { pixels[y*size+x] = c }
+
+	assert.Equal(t, size*size, FloodFill(size, size, size/2, size/2, 3, get, set))
+	assert.Equal(t, 3, pixels[0])
+	assert.Equal(t, 3, pixels[len(pixels)-1])
+}
+
+func TestFlipAndRotatePixels(t *testing.T) {
+	g := newTestGrid(
+		"120",
+		"300",
+		"004",
+	)
+	FlipPixelsH(g.w, g.h, g.get, g.set)
+	assert.Equal(t, newTestGrid("021", "003", "400").pixels, g.pixels)
+
+	FlipPixelsV(g.w, g.h, g.get, g.set)
+	assert.Equal(t, newTestGrid("400", "003", "021").pixels, g.pixels)
+
+	g = newTestGrid(
+		"120",
+		"300",
+		"004",
+	)
+	RotatePixels90(g.w, g.get, g.set)
+	assert.Equal(t, newTestGrid("031", "002", "400").pixels, g.pixels)
+
+	for i := 0; i < 3; i++ {
+		RotatePixels90(g.w, g.get, g.set)
+	}
+	assert.Equal(t, newTestGrid("120", "300", "004").pixels, g.pixels, "four rotations are the identity")
+}
+
+func TestSpriteBlock(t *testing.T) {
+	x, y, size, ok := spriteBlock(17, nil)
+	assert.True(t, ok)
+	assert.Equal(t, 8, x)
+	assert.Equal(t, 8, y)
+	assert.Equal(t, 8, size)
+
+	_, _, size, ok = spriteBlock(0, []int{2})
+	assert.True(t, ok)
+	assert.Equal(t, 16, size)
+
+	_, _, _, ok = spriteBlock(15, []int{2})
+	assert.False(t, ok, "block must fit on the spritesheet")
+}
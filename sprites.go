@@ -4,6 +4,9 @@ import (
 	"image/color"
 	"log"
 	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -21,10 +24,16 @@ type Number interface {
 
 // Add sprite caching for transparent versions
 var (
-	// Global sprite cache for transparent versions
-	spriteCache      = make(map[*ebiten.Image]*ebiten.Image)
+	// Global sprite cache for transparent versions, per sprite and transparency
+	spriteCache      = make(map[spriteCacheKey]*ebiten.Image)
 	spriteCacheMutex sync.RWMutex
 
+	// Recolored transparent sprites drawn with WithPalette, guarded by spriteCacheMutex
+	remappedSpriteCache = make(map[remappedSpriteKey]*ebiten.Image)
+
+	// Spritesheet regions drawn with Sspr, guarded by spriteCacheMutex
+	spriteRegionCache = make(map[spriteRegionKey]*ebiten.Image)
+
 	// Sprite pixel cache for batch reading operations
 	spritePixelCache      = make(map[int][]byte) // spriteID -> pixel data
 	spritePixelCacheSize  = make(map[int]int)    // spriteID -> width*height
@@ -50,12 +59,17 @@ var (
 //   - flipX (bool):       Flip horizontally (default false). Handled via interface{}.
 //   - flipY (bool):       Flip vertically (default false). Handled via interface{}.
 //
+// Typed options (see WithPalette and WithAnchor) can be passed anywhere after
+// y; they are not counted as w, h, flipX or flipY.
+//
 // Usage:
 //
 //	Spr(spriteNumber, x, y)
 //	Spr(spriteNumber, x, y, w, h)
 //	Spr(spriteNumber, x, y, w, h, flipX)
 //	Spr(spriteNumber, x, y, w, h, flipX, flipY)
+//	Spr(spriteNumber, x, y, WithPalette(map[int]int{8: 12}))
+//	Spr(spriteNumber, x, y, WithAnchor(AnchorCenter))
 //
 // Example:
 //
@@ -96,23 +110,17 @@ func Spr[SN Number, X Number, Y Number](spriteNumber SN, x X, y Y, options ...an
 
 	// Apply camera offset before using coordinates for drawing
 	screenFx, screenFy := applyCameraOffset(fx, fy)
-	// Always round destination coordinates to nearest integer for pixel-perfect rendering
-	screenFx = math.Round(screenFx)
-	screenFy = math.Round(screenFy)
 
 	// Use internal package variables set by engine.Draw
 	if currentScreen == nil {
-		log.Println("Warning: Spr() called before screen was ready.")
+		warnScreenNotReady("Spr")
 		return
 	}
+	beforeScreenDraw()
 
 	// --- Lazy Loading Logic ---
-	if currentSprites == nil {
-		loaded, err := loadSpritesheet() // Call the loading function from spritesheet.go
-		if err != nil {
-			log.Fatalf("Fatal: Failed to load required spritesheet for Spr(): %v", err)
-		}
-		currentSprites = loaded // Store successfully loaded sprites
+	if !ensureSpritesLoaded("Spr") {
+		return
 	}
 
 	// Find the sprite by ID or index
@@ -123,6 +131,8 @@ func Spr[SN Number, X Number, Y Number](spriteNumber SN, x X, y Y, options ...an
 	}
 
 	// Parse optional arguments
+	options, remap := splitSpritePalette(options)
+	options, anchor := splitSpriteAnchor(options)
 	scaleW, scaleH, flipX, flipY := parseSprOptions(options)
 
 	// Get sprite dimensions
@@ -130,13 +140,23 @@ func Spr[SN Number, X Number, Y Number](spriteNumber SN, x X, y Y, options ...an
 	spriteWidth := float64(tileImage.Bounds().Dx())
 	spriteHeight := float64(tileImage.Bounds().Dy())
 
-	// Create a transparent version of the sprite
-	tempImage := createTransparentSpriteImage(tileImage)
+	// Create a transparent (and possibly recolored) version of the sprite
+	var tempImage *ebiten.Image
+	if len(remap) > 0 {
+		tempImage = createRemappedSpriteImage(tileImage, remap)
+	} else {
+		tempImage = createTransparentSpriteImage(tileImage)
+	}
 
 	// Calculate final dimensions
 	destWidth := spriteWidth * scaleW
 	destHeight := spriteHeight * scaleH
 
+	// Move the anchor onto (x, y), then round destination coordinates to whole
+	// pixels, unless sub-pixel sprites are on
+	offsetX, offsetY := anchor.offset(destWidth, destHeight)
+	screenFx, screenFy = snapSpritePosition(screenFx+offsetX, screenFy+offsetY)
+
 	// Setup drawing options
 	opts := setupDrawOptions(screenFx, screenFy, destWidth, destHeight, scaleW, scaleH, flipX, flipY)
 
@@ -225,10 +245,49 @@ func parseSprOptions(options []any) (scaleW float64, scaleH float64, flipX bool,
 	return scaleW, scaleH, flipX, flipY
 }
 
-// createTransparentSpriteImage creates a transparent version of a sprite, with caching
+// maxSpriteCacheSize bounds spriteCache, which is emptied when full.
+const maxSpriteCacheSize = 2048
+
+// transparencyKey identifies which palette colors are transparent, so images
+// built for one Palt setting are reused whenever it comes back.
+type transparencyKey struct {
+	bits  [4]uint64 // Colors 0-255
+	extra string    // Colors from 256 on, for very large palettes
+}
+
+// spriteCacheKey identifies a transparent sprite image in spriteCache.
+type spriteCacheKey struct {
+	image        *ebiten.Image
+	transparency transparencyKey
+}
+
+// currentTransparencyKey returns the transparencyKey of the current Palt setting.
+func currentTransparencyKey() transparencyKey {
+	var key transparencyKey
+	var extra []byte
+	for i, transparent := range paletteTransparency {
+		switch {
+		case i < 256 && transparent:
+			key.bits[i/64] |= 1 << (i % 64)
+		case i >= 256 && transparent:
+			extra = append(extra, '1')
+		case i >= 256:
+			extra = append(extra, '0')
+		}
+	}
+	key.extra = string(extra)
+	return key
+}
+
+// createTransparentSpriteImage creates a transparent version of a sprite, with caching.
+// Pixels whose palette color is marked transparent (see Palt) are cleared.
+// Images are cached per sprite and transparency, so toggling Palt around a
+// few draws every frame doesn't rebuild them.
 func createTransparentSpriteImage(tileImage *ebiten.Image) *ebiten.Image {
+	key := spriteCacheKey{image: tileImage, transparency: currentTransparencyKey()}
+
 	spriteCacheMutex.RLock()
-	if cached, exists := spriteCache[tileImage]; exists {
+	if cached, exists := spriteCache[key]; exists {
 		spriteCacheMutex.RUnlock()
 		return cached
 	}
@@ -243,44 +302,206 @@ func createTransparentSpriteImage(tileImage *ebiten.Image) *ebiten.Image {
 	sourcePixels := make([]byte, width*height*4)
 	tileImage.ReadPixels(sourcePixels)
 
-	// Create destination pixel buffer
-	destPixels := make([]byte, width*height*4)
-
-	// Process pixels in memory (much faster than individual At()/Set() calls)
-	for i := 0; i < len(sourcePixels); i += 4 {
-		r, g, b, a := sourcePixels[i], sourcePixels[i+1], sourcePixels[i+2], sourcePixels[i+3]
-
-		// Check if this pixel should be transparent (color 0 or fully transparent)
-		if a == 0 || (r == 0 && g == 0 && b == 0 && a == 255) {
-			// Skip setting transparent pixels - leave as 0
-			continue
-		}
-
-		// Copy pixel to destination
-		destPixels[i] = r   // Red
-		destPixels[i+1] = g // Green
-		destPixels[i+2] = b // Blue
-		destPixels[i+3] = a // Alpha
-	}
+	destPixels := transparentSpritePixels(sourcePixels)
 
 	// Upload all pixels to GPU in one operation
 	tempImage.WritePixels(destPixels)
 
 	// Cache the result
 	spriteCacheMutex.Lock()
-	spriteCache[tileImage] = tempImage
+	if len(spriteCache) >= maxSpriteCacheSize {
+		spriteCache = make(map[spriteCacheKey]*ebiten.Image)
+	}
+	spriteCache[key] = tempImage
 	spriteCacheMutex.Unlock()
 
 	return tempImage
 }
 
+// transparentSpritePixels returns a copy of RGBA pixel data with every pixel
+// that is already fully transparent, or whose color maps to a transparent
+// palette index, cleared. Colors that are not in the palette are kept.
+func transparentSpritePixels(src []byte) []byte {
+	transparentRGBA := make(map[[4]byte]bool, len(pico8Palette))
+	for i := len(pico8Palette) - 1; i >= 0; i-- {
+		r, g, b, a := pico8Palette[i].RGBA()
+		key := [4]byte{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+		// Going backwards lets the lowest index win if a color appears twice
+		transparentRGBA[key] = i < len(paletteTransparency) && paletteTransparency[i]
+	}
+
+	dst := make([]byte, len(src))
+	for i := 0; i+3 < len(src); i += 4 {
+		px := [4]byte{src[i], src[i+1], src[i+2], src[i+3]}
+		if px[3] == 0 || transparentRGBA[px] {
+			continue
+		}
+		copy(dst[i:i+4], px[:])
+	}
+	return dst
+}
+
 // ClearSpriteCache clears the sprite cache (useful for memory management)
 func ClearSpriteCache() {
 	spriteCacheMutex.Lock()
-	spriteCache = make(map[*ebiten.Image]*ebiten.Image)
+	spriteCache = make(map[spriteCacheKey]*ebiten.Image)
+	remappedSpriteCache = make(map[remappedSpriteKey]*ebiten.Image)
+	spriteRegionCache = make(map[spriteRegionKey]*ebiten.Image)
 	spriteCacheMutex.Unlock()
 }
 
+// --- Per-draw palette remapping ---
+
+// SpritePalette is a Spr option that recolors the sprite for that one draw:
+// each pixel of color key is drawn with color value instead. Create it with
+// WithPalette.
+type SpritePalette map[int]int
+
+// WithPalette returns a Spr option that swaps colors for a single draw,
+// without touching global state like Pal does. Transparency (see Palt) is
+// decided by the original colors, before they are swapped.
+//
+// Recolored sprites are cached per sprite and per remap, so drawing the same
+// few variations every frame is cheap.
+//
+// Example:
+//
+//	red := WithPalette(map[int]int{12: 8}) // blue (12) becomes red (8)
+//	for _, p := range players {
+//		if p.team == redTeam {
+//			Spr(1, p.x, p.y, red)
+//		} else {
+//			Spr(1, p.x, p.y) // original blue
+//		}
+//	}
+//
+//	// Combined with the other options
+//	Spr(1, x, y, 2, 2, true, WithPalette(map[int]int{7: 10}))
+func WithPalette(remap map[int]int) SpritePalette {
+	return SpritePalette(remap)
+}
+
+// remappedSpriteKey identifies a recolored sprite in remappedSpriteCache.
+type remappedSpriteKey struct {
+	image        *ebiten.Image
+	remap        string // The remap's non-identity entries, sorted, e.g. "8:12,12:8"
+	transparency transparencyKey
+}
+
+// splitSpritePalette removes SpritePalette options from options and returns
+// the remaining positional options and the merged remap (nil if none).
+func splitSpritePalette(options []any) ([]any, SpritePalette) {
+	found := false
+	for _, opt := range options {
+		if _, ok := opt.(SpritePalette); ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return options, nil
+	}
+
+	rest := make([]any, 0, len(options))
+	remap := SpritePalette{}
+	for _, opt := range options {
+		p, ok := opt.(SpritePalette)
+		if !ok {
+			rest = append(rest, opt)
+			continue
+		}
+		for from, to := range p {
+			if from == to {
+				continue
+			}
+			if from < 0 || from >= len(pico8Palette) || to < 0 || to >= len(pico8Palette) {
+				log.Printf("Warning: WithPalette() mapping %d->%d is out of range for a palette of %d colors. Ignoring it.", from, to, len(pico8Palette))
+				continue
+			}
+			remap[from] = to
+		}
+	}
+	return rest, remap
+}
+
+// key returns the remap as a canonical string, so equal remaps share a cache entry.
+func (p SpritePalette) key() string {
+	from := make([]int, 0, len(p))
+	for c := range p {
+		from = append(from, c)
+	}
+	slices.Sort(from)
+
+	var b strings.Builder
+	for i, c := range from {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(c))
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(p[c]))
+	}
+	return b.String()
+}
+
+// createRemappedSpriteImage creates a transparent version of a sprite with
+// its colors swapped by remap, with caching.
+func createRemappedSpriteImage(tileImage *ebiten.Image, remap SpritePalette) *ebiten.Image {
+	key := remappedSpriteKey{image: tileImage, remap: remap.key(), transparency: currentTransparencyKey()}
+
+	spriteCacheMutex.RLock()
+	if cached, exists := remappedSpriteCache[key]; exists {
+		spriteCacheMutex.RUnlock()
+		return cached
+	}
+	spriteCacheMutex.RUnlock()
+
+	width := tileImage.Bounds().Dx()
+	height := tileImage.Bounds().Dy()
+	sourcePixels := make([]byte, width*height*4)
+	tileImage.ReadPixels(sourcePixels)
+
+	tempImage := ebiten.NewImage(width, height)
+	tempImage.WritePixels(remapSpritePixels(transparentSpritePixels(sourcePixels), remap))
+
+	spriteCacheMutex.Lock()
+	if len(remappedSpriteCache) >= maxSpriteCacheSize {
+		remappedSpriteCache = make(map[remappedSpriteKey]*ebiten.Image)
+	}
+	remappedSpriteCache[key] = tempImage
+	spriteCacheMutex.Unlock()
+
+	return tempImage
+}
+
+// remapSpritePixels swaps the colors of RGBA pixel data in place following
+// remap and returns it. Transparent pixels and colors that are not in the
+// palette are left alone.
+func remapSpritePixels(pixels []byte, remap SpritePalette) []byte {
+	paletteRGBA := make([][4]byte, len(pico8Palette))
+	indexOf := make(map[[4]byte]int, len(pico8Palette))
+	for i := len(pico8Palette) - 1; i >= 0; i-- {
+		r, g, b, a := pico8Palette[i].RGBA()
+		paletteRGBA[i] = [4]byte{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+		// Going backwards lets the lowest index win if a color appears twice
+		indexOf[paletteRGBA[i]] = i
+	}
+
+	for i := 0; i+3 < len(pixels); i += 4 {
+		if pixels[i+3] == 0 {
+			continue
+		}
+		index, ok := indexOf[[4]byte{pixels[i], pixels[i+1], pixels[i+2], pixels[i+3]}]
+		if !ok {
+			continue
+		}
+		if to, ok := remap[index]; ok && to >= 0 && to < len(paletteRGBA) {
+			copy(pixels[i:i+4], paletteRGBA[to][:])
+		}
+	}
+	return pixels
+}
+
 // setupDrawOptions creates and configures the drawing options for a sprite
 func setupDrawOptions(fx, fy, destWidth, destHeight, scaleW, scaleH float64, flipX, flipY bool) *ebiten.DrawImageOptions {
 	// Create drawing options
@@ -318,22 +539,12 @@ func setupDrawOptions(fx, fy, destWidth, destHeight, scaleW, scaleH float64, fli
 // If not found, it tries to use the spriteID as an index into the spritesheet.
 // Returns nil if the sprite cannot be found.
 func getSpriteImage(spriteID int) *ebiten.Image {
-	allSprites := getCurrentSprites() // Get sprites from engine
-	if allSprites == nil {
-		// This can happen if sprites haven't been loaded yet.
-		// Attempt to load them, similar to Spr/Sspr.
-		loaded, err := loadSpritesheet()
-		if err != nil {
-			log.Printf("Warning: GetSpriteImage failed to load spritesheet: %v", err)
-			return nil
-		}
-		currentSprites = loaded // Store for future calls within this package
-		allSprites = currentSprites
-		if allSprites == nil { // Still nil after attempt
-			log.Println("Warning: GetSpriteImage called when currentSprites is nil and load failed")
-			return nil
-		}
+	// This can happen if sprites haven't been loaded yet.
+	// Attempt to load them, similar to Spr/Sspr.
+	if !ensureSpritesLoaded("GetSpriteImage") {
+		return nil
 	}
+	allSprites := getCurrentSprites() // Get sprites from engine
 
 	var foundSpriteInfo *spriteInfo
 
@@ -382,26 +593,26 @@ func Sget[X Number, Y Number](x X, y Y) int {
 	px := int(x)
 	py := int(y)
 
+	// Spritesheet pixels live on the GPU, which isn't available without a window
+	if headless {
+		return 0
+	}
+
 	// Ensure spritesheet is loaded
-	if currentSprites == nil {
-		loaded, err := loadSpritesheet()
-		if err != nil {
-			log.Printf("Warning: Failed to load spritesheet for Sget(): %v", err)
-			return 0 // Return 0 if spritesheet couldn't be loaded
-		}
-		currentSprites = loaded
+	if !ensureSpritesLoaded("Sget") {
+		return 0 // Return 0 if spritesheet couldn't be loaded
 	}
 
 	// In PICO-8, sprites are arranged in a grid on the spritesheet
-	// Each sprite is 8x8 pixels, and the spritesheet is 128x128 pixels (16x16 sprites)
+	// Each sprite is 8x8 pixels by default (see SetTileSize), and the spritesheet is 128x128 pixels (16x16 sprites)
 	// Find which sprite contains the specified pixel coordinates
-	spriteX := px / 8                                   // Determine which sprite column contains the pixel
-	spriteY := py / 8                                   // Determine which sprite row contains the pixel
+	spriteX := px / tileSize                            // Determine which sprite column contains the pixel
+	spriteY := py / tileSize                            // Determine which sprite row contains the pixel
 	spriteCellID := calculateSpriteID(spriteX, spriteY) // Calculate sprite ID based on dynamic dimensions
 
 	// Calculate the pixel position within the sprite
-	localX := px % 8 // X position within the sprite (0-7)
-	localY := py % 8 // Y position within the sprite (0-7)
+	localX := px % tileSize // X position within the sprite (0 to tile size - 1)
+	localY := py % tileSize // Y position within the sprite (0 to tile size - 1)
 
 	// Find the sprite with the matching ID
 	for _, sprite := range currentSprites {
@@ -411,7 +622,7 @@ func Sget[X Number, Y Number](x X, y Y) int {
 			if spriteCacheValid[spriteCellID] && spritePixelCache[spriteCellID] != nil {
 				cacheSize := spritePixelCacheSize[spriteCellID]
 				if cacheSize > 0 {
-					offset := (localY*8 + localX) * 4
+					offset := (localY*sprite.Image.Bounds().Dx() + localX) * 4
 					if offset+3 < len(spritePixelCache[spriteCellID]) {
 						r := spritePixelCache[spriteCellID][offset]
 						g := spritePixelCache[spriteCellID][offset+1]
@@ -419,16 +630,9 @@ func Sget[X Number, Y Number](x X, y Y) int {
 						a := spritePixelCache[spriteCellID][offset+3]
 						spritePixelCacheMutex.RUnlock()
 
-						// Create color from RGBA values
-						pixelColor := color.RGBA{r, g, b, a}
-
-						// Find the matching color in the PICO-8 palette
-						for i, color := range pico8Palette {
-							if colorEquals(pixelColor, color) {
-								return i // Return the color index (0-15)
-							}
-						}
-						return 0
+						// Find the matching color in the PICO-8 palette, 0 if none
+						index, _ := paletteIndexOf(color.RGBA{r, g, b, a})
+						return index
 					}
 				}
 			}
@@ -438,13 +642,9 @@ func Sget[X Number, Y Number](x X, y Y) int {
 			pixelColor := sprite.Image.At(localX, localY)
 
 			// Find the matching color in the PICO-8 palette
-			for i, color := range pico8Palette {
-				if colorEquals(pixelColor, color) {
-					return i // Return the color index (0-15)
-				}
-			}
 			// If no matching color found, return 0 (transparent/black)
-			return 0
+			index, _ := paletteIndexOf(pixelColor)
+			return index
 		}
 	}
 
@@ -452,13 +652,6 @@ func Sget[X Number, Y Number](x X, y Y) int {
 	return 0
 }
 
-// colorEquals compares two colors for equality
-func colorEquals(c1, c2 color.Color) bool {
-	r1, g1, b1, a1 := c1.RGBA()
-	r2, g2, b2, a2 := c2.RGBA()
-	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
-}
-
 // Color sets the current draw color to be used by subsequent drawing operations.
 // The color parameter should be a number from 0 to 15 corresponding to the PICO-8 palette.
 //
@@ -479,6 +672,45 @@ func Color(colorIndex int) {
 	cursorColor = colorIndex
 }
 
+// colorStack holds the draw and cursor colors saved by PushColor.
+var colorStack [][2]int
+
+// CurrentColor returns the current draw color, as set by Color or by the
+// last drawing function given a color.
+func CurrentColor() int {
+	return currentDrawColor
+}
+
+// PushColor saves the current draw color and the Print cursor color so
+// PopColor can restore them. Pushes nest, so drawing helpers can change the
+// color freely without clobbering the caller's.
+//
+// Example:
+//
+//	func drawBadge(x, y int) {
+//		PushColor()
+//		defer PopColor()
+//		Color(10)
+//		Circfill(x, y, 3)
+//		Print("!", x-1, y-2, 0)
+//	}
+func PushColor() {
+	colorStack = append(colorStack, [2]int{currentDrawColor, cursorColor})
+}
+
+// PopColor restores the draw color and the Print cursor color saved by the
+// last PushColor. Calling it without a matching PushColor logs a warning and
+// does nothing.
+func PopColor() {
+	if len(colorStack) == 0 {
+		log.Printf("Warning: PopColor() called without a matching PushColor()")
+		return
+	}
+	saved := colorStack[len(colorStack)-1]
+	colorStack = colorStack[:len(colorStack)-1]
+	currentDrawColor, cursorColor = saved[0], saved[1]
+}
+
 // Sset sets the color of a pixel at the specified coordinates on the spritesheet.
 // If the optional color parameter is not provided, it uses the current draw color.
 //
@@ -543,13 +775,8 @@ func Color(colorIndex int) {
 // value: (optional) true/false to turn the flag on/off.
 func Fset(spriteNum int, flagOrValue interface{}, value ...interface{}) {
 	// Lazy-load sprites if needed
-	if currentSprites == nil {
-		sprites, err := loadSpritesheet()
-		if err != nil {
-			log.Printf("Warning: Fset() called but failed to load spritesheet: %v", err)
-			return
-		}
-		currentSprites = sprites
+	if !ensureSpritesLoaded("Fset") {
+		return
 	}
 
 	// Find the sprite with the matching ID
@@ -651,13 +878,8 @@ func Fset(spriteNum int, flagOrValue interface{}, value ...interface{}) {
 // When a flag is specified, check isSet for that specific flag's status.
 func Fget(spriteNum int, flag ...int) (bitfield int, isSet bool) {
 	// Lazy-load sprites if needed
-	if currentSprites == nil {
-		sprites, err := loadSpritesheet()
-		if err != nil {
-			log.Printf("Warning: Fget() called but failed to load spritesheet: %v", err)
-			return 0, false
-		}
-		currentSprites = sprites
+	if !ensureSpritesLoaded("Fget") {
+		return 0, false
 	}
 
 	// Find the sprite with the matching ID
@@ -696,6 +918,112 @@ func Fget(spriteNum int, flag ...int) (bitfield int, isSet bool) {
 	return bitfield, isSet
 }
 
+// --- Flag helpers ---
+
+// HasFlag reports whether flag (0-7) is set on sprite spriteNum. It is the
+// same as the isSet result of Fget(spriteNum, flag), without the bitfield.
+//
+// Games usually name their flags with constants:
+//
+//	const FlagSolid = 0
+//
+//	if HasFlag(Mget(tx, ty), FlagSolid) {
+//		player.vy = 0
+//	}
+func HasFlag(spriteNum, flag int) bool {
+	_, isSet := Fget(spriteNum, flag)
+	return isSet
+}
+
+// SetFlag sets flag (0-7) on sprite spriteNum, like Fset(spriteNum, flag, true).
+func SetFlag(spriteNum, flag int) {
+	updateFlag("SetFlag", spriteNum, flag, func(bool) bool { return true })
+}
+
+// ClearFlag clears flag (0-7) on sprite spriteNum, like Fset(spriteNum, flag, false).
+func ClearFlag(spriteNum, flag int) {
+	updateFlag("ClearFlag", spriteNum, flag, func(bool) bool { return false })
+}
+
+// ToggleFlag flips flag (0-7) on sprite spriteNum and returns its new state.
+//
+// Example:
+//
+//	if Btnp(X) {
+//		ToggleFlag(door, FlagSolid) // Open or close the door
+//	}
+func ToggleFlag(spriteNum, flag int) bool {
+	return updateFlag("ToggleFlag", spriteNum, flag, func(set bool) bool { return !set })
+}
+
+// FlagsOf returns the 8 flags of sprite spriteNum, flag 0 first, or nil if
+// there is no such sprite.
+//
+// Example:
+//
+//	for flag, set := range FlagsOf(12) {
+//		if set {
+//			Print(flag, flag*8, 0, 7)
+//		}
+//	}
+func FlagsOf(spriteNum int) []bool {
+	if !ensureSpritesLoaded("FlagsOf") {
+		return nil
+	}
+	sprite := findSpriteFlags(spriteNum)
+	if sprite == nil {
+		log.Printf("Warning: FlagsOf() called for non-existent sprite ID %d", spriteNum)
+		return nil
+	}
+	flags := make([]bool, 8)
+	for i := range flags {
+		flags[i] = sprite.Flags.Bitfield&(1<<i) != 0
+	}
+	return flags
+}
+
+// updateFlag sets flag on sprite spriteNum to update(its current state) and
+// returns the new state, keeping the bitfield and the individual flags in sync.
+func updateFlag(caller string, spriteNum, flag int, update func(bool) bool) bool {
+	if !ensureSpritesLoaded(caller) {
+		return false
+	}
+	sprite := findSpriteFlags(spriteNum)
+	if sprite == nil {
+		log.Printf("Warning: %s() called for non-existent sprite ID %d", caller, spriteNum)
+		return false
+	}
+	if flag < 0 || flag > 7 {
+		log.Printf("Warning: %s() called with invalid flag number %d. Valid range is 0-7.", caller, flag)
+		return false
+	}
+	set := update(sprite.Flags.Bitfield&(1<<flag) != 0)
+	if set {
+		sprite.Flags.Bitfield |= 1 << flag
+	} else {
+		sprite.Flags.Bitfield &^= 1 << flag
+	}
+	if len(sprite.Flags.Individual) < 8 {
+		individual := make([]bool, 8)
+		copy(individual, sprite.Flags.Individual)
+		sprite.Flags.Individual = individual
+	}
+	sprite.Flags.Individual[flag] = set
+	return set
+}
+
+// findSpriteFlags returns the loaded sprite with number spriteNum, or nil.
+// Unlike findSpriteByID it doesn't fall back to the slice index, matching
+// Fget and Fset.
+func findSpriteFlags(spriteNum int) *spriteInfo {
+	for i := range currentSprites {
+		if currentSprites[i].ID == spriteNum {
+			return &currentSprites[i]
+		}
+	}
+	return nil
+}
+
 // Sset sets the color of a pixel at the specified coordinates on the spritesheet.
 // If the optional color parameter is not provided, it uses the current draw color.
 //
@@ -719,26 +1047,26 @@ func Sset[X Number, Y Number](x X, y Y, colorIndex ...int) {
 		}
 	}
 
+	// Spritesheet pixels live on the GPU, which isn't available without a window
+	if headless {
+		return
+	}
+
 	// Ensure spritesheet is loaded
-	if currentSprites == nil {
-		loaded, err := loadSpritesheet()
-		if err != nil {
-			log.Printf("Warning: Failed to load spritesheet for Sset(): %v", err)
-			return // Can't set pixel if spritesheet couldn't be loaded
-		}
-		currentSprites = loaded
+	if !ensureSpritesLoaded("Sset") {
+		return // Can't set pixel if spritesheet couldn't be loaded
 	}
 
 	// In PICO-8, sprites are arranged in a grid on the spritesheet
-	// Each sprite is 8x8 pixels, and the spritesheet is 128x128 pixels (16x16 sprites)
+	// Each sprite is 8x8 pixels by default (see SetTileSize), and the spritesheet is 128x128 pixels (16x16 sprites)
 	// Find which sprite contains the specified pixel coordinates
-	spriteX := px / 8                                   // Determine which sprite column contains the pixel
-	spriteY := py / 8                                   // Determine which sprite row contains the pixel
+	spriteX := px / tileSize                            // Determine which sprite column contains the pixel
+	spriteY := py / tileSize                            // Determine which sprite row contains the pixel
 	spriteCellID := calculateSpriteID(spriteX, spriteY) // Calculate sprite ID based on dynamic dimensions
 
 	// Calculate the pixel position within the sprite
-	localX := px % 8 // X position within the sprite (0-7)
-	localY := py % 8 // Y position within the sprite (0-7)
+	localX := px % tileSize // X position within the sprite (0 to tile size - 1)
+	localY := py % tileSize // Y position within the sprite (0 to tile size - 1)
 
 	// Find the sprite with the matching ID
 	for i := range currentSprites {
@@ -854,47 +1182,81 @@ func parseSsprOptions(options []any, sourceWidth, sourceHeight int) (destWidth,
 	return destWidth, destHeight, flipX, flipY
 }
 
-// createSpriteSourceImage creates a temporary image from the specified region of the spritesheet
-func createSpriteSourceImage(sourceX, sourceY, sourceWidth, sourceHeight int) *ebiten.Image {
-	// Create a temporary image for the source region with transparency
-	sourceImage := ebiten.NewImage(sourceWidth, sourceHeight)
+// spriteRegionKey identifies a spritesheet region, and its remap, in spriteRegionCache.
+type spriteRegionKey struct {
+	x, y, width, height int
+	remap               string
+	transparency        transparencyKey
+}
 
-	// Clear the image with transparent color
-	sourceImage.Fill(color.RGBA{0, 0, 0, 0})
+// maxSpriteRegionCacheSize bounds spriteRegionCache, which is emptied when full.
+const maxSpriteRegionCacheSize = 256
 
-	// Create pixel buffer for batch operations
-	pixels := make([]byte, sourceWidth*sourceHeight*4)
+// spriteRegionTile is a sprite that overlaps a spritesheet region, with the
+// offset of its top-left corner inside the region.
+type spriteRegionTile struct {
+	image  *ebiten.Image
+	dx, dy int
+}
 
-	// Process all pixels in batch
-	for y := 0; y < sourceHeight; y++ {
-		for x := 0; x < sourceWidth; x++ {
-			// Get the color at this position on the spritesheet
-			colorIndex := Sget(sourceX+x, sourceY+y)
+// createSpriteSourceImage returns a transparent (and possibly recolored)
+// image of a spritesheet region, with caching. It is assembled from the same
+// cached sprite images Spr draws, so Spr and Sspr treat Palt transparency
+// and colors alike.
+func createSpriteSourceImage(sourceX, sourceY, sourceWidth, sourceHeight int, remap SpritePalette) *ebiten.Image {
+	key := spriteRegionKey{sourceX, sourceY, sourceWidth, sourceHeight, remap.key(), currentTransparencyKey()}
 
-			// Skip transparent pixels based on the palette transparency settings
-			if colorIndex >= 0 && colorIndex < len(paletteTransparency) && paletteTransparency[colorIndex] {
-				// Skip this pixel, leaving it transparent
-				continue
-			}
+	spriteCacheMutex.RLock()
+	if cached, exists := spriteRegionCache[key]; exists {
+		spriteCacheMutex.RUnlock()
+		return cached
+	}
+	spriteCacheMutex.RUnlock()
 
-			if colorIndex >= 0 && colorIndex < len(pico8Palette) {
-				// Set the pixel in the buffer
-				offset := (y*sourceWidth + x) * 4
-				r, g, b, a := pico8Palette[colorIndex].RGBA()
-				pixels[offset] = uint8(r >> 8)   // Red
-				pixels[offset+1] = uint8(g >> 8) // Green
-				pixels[offset+2] = uint8(b >> 8) // Blue
-				pixels[offset+3] = uint8(a >> 8) // Alpha
-			}
+	// Parts of the region without a sprite stay transparent
+	sourceImage := ebiten.NewImage(sourceWidth, sourceHeight)
+	for _, tile := range spriteRegionTiles(sourceX, sourceY, sourceWidth, sourceHeight) {
+		var tileImage *ebiten.Image
+		if len(remap) > 0 {
+			tileImage = createRemappedSpriteImage(tile.image, remap)
+		} else {
+			tileImage = createTransparentSpriteImage(tile.image)
 		}
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Translate(float64(tile.dx), float64(tile.dy))
+		sourceImage.DrawImage(tileImage, opts)
 	}
 
-	// Upload all pixels to GPU in one operation
-	sourceImage.WritePixels(pixels)
+	spriteCacheMutex.Lock()
+	if len(spriteRegionCache) >= maxSpriteRegionCacheSize {
+		spriteRegionCache = make(map[spriteRegionKey]*ebiten.Image)
+	}
+	spriteRegionCache[key] = sourceImage
+	spriteCacheMutex.Unlock()
 
 	return sourceImage
 }
 
+// spriteRegionTiles returns the loaded sprites whose cell on the spritesheet
+// (see SetTileSize) overlaps the given region, like Sget finds them.
+func spriteRegionTiles(sourceX, sourceY, sourceWidth, sourceHeight int) []spriteRegionTile {
+	if sourceWidth <= 0 || sourceHeight <= 0 || spritesheetColumns <= 0 {
+		return nil
+	}
+	minCol, minRow := floorDiv(sourceX, tileSize), floorDiv(sourceY, tileSize)
+	maxCol, maxRow := floorDiv(sourceX+sourceWidth-1, tileSize), floorDiv(sourceY+sourceHeight-1, tileSize)
+
+	var tiles []spriteRegionTile
+	for _, sprite := range currentSprites {
+		col, row := sprite.ID%spritesheetColumns, sprite.ID/spritesheetColumns
+		if sprite.Image == nil || sprite.ID < 0 || col < minCol || col > maxCol || row < minRow || row > maxRow {
+			continue
+		}
+		tiles = append(tiles, spriteRegionTile{image: sprite.Image, dx: col*tileSize - sourceX, dy: row*tileSize - sourceY})
+	}
+	return tiles
+}
+
 // Sspr draws a sprite from the spritesheet with custom dimensions and optional stretching and flipping.
 // Mimics PICO-8's sspr(sx, sy, sw, sh, dx, dy, [dw, dh], [flip_x], [flip_y]) function.
 //
@@ -922,6 +1284,14 @@ func createSpriteSourceImage(sourceX, sourceY, sourceWidth, sourceHeight int) *e
 //
 //	// Draw a 16x16 sprite, flipped horizontally
 //	Sspr(8, 8, 16, 16, 10, 20, 16, 16, true, false)
+//
+// Transparency (see Palt) works exactly like in Spr, and WithPalette can be
+// passed after dy to recolor the region for one draw. The region's image is
+// cached, so drawing the same region every frame is cheap. WithAnchor places
+// another point of the drawn region than its top-left corner on (dx, dy).
+//
+//	Sspr(8, 8, 16, 16, 10, 20, WithPalette(map[int]int{12: 8}))
+//	Sspr(8, 8, 16, 16, 64, 64, 32, 32, WithAnchor(AnchorCenter))
 func Sspr[SX Number, SY Number, SW Number, SH Number, DX Number, DY Number](sx SX, sy SY, sw SW, sh SH, dx DX, dy DY, options ...any) {
 	// Convert generic types to required types
 	sourceX := int(sx)      // Source X on spritesheet
@@ -930,29 +1300,29 @@ func Sspr[SX Number, SY Number, SW Number, SH Number, DX Number, DY Number](sx S
 	sourceHeight := int(sh) // Source height on spritesheet
 	destX := float64(dx)
 	destY := float64(dy)
-	// Always round destination coordinates to nearest integer for pixel-perfect rendering
-	destX = math.Round(destX)
-	destY = math.Round(destY)
 
 	// Use internal package variables set by engine.Draw
 	if currentScreen == nil {
-		log.Println("Warning: Sspr() called before screen was ready.")
+		warnScreenNotReady("Sspr")
 		return
 	}
+	beforeScreenDraw()
 
 	// --- Lazy Loading Logic ---
-	if currentSprites == nil {
-		loaded, err := loadSpritesheet()
-		if err != nil {
-			log.Printf("Warning: Failed to load spritesheet for Sspr(): %v", err)
-			return
-		}
-		currentSprites = loaded
+	if !ensureSpritesLoaded("Sspr") {
+		return
 	}
 
 	// Parse optional arguments
+	options, remap := splitSpritePalette(options)
+	options, anchor := splitSpriteAnchor(options)
 	destWidth, destHeight, flipX, flipY := parseSsprOptions(options, sourceWidth, sourceHeight)
 
+	// Move the anchor onto (dx, dy), then round destination coordinates to
+	// whole pixels, unless sub-pixel sprites are on
+	offsetX, offsetY := anchor.offset(destWidth, destHeight)
+	destX, destY = snapSpritePosition(destX+offsetX, destY+offsetY)
+
 	// Validate source rectangle is within spritesheet bounds
 	if !validateSpriteSheetBounds(sourceX, sourceY, sourceWidth, sourceHeight) {
 		log.Printf("Warning: Sspr() source rectangle (%d,%d,%d,%d) is outside spritesheet bounds (0,0,%d,%d)",
@@ -967,8 +1337,19 @@ func Sspr[SX Number, SY Number, SW Number, SH Number, DX Number, DY Number](sx S
 		return // Don't draw if scaled to zero size
 	}
 
-	// Create a temporary image for the source region
-	sourceImage := createSpriteSourceImage(sourceX, sourceY, sourceWidth, sourceHeight)
+	// Get the (cached) image of the source region
+	sourceImage := createSpriteSourceImage(sourceX, sourceY, sourceWidth, sourceHeight, remap)
+	drawSsprImage(sourceImage, destX, destY, destWidth, destHeight, flipX, flipY)
+}
+
+// drawSsprImage draws sourceImage to the screen with its top-left corner at
+// (destX, destY) before the camera, stretched to destWidth x destHeight and
+// flipped as requested. It is the transform shared by Sspr and SsprFrom.
+func drawSsprImage(sourceImage *ebiten.Image, destX, destY, destWidth, destHeight float64, flipX, flipY bool) {
+	sourceWidth, sourceHeight := sourceImage.Bounds().Dx(), sourceImage.Bounds().Dy()
+	if sourceWidth == 0 || sourceHeight == 0 {
+		return
+	}
 
 	// Set up drawing options
 	op := &ebiten.DrawImageOptions{}
@@ -1045,6 +1426,7 @@ func flushSpriteModifications() {
 
 			// Upload all changes back to GPU in one operation
 			sprite.WritePixels(pixels)
+			invalidateSpriteImageCaches(sprite)
 
 			// Update sprite pixel cache after modifications
 			// Find sprite ID by searching through currentSprites
@@ -1061,6 +1443,24 @@ func flushSpriteModifications() {
 	spriteModifications = make(map[*ebiten.Image][]pixelMod)
 }
 
+// invalidateSpriteImageCaches drops the cached transparent images made from
+// sprite, so Spr and Sspr show its new pixels.
+func invalidateSpriteImageCaches(sprite *ebiten.Image) {
+	spriteCacheMutex.Lock()
+	defer spriteCacheMutex.Unlock()
+	for key := range spriteCache {
+		if key.image == sprite {
+			delete(spriteCache, key)
+		}
+	}
+	for key := range remappedSpriteCache {
+		if key.image == sprite {
+			delete(remappedSpriteCache, key)
+		}
+	}
+	clear(spriteRegionCache)
+}
+
 // initSpritePixelCache initializes the sprite pixel cache for batch reading operations
 func initSpritePixelCache(spriteID int, sprite *ebiten.Image) {
 	spritePixelCacheMutex.Lock()
@@ -1,6 +1,7 @@
 package pigo8
 
 import (
+	"image/color"
 	"testing"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -844,3 +845,256 @@ func TestSget(t *testing.T) {
 	// Restore original sprites
 	currentSprites = originalSprites
 }
+
+func TestTransparentSpritePixels(t *testing.T) {
+	originalPalette := pico8Palette
+	originalTransparency := paletteTransparency
+	t.Cleanup(func() {
+		pico8Palette = originalPalette
+		paletteTransparency = originalTransparency
+	})
+
+	// A custom palette where index 0 is an opaque dark blue and index 2 is black
+	pico8Palette = []color.Color{
+		color.RGBA{R: 10, G: 20, B: 60, A: 255},
+		color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		color.RGBA{R: 0, G: 0, B: 0, A: 255},
+	}
+	paletteTransparency = []bool{false, false, true}
+
+	src := []byte{
+		10, 20, 60, 255, // index 0: opaque, kept
+		255, 255, 255, 255, // index 1: kept
+		0, 0, 0, 255, // index 2: transparent, cleared
+		1, 2, 3, 0, // already transparent, cleared
+	}
+	dst := transparentSpritePixels(src)
+
+	assert.Equal(t, []byte{
+		10, 20, 60, 255,
+		255, 255, 255, 255,
+		0, 0, 0, 0,
+		0, 0, 0, 0,
+	}, dst)
+
+	// Black is only special when its palette index is transparent
+	paletteTransparency = []bool{true, false, false}
+	dst = transparentSpritePixels(src)
+	assert.Equal(t, []byte{0, 0, 0, 0}, dst[0:4], "index 0 should now be cleared")
+	assert.Equal(t, []byte{0, 0, 0, 255}, dst[8:12], "black (index 2) should now be kept")
+}
+
+func TestSplitSpritePalette(t *testing.T) {
+	// Without a palette option the options are returned untouched
+	options := []any{2, 2, true}
+	rest, remap := splitSpritePalette(options)
+	assert.Equal(t, options, rest)
+	assert.Nil(t, remap)
+
+	// Palette options are removed wherever they are, and merged
+	rest, remap = splitSpritePalette([]any{WithPalette(map[int]int{8: 12}), 2, 2, WithPalette(map[int]int{7: 10, 3: 3}), true})
+	assert.Equal(t, []any{2, 2, true}, rest)
+	assert.Equal(t, SpritePalette{8: 12, 7: 10}, remap, "identity mappings should be dropped")
+
+	// Out of range colors are ignored
+	_, remap = splitSpritePalette([]any{WithPalette(map[int]int{8: 999, -1: 2})})
+	assert.Empty(t, remap)
+}
+
+func TestSpritePaletteKey(t *testing.T) {
+	assert.Equal(t, "2:9,8:12,12:8", SpritePalette{12: 8, 8: 12, 2: 9}.key())
+	assert.Equal(t, SpritePalette{1: 2, 3: 4}.key(), SpritePalette{3: 4, 1: 2}.key(), "equal remaps should share a cache key")
+}
+
+func TestRemapSpritePixels(t *testing.T) {
+	originalPalette := pico8Palette
+	originalTransparency := paletteTransparency
+	t.Cleanup(func() {
+		pico8Palette = originalPalette
+		paletteTransparency = originalTransparency
+	})
+
+	pico8Palette = []color.Color{
+		color.RGBA{R: 0, G: 0, B: 0, A: 255},
+		color.RGBA{R: 255, G: 0, B: 0, A: 255},
+		color.RGBA{R: 0, G: 0, B: 255, A: 255},
+	}
+	paletteTransparency = []bool{true, false, false}
+
+	src := []byte{
+		0, 0, 0, 255, // index 0: transparent, stays cleared even though it is remapped
+		255, 0, 0, 255, // index 1: becomes index 2
+		0, 0, 255, 255, // index 2: becomes index 1
+		1, 2, 3, 255, // not in the palette: kept
+	}
+	dst := remapSpritePixels(transparentSpritePixels(src), SpritePalette{0: 1, 1: 2, 2: 1})
+
+	assert.Equal(t, []byte{
+		0, 0, 0, 0,
+		0, 0, 255, 255,
+		255, 0, 0, 255,
+		1, 2, 3, 255,
+	}, dst)
+}
+
+func TestSpriteRegionTiles(t *testing.T) {
+	originalSprites, originalColumns := currentSprites, spritesheetColumns
+	t.Cleanup(func() { currentSprites, spritesheetColumns = originalSprites, originalColumns })
+
+	spritesheetColumns = 16
+	first, right, below, far := ebiten.NewImage(8, 8), ebiten.NewImage(8, 8), ebiten.NewImage(8, 8), ebiten.NewImage(8, 8)
+	currentSprites = []spriteInfo{
+		{ID: 17, Image: below},
+		{ID: 1, Image: first},
+		{ID: 2, Image: right},
+		{ID: 40, Image: far},
+	}
+
+	// A 32x32 region at (8, 0) covers columns 1-4 and rows 0-3
+	tiles := spriteRegionTiles(8, 0, 32, 32)
+	assert.ElementsMatch(t, []spriteRegionTile{
+		{image: below, dx: 0, dy: 8},
+		{image: first, dx: 0, dy: 0},
+		{image: right, dx: 8, dy: 0},
+	}, tiles, "sprite 40 (column 8, row 2) is outside the region")
+
+	// Regions not aligned to the 8x8 grid include partly covered sprites
+	tiles = spriteRegionTiles(12, 4, 2, 2)
+	assert.Equal(t, []spriteRegionTile{{image: first, dx: -4, dy: -4}}, tiles)
+
+	assert.Empty(t, spriteRegionTiles(8, 0, 0, 8), "an empty region has no sprites")
+	assert.Empty(t, spriteRegionTiles(-16, -16, 8, 8))
+}
+
+// useTestSpriteCache empties the sprite caches and resets Palt for the test.
+func useTestSpriteCache(t *testing.T) {
+	t.Cleanup(func() {
+		Palt()
+		ClearSpriteCache()
+	})
+	Palt()
+	ClearSpriteCache()
+}
+
+func TestTransparentSpriteCacheFollowsPalt(t *testing.T) {
+	useTestSpriteCache(t)
+	sprite := ebiten.NewImage(8, 8)
+	defaultImage := ebiten.NewImage(8, 8)
+	spriteCache[spriteCacheKey{sprite, currentTransparencyKey()}] = defaultImage
+
+	assert.Same(t, defaultImage, createTransparentSpriteImage(sprite), "repeated draws reuse the cached image")
+
+	Palt(8, true)
+	assert.Equal(t, transparencyKey{bits: [4]uint64{1<<GetTransparentColor() | 1<<8}}, currentTransparencyKey())
+	_, cached := spriteCache[spriteCacheKey{sprite, currentTransparencyKey()}]
+	assert.False(t, cached, "the image built without color 8 transparent must not be used")
+
+	Palt()
+	assert.Same(t, defaultImage, createTransparentSpriteImage(sprite), "the default image is reused once Palt is reset")
+}
+
+func TestSprAndSsprHonorPaltAlike(t *testing.T) {
+	setupRowTest(t)
+	useTestSpriteCache(t)
+	originalSprites := currentSprites
+	t.Cleanup(func() { currentSprites = originalSprites })
+	sprite := ebiten.NewImage(8, 8)
+	currentSprites = []spriteInfo{{ID: 1, Image: sprite}}
+
+	// Reading the drawn pixels back needs a graphics context, so the cache
+	// holds the transparent images Spr would build: if Sspr draws from the
+	// same ones, both skip the same colors.
+	Palt(8, true)
+	paltKey := currentTransparencyKey()
+	spriteCache[spriteCacheKey{sprite, paltKey}] = ebiten.NewImage(8, 8)
+
+	Spr(1, 0, 0)
+	Sspr(8, 0, 8, 8, 8, 0)
+	assert.Len(t, spriteCache, 1, "Sspr draws from the image Spr made transparent, not one of its own")
+	assert.Contains(t, spriteRegionCache, spriteRegionKey{8, 0, 8, 8, "", paltKey})
+
+	Palt()
+	spriteCache[spriteCacheKey{sprite, currentTransparencyKey()}] = ebiten.NewImage(8, 8)
+
+	Spr(1, 0, 0)
+	Sspr(8, 0, 8, 8, 8, 0)
+	assert.Len(t, spriteCache, 2, "both functions use the image for the new transparency")
+	assert.Contains(t, spriteRegionCache, spriteRegionKey{8, 0, 8, 8, "", currentTransparencyKey()},
+		"the region drawn with color 8 transparent isn't reused once Palt changes")
+}
+
+func TestTransparencyKey(t *testing.T) {
+	useTestSpriteCache(t)
+	assert.Equal(t, transparencyKey{bits: [4]uint64{1 << GetTransparentColor()}}, currentTransparencyKey())
+
+	Palt(GetTransparentColor(), false)
+	Palt(15, true)
+	assert.Equal(t, transparencyKey{bits: [4]uint64{1 << 15}}, currentTransparencyKey())
+}
+
+func TestSsetInvalidatesSpriteImages(t *testing.T) {
+	useTestSpriteCache(t)
+	edited, other := ebiten.NewImage(8, 8), ebiten.NewImage(8, 8)
+	key := currentTransparencyKey()
+	spriteCache[spriteCacheKey{edited, key}] = ebiten.NewImage(8, 8)
+	spriteCache[spriteCacheKey{edited, transparencyKey{bits: [4]uint64{0b11}}}] = ebiten.NewImage(8, 8)
+	spriteCache[spriteCacheKey{other, key}] = ebiten.NewImage(8, 8)
+	remappedSpriteCache[remappedSpriteKey{edited, "8:12", key}] = ebiten.NewImage(8, 8)
+	spriteRegionCache[spriteRegionKey{0, 0, 8, 8, "", key}] = ebiten.NewImage(8, 8)
+
+	// What flushSpriteModifications does after writing Sset's pixels
+	invalidateSpriteImageCaches(edited)
+
+	assert.Len(t, spriteCache, 1, "only the edited sprite's images are dropped, for every transparency")
+	assert.Contains(t, spriteCache, spriteCacheKey{other, key})
+	assert.Empty(t, remappedSpriteCache)
+	assert.Empty(t, spriteRegionCache, "Sspr regions may include the edited sprite")
+}
+
+func TestPushPopColor(t *testing.T) {
+	originalDraw, originalCursor := currentDrawColor, cursorColor
+	t.Cleanup(func() {
+		currentDrawColor, cursorColor = originalDraw, originalCursor
+		colorStack = nil
+	})
+
+	Color(8)
+	PushColor()
+	Color(12)
+	PushColor()
+	currentDrawColor = 3 // Like Line with a color, which leaves the cursor color alone
+	assert.Equal(t, 3, CurrentColor())
+
+	PopColor()
+	assert.Equal(t, [2]int{12, 12}, [2]int{currentDrawColor, cursorColor}, "pops should unwind in order")
+	PopColor()
+	assert.Equal(t, [2]int{8, 8}, [2]int{currentDrawColor, cursorColor})
+
+	PopColor() // Unbalanced pop is ignored
+	assert.Equal(t, 8, CurrentColor())
+}
+
+func BenchmarkSspr32(b *testing.B) {
+	setupRowTest(b)
+	originalSprites := currentSprites
+	b.Cleanup(func() {
+		currentSprites = originalSprites
+		ClearSpriteCache()
+	})
+	// The 4x4 sprites of the region, with their transparent images cached;
+	// every draw after the first finds the region in the cache
+	currentSprites = make([]spriteInfo, 0, 16)
+	key := currentTransparencyKey()
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 4; col++ {
+			info := spriteInfo{ID: row*spritesheetColumns + col, Image: ebiten.NewImage(8, 8)}
+			spriteCache[spriteCacheKey{info.Image, key}] = ebiten.NewImage(8, 8)
+			currentSprites = append(currentSprites, info)
+		}
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		Sspr(0, 0, 32, 32, 0, 0)
+	}
+}
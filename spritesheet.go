@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -41,6 +40,10 @@ type spriteSheet struct { // Internal
 	SpriteSheetWidth   int          `json:"SpriteSheetWidth,omitempty"`
 	SpriteSheetHeight  int          `json:"SpriteSheetHeight,omitempty"`
 	Sprites            []spriteData `json:"sprites"`
+	// Animations are optional named sprite sequences, see SpriteAnimation
+	Animations []SpriteAnimation `json:"animations,omitempty"`
+	// FlagNames optionally name the sprite flags by number, see RegisterFlagName
+	FlagNames []string `json:"flagNames,omitempty"`
 }
 
 // --- Sprite sheet dimensions ---
@@ -88,6 +91,20 @@ func loadSpritesheetFromDataForTest(data []byte) ([]spriteInfo, error) {
 
 // loadSpritesheetFromDataInternal is the internal implementation
 func loadSpritesheetFromDataInternal(data []byte, updatePixelCache bool) ([]spriteInfo, error) {
+	sheet, err := parseSpritesheetData(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(sheet.Sprites) == 0 {
+		// Return empty slice, not necessarily an error
+		return []spriteInfo{}, nil
+	}
+	return buildSpritesFromSheet(sheet, updatePixelCache), nil
+}
+
+// parseSpritesheetData unmarshals spritesheet JSON without touching the
+// engine state, so Preload can run it off the game loop.
+func parseSpritesheetData(data []byte) (*spriteSheet, error) {
 	// Basic check if data is empty
 	if len(data) == 0 {
 		return nil, fmt.Errorf("provided spritesheet data is empty")
@@ -107,10 +124,13 @@ func loadSpritesheetFromDataInternal(data []byte, updatePixelCache bool) ([]spri
 		log.Printf(
 			"Warning: No sprites found after unmarshalling spritesheet data. Check JSON format and tags.",
 		)
-		// Return empty slice, not necessarily an error
-		return []spriteInfo{}, nil
 	}
+	return &sheet, nil
+}
 
+// buildSpritesFromSheet applies the sheet dimensions and turns every used sprite
+// into an Ebiten image. It is shared by the JSON loader and the .p8 cart importer.
+func buildSpritesFromSheet(sheet *spriteSheet, updatePixelCache bool) []spriteInfo {
 	// Check for custom spritesheet dimensions in the JSON file
 	if sheet.SpriteSheetColumns > 0 && sheet.SpriteSheetRows > 0 {
 		// Update the global sprite sheet dimensions
@@ -122,9 +142,9 @@ func loadSpritesheetFromDataInternal(data []byte, updatePixelCache bool) ([]spri
 			spritesheetWidth = sheet.SpriteSheetWidth
 			spritesheetHeight = sheet.SpriteSheetHeight
 		} else {
-			// Otherwise calculate them from columns and rows (assuming 8x8 sprites)
-			spritesheetWidth = spritesheetColumns * 8
-			spritesheetHeight = spritesheetRows * 8
+			// Otherwise calculate them from columns and rows (see SetTileSize)
+			spritesheetWidth = spritesheetColumns * tileSize
+			spritesheetHeight = spritesheetRows * tileSize
 		}
 
 		log.Printf("Custom spritesheet dimensions detected: %dx%d sprites (%dx%d pixels)",
@@ -137,6 +157,9 @@ func loadSpritesheetFromDataInternal(data []byte, updatePixelCache bool) ([]spri
 		)
 	}
 
+	setSpriteAnimations(sheet.Animations)
+	setSheetFlagNames(sheet.FlagNames)
+
 	// Process used sprites
 	var loadedSprites []spriteInfo
 	for _, spriteData := range sheet.Sprites {
@@ -165,15 +188,14 @@ func loadSpritesheetFromDataInternal(data []byte, updatePixelCache bool) ([]spri
 			for x, colorIndex := range row {
 				// Use Pico8Palette (defined in screen.go, same package)
 				if colorIndex >= 0 && colorIndex < len(pico8Palette) {
-					// PICO-8 color 0 is often transparent
-					if colorIndex != 0 {
-						offset := (y*spriteData.Width + x) * 4
-						r, g, b, a := pico8Palette[colorIndex].RGBA()
-						pixels[offset] = uint8(r >> 8)   // Red
-						pixels[offset+1] = uint8(g >> 8) // Green
-						pixels[offset+2] = uint8(b >> 8) // Blue
-						pixels[offset+3] = uint8(a >> 8) // Alpha
-					}
+					// Every color is stored, including transparent ones; Spr, Sspr and Map
+					// apply the Palt settings when drawing
+					offset := (y*spriteData.Width + x) * 4
+					r, g, b, a := pico8Palette[colorIndex].RGBA()
+					pixels[offset] = uint8(r >> 8)   // Red
+					pixels[offset+1] = uint8(g >> 8) // Green
+					pixels[offset+2] = uint8(b >> 8) // Blue
+					pixels[offset+3] = uint8(a >> 8) // Alpha
 				} else {
 					log.Printf("Warning: Sprite %d has out-of-range color index %d at (%d, %d)", spriteData.ID, colorIndex, x, y)
 				}
@@ -205,11 +227,11 @@ func loadSpritesheetFromDataInternal(data []byte, updatePixelCache bool) ([]spri
 		)
 	}
 
-	return loadedSprites, nil
+	return loadedSprites
 }
 
-// loadSpritesheet tries to load spritesheet.json from the current directory, then from common locations,
-// then from custom embedded resources, and finally falls back to default embedded resources.
+// loadSpritesheet loads spritesheet.json from disk (the current directory, then common locations)
+// and/or from embedded resources (custom, then default), as chosen with SetAssetSource.
 func loadSpritesheet() ([]spriteInfo, error) {
 	return loadSpritesheetInternal(true)
 }
@@ -223,36 +245,10 @@ func loadSpritesheetForTest() ([]spriteInfo, error) {
 func loadSpritesheetInternal(updatePixelCache bool) ([]spriteInfo, error) {
 	const spritesheetFilename = "spritesheet.json"
 
-	// First try to load from the file system
-	data, err := os.ReadFile(spritesheetFilename)
+	// Load from disk or embedded resources, depending on the asset source
+	data, err := readAssetFile(spritesheetFilename, tryLoadEmbeddedSpritesheet)
 	if err != nil {
-		// Check common alternative locations
-		commonLocations := []string{
-			filepath.Join("assets", spritesheetFilename),
-			filepath.Join("resources", spritesheetFilename),
-			filepath.Join("data", spritesheetFilename),
-			filepath.Join("static", spritesheetFilename),
-		}
-
-		for _, location := range commonLocations {
-			data, err = os.ReadFile(location)
-			if err == nil {
-				log.Printf("Loaded spritesheet from %s", location)
-				break
-			}
-		}
-
-		// If still not found, try embedded resources
-		if err != nil {
-			log.Printf("Spritesheet file not found in common locations, trying embedded resources")
-			embeddedData, embErr := tryLoadEmbeddedSpritesheet()
-			if embErr != nil {
-				return nil, fmt.Errorf("failed to load embedded spritesheet: %w", embErr)
-			}
-			data = embeddedData
-		}
-	} else {
-		log.Printf("Using spritesheet file from current directory: %s", spritesheetFilename)
+		return nil, err
 	}
 
 	// Log memory after reading file
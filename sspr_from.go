@@ -0,0 +1,90 @@
+package pigo8
+
+import (
+	"image"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Sspr from any image ---
+
+// SsprFrom is Sspr for any image instead of the spritesheet: it draws the
+// region (sx, sy, sw, sh) of img at (dx, dy), with the same optional dw, dh,
+// flipX and flipY, the camera, WithPalette and WithAnchor. Use it to draw
+// generated textures, loaded PNGs or an offscreen image with the transforms
+// of Sspr.
+//
+// Pixels with a palette color marked transparent by Palt are skipped, like
+// in Spr, so palette-based images behave like sprites; other colors,
+// including semi-transparent ones, are drawn as they are. Since img can
+// change between draws, the region is read back from the GPU on every call:
+// fine for a few draws per frame, but draw the same static image many times
+// with Sspr from the spritesheet instead. The region is clipped to img, and
+// an empty or nil image draws nothing.
+//
+// Example:
+//
+//	// A 16x16 generated texture, drawn twice as big and mirrored
+//	tex := ebiten.NewImage(16, 16)
+//	for y := 0; y < 16; y++ {
+//		for x := 0; x < 16; x++ {
+//			tex.Set(x, y, GetPaletteColor((x^y)%16))
+//		}
+//	}
+//	SsprFrom(tex, 0, 0, 16, 16, 10, 20, 32, 32, true)
+func SsprFrom[SX Number, SY Number, SW Number, SH Number, DX Number, DY Number](img *ebiten.Image, sx SX, sy SY, sw SW, sh SH, dx DX, dy DY, options ...any) {
+	if img == nil {
+		log.Println("Warning: SsprFrom() called with a nil image. Ignoring.")
+		return
+	}
+	sourceWidth, sourceHeight := int(sw), int(sh)
+
+	if currentScreen == nil {
+		warnScreenNotReady("SsprFrom")
+		return
+	}
+	beforeScreenDraw()
+
+	options, remap := splitSpritePalette(options)
+	options, anchor := splitSpriteAnchor(options)
+	destWidth, destHeight, flipX, flipY := parseSsprOptions(options, sourceWidth, sourceHeight)
+	if destWidth <= 0 || destHeight <= 0 {
+		return
+	}
+	offsetX, offsetY := anchor.offset(destWidth, destHeight)
+	destX, destY := snapSpritePosition(float64(dx)+offsetX, float64(dy)+offsetY)
+
+	region := image.Rect(int(sx), int(sy), int(sx)+sourceWidth, int(sy)+sourceHeight)
+	clipped := region.Intersect(img.Bounds())
+	if clipped.Empty() {
+		return
+	}
+	sourceImage := paletteSourceImage(img.SubImage(clipped).(*ebiten.Image), remap)
+
+	// The parts of the region outside img stay transparent, as in Sspr
+	if clipped != region {
+		full := ebiten.NewImage(sourceWidth, sourceHeight)
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Translate(float64(clipped.Min.X-region.Min.X), float64(clipped.Min.Y-region.Min.Y))
+		full.DrawImage(sourceImage, opts)
+		sourceImage = full
+	}
+	drawSsprImage(sourceImage, destX, destY, destWidth, destHeight, flipX, flipY)
+}
+
+// paletteSourceImage returns a copy of img with the Palt transparent palette
+// colors cleared and remap applied, like the sprite images Spr draws.
+func paletteSourceImage(img *ebiten.Image, remap SpritePalette) *ebiten.Image {
+	bounds := img.Bounds()
+	pixels := make([]byte, bounds.Dx()*bounds.Dy()*4)
+	img.ReadPixels(pixels)
+
+	pixels = transparentSpritePixels(pixels)
+	if len(remap) > 0 {
+		pixels = remapSpritePixels(pixels, remap)
+	}
+	out := ebiten.NewImage(bounds.Dx(), bounds.Dy())
+	out.WritePixels(pixels)
+	return out
+}
@@ -0,0 +1,28 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSsprFromWithoutSomethingToDraw(t *testing.T) {
+	img := ebiten.NewImage(8, 8)
+
+	assert.NotPanics(t, func() {
+		SsprFrom[int, int, int, int, int, int](nil, 0, 0, 8, 8, 0, 0)
+	}, "nil image")
+
+	savedScreen := currentScreen
+	currentScreen = nil
+	assert.NotPanics(t, func() { SsprFrom(img, 0, 0, 8, 8, 0, 0) }, "no screen yet")
+	currentScreen = savedScreen
+
+	setupRowTest(t)
+	assert.NotPanics(t, func() {
+		SsprFrom(img, 8, 8, 4, 4, 0, 0)    // Outside the image
+		SsprFrom(img, 0, 0, 0, 8, 0, 0)    // Empty region
+		SsprFrom(img, 0, 0, 8, 8, 0, 0, 0) // Drawn zero pixels wide
+	})
+}
@@ -0,0 +1,218 @@
+package pigo8
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log"
+	"os"
+)
+
+// --- Console state ---
+
+// consoleStateVersion is the version written by SaveState. Bump it when the
+// meaning of an existing field changes; new optional fields don't need it.
+const consoleStateVersion = 1
+
+// consoleState is the file format of SaveState and LoadState. The sprite
+// sheet and map reuse the spritesheet.json and map.json formats.
+type consoleState struct {
+	Version          int          `json:"version"`
+	Palette          [][4]uint8   `json:"palette"`      // RGBA of every palette color
+	Transparency     []bool       `json:"transparency"` // Palt state per palette color
+	TransparentColor int          `json:"transparentColor"`
+	Spritesheet      *spriteSheet `json:"spritesheet,omitempty"` // Pixels as palette indices, and flags
+	Map              mapDataJSON  `json:"map"`
+}
+
+// SaveState writes the whole console state to a file: the sprite sheet
+// pixels and flags, the map, the palette and the transparency settings.
+// LoadState restores it, which makes it suitable for save states, level
+// editors and snapshots of procedurally generated worlds.
+//
+// Sprite pixels live on the GPU, so in headless mode the sprite sheet is
+// left out of the file and LoadState keeps the sprites it has.
+//
+// Example:
+//
+//	if Btnp(X) {
+//		if err := SaveState("quicksave.json"); err != nil {
+//			log.Printf("quick save failed: %v", err)
+//		}
+//	}
+func SaveState(path string) error {
+	data, err := json.Marshal(snapshotConsoleState())
+	if err != nil {
+		return fmt.Errorf("error encoding console state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing console state %s: %w", path, err)
+	}
+	log.Printf("Saved console state to %s", path)
+	return nil
+}
+
+// LoadState restores a console state written by SaveState. The palette is
+// applied first, so the sprites are rebuilt with the saved colors. Files
+// from a newer version of the format are rejected.
+//
+// Example:
+//
+//	if Btnp(O) {
+//		if err := LoadState("quicksave.json"); err != nil {
+//			log.Printf("quick load failed: %v", err)
+//		}
+//	}
+func LoadState(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading console state %s: %w", path, err)
+	}
+	state, err := parseConsoleState(data)
+	if err != nil {
+		return fmt.Errorf("error parsing console state %s: %w", path, err)
+	}
+	applyConsoleState(state)
+	log.Printf("Loaded console state from %s", path)
+	return nil
+}
+
+// parseConsoleState decodes and checks a console state file.
+func parseConsoleState(data []byte) (*consoleState, error) {
+	var state consoleState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	if state.Version < 1 || state.Version > consoleStateVersion {
+		return nil, fmt.Errorf("unsupported version %d (supported: 1-%d)", state.Version, consoleStateVersion)
+	}
+	if len(state.Palette) == 0 {
+		return nil, fmt.Errorf("no palette colors")
+	}
+	return &state, nil
+}
+
+// snapshotConsoleState copies the active palette, transparency, sprites and map.
+func snapshotConsoleState() *consoleState {
+	state := &consoleState{
+		Version:          consoleStateVersion,
+		Transparency:     append([]bool(nil), paletteTransparency...),
+		TransparentColor: transparentColor,
+	}
+	for _, c := range pico8Palette {
+		rgba := color.RGBAModel.Convert(c).(color.RGBA)
+		state.Palette = append(state.Palette, [4]uint8{rgba.R, rgba.G, rgba.B, rgba.A})
+	}
+
+	if headless {
+		log.Println("Warning: SaveState() can't read sprite pixels in headless mode. The sprite sheet is not saved.")
+	} else {
+		state.Spritesheet = snapshotSpriteSheet()
+	}
+
+	ensureStreamingSystemInitialized()
+	worldMapMutex.RLock()
+	if worldMapStream != nil {
+		state.Map.Width = worldMapStream.WorldWidthInTiles
+		state.Map.Height = worldMapStream.WorldHeightInTiles
+		for i, sprite := range worldMapStream.Data {
+			if sprite != 0 {
+				state.Map.Cells = append(state.Map.Cells, mapCellJSON{
+					X:      i % worldMapStream.WorldWidthInTiles,
+					Y:      i / worldMapStream.WorldWidthInTiles,
+					Sprite: sprite,
+				})
+			}
+		}
+	}
+	worldMapMutex.RUnlock()
+
+	return state
+}
+
+// snapshotSpriteSheet reads every loaded sprite back from the GPU as palette
+// indices, including pending Sset changes.
+func snapshotSpriteSheet() *spriteSheet {
+	flushSpriteModifications()
+
+	sheet := &spriteSheet{
+		SpriteSheetColumns: spritesheetColumns,
+		SpriteSheetRows:    spritesheetRows,
+		SpriteSheetWidth:   spritesheetWidth,
+		SpriteSheetHeight:  spritesheetHeight,
+		Animations:         spriteAnimations,
+		FlagNames:          flagNamesForSheet(),
+	}
+	outOfRange := 0
+	for _, sprite := range currentSprites {
+		if sprite.Image == nil {
+			continue
+		}
+		width, height := sprite.Image.Bounds().Dx(), sprite.Image.Bounds().Dy()
+		rgba := make([]byte, width*height*4)
+		sprite.Image.ReadPixels(rgba)
+
+		pixels := make([][]int, height)
+		for y := range pixels {
+			pixels[y] = make([]int, width)
+			for x := range pixels[y] {
+				offset := (y*width + x) * 4
+				index, ok := paletteIndexOf(color.RGBA{rgba[offset], rgba[offset+1], rgba[offset+2], rgba[offset+3]})
+				if !ok {
+					outOfRange++
+				}
+				pixels[y][x] = index
+			}
+		}
+
+		sheet.Sprites = append(sheet.Sprites, spriteData{
+			ID:     sprite.ID,
+			X:      (sprite.ID % spritesheetColumns) * tileSize,
+			Y:      (sprite.ID / spritesheetColumns) * tileSize,
+			Width:  width,
+			Height: height,
+			Pixels: pixels,
+			Flags:  sprite.Flags,
+			Used:   true,
+		})
+	}
+	if outOfRange > 0 {
+		log.Printf("SaveState: %d sprite pixels use colors outside the palette and were written as 0", outOfRange)
+	}
+	return sheet
+}
+
+// applyConsoleState makes state the active palette, transparency, sprites and map.
+func applyConsoleState(state *consoleState) {
+	palette := make([]color.Color, len(state.Palette))
+	for i, c := range state.Palette {
+		palette[i] = color.RGBA{c[0], c[1], c[2], c[3]}
+	}
+	if state.TransparentColor >= 0 && state.TransparentColor < len(palette) {
+		transparentColor = state.TransparentColor
+	}
+	SetPalette(palette)
+	if len(state.Transparency) == len(paletteTransparency) {
+		copy(paletteTransparency, state.Transparency)
+	} else {
+		log.Printf("Warning: LoadState() found %d transparency entries for %d colors. Using the default transparency.",
+			len(state.Transparency), len(paletteTransparency))
+	}
+
+	if state.Spritesheet != nil {
+		ClearSpriteCache()
+		clearSpritePixelCache()
+		currentSprites = buildSpritesFromSheet(state.Spritesheet, !headless)
+		ClearFlagCache()
+	}
+
+	ensureStreamingSystemInitialized()
+	width, height := state.Map.Width, state.Map.Height
+	if width <= 0 {
+		width = defaultPico8MapWidth
+	}
+	if height <= 0 {
+		height = defaultPico8MapHeight
+	}
+	setWorldMap(newTilemapStream(width, height, &state.Map, "console state"))
+}
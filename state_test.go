@@ -0,0 +1,112 @@
+package pigo8
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useTestConsoleState gives the test a 128x128 map and restores the palette,
+// transparency, sprites and map when it ends.
+func useTestConsoleState(t *testing.T) {
+	savedPalette := GetPalette()
+	savedTransparency := append([]bool(nil), paletteTransparency...)
+	savedTransparentColor := transparentColor
+	savedSprites := currentSprites
+	savedStream := worldMapStream
+	savedBuffer := activeTileBufferInstance
+	savedInitialized := streamingSystemInitialized
+	t.Cleanup(func() {
+		transparentColor = savedTransparentColor
+		SetPalette(savedPalette)
+		copy(paletteTransparency, savedTransparency)
+		currentSprites = savedSprites
+		ClearFlagCache()
+		worldMapStream = savedStream
+		activeTileBufferInstance = savedBuffer
+		streamingSystemInitialized = savedInitialized
+		headless = false
+	})
+
+	headless = true // No GPU in tests, so sprite pixels can't be read back
+	streamingSystemInitialized = true
+	currentSprites = []spriteInfo{}
+	worldMapStream = newTilemapStream(defaultPico8MapWidth, defaultPico8MapHeight, nil, "test")
+	activeTileBufferInstance = newActiveTileBuffer(activeTileBufferWidthInTiles, activeTileBufferHeightInTiles)
+}
+
+func TestSaveAndLoadState_RoundTrip(t *testing.T) {
+	useTestConsoleState(t)
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	SetPaletteColor(3, color.RGBA{10, 20, 30, 255})
+	SetTransparentColor(1)
+	Palt(5, true)
+	Mset(2, 3, 42)
+	Mset(127, 63, 7)
+	require.NoError(t, SaveState(path))
+
+	// Change everything that was saved
+	SetPaletteColor(3, color.RGBA{255, 255, 255, 255})
+	SetTransparentColor(0)
+	Mset(2, 3, 0)
+	Mset(10, 10, 9)
+
+	require.NoError(t, LoadState(path))
+	assert.Equal(t, color.RGBA{10, 20, 30, 255}, GetPaletteColor(3))
+	assert.Equal(t, 1, GetTransparentColor())
+	assert.True(t, IsColorTransparent(1))
+	assert.True(t, IsColorTransparent(5))
+	assert.False(t, IsColorTransparent(0))
+	assert.Equal(t, 42, Mget(2, 3))
+	assert.Equal(t, 7, Mget(127, 63))
+	assert.Equal(t, 0, Mget(10, 10), "tiles set after saving are cleared")
+}
+
+func TestApplyConsoleState_Sprites(t *testing.T) {
+	useTestConsoleState(t)
+
+	pixels := [][]int{{0, 1}, {2, 3}}
+	state := snapshotConsoleState()
+	state.Spritesheet = &spriteSheet{Sprites: []spriteData{
+		{ID: 4, Width: 2, Height: 2, Pixels: pixels, Flags: FlagsData{Bitfield: 0b101}, Used: true},
+		{ID: 5, Width: 2, Height: 2, Pixels: pixels, Used: false},
+	}}
+	applyConsoleState(state)
+
+	require.Len(t, currentSprites, 1, "unused sprites are skipped like in spritesheet.json")
+	assert.Equal(t, 4, currentSprites[0].ID)
+	assert.True(t, getCachedFlag(4, 0))
+	assert.False(t, getCachedFlag(4, 1))
+	assert.True(t, getCachedFlag(4, 2))
+
+	// A state without a sprite sheet keeps the sprites
+	state.Spritesheet = nil
+	applyConsoleState(state)
+	assert.Len(t, currentSprites, 1)
+}
+
+func TestParseConsoleState_Errors(t *testing.T) {
+	_, err := parseConsoleState([]byte("{not json"))
+	assert.Error(t, err)
+
+	_, err = parseConsoleState([]byte(`{"version": 2, "palette": [[0, 0, 0, 255]]}`))
+	assert.ErrorContains(t, err, "unsupported version")
+
+	_, err = parseConsoleState([]byte(`{"palette": [[0, 0, 0, 255]]}`))
+	assert.ErrorContains(t, err, "unsupported version", "files without a version are rejected")
+
+	_, err = parseConsoleState([]byte(`{"version": 1, "palette": []}`))
+	assert.ErrorContains(t, err, "no palette")
+
+	state, err := parseConsoleState([]byte(`{"version": 1, "palette": [[1, 2, 3, 255]], "future": true}`))
+	require.NoError(t, err, "unknown fields are ignored so newer optional fields still load")
+	assert.Equal(t, [][4]uint8{{1, 2, 3, 255}}, state.Palette)
+
+	err = LoadState(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
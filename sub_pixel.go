@@ -0,0 +1,51 @@
+package pigo8
+
+import "math"
+
+// --- Sub-pixel sprites ---
+
+// subPixelSprites keeps the fractional part of Spr and Sspr positions
+// instead of rounding them to whole pixels, see SetSubPixelSprites.
+var subPixelSprites bool
+
+// SetSubPixelSprites chooses whether Spr and Sspr draw at fractional
+// positions (on) or round them to the nearest whole pixel first (off, the
+// default, as in PICO-8). It can also be set with Settings.SubPixelSprites.
+//
+// Either way sprites are drawn with nearest-neighbor sampling onto the
+// game's screen, one screen pixel per game pixel, so they always land on
+// whole pixels and never blur. The difference is where the rounding happens:
+//
+//   - Off, a sprite is rounded on its own, before the camera for Sspr and
+//     after it for Spr. Positions are exact and predictable, but with a
+//     fractional camera (e.g. a lerping CameraFollow) sprites can jitter by a
+//     pixel against the map, which Map draws without rounding.
+//   - On, the GPU rounds the final position the same way for sprites and the
+//     map, so they scroll together smoothly at non-integer camera speeds. The
+//     pixel a sprite lands on is less obvious from its coordinates (x.5 can
+//     go either way), and scaled Sspr regions may drop or double a row of
+//     pixels differently from frame to frame.
+//
+// Example:
+//
+//	settings := NewSettings()
+//	settings.SubPixelSprites = true
+//	PlayGameWith(settings)
+func SetSubPixelSprites(on bool) {
+	subPixelSprites = on
+}
+
+// SubPixelSprites reports whether Spr and Sspr draw at fractional positions,
+// see SetSubPixelSprites.
+func SubPixelSprites() bool {
+	return subPixelSprites
+}
+
+// snapSpritePosition rounds a sprite position to whole pixels, unless
+// sub-pixel sprites are on.
+func snapSpritePosition(x, y float64) (float64, float64) {
+	if subPixelSprites {
+		return x, y
+	}
+	return math.Round(x), math.Round(y)
+}
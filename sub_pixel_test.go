@@ -0,0 +1,36 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapSpritePosition(t *testing.T) {
+	t.Cleanup(func() { SetSubPixelSprites(false) })
+
+	assert.False(t, SubPixelSprites(), "PICO-8 rounding by default")
+	x, y := snapSpritePosition(10.4, 20.6)
+	assert.Equal(t, 10.0, x)
+	assert.Equal(t, 21.0, y)
+
+	SetSubPixelSprites(true)
+	assert.True(t, SubPixelSprites())
+	x, y = snapSpritePosition(10.4, 20.6)
+	assert.Equal(t, 10.4, x)
+	assert.Equal(t, 20.6, y)
+}
+
+func TestSubPixelSpritesSetting(t *testing.T) {
+	originalCart := loadedCartridge
+	t.Cleanup(func() {
+		InsertGame(originalCart)
+		SetSubPixelSprites(false)
+	})
+
+	settings := NewSettings()
+	assert.False(t, settings.SubPixelSprites)
+	settings.SubPixelSprites = true
+	NewTestHarness(&walkerCartridge{}, settings)
+	assert.True(t, SubPixelSprites())
+}
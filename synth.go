@@ -0,0 +1,158 @@
+package pigo8
+
+import (
+	"encoding/binary"
+	"log"
+	"math"
+)
+
+// --- Note synthesis ---
+
+// WaveType is the waveform of a note played with PlayNote.
+type WaveType int
+
+const (
+	// WaveSquare is a square wave, the classic chiptune beep.
+	WaveSquare WaveType = iota
+	// WaveTriangle is a triangle wave, softer than a square wave.
+	WaveTriangle
+	// WaveSawtooth is a sawtooth wave, bright and buzzy.
+	WaveSawtooth
+	// WaveSine is a sine wave, a pure tone.
+	WaveSine
+)
+
+// noteGain is the peak level of a note at full volume, leaving headroom for
+// several notes and sound effects playing at once.
+const noteGain = 0.5
+
+// NoteEnvelope shapes the volume of the notes played with PlayNote (ADSR).
+// A note fades in over AttackMs, falls to the Sustain level (0-1) over
+// DecayMs, and fades out over the last ReleaseMs of its duration. Even short
+// fades avoid the click of a tone that starts or stops abruptly.
+type NoteEnvelope struct {
+	AttackMs  int
+	DecayMs   int
+	Sustain   float64
+	ReleaseMs int
+}
+
+// DefaultNoteEnvelope is the envelope PlayNote starts with: a quick fade in
+// and out around a steady tone.
+var DefaultNoteEnvelope = NoteEnvelope{AttackMs: 5, DecayMs: 0, Sustain: 1, ReleaseMs: 20}
+
+// noteEnvelope is the envelope used by PlayNote, set with SetNoteEnvelope.
+var noteEnvelope = DefaultNoteEnvelope
+
+// SetNoteEnvelope changes the envelope of the notes played with PlayNote
+// from now on. Negative times count as 0 and Sustain is clamped to 0-1.
+//
+// Example:
+//
+//	// A plucked sound: instant attack, then fading away
+//	SetNoteEnvelope(NoteEnvelope{AttackMs: 1, DecayMs: 150, Sustain: 0, ReleaseMs: 10})
+//	PlayNote(440, 200, WaveTriangle)
+//	SetNoteEnvelope(DefaultNoteEnvelope)
+func SetNoteEnvelope(env NoteEnvelope) {
+	env.AttackMs = max(env.AttackMs, 0)
+	env.DecayMs = max(env.DecayMs, 0)
+	env.ReleaseMs = max(env.ReleaseMs, 0)
+	env.Sustain = math.Max(0, math.Min(1, env.Sustain))
+	noteEnvelope = env
+}
+
+// PlayNote plays a generated tone, so games can make sound without any
+// audio files. The tone plays once, on top of whatever else is playing,
+// and Sfx(-1) stops it like any sound effect.
+//
+// Args:
+//   - freq: frequency in Hz (e.g. 440 for A4)
+//   - durationMs: length of the note in milliseconds, including the release
+//   - waveform: WaveSquare, WaveTriangle, WaveSawtooth or WaveSine
+//   - volume: (optional) 0 to 1, defaults to 1
+//
+// Example:
+//
+//	if Btnp(O) {
+//		PlayNote(880, 80, WaveSquare) // Short high beep
+//	}
+//	if g.gameOver {
+//		PlayNote(110, 600, WaveSawtooth, 0.5) // Low buzz at half volume
+//	}
+func PlayNote(freq float64, durationMs int, waveform WaveType, volume ...float64) {
+	if freq <= 0 || durationMs <= 0 {
+		log.Printf("Warning: PlayNote() called with frequency %v and duration %dms. Both must be positive. Ignoring.", freq, durationMs)
+		return
+	}
+	if waveform < WaveSquare || waveform > WaveSine {
+		log.Printf("Warning: PlayNote() called with unknown waveform %d. Ignoring.", waveform)
+		return
+	}
+	vol := 1.0
+	if len(volume) > 0 {
+		vol = math.Max(0, math.Min(1, volume[0]))
+	}
+
+	pcm := synthNote(freq, durationMs, waveform, vol, noteEnvelope)
+
+	ap := getAudioPlayer()
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+	ap.pruneSfxPlayers(false)
+	player := ap.audioContext.NewPlayerFromBytes(pcm)
+	player.Play()
+	ap.sfxPlayers = append(ap.sfxPlayers, player)
+}
+
+// synthNote generates a note as 16-bit stereo PCM at sampleRate.
+func synthNote(freq float64, durationMs int, waveform WaveType, volume float64, env NoteEnvelope) []byte {
+	samples := durationMs * sampleRate / 1000
+	pcm := make([]byte, samples*4)
+	for i := 0; i < samples; i++ {
+		phase := math.Mod(float64(i)*freq/sampleRate, 1)
+		level := waveSample(waveform, phase) * envelopeLevel(env, i, samples) * volume * noteGain
+		v := uint16(int16(level * math.MaxInt16))
+		binary.LittleEndian.PutUint16(pcm[i*4:], v)
+		binary.LittleEndian.PutUint16(pcm[i*4+2:], v)
+	}
+	return pcm
+}
+
+// waveSample returns the value (-1 to 1) of a waveform at a phase (0 to 1)
+// of its period.
+func waveSample(waveform WaveType, phase float64) float64 {
+	switch waveform {
+	case WaveSquare:
+		if phase < 0.5 {
+			return 1
+		}
+		return -1
+	case WaveTriangle:
+		return 1 - 4*math.Abs(phase-0.5)
+	case WaveSawtooth:
+		return 2*phase - 1
+	default:
+		return math.Sin(2 * math.Pi * phase)
+	}
+}
+
+// envelopeLevel returns the volume (0 to 1) of sample i of a note that is
+// samples long. The release always ends the note at 0, even when the
+// attack and decay haven't finished yet.
+func envelopeLevel(env NoteEnvelope, i, samples int) float64 {
+	ms := func(n int) float64 { return float64(n) * sampleRate / 1000 }
+	t := float64(i)
+
+	level := env.Sustain
+	switch attack, decay := ms(env.AttackMs), ms(env.DecayMs); {
+	case t < attack:
+		level = t / attack
+	case t < attack+decay:
+		level = 1 - (1-env.Sustain)*(t-attack)/decay
+	}
+
+	if release := ms(env.ReleaseMs); release > 0 {
+		level *= math.Min(1, float64(samples-1-i)/release)
+	}
+	return level
+}
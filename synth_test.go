@@ -0,0 +1,66 @@
+package pigo8
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// noteLevels returns the left channel of synthesized PCM as levels from -1 to 1.
+func noteLevels(pcm []byte) []float64 {
+	levels := make([]float64, len(pcm)/4)
+	for i := range levels {
+		levels[i] = float64(int16(binary.LittleEndian.Uint16(pcm[i*4:]))) / math.MaxInt16
+	}
+	return levels
+}
+
+func TestWaveSample(t *testing.T) {
+	assert.Equal(t, 1.0, waveSample(WaveSquare, 0.25))
+	assert.Equal(t, -1.0, waveSample(WaveSquare, 0.75))
+	assert.Equal(t, 0.0, waveSample(WaveTriangle, 0.25))
+	assert.Equal(t, 1.0, waveSample(WaveTriangle, 0.5))
+	assert.Equal(t, -1.0, waveSample(WaveSawtooth, 0))
+	assert.Equal(t, 0.0, waveSample(WaveSawtooth, 0.5))
+	assert.InDelta(t, 1.0, waveSample(WaveSine, 0.25), 1e-9)
+}
+
+func TestEnvelopeLevel(t *testing.T) {
+	env := NoteEnvelope{AttackMs: 1, DecayMs: 1, Sustain: 0.5, ReleaseMs: 2} // 44.1, 44.1 and 88.2 samples
+	samples := 1000
+
+	assert.Equal(t, 0.0, envelopeLevel(env, 0, samples), "starts silent")
+	assert.InDelta(t, 0.5, envelopeLevel(env, 22, samples), 0.01, "halfway through the attack")
+	assert.InDelta(t, 0.75, envelopeLevel(env, 66, samples), 0.01, "halfway through the decay")
+	assert.Equal(t, 0.5, envelopeLevel(env, 500, samples), "sustain")
+	assert.InDelta(t, 0.25, envelopeLevel(env, samples-1-44, samples), 0.01, "halfway through the release")
+	assert.Equal(t, 0.0, envelopeLevel(env, samples-1, samples), "ends silent")
+
+	flat := NoteEnvelope{Sustain: 1}
+	assert.Equal(t, 1.0, envelopeLevel(flat, 0, samples), "no attack starts at full level")
+	assert.Equal(t, 1.0, envelopeLevel(flat, samples-1, samples), "no release ends at full level")
+}
+
+func TestSynthNote(t *testing.T) {
+	pcm := synthNote(441, 100, WaveSquare, 1, DefaultNoteEnvelope)
+	assert.Len(t, pcm, 4410*4, "100ms of 16-bit stereo")
+	assert.Equal(t, pcm[0:2], pcm[2:4], "both channels are the same")
+
+	levels := noteLevels(pcm)
+	assert.Equal(t, 0.0, levels[0], "fades in instead of clicking")
+	assert.Equal(t, 0.0, levels[len(levels)-1], "fades out instead of clicking")
+	assert.InDelta(t, noteGain, levels[1000], 0.001, "full volume in the first half of a period")
+	assert.InDelta(t, -noteGain, levels[1050], 0.001, "second half of the 100 sample period")
+
+	quiet := noteLevels(synthNote(441, 100, WaveSquare, 0.5, DefaultNoteEnvelope))
+	assert.InDelta(t, noteGain/2, quiet[1000], 0.001)
+}
+
+func TestSetNoteEnvelope(t *testing.T) {
+	t.Cleanup(func() { SetNoteEnvelope(DefaultNoteEnvelope) })
+
+	SetNoteEnvelope(NoteEnvelope{AttackMs: -5, DecayMs: 10, Sustain: 3, ReleaseMs: -1})
+	assert.Equal(t, NoteEnvelope{AttackMs: 0, DecayMs: 10, Sustain: 1, ReleaseMs: 0}, noteEnvelope)
+}
@@ -0,0 +1,138 @@
+package pigo8
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- Aligned text ---
+
+// TextAlign is how PrintAligned and PrintBox place each line of text
+// horizontally.
+type TextAlign int
+
+const (
+	// AlignLeft starts each line at x, like Print.
+	AlignLeft TextAlign = iota
+	// AlignCenter centers each line on x.
+	AlignCenter
+	// AlignRight ends each line at x.
+	AlignRight
+)
+
+// TextWidth returns the width in pixels Print draws s with, the width of its
+// longest line if it has several: 4 pixels per character, including the
+// spacing after the last one.
+//
+// Example:
+//
+//	label := "paused"
+//	Rectfill(64-TextWidth(label)/2-2, 60, 64+TextWidth(label)/2, 68, 0)
+func TextWidth(s string) int {
+	width := 0
+	for _, line := range strings.Split(s, "\n") {
+		width = max(width, lineWidth(line))
+	}
+	return width
+}
+
+// lineWidth returns the width in pixels of a line of text.
+func lineWidth(line string) int {
+	return len([]rune(line)) * int(CharWidthApproximation)
+}
+
+// PrintAligned prints s (converted like Print) with each line aligned on x:
+// starting at x with AlignLeft, centered on x with AlignCenter and ending at
+// x with AlignRight. Lines split on "\n" are aligned separately and drawn
+// 6 pixels apart, so a centered block of text stays centered line by line.
+// Like Print, x and y are moved by the camera (use Camera() first for screen
+// coordinates), the color becomes the cursor color, and the cursor moves to
+// the line below the text at x. It returns the right edge and the bottom of
+// the printed text.
+//
+// Example:
+//
+//	// Centered on the screen, whatever the text's length
+//	PrintAligned("game over\npress o to restart", 64, 56, 8, AlignCenter)
+//
+//	// Right-aligned against the screen edge
+//	PrintAligned(fmt.Sprintf("score: %d", g.score), 124, 4, 7, AlignRight)
+func PrintAligned(s any, x, y, color int, align TextAlign) (int, int) {
+	lines := strings.Split(fmt.Sprintf("%v", s), "\n")
+	// Lines are as far apart as Print moves the cursor down
+	lineHeight := int(defaultFontSize)
+	right := x
+	for i, line := range lines {
+		lineX := alignedLineX(line, x, align)
+		Print(line, lineX, y+i*lineHeight, color)
+		right = max(right, lineX+lineWidth(line))
+	}
+	bottom := y + len(lines)*lineHeight
+	cursorX, cursorY = x, bottom
+	return right, bottom
+}
+
+// PrintBox prints s wrapped to lines at most width pixels wide, breaking at
+// spaces (and inside words longer than a line) and at "\n", with each line
+// aligned in the box: against its left edge x with AlignLeft, centered with
+// AlignCenter or against its right edge x+width with AlignRight. The camera,
+// color and cursor work as in PrintAligned, with the cursor moving to the
+// line below the box at x. It returns the right edge and the bottom of the
+// printed text, e.g. to size a dialog box around it.
+//
+// Example:
+//
+//	_, bottom := PrintBox(g.dialog, 12, 80, 104, 7, AlignCenter)
+//	Rect(10, 78, 117, bottom+1, 7)
+func PrintBox(s any, x, y, width, color int, align TextAlign) (int, int) {
+	anchor := x
+	switch align {
+	case AlignCenter:
+		anchor = x + width/2
+	case AlignRight:
+		anchor = x + width
+	}
+	right, bottom := PrintAligned(strings.Join(wrapText(fmt.Sprintf("%v", s), width), "\n"), anchor, y, color, align)
+	cursorX = x
+	return right, bottom
+}
+
+// alignedLineX returns where a line of text starts to be aligned on x.
+func alignedLineX(line string, x int, align TextAlign) int {
+	switch align {
+	case AlignCenter:
+		return x - lineWidth(line)/2
+	case AlignRight:
+		return x - lineWidth(line)
+	}
+	return x
+}
+
+// wrapText splits s into lines at most width pixels wide, at "\n" and between
+// words, and breaks the words that don't fit on a line of their own. There is
+// at least one character per line, however narrow width is.
+func wrapText(s string, width int) []string {
+	maxChars := max(width/int(CharWidthApproximation), 1)
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		var line []rune
+		for _, word := range strings.Fields(paragraph) {
+			runes := []rune(word)
+			if len(line) > 0 && len(line)+1+len(runes) > maxChars {
+				lines = append(lines, string(line))
+				line = nil
+			}
+			if len(line) > 0 {
+				line = append(line, ' ')
+			}
+			for len(line)+len(runes) > maxChars {
+				n := maxChars - len(line)
+				lines = append(lines, string(append(line, runes[:n]...)))
+				line, runes = nil, runes[n:]
+			}
+			line = append(line, runes...)
+		}
+		lines = append(lines, string(line))
+	}
+	return lines
+}
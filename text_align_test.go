@@ -0,0 +1,45 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextWidth(t *testing.T) {
+	assert.Equal(t, 0, TextWidth(""))
+	assert.Equal(t, 20, TextWidth("hello"))
+	assert.Equal(t, 24, TextWidth("hi\nthere!\nyo"), "the longest line")
+}
+
+func TestAlignedLineX(t *testing.T) {
+	assert.Equal(t, 64, alignedLineX("game over", 64, AlignLeft))
+	assert.Equal(t, 46, alignedLineX("game over", 64, AlignCenter), "the usual x - #s*2")
+	assert.Equal(t, 28, alignedLineX("game over", 64, AlignRight), "the text's advance ends at x")
+}
+
+func TestPrintAlignedCursorAndBounds(t *testing.T) {
+	savedX, savedY, savedColor := cursorX, cursorY, cursorColor
+	t.Cleanup(func() { cursorX, cursorY, cursorColor = savedX, savedY, savedColor })
+
+	right, bottom := PrintAligned("ab\nabcd", 64, 10, 7, AlignCenter)
+	assert.Equal(t, 64+8, right, "the right edge of the widest line")
+	assert.Equal(t, 10+2*6, bottom, "two lines, 6 pixels apart")
+	assert.Equal(t, [2]int{64, 22}, [2]int{cursorX, cursorY}, "the cursor goes below the text, at x")
+
+	right, _ = PrintAligned("score: 10", 124, 4, 7, AlignRight)
+	assert.Equal(t, 124, right)
+
+	right, bottom = PrintBox("one two three", 10, 0, 32, 7, AlignRight)
+	assert.Equal(t, 42, right, "lines end at the right edge of the box")
+	assert.Equal(t, 12, bottom, "\"one two\" and \"three\"")
+	assert.Equal(t, [2]int{10, 12}, [2]int{cursorX, cursorY}, "the cursor goes below the box, at its left edge")
+}
+
+func TestWrapText(t *testing.T) {
+	assert.Equal(t, []string{"hello", "world foo"}, wrapText("hello world foo", 36))
+	assert.Equal(t, []string{"abcd", "efgh", "ij"}, wrapText("abcdefghij", 16), "long words are broken")
+	assert.Equal(t, []string{"a b", "", "c"}, wrapText("a b\n\nc", 40), "newlines and blank lines are kept")
+	assert.Equal(t, []string{"hi", "super", "calif", "ragil", "istic", "x"}, wrapText("hi supercalifragilistic x", 20))
+	assert.Equal(t, []string{"a", "b"}, wrapText("ab", 0), "one character per line at least")
+}
@@ -0,0 +1,104 @@
+package pigo8
+
+import (
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// --- Text input ---
+
+var (
+	// textInputActive is true between StartTextInput and StopTextInput.
+	textInputActive bool
+	// textInput holds the typed text.
+	textInput []rune
+	// textInputMaxLength caps the length of textInput in characters; 0 means no cap.
+	textInputMaxLength int
+	// textInputChars is reused to read the characters typed each frame.
+	textInputChars []rune
+)
+
+// StartTextInput starts capturing typed text, e.g. for a name entry or a
+// chat line, with an empty text. Read it with GetTextInput. Typed characters
+// are added every frame until StopTextInput, Backspace deletes the last one
+// (repeating while held), and characters beyond maxLength are dropped; 0
+// means no limit.
+//
+// While text input is active, the keyboard no longer presses PICO-8 buttons,
+// so typing "x" doesn't also fire the X button. Gamepads and the mouse still
+// do, and Key and Keyp still read the keyboard, so check Keyp("Enter") or
+// Keyp("Escape") to finish the entry.
+//
+// Text from an input method (IME) arrives once it is committed; the
+// composition in progress isn't shown.
+//
+// Example:
+//
+//	func (g *Game) Update() {
+//		if !IsTextInputActive() && Btnp(O) {
+//			StartTextInput(12)
+//		}
+//		if IsTextInputActive() && Keyp("Enter") {
+//			g.name = GetTextInput()
+//			StopTextInput()
+//		}
+//	}
+//
+//	func (g *Game) Draw() {
+//		Cls(0)
+//		Print("name: "+GetTextInput()+"_", 10, 60, 7)
+//	}
+func StartTextInput(maxLength int) {
+	textInputActive = true
+	textInput = textInput[:0]
+	textInputMaxLength = max(maxLength, 0)
+}
+
+// StopTextInput stops capturing typed text. GetTextInput keeps returning the
+// text until the next StartTextInput.
+func StopTextInput() {
+	textInputActive = false
+}
+
+// IsTextInputActive reports whether typed text is being captured.
+func IsTextInputActive() bool {
+	return textInputActive
+}
+
+// GetTextInput returns the text typed since StartTextInput.
+func GetTextInput() string {
+	return string(textInput)
+}
+
+// updateTextInput adds the characters typed this frame to the text input.
+// The engine calls it every frame, before the input cache is refreshed.
+func updateTextInput() {
+	if !textInputActive {
+		return
+	}
+	textInputChars = ebiten.AppendInputChars(textInputChars[:0])
+
+	backspaces := 0
+	held := inpututil.KeyPressDuration(ebiten.KeyBackspace)
+	if held == 1 || isBtnRepeatFrame(held, defaultBtnRepeatDelay, defaultBtnRepeatInterval) {
+		backspaces = 1
+	}
+	textInput = editTextInput(textInput, textInputChars, backspaces, textInputMaxLength)
+}
+
+// editTextInput removes the last backspaces characters from text and then
+// appends the printable typed characters that fit within maxLength.
+func editTextInput(text, typed []rune, backspaces, maxLength int) []rune {
+	text = text[:max(len(text)-backspaces, 0)]
+	for _, r := range typed {
+		if maxLength > 0 && len(text) >= maxLength {
+			break
+		}
+		if unicode.IsPrint(r) {
+			text = append(text, r)
+		}
+	}
+	return text
+}
@@ -0,0 +1,34 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditTextInput(t *testing.T) {
+	assert.Equal(t, "abc", string(editTextInput(nil, []rune("abc"), 0, 0)))
+	assert.Equal(t, "ab", string(editTextInput([]rune("abc"), nil, 1, 0)), "backspace")
+	assert.Equal(t, "", string(editTextInput([]rune("a"), nil, 3, 0)), "backspace on empty text")
+	assert.Equal(t, "abx", string(editTextInput([]rune("abc"), []rune("x"), 1, 0)), "backspace before typing")
+	assert.Equal(t, "abcd", string(editTextInput([]rune("ab"), []rune("cdef"), 0, 4)), "max length")
+	assert.Equal(t, "héllo", string(editTextInput(nil, []rune("h\tél\nlo\x7f"), 0, 0)), "control characters are dropped")
+	assert.Equal(t, "日本", string(editTextInput([]rune("日"), []rune("本語"), 0, 2)), "length counts characters, not bytes")
+}
+
+func TestTextInputState(t *testing.T) {
+	t.Cleanup(StopTextInput)
+
+	StartTextInput(8)
+	assert.True(t, IsTextInputActive())
+	textInput = editTextInput(textInput, []rune("player"), 0, textInputMaxLength)
+	assert.Equal(t, "player", GetTextInput())
+
+	StopTextInput()
+	assert.False(t, IsTextInputActive())
+	assert.Equal(t, "player", GetTextInput(), "the text stays after stopping")
+
+	StartTextInput(-1)
+	assert.Equal(t, "", GetTextInput(), "starting again clears the text")
+	assert.Equal(t, 0, textInputMaxLength)
+}
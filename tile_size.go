@@ -0,0 +1,44 @@
+package pigo8
+
+// --- Tile size ---
+
+// defaultTileSize is the size of a PICO-8 sprite and map tile.
+const defaultTileSize = 8
+
+// tileSize is the width and height in pixels of a spritesheet cell and of a
+// map tile, see SetTileSize.
+var tileSize = defaultTileSize
+
+// SetTileSize sets the width and height in pixels of a sprite cell on the
+// spritesheet and of a map tile, 8 by default as in PICO-8. It can also be
+// set with Settings.TileSize; set it before the spritesheet is used, e.g. in
+// Init. It changes how Sget, Sset and Sspr find sprites on the spritesheet,
+// where Map draws each tile, and the pixel-to-tile math of MapCollision,
+// FindPath and the camera's ClampToMap. A size below 1 goes back to 8.
+//
+// Example:
+//
+//	settings := NewSettings()
+//	settings.TileSize = 16 // A spritesheet of 16x16 sprites
+//	PlayGameWith(settings)
+//
+//	// Elsewhere, tile (tx, ty) covers these pixels
+//	Mset(tx, ty, 3)
+//	Rect(tx*GetTileSize(), ty*GetTileSize(), (tx+1)*GetTileSize()-1, (ty+1)*GetTileSize()-1, 8)
+func SetTileSize(size int) {
+	if size < 1 {
+		size = defaultTileSize
+	}
+	if size == tileSize {
+		return
+	}
+	tileSize = size
+	// Cached map regions and Sspr regions were laid out for the old size
+	mapCacheIsValid = false
+	ClearSpriteCache()
+}
+
+// GetTileSize returns the tile size set with SetTileSize.
+func GetTileSize() int {
+	return tileSize
+}
@@ -0,0 +1,93 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useTileSize sets the tile size for one test.
+func useTileSize(t *testing.T, size int) {
+	t.Helper()
+	t.Cleanup(func() { SetTileSize(defaultTileSize) })
+	SetTileSize(size)
+}
+
+func TestSetTileSize(t *testing.T) {
+	useTileSize(t, 16)
+	assert.Equal(t, 16, GetTileSize())
+
+	SetTileSize(0)
+	assert.Equal(t, defaultTileSize, GetTileSize(), "sizes below 1 go back to 8")
+}
+
+func TestMapWith16PixelTiles(t *testing.T) {
+	useTestLayerSprites(t)
+	setupRowTest(t)
+	useTileSize(t, 16)
+	Mset(0, 0, 1)
+	Mset(1, 0, 2)
+	Mset(1, 1, 4)
+
+	assert.Equal(t, []mapTileDraw{{1, 0, 0}, {2, 16, 0}, {4, 16, 16}}, mapRegionTiles(0, 0, 2, 2, 0))
+
+	Map(0, 0, 0, 0, 2, 2)
+	require.Len(t, mapCaches, 1)
+	for _, img := range mapCaches {
+		assert.Equal(t, 32, img.Bounds().Dx(), "the region is cached at 16 pixels per tile")
+		assert.Equal(t, 32, img.Bounds().Dy())
+	}
+}
+
+func TestMapCollisionWith16PixelTiles(t *testing.T) {
+	useTestLayerSprites(t)
+	useTileSize(t, 16)
+	ClearFlagCache()
+	Mset(2, 1, 4) // Sprite 4 has flag 0, at pixels 32-47, 16-31
+
+	assert.True(t, MapCollision(32, 16, Flag0))
+	assert.True(t, MapCollision(17, 16, Flag0), "the default size is one 16 pixel tile")
+	assert.False(t, MapCollision(16, 16, Flag0))
+	assert.False(t, MapCollision(20, 16, Flag0, 8), "an 8 pixel box at x=20 ends at 27")
+	assert.True(t, MapCollision(40, 0, Flag0, 4, 17))
+	assert.False(t, MapCollision(40, 0, Flag0, 4, 16))
+}
+
+func TestFindPathWith16PixelTiles(t *testing.T) {
+	useTestConsoleState(t)
+	useTileSize(t, 16)
+
+	path := FindPath(0, 0, 2, 0, PathAvoidFlag(0))
+	assert.Equal(t, []Vector2D{{24, 8}, {40, 8}}, path, "the path goes through tile centers")
+}
+
+func TestSpriteRegionTilesWith16PixelTiles(t *testing.T) {
+	originalSprites, originalColumns := currentSprites, spritesheetColumns
+	t.Cleanup(func() { currentSprites, spritesheetColumns = originalSprites, originalColumns })
+	useTileSize(t, 16)
+
+	spritesheetColumns = 8
+	first, below := ebiten.NewImage(16, 16), ebiten.NewImage(16, 16)
+	currentSprites = []spriteInfo{{ID: 1, Image: first}, {ID: 9, Image: below}}
+
+	assert.ElementsMatch(t, []spriteRegionTile{
+		{image: first, dx: 0, dy: 0},
+		{image: below, dx: 0, dy: 16},
+	}, spriteRegionTiles(16, 0, 16, 32))
+	assert.Equal(t, []spriteRegionTile{{image: first, dx: -8, dy: -8}}, spriteRegionTiles(24, 8, 4, 4))
+
+	x, y, size, _ := spriteBlock(9, []int{2})
+	assert.Equal(t, []int{16, 16, 32}, []int{x, y, size})
+}
+
+func TestCameraClampWith16PixelTiles(t *testing.T) {
+	useCameraFollow(t, CameraFollowOptions{ClampToMap: true})
+	useTileSize(t, 16)
+	SetMapSize(16, 16) // 256 pixels
+
+	SetCameraTarget(250, 250)
+	SnapCamera()
+	assert.Equal(t, Vector2D{256 - 128, 256 - 128}, cameraPos())
+}
@@ -0,0 +1,277 @@
+package pigo8
+
+import "slices"
+
+// --- Timelines ---
+
+// Timeline schedules tweens and callbacks on a shared frame clock, for
+// cutscenes, title sequences and scripted events. Events are placed with At
+// and run in parallel when their frames overlap; Then places the next events
+// after the previous ones, for sequences.
+//
+// Like a Tween, a playing timeline (see Play) is advanced by the engine once
+// per Update, after the cartridge's Update, and stops while the game is
+// paused; one that isn't playing can be advanced by hand with Step. It only
+// counts frames, so it plays out the same way every time, in replays too.
+//
+// An event placed at frame f happens on the Step that starts at frame f: a
+// callback is called, and a tween makes its first move, reaching its end
+// value frames steps later. A timeline only writes a target while one of its
+// tweens on that target has started, so the game can move it freely before.
+//
+// Example:
+//
+//	var titleY, logoAlpha float64
+//
+//	intro := NewTimeline()
+//	intro.At(0).
+//		Tween(&titleY, -16, 40, 30, EaseOutBounce).
+//		Tween(&logoAlpha, 0, 1, 20, nil). // In parallel with the title
+//		Then().
+//		Call(func() { Music(0) })
+//	intro.Play()
+//
+//	// Skip the intro with X
+//	if Btnp(X) {
+//		intro.SkipToEnd()
+//	}
+type Timeline struct {
+	OnComplete func() // Called once when a playing timeline reaches its end, if not nil
+
+	events []timelineEvent
+	frame  int
+}
+
+// timelineEvent is a tween (if target is not nil) or a callback placed on a
+// timeline.
+type timelineEvent struct {
+	start  int
+	target *float64
+	tween  Tween
+	call   func()
+	called bool
+}
+
+// TimelineCursor places events on a timeline at a frame, see Timeline.At.
+type TimelineCursor struct {
+	timeline *Timeline
+	frame    int
+	end      int // Frame the events placed with this cursor end at
+}
+
+// activeTimelines are the playing timelines the engine advances every Update.
+var activeTimelines []*Timeline
+
+// NewTimeline creates an empty timeline at frame 0.
+func NewTimeline() *Timeline {
+	return &Timeline{}
+}
+
+// At returns a cursor that places events at frame (0 is the first Step).
+// Negative frames count as 0.
+func (tl *Timeline) At(frame int) *TimelineCursor {
+	frame = max(frame, 0)
+	return &TimelineCursor{timeline: tl, frame: frame, end: frame}
+}
+
+// Tween places a tween of *target from from to to over frames frames, with
+// the given easing (Linear if nil), at the cursor's frame. It returns the
+// cursor so events can be chained.
+func (c *TimelineCursor) Tween(target *float64, from, to float64, frames int, ease EasingFunc) *TimelineCursor {
+	if target == nil {
+		return c
+	}
+	frames = max(frames, 0)
+	c.timeline.events = append(c.timeline.events, timelineEvent{
+		start:  c.frame,
+		target: target,
+		tween:  Tween{From: from, To: to, Frames: frames, Ease: ease},
+	})
+	c.end = max(c.end, c.frame+frames)
+	return c
+}
+
+// Call places a callback at the cursor's frame. It returns the cursor so
+// events can be chained.
+func (c *TimelineCursor) Call(fn func()) *TimelineCursor {
+	if fn == nil {
+		return c
+	}
+	c.timeline.events = append(c.timeline.events, timelineEvent{start: c.frame, call: fn})
+	c.end = max(c.end, c.frame+1)
+	return c
+}
+
+// Then returns a cursor at the frame the events placed with c end, so the
+// next events play after them.
+func (c *TimelineCursor) Then() *TimelineCursor {
+	return c.timeline.At(c.end)
+}
+
+// Wait returns a cursor frames frames after c.
+func (c *TimelineCursor) Wait(frames int) *TimelineCursor {
+	return c.timeline.At(c.frame + frames)
+}
+
+// Play lets the engine advance the timeline every Update from where it is,
+// until it reaches its end. It returns the timeline so it can be created and
+// played in one go.
+func (tl *Timeline) Play() *Timeline {
+	if !tl.Playing() {
+		activeTimelines = append(activeTimelines, tl)
+	}
+	return tl
+}
+
+// Pause stops the engine from advancing the timeline. Its targets stay where
+// they are, and Play resumes it.
+func (tl *Timeline) Pause() {
+	activeTimelines = slices.DeleteFunc(activeTimelines, func(active *Timeline) bool { return active == tl })
+}
+
+// Playing reports whether the engine is advancing the timeline.
+func (tl *Timeline) Playing() bool {
+	return slices.Contains(activeTimelines, tl)
+}
+
+// Step runs the events of the current frame and moves to the next one:
+// callbacks placed at this frame are called, in the order they were placed,
+// and every started tween writes its value for the next frame. It does
+// nothing once the timeline is Done.
+func (tl *Timeline) Step() {
+	if tl.Done() {
+		return
+	}
+	for i := range tl.events {
+		event := &tl.events[i]
+		if event.call != nil && event.start == tl.frame && !event.called {
+			event.called = true
+			event.call()
+		}
+	}
+	tl.frame++
+	tl.applyTweens()
+}
+
+// Seek jumps to frame, clamped to 0 and Length, and sets every target to the
+// value it has there, as if the timeline had played to that frame. A target
+// whose tweens haven't started is set to the start value of its first tween,
+// so seeking back rewinds it. Callbacks are not called: the ones before frame
+// count as called, and the ones from frame on will be called when reached.
+func (tl *Timeline) Seek(frame int) {
+	tl.frame = min(max(frame, 0), tl.Length())
+	for i := range tl.events {
+		tl.events[i].called = tl.events[i].start < tl.frame
+	}
+	for i := range tl.events {
+		event := &tl.events[i]
+		if event.target != nil && !tl.startedTween(event.target) && tl.firstTween(event.target) == event {
+			*event.target = event.tween.From
+		}
+	}
+	tl.applyTweens()
+}
+
+// SkipToEnd jumps to the end of the timeline, setting every target to its
+// final value without calling the callbacks skipped over, e.g. to skip a
+// cutscene. OnComplete is called if the timeline was playing.
+func (tl *Timeline) SkipToEnd() {
+	tl.Seek(tl.Length())
+	if tl.Playing() {
+		tl.Pause()
+		if tl.OnComplete != nil {
+			tl.OnComplete()
+		}
+	}
+}
+
+// Frame returns the number of frames the timeline has played.
+func (tl *Timeline) Frame() int {
+	return tl.frame
+}
+
+// Length returns the frame the last event of the timeline ends at.
+func (tl *Timeline) Length() int {
+	length := 0
+	for _, event := range tl.events {
+		if event.target != nil {
+			length = max(length, event.start+event.tween.Frames)
+		} else {
+			length = max(length, event.start+1)
+		}
+	}
+	return length
+}
+
+// Done reports whether the timeline has played to its end.
+func (tl *Timeline) Done() bool {
+	return tl.frame >= tl.Length()
+}
+
+// applyTweens writes the value of every started tween at the current frame.
+// When tweens on the same target overlap, the one that started last (or was
+// placed last at the same frame) wins.
+func (tl *Timeline) applyTweens() {
+	for _, event := range tl.tweensByStart() {
+		if event.start < tl.frame {
+			event.tween.frame = min(tl.frame-event.start, event.tween.Frames)
+			*event.target = event.tween.Value()
+		}
+	}
+}
+
+// tweensByStart returns the tween events ordered by start frame, keeping the
+// placing order for events at the same frame.
+func (tl *Timeline) tweensByStart() []*timelineEvent {
+	var tweens []*timelineEvent
+	for i := range tl.events {
+		if tl.events[i].target != nil {
+			tweens = append(tweens, &tl.events[i])
+		}
+	}
+	slices.SortStableFunc(tweens, func(a, b *timelineEvent) int { return a.start - b.start })
+	return tweens
+}
+
+// startedTween reports whether a tween on target has started at the current frame.
+func (tl *Timeline) startedTween(target *float64) bool {
+	for _, event := range tl.events {
+		if event.target == target && event.start < tl.frame {
+			return true
+		}
+	}
+	return false
+}
+
+// firstTween returns the earliest tween on target.
+func (tl *Timeline) firstTween(target *float64) *timelineEvent {
+	var first *timelineEvent
+	for i := range tl.events {
+		event := &tl.events[i]
+		if event.target == target && (first == nil || event.start < first.start) {
+			first = event
+		}
+	}
+	return first
+}
+
+// updateTimelines advances every playing timeline by one frame and stops the
+// ones that reached their end. Callbacks may play or pause timelines.
+func updateTimelines() {
+	if len(activeTimelines) == 0 {
+		return
+	}
+	playing := slices.Clone(activeTimelines)
+	for _, tl := range playing {
+		if !tl.Playing() {
+			continue
+		}
+		tl.Step()
+		if tl.Done() {
+			tl.Pause()
+			if tl.OnComplete != nil {
+				tl.OnComplete()
+			}
+		}
+	}
+}
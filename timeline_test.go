@@ -0,0 +1,99 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimelineStep(t *testing.T) {
+	var x, y float64 = 5, 5
+	var calls []int
+
+	tl := NewTimeline()
+	tl.At(0).
+		Tween(&x, 0, 40, 4, nil).
+		Tween(&y, 0, 10, 2, nil).
+		Call(func() { calls = append(calls, tl.Frame()) }).
+		Then().
+		Tween(&y, 10, 0, 2, nil).
+		Call(func() { calls = append(calls, tl.Frame()) })
+	assert.Equal(t, 6, tl.Length())
+
+	tl.Step()
+	assert.Equal(t, [2]float64{10, 5}, [2]float64{x, y}, "parallel tweens move on the first step")
+	assert.Equal(t, []int{0}, calls)
+	tl.Step()
+	assert.Equal(t, [2]float64{20, 10}, [2]float64{x, y})
+	tl.Step()
+	tl.Step()
+	assert.Equal(t, [2]float64{40, 10}, [2]float64{x, y})
+	assert.Equal(t, []int{0}, calls)
+	tl.Step()
+	assert.Equal(t, [2]float64{40, 5}, [2]float64{x, y}, "Then starts after the longest event")
+	assert.Equal(t, []int{0, 4}, calls)
+	tl.Step()
+	assert.True(t, tl.Done())
+	assert.Equal(t, [2]float64{40, 0}, [2]float64{x, y})
+	tl.Step()
+	assert.Equal(t, 6, tl.Frame(), "a finished timeline stays at its end")
+}
+
+func TestTimelineSeek(t *testing.T) {
+	var x float64 = 99
+	called := 0
+	tl := NewTimeline()
+	tl.At(2).Tween(&x, 0, 10, 10, nil)
+	tl.At(5).Call(func() { called++ })
+	tl.At(6).Tween(&x, 50, 100, 4, nil)
+
+	tl.Seek(4)
+	assert.Equal(t, 2.0, x)
+	tl.Seek(100)
+	assert.Equal(t, 12, tl.Frame(), "seeking is clamped to the length")
+	assert.Equal(t, 100.0, x, "the last tween on a target wins")
+	assert.Zero(t, called, "seeking doesn't call callbacks")
+
+	tl.Seek(0)
+	assert.Equal(t, 0.0, x, "seeking back rewinds to the first tween's start")
+	for !tl.Done() {
+		tl.Step()
+	}
+	assert.Equal(t, 1, called)
+}
+
+func TestTimelinePlaysWithTheClock(t *testing.T) {
+	originalTime, originalFrame := elapsedTime, frameCount
+	t.Cleanup(func() {
+		elapsedTime, frameCount = originalTime, originalFrame
+		activeTimelines = nil
+	})
+
+	var x float64
+	completed := 0
+	tl := NewTimeline()
+	tl.At(0).Tween(&x, 0, 3, 3, nil)
+	tl.OnComplete = func() { completed++ }
+	tl.Play()
+	tl.Play()
+	assert.Len(t, activeTimelines, 1, "playing twice doesn't advance twice")
+
+	advanceClock()
+	tl.Pause()
+	advanceClock()
+	assert.Equal(t, 1.0, x, "a paused timeline doesn't advance")
+
+	tl.Play()
+	advanceClock()
+	advanceClock()
+	assert.Equal(t, 3.0, x)
+	assert.Equal(t, 1, completed)
+	assert.Empty(t, activeTimelines)
+
+	tl.Seek(0)
+	tl.Play()
+	tl.SkipToEnd()
+	assert.Equal(t, 3.0, x)
+	assert.Equal(t, 2, completed, "skipping a playing timeline completes it")
+	assert.False(t, tl.Playing())
+}
@@ -0,0 +1,131 @@
+package pigo8
+
+import "math"
+
+// --- Timers ---
+
+// defaultTimeStep is the time step used by second-based timers before the
+// game started, matching the default TargetFPS of 30.
+const defaultTimeStep = 1.0 / 30
+
+// timerEpsilon absorbs float rounding when adding up time steps, so e.g.
+// 30 steps of 1/30 reach 1 second on the 30th Update.
+const timerEpsilon = 1e-9
+
+// Timer fires after a number of frames or seconds, once or repeatedly.
+//
+// Timers are not advanced by the engine: call Update once per Update of the
+// cartridge. This keeps them in step with the game, so a timer stops while
+// the game is paused or frozen, and behaves the same in headless tests.
+//
+// Example:
+//
+//	type Game struct {
+//		blink *Timer
+//		on    bool
+//	}
+//
+//	func (g *Game) Init() {
+//		g.blink = NewTimer(15, true) // Every 15 frames
+//	}
+//
+//	func (g *Game) Update() {
+//		if g.blink.Update() {
+//			g.on = !g.on
+//		}
+//	}
+type Timer struct {
+	duration float64 // In frames, or in seconds if inSeconds
+	elapsed  float64
+	repeat   bool
+	done     bool
+
+	inSeconds bool
+}
+
+// NewTimer creates a timer that fires after frames calls to Update. With
+// repeat, it fires every frames calls; otherwise it fires once and is Done.
+// A timer of 0 frames or less fires on the first Update.
+func NewTimer(frames int, repeat bool) *Timer {
+	return &Timer{duration: float64(frames), repeat: repeat}
+}
+
+// TimerFromSeconds creates a timer that fires after seconds of game time,
+// measured like T(): every Update adds one time step (1/TargetFPS). With
+// repeat, it fires every seconds; time left over from one period counts
+// towards the next, so a repeating timer doesn't drift.
+//
+// Example:
+//
+//	spawn := TimerFromSeconds(2.5, true)
+//
+//	// In Update:
+//	if spawn.Update() {
+//		enemies = append(enemies, newEnemy())
+//	}
+func TimerFromSeconds(seconds float64, repeat bool) *Timer {
+	return &Timer{duration: seconds, repeat: repeat, inSeconds: true}
+}
+
+// Update advances the timer by one frame and reports whether it fired.
+// A one-shot timer that is Done never fires again until Reset.
+func (tm *Timer) Update() bool {
+	if tm.done {
+		return false
+	}
+
+	tm.elapsed += tm.step()
+	if tm.elapsed < tm.duration-timerEpsilon {
+		return false
+	}
+
+	if !tm.repeat {
+		tm.elapsed = tm.duration
+		tm.done = true
+		return true
+	}
+	if tm.duration <= 0 {
+		tm.elapsed = 0
+	} else {
+		// Keep the remainder so second-based timers don't drift
+		tm.elapsed = math.Max(tm.elapsed-tm.duration, 0)
+	}
+	return true
+}
+
+// step returns how far one Update advances the timer.
+func (tm *Timer) step() float64 {
+	if !tm.inSeconds {
+		return 1
+	}
+	if timeIncrement > 0 {
+		return timeIncrement
+	}
+	return defaultTimeStep
+}
+
+// Reset rewinds the timer to the start, as if it was just created.
+func (tm *Timer) Reset() {
+	tm.elapsed = 0
+	tm.done = false
+}
+
+// Progress returns how far the timer is towards firing, from 0 just after it
+// started (or fired, for a repeating timer) to 1. It never goes past 1, so it
+// can feed an easing function or Lerp directly.
+//
+// Example:
+//
+//	// Grow a charge bar while the timer runs
+//	Rectfill(10, 120, 10+int(40*charge.Progress()), 124, 8)
+func (tm *Timer) Progress() float64 {
+	if tm.duration <= 0 {
+		return 1
+	}
+	return math.Min(tm.elapsed/tm.duration, 1)
+}
+
+// Done reports whether a one-shot timer has fired. Repeating timers are never done.
+func (tm *Timer) Done() bool {
+	return tm.done
+}
@@ -0,0 +1,77 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimerOneShot(t *testing.T) {
+	tm := NewTimer(3, false)
+	assert.False(t, tm.Update())
+	assert.False(t, tm.Update())
+	assert.InDelta(t, 2.0/3, tm.Progress(), 1e-9)
+	assert.True(t, tm.Update(), "should fire on the third Update")
+	assert.True(t, tm.Done())
+
+	// It stays done, with progress clamped at 1
+	for i := 0; i < 5; i++ {
+		assert.False(t, tm.Update(), "a one-shot timer should fire only once")
+	}
+	assert.Equal(t, 1.0, tm.Progress())
+
+	tm.Reset()
+	assert.False(t, tm.Done())
+	assert.Equal(t, 0.0, tm.Progress())
+	assert.False(t, tm.Update())
+}
+
+func TestTimerRepeat(t *testing.T) {
+	tm := NewTimer(4, true)
+	var fired []int
+	for frame := 1; frame <= 12; frame++ {
+		if tm.Update() {
+			fired = append(fired, frame)
+		}
+	}
+	assert.Equal(t, []int{4, 8, 12}, fired)
+	assert.False(t, tm.Done(), "a repeating timer is never done")
+	assert.Equal(t, 0.0, tm.Progress(), "progress should restart after firing")
+
+	// A zero-length timer fires every Update
+	zero := NewTimer(0, true)
+	assert.True(t, zero.Update())
+	assert.True(t, zero.Update())
+	assert.Equal(t, 1.0, zero.Progress())
+}
+
+func TestTimerFromSeconds(t *testing.T) {
+	originalIncrement := timeIncrement
+	t.Cleanup(func() { timeIncrement = originalIncrement })
+	timeIncrement = 1.0 / 30
+
+	tm := TimerFromSeconds(1, true)
+	var fired []int
+	for frame := 1; frame <= 90; frame++ {
+		if tm.Update() {
+			fired = append(fired, frame)
+		}
+	}
+	assert.Equal(t, []int{30, 60, 90}, fired, "should fire every second without drifting")
+
+	// Periods that are not a whole number of frames carry the remainder over
+	timeIncrement = 0.4
+	tm = TimerFromSeconds(1, true)
+	fired = nil
+	for frame := 1; frame <= 10; frame++ {
+		if tm.Update() {
+			fired = append(fired, frame)
+		}
+	}
+	assert.Equal(t, []int{3, 5, 8, 10}, fired)
+
+	oneShot := TimerFromSeconds(0.5, false)
+	assert.False(t, oneShot.Update())
+	assert.True(t, oneShot.Update())
+	assert.Equal(t, 1.0, oneShot.Progress(), "progress should clamp at completion")
+}
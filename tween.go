@@ -0,0 +1,226 @@
+package pigo8
+
+import (
+	"math"
+	"slices"
+)
+
+// --- Easing ---
+
+// EasingFunc maps linear progress t (0 to 1) to eased progress. Most easings
+// return 0 at t=0 and 1 at t=1; "Back" and "Elastic" easings overshoot in
+// between.
+type EasingFunc func(t float64) float64
+
+// Lerp returns the value a fraction t of the way from a to b.
+// t=0 returns a, t=1 returns b; values outside 0-1 extrapolate.
+//
+// Example:
+//
+//	// Move the camera 10% of the way to the player every frame
+//	camX = Lerp(camX, playerX-64, 0.1)
+func Lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// LerpVector returns the point a fraction t of the way from a to b.
+//
+// Example:
+//
+//	pos := LerpVector(start, end, EaseOutQuad(progress))
+func LerpVector(a, b Vector2D, t float64) Vector2D {
+	return Vector2D{X: Lerp(a.X, b.X, t), Y: Lerp(a.Y, b.Y, t)}
+}
+
+// Linear is the identity easing: constant speed.
+func Linear(t float64) float64 {
+	return t
+}
+
+// EaseInQuad starts slow and speeds up.
+func EaseInQuad(t float64) float64 {
+	return t * t
+}
+
+// EaseOutQuad starts fast and slows down.
+func EaseOutQuad(t float64) float64 {
+	return 1 - (1-t)*(1-t)
+}
+
+// EaseInOutQuad speeds up, then slows down.
+func EaseInOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return 1 - math.Pow(-2*t+2, 2)/2
+}
+
+// EaseInCubic starts slower than EaseInQuad and speeds up more sharply.
+func EaseInCubic(t float64) float64 {
+	return t * t * t
+}
+
+// EaseOutCubic starts fast and slows down more gently than EaseOutQuad.
+func EaseOutCubic(t float64) float64 {
+	return 1 - math.Pow(1-t, 3)
+}
+
+// EaseInOutCubic speeds up, then slows down, with a sharper middle than EaseInOutQuad.
+func EaseInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	return 1 - math.Pow(-2*t+2, 3)/2
+}
+
+// EaseInOutSine follows a sine curve: a soft start and a soft stop.
+func EaseInOutSine(t float64) float64 {
+	return -(math.Cos(math.Pi*t) - 1) / 2
+}
+
+// easeBackOvershoot controls how far the Back easings overshoot (about 10%).
+const easeBackOvershoot = 1.70158
+
+// EaseInBack pulls back a little before moving to the target.
+func EaseInBack(t float64) float64 {
+	return (easeBackOvershoot+1)*t*t*t - easeBackOvershoot*t*t
+}
+
+// EaseOutBack overshoots the target a little and settles back, good for
+// popups and menus sliding in.
+func EaseOutBack(t float64) float64 {
+	u := t - 1
+	return 1 + (easeBackOvershoot+1)*u*u*u + easeBackOvershoot*u*u
+}
+
+// EaseOutBounce bounces against the target like a dropped ball.
+func EaseOutBounce(t float64) float64 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+}
+
+// EaseOutElastic overshoots and wobbles around the target like a spring.
+func EaseOutElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return math.Max(0, math.Min(1, t))
+	}
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*(2*math.Pi/3)) + 1
+}
+
+// --- Tweens ---
+
+// Tween animates a value from From to To over a number of frames, shaped by
+// an easing function.
+//
+// A started tween (see Start) is advanced by the engine once per Update,
+// after the cartridge's Update, and stops advancing while the game is
+// paused. A tween that is not started can be advanced by hand with Step.
+//
+// Example:
+//
+//	// Slide a menu in from the left over half a second at 30 FPS
+//	menuX := NewTween(-64, 8, 15, EaseOutBack).Start()
+//
+//	// In Draw:
+//	Rectfill(int(menuX.Value()), 40, int(menuX.Value())+48, 80, 1)
+type Tween struct {
+	From, To   float64
+	Frames     int        // Duration in frames
+	Ease       EasingFunc // Linear if nil
+	OnComplete func()     // Called once when the tween reaches To, if not nil
+
+	frame int
+}
+
+var (
+	// activeTweens are the started tweens the engine advances every Update.
+	activeTweens []*Tween
+	// tweenScratch is reused by updateTweens to iterate while tweens change.
+	tweenScratch []*Tween
+)
+
+// NewTween creates a tween from from to to over frames frames with the
+// given easing (Linear if nil).
+func NewTween(from, to float64, frames int, ease EasingFunc) *Tween {
+	return &Tween{From: from, To: to, Frames: frames, Ease: ease}
+}
+
+// Start rewinds the tween and lets the engine advance it every Update until
+// it is done. It returns the tween so it can be created and started in one go.
+func (tw *Tween) Start() *Tween {
+	tw.frame = 0
+	tw.Stop()
+	activeTweens = append(activeTweens, tw)
+	return tw
+}
+
+// Stop stops the engine from advancing the tween. Its value stays where it is.
+func (tw *Tween) Stop() {
+	for i, active := range activeTweens {
+		if active == tw {
+			activeTweens = append(activeTweens[:i], activeTweens[i+1:]...)
+			return
+		}
+	}
+}
+
+// Step advances the tween by one frame and returns its new value. When the
+// last frame is reached, OnComplete is called.
+func (tw *Tween) Step() float64 {
+	if !tw.Done() {
+		tw.frame++
+		if tw.Done() && tw.OnComplete != nil {
+			tw.OnComplete()
+		}
+	}
+	return tw.Value()
+}
+
+// Progress returns the eased progress of the tween, from 0 at the start to 1
+// at the end. Use it to animate anything else, e.g. a Vector2D with LerpVector.
+func (tw *Tween) Progress() float64 {
+	if tw.Frames <= 0 {
+		return 1
+	}
+	t := math.Min(float64(tw.frame)/float64(tw.Frames), 1)
+	if tw.Ease == nil {
+		return t
+	}
+	return tw.Ease(t)
+}
+
+// Value returns the current value of the tween.
+func (tw *Tween) Value() float64 {
+	return Lerp(tw.From, tw.To, tw.Progress())
+}
+
+// Done reports whether the tween has reached its last frame.
+func (tw *Tween) Done() bool {
+	return tw.frame >= tw.Frames
+}
+
+// updateTweens advances every started tween by one frame and forgets
+// the ones that finished. OnComplete callbacks may start or stop tweens.
+func updateTweens() {
+	if len(activeTweens) == 0 {
+		return
+	}
+	tweenScratch = append(tweenScratch[:0], activeTweens...)
+	for _, tw := range tweenScratch {
+		tw.Step()
+	}
+	clear(tweenScratch)
+	activeTweens = slices.DeleteFunc(activeTweens, (*Tween).Done)
+}
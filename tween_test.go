@@ -0,0 +1,80 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLerp(t *testing.T) {
+	assert.Equal(t, 10.0, Lerp(10, 20, 0))
+	assert.Equal(t, 15.0, Lerp(10, 20, 0.5))
+	assert.Equal(t, 20.0, Lerp(10, 20, 1))
+	assert.Equal(t, Vector2D{X: 5, Y: -5}, LerpVector(Vector2D{}, Vector2D{X: 10, Y: -10}, 0.5))
+}
+
+func TestEasingEndpoints(t *testing.T) {
+	easings := map[string]EasingFunc{
+		"Linear":         Linear,
+		"EaseInQuad":     EaseInQuad,
+		"EaseOutQuad":    EaseOutQuad,
+		"EaseInOutQuad":  EaseInOutQuad,
+		"EaseInCubic":    EaseInCubic,
+		"EaseOutCubic":   EaseOutCubic,
+		"EaseInOutCubic": EaseInOutCubic,
+		"EaseInOutSine":  EaseInOutSine,
+		"EaseInBack":     EaseInBack,
+		"EaseOutBack":    EaseOutBack,
+		"EaseOutBounce":  EaseOutBounce,
+		"EaseOutElastic": EaseOutElastic,
+	}
+	for name, ease := range easings {
+		assert.InDelta(t, 0, ease(0), 1e-9, "%s(0) should be 0", name)
+		assert.InDelta(t, 1, ease(1), 1e-9, "%s(1) should be 1", name)
+	}
+
+	assert.Less(t, EaseInQuad(0.5), 0.5, "ease-in should lag behind linear")
+	assert.Greater(t, EaseOutQuad(0.5), 0.5, "ease-out should run ahead of linear")
+	assert.Greater(t, EaseOutBack(0.8), 1.0, "EaseOutBack should overshoot")
+}
+
+func TestTweenStep(t *testing.T) {
+	completed := 0
+	tw := NewTween(0, 100, 4, nil)
+	tw.OnComplete = func() { completed++ }
+
+	assert.Equal(t, 25.0, tw.Step())
+	assert.Equal(t, 50.0, tw.Step())
+	tw.Step()
+	assert.False(t, tw.Done())
+	assert.Equal(t, 100.0, tw.Step())
+	assert.True(t, tw.Done())
+
+	tw.Step()
+	assert.Equal(t, 100.0, tw.Value(), "a finished tween should stay at its end value")
+	assert.Equal(t, 1, completed, "OnComplete should be called once")
+}
+
+func TestStartedTweensAdvanceWithTheClock(t *testing.T) {
+	originalTime, originalFrame := elapsedTime, frameCount
+	t.Cleanup(func() {
+		elapsedTime, frameCount = originalTime, originalFrame
+		activeTweens = nil
+	})
+
+	chained := NewTween(0, 1, 2, nil)
+	first := NewTween(10, 20, 2, EaseOutQuad)
+	first.OnComplete = func() { chained.Start() }
+	first.Start()
+
+	advanceClock()
+	assert.InDelta(t, 17.5, first.Value(), 1e-9)
+	advanceClock()
+	assert.True(t, first.Done())
+	assert.Equal(t, []*Tween{chained}, activeTweens, "finished tweens should be dropped and new ones kept")
+
+	advanceClock()
+	advanceClock()
+	assert.True(t, chained.Done())
+	assert.Empty(t, activeTweens)
+}
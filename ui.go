@@ -0,0 +1,210 @@
+package pigo8
+
+import "math"
+
+// --- UI widgets ---
+
+// UIStyle holds the palette colors of Button, Toggle and Slider.
+type UIStyle struct {
+	Background int // Fill of buttons, boxes and slider tracks
+	Border     int
+	Hover      int // Fill while the mouse is over a widget
+	Active     int // Fill while pressed, the toggle check and the slider knob
+	Text       int
+}
+
+// uiRect is the area of a widget on the screen, which also identifies it.
+type uiRect struct {
+	x, y, w, h int
+}
+
+var (
+	// uiStyle is the style set with SetUIStyle, nil to follow the palette.
+	uiStyle *UIStyle
+	// uiActive is the widget the left mouse button was pressed on, while
+	// uiHasActive is true. It keeps a drag or a click on that widget even when
+	// the mouse leaves it.
+	uiActive    uiRect
+	uiHasActive bool
+)
+
+// SetUIStyle sets the colors of the UI widgets, e.g. to match the game's
+// palette.
+//
+// Example:
+//
+//	SetUIStyle(UIStyle{Background: 1, Border: 12, Hover: 13, Active: 12, Text: 7})
+func SetUIStyle(style UIStyle) {
+	uiStyle = &style
+}
+
+// ResetUIStyle goes back to the default style, see GetUIStyle.
+func ResetUIStyle() {
+	uiStyle = nil
+}
+
+// GetUIStyle returns the colors the UI widgets use. Unless SetUIStyle was
+// called, they are picked from the current palette, like the pause menu:
+// the darkest color for the background, a mid tone for hovering and the
+// lightest color for borders, text and pressed widgets.
+func GetUIStyle() UIStyle {
+	if uiStyle != nil {
+		return *uiStyle
+	}
+	dark, mid, light := findDarkestColorIndex(), findMidToneColorIndex(), findLightestColorIndex()
+	return UIStyle{Background: dark, Border: light, Hover: mid, Active: light, Text: light}
+}
+
+// Button draws a w x h button with a centered label and returns true on the
+// frame it is clicked: when the left mouse button is released over it after
+// being pressed on it, so a click can be cancelled by moving away.
+//
+// Like the other widgets it is immediate-mode: call it in Draw every frame
+// the button is shown and act on the result there. Coordinates are screen
+// pixels, as the mouse reports them, so the camera is ignored, and the
+// camera, cursor and draw color are left as they were.
+//
+// Example:
+//
+//	func (g *Game) Draw() {
+//		Cls(0)
+//		if Button(44, 60, 40, 11, "start") {
+//			g.started = true
+//		}
+//	}
+func Button(x, y, w, h int, label string) bool {
+	r := uiRect{x, y, w, h}
+	hover, held, clicked := uiInteract(r)
+	style := GetUIStyle()
+
+	fill, text := style.Background, style.Text
+	switch {
+	case held && hover:
+		fill, text = style.Active, style.Background
+	case hover:
+		fill = style.Hover
+	}
+	drawOnScreen(func() {
+		Rectfill(x, y, x+w-1, y+h-1, fill)
+		Rect(x, y, x+w-1, y+h-1, style.Border)
+		textW := int(math.Ceil(float64(len([]rune(label))) * CharWidthApproximation))
+		Print(label, x+(w-textW)/2+1, y+(h-int(defaultFontSize))/2+1, text)
+	})
+	return clicked
+}
+
+// Toggle draws a check box with a label at (x, y) and returns on, flipped
+// on the frame the box or its label is clicked. See Button for how the
+// widgets are used.
+//
+// Example:
+//
+//	g.music = Toggle(10, 20, "music", g.music)
+func Toggle(x, y int, label string, on bool) bool {
+	const box = 7
+	textW := int(math.Ceil(float64(len([]rune(label))) * CharWidthApproximation))
+	r := uiRect{x, y, box + 3 + textW, box}
+	hover, _, clicked := uiInteract(r)
+	if clicked {
+		on = !on
+	}
+	style := GetUIStyle()
+
+	fill := style.Background
+	if hover {
+		fill = style.Hover
+	}
+	drawOnScreen(func() {
+		Rectfill(x, y, x+box-1, y+box-1, fill)
+		Rect(x, y, x+box-1, y+box-1, style.Border)
+		if on {
+			Rectfill(x+2, y+2, x+box-3, y+box-3, style.Active)
+		}
+		Print(label, x+box+3, y+1, style.Text)
+	})
+	return on
+}
+
+// Slider draws a w x h horizontal slider for a value between lo and hi and
+// returns the value, moved to the mouse while the slider is dragged. Values
+// outside lo..hi are clamped. See Button for how the widgets are used.
+//
+// Example:
+//
+//	g.volume = Slider(10, 30, 60, 7, g.volume, 0, 1)
+func Slider(x, y, w, h int, value, lo, hi float64) float64 {
+	r := uiRect{x, y, w, h}
+	hover, held, _ := uiInteract(r)
+	if held {
+		mx, _ := GetMouseXY()
+		value = sliderValue(mx, r, lo, hi)
+	}
+	value = math.Max(math.Min(lo, hi), math.Min(value, math.Max(lo, hi)))
+	style := GetUIStyle()
+
+	knob := style.Border
+	if held || hover {
+		knob = style.Active
+	}
+	knobX := x
+	if hi != lo && w > 3 {
+		knobX = x + int(math.Round((value-lo)/(hi-lo)*float64(w-3)))
+	}
+	drawOnScreen(func() {
+		Rectfill(x, y, x+w-1, y+h-1, style.Background)
+		Rect(x, y, x+w-1, y+h-1, style.Border)
+		Rectfill(knobX, y, knobX+2, y+h-1, knob)
+	})
+	return value
+}
+
+// sliderValue returns the value of a slider in r at mouse x, from lo at the
+// left edge to hi at the right edge.
+func sliderValue(mx int, r uiRect, lo, hi float64) float64 {
+	if r.w <= 1 {
+		return lo
+	}
+	t := float64(mx-r.x) / float64(r.w-1)
+	return Lerp(lo, hi, math.Max(0, math.Min(t, 1)))
+}
+
+// uiInteract handles the mouse for the widget in r. It reports whether the
+// mouse is over it, whether the left button is held after being pressed on
+// it, and whether it was clicked: released over it after being pressed on it.
+func uiInteract(r uiRect) (hover, held, clicked bool) {
+	mx, my := GetMouseXY()
+	hover = mx >= r.x && mx < r.x+r.w && my >= r.y && my < r.y+r.h
+	if hover && Btnp(ButtonMouseLeft) {
+		uiActive, uiHasActive = r, true
+	}
+	if !uiHasActive || uiActive != r {
+		return hover, false, false
+	}
+	if !Btn(ButtonMouseLeft) {
+		uiHasActive = false
+		return hover, false, hover
+	}
+	return hover, true, false
+}
+
+// endUIFrame forgets the pressed widget once the mouse button is up, in case
+// it wasn't drawn this frame to see the release. The engine calls it after
+// every Draw.
+func endUIFrame() {
+	if !Btn(ButtonMouseLeft) {
+		uiHasActive = false
+	}
+}
+
+// drawOnScreen runs draw with the camera reset, then restores the camera,
+// cursor and draw color, so overlays don't affect the game's drawing.
+func drawOnScreen(draw func()) {
+	CameraPush()
+	Camera()
+	savedCursorX, savedCursorY, savedCursorColor, savedDrawColor := cursorX, cursorY, cursorColor, currentDrawColor
+	defer func() {
+		cursorX, cursorY, cursorColor, currentDrawColor = savedCursorX, savedCursorY, savedCursorColor, savedDrawColor
+		CameraPop()
+	}()
+	draw()
+}
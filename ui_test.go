@@ -0,0 +1,141 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// useUITest gives the widgets a screen and a clean mouse for one test.
+func useUITest(t *testing.T) {
+	t.Helper()
+	setupRowTest(t)
+	resetInputState()
+	t.Cleanup(func() {
+		resetInputState()
+		mouseX, mouseY = 0, 0
+		uiHasActive = false
+		ResetUIStyle()
+	})
+	uiHasActive = false
+}
+
+// uiMouse moves the mouse to (x, y) and starts a frame with the left button
+// down or up, as the engine does before Draw.
+func uiMouse(x, y int, down bool) {
+	mouseX, mouseY = x, y
+	InjectButton(ButtonMouseLeft, down)
+	refreshInputCache(false)
+}
+
+func TestButtonClicksOnRelease(t *testing.T) {
+	useUITest(t)
+
+	uiMouse(5, 5, false)
+	assert.False(t, Button(2, 2, 10, 8, "ok"), "hovering doesn't click")
+	uiMouse(5, 5, true)
+	assert.False(t, Button(2, 2, 10, 8, "ok"), "pressing doesn't click yet")
+	uiMouse(6, 5, true)
+	assert.False(t, Button(2, 2, 10, 8, "ok"))
+	uiMouse(6, 5, false)
+	assert.True(t, Button(2, 2, 10, 8, "ok"), "releasing over the button clicks")
+	uiMouse(6, 5, false)
+	assert.False(t, Button(2, 2, 10, 8, "ok"), "a click fires once")
+
+	// Moving away before releasing cancels the click
+	uiMouse(5, 5, true)
+	Button(2, 2, 10, 8, "ok")
+	uiMouse(40, 40, false)
+	assert.False(t, Button(2, 2, 10, 8, "ok"))
+
+	// Pressing elsewhere and releasing over the button isn't a click either
+	uiMouse(40, 40, true)
+	Button(2, 2, 10, 8, "ok")
+	uiMouse(5, 5, true)
+	Button(2, 2, 10, 8, "ok")
+	uiMouse(5, 5, false)
+	assert.False(t, Button(2, 2, 10, 8, "ok"))
+}
+
+func TestButtonForgetsPressWhenNotDrawn(t *testing.T) {
+	useUITest(t)
+
+	uiMouse(5, 5, true)
+	Button(2, 2, 10, 8, "ok")
+	uiMouse(5, 5, false)
+	endUIFrame() // The button wasn't drawn on the frame of the release
+	uiMouse(5, 5, false)
+	assert.False(t, Button(2, 2, 10, 8, "ok"))
+}
+
+func TestToggle(t *testing.T) {
+	useUITest(t)
+
+	on := false
+	uiMouse(12, 3, true) // On the label
+	on = Toggle(0, 0, "sound", on)
+	assert.False(t, on)
+	uiMouse(12, 3, false)
+	on = Toggle(0, 0, "sound", on)
+	assert.True(t, on)
+
+	uiMouse(3, 3, true)
+	on = Toggle(0, 0, "sound", on)
+	uiMouse(3, 3, false)
+	on = Toggle(0, 0, "sound", on)
+	assert.False(t, on)
+}
+
+func TestSlider(t *testing.T) {
+	useUITest(t)
+
+	uiMouse(40, 40, false)
+	assert.Equal(t, 1.0, Slider(0, 0, 11, 5, 2, 0, 1), "values are clamped")
+
+	uiMouse(5, 2, true)
+	assert.Equal(t, 0.5, Slider(0, 0, 11, 5, 0.2, 0, 1), "pressing jumps to the mouse")
+	uiMouse(60, 30, true)
+	assert.Equal(t, 1.0, Slider(0, 0, 11, 5, 0.5, 0, 1), "dragging continues outside the slider")
+	uiMouse(-5, 30, true)
+	assert.Equal(t, 0.0, Slider(0, 0, 11, 5, 1, 0, 1))
+	uiMouse(2, 2, false)
+	assert.Equal(t, 0.3, Slider(0, 0, 11, 5, 0.3, 0, 1), "releasing stops dragging")
+
+	assert.InDelta(t, 75.0, sliderValue(2, uiRect{0, 0, 9, 5}, 100, 0), 1e-9, "lo can be above hi")
+}
+
+func TestWidgetsKeepDrawingState(t *testing.T) {
+	useUITest(t)
+	savedCursorX, savedCursorY, savedCursorColor, savedDrawColor := cursorX, cursorY, cursorColor, currentDrawColor
+	t.Cleanup(func() {
+		cursorX, cursorY, cursorColor, currentDrawColor = savedCursorX, savedCursorY, savedCursorColor, savedDrawColor
+	})
+	Camera(10, 20)
+	Color(9)
+	Cursor(3, 4)
+
+	uiMouse(5, 5, false)
+	Button(0, 0, 10, 8, "ok")
+	Toggle(0, 0, "on", true)
+	Slider(0, 0, 10, 5, 0.5, 0, 1)
+
+	assert.Equal(t, 10.0, cameraX)
+	assert.Equal(t, 20.0, cameraY)
+	assert.Equal(t, 9, currentDrawColor)
+	assert.Equal(t, 3, cursorX)
+	assert.Equal(t, 4, cursorY)
+}
+
+func TestUIStyle(t *testing.T) {
+	useUITest(t)
+
+	style := GetUIStyle()
+	assert.Equal(t, findDarkestColorIndex(), style.Background, "the default follows the palette")
+	assert.Equal(t, findLightestColorIndex(), style.Text)
+
+	custom := UIStyle{Background: 1, Border: 12, Hover: 13, Active: 12, Text: 7}
+	SetUIStyle(custom)
+	assert.Equal(t, custom, GetUIStyle())
+	ResetUIStyle()
+	assert.Equal(t, style, GetUIStyle())
+}
@@ -0,0 +1,277 @@
+package pigo8
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// --- Undo/redo history ---
+
+// defaultUndoLimit is the number of snapshots kept when NewUndoStack is given a limit <= 0.
+const defaultUndoLimit = 50
+
+// SnapshotStore is where an UndoStack keeps its snapshots.
+// Implement it to keep history somewhere other than memory, e.g. on disk.
+type SnapshotStore[T any] interface {
+	Save(key string, state T) error
+	Load(key string) (T, error)
+	Delete(key string) error
+}
+
+// MemorySnapshotStore keeps snapshots in a map. It is the default store.
+// Note that states are stored as-is, so push copies (arrays, cloned slices)
+// rather than values that you keep mutating.
+type MemorySnapshotStore[T any] struct {
+	mu     sync.RWMutex
+	states map[string]T
+}
+
+// NewMemorySnapshotStore creates an empty in-memory snapshot store.
+func NewMemorySnapshotStore[T any]() *MemorySnapshotStore[T] {
+	return &MemorySnapshotStore[T]{states: make(map[string]T)}
+}
+
+// Save stores state under key.
+func (s *MemorySnapshotStore[T]) Save(key string, state T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[key] = state
+	return nil
+}
+
+// Load returns the state stored under key.
+func (s *MemorySnapshotStore[T]) Load(key string) (T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, ok := s.states[key]
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("snapshot %s not found", key)
+	}
+	return state, nil
+}
+
+// Delete removes the state stored under key.
+func (s *MemorySnapshotStore[T]) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, key)
+	return nil
+}
+
+// AferoSnapshotStore stores snapshots as JSON files in an afero filesystem.
+// Because every state is serialized, later changes to the pushed value never
+// leak into the history. Use afero.NewMemMapFs() for a RAM-backed store or
+// afero.NewOsFs() to keep history on disk.
+type AferoSnapshotStore[T any] struct {
+	fs afero.Fs
+}
+
+// NewAferoSnapshotStore creates a snapshot store backed by fs.
+func NewAferoSnapshotStore[T any](fs afero.Fs) *AferoSnapshotStore[T] {
+	return &AferoSnapshotStore[T]{fs: fs}
+}
+
+// Save writes state as JSON to the file named key.
+func (s *AferoSnapshotStore[T]) Save(key string, state T) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("error marshaling snapshot: %w", err)
+	}
+	if err := afero.WriteFile(s.fs, key, data, 0o644); err != nil {
+		return fmt.Errorf("error writing snapshot %s: %w", key, err)
+	}
+	return nil
+}
+
+// Load reads the JSON file named key back into a state.
+func (s *AferoSnapshotStore[T]) Load(key string) (T, error) {
+	var state T
+	data, err := afero.ReadFile(s.fs, key)
+	if err != nil {
+		return state, fmt.Errorf("error reading snapshot %s: %w", key, err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("error unmarshaling snapshot %s: %w", key, err)
+	}
+	return state, nil
+}
+
+// Delete removes the file named key. A missing file is not an error.
+func (s *AferoSnapshotStore[T]) Delete(key string) error {
+	if err := s.fs.Remove(key); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing snapshot %s: %w", key, err)
+	}
+	return nil
+}
+
+// UndoStack is a generic undo/redo history of snapshots.
+//
+// The top of the stack is always the current state: push the initial state
+// once, then push again after every change. Undo returns the state before
+// the current one and Redo walks forward again. Pushing after an Undo
+// discards everything that could have been redone.
+//
+// Example:
+//
+//	history := pigo8.NewUndoStack[[16]int](100, nil)
+//	history.Push(board)
+//	board[3] = 7
+//	history.Push(board)
+//	if prev, ok := history.Undo(); ok {
+//		board = prev // board[3] is back to its old value
+//	}
+type UndoStack[T any] struct {
+	mu       sync.Mutex
+	store    SnapshotStore[T]
+	undo     []string
+	redo     []string
+	limit    int
+	cooldown time.Duration
+	lastPush time.Time
+	nextKey  int
+}
+
+// NewUndoStack creates an undo history that keeps at most limit snapshots
+// (50 if limit <= 0). If store is nil, snapshots are kept in memory.
+func NewUndoStack[T any](limit int, store SnapshotStore[T]) *UndoStack[T] {
+	if limit <= 0 {
+		limit = defaultUndoLimit
+	}
+	if store == nil {
+		store = NewMemorySnapshotStore[T]()
+	}
+	return &UndoStack[T]{store: store, limit: limit}
+}
+
+// SetCooldown sets the minimum time between two pushes. Pushes that arrive
+// sooner are ignored, which keeps continuous edits (e.g. dragging a brush)
+// from flooding the history. The default is 0 (no cooldown).
+func (u *UndoStack[T]) SetCooldown(d time.Duration) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.cooldown = d
+}
+
+// Push records state as the new current state and clears the redo history.
+// It returns false if the push was skipped because of the cooldown.
+func (u *UndoStack[T]) Push(state T) (bool, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.cooldown > 0 && time.Since(u.lastPush) < u.cooldown {
+		return false, nil
+	}
+
+	key := fmt.Sprintf("state_%d.json", u.nextKey)
+	if err := u.store.Save(key, state); err != nil {
+		return false, fmt.Errorf("error saving undo state: %w", err)
+	}
+	u.nextKey++
+
+	u.discard(u.redo)
+	u.redo = u.redo[:0]
+
+	u.undo = append(u.undo, key)
+	if len(u.undo) > u.limit {
+		excess := len(u.undo) - u.limit
+		u.discard(u.undo[:excess])
+		u.undo = append(u.undo[:0], u.undo[excess:]...)
+	}
+
+	u.lastPush = time.Now()
+	return true, nil
+}
+
+// Undo moves back one step and returns the state that is now current.
+// It returns false if there is nothing to undo.
+func (u *UndoStack[T]) Undo() (T, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var zero T
+	if len(u.undo) < 2 {
+		return zero, false
+	}
+
+	state, err := u.store.Load(u.undo[len(u.undo)-2])
+	if err != nil {
+		log.Printf("Warning: Undo failed: %v", err)
+		return zero, false
+	}
+
+	current := u.undo[len(u.undo)-1]
+	u.undo = u.undo[:len(u.undo)-1]
+	u.redo = append(u.redo, current)
+	return state, true
+}
+
+// Redo moves forward one step and returns the state that is now current.
+// It returns false if there is nothing to redo.
+func (u *UndoStack[T]) Redo() (T, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var zero T
+	if len(u.redo) == 0 {
+		return zero, false
+	}
+
+	next := u.redo[len(u.redo)-1]
+	state, err := u.store.Load(next)
+	if err != nil {
+		log.Printf("Warning: Redo failed: %v", err)
+		return zero, false
+	}
+
+	u.redo = u.redo[:len(u.redo)-1]
+	u.undo = append(u.undo, next)
+	return state, true
+}
+
+// CanUndo reports whether Undo would succeed.
+func (u *UndoStack[T]) CanUndo() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return len(u.undo) >= 2
+}
+
+// CanRedo reports whether Redo would succeed.
+func (u *UndoStack[T]) CanRedo() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return len(u.redo) > 0
+}
+
+// Len returns the number of undo and redo snapshots currently kept.
+func (u *UndoStack[T]) Len() (undo, redo int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return len(u.undo), len(u.redo)
+}
+
+// Clear drops the whole history.
+func (u *UndoStack[T]) Clear() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.discard(u.undo)
+	u.discard(u.redo)
+	u.undo = u.undo[:0]
+	u.redo = u.redo[:0]
+	u.lastPush = time.Time{}
+}
+
+// discard deletes the given snapshots from the store. Callers hold u.mu.
+func (u *UndoStack[T]) discard(keys []string) {
+	for _, key := range keys {
+		if err := u.store.Delete(key); err != nil {
+			log.Printf("Warning: failed to remove undo state %s: %v", key, err)
+		}
+	}
+}
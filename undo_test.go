@@ -0,0 +1,102 @@
+package pigo8
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndoStack_UndoRedo(t *testing.T) {
+	u := NewUndoStack[int](0, nil)
+	assert.False(t, u.CanUndo())
+
+	for i := 1; i <= 3; i++ {
+		pushed, err := u.Push(i)
+		require.NoError(t, err)
+		assert.True(t, pushed)
+	}
+
+	state, ok := u.Undo()
+	assert.True(t, ok)
+	assert.Equal(t, 2, state)
+	state, ok = u.Undo()
+	assert.True(t, ok)
+	assert.Equal(t, 1, state)
+	_, ok = u.Undo()
+	assert.False(t, ok, "the initial state cannot be undone")
+
+	state, ok = u.Redo()
+	assert.True(t, ok)
+	assert.Equal(t, 2, state)
+
+	// A new push invalidates the redo history.
+	_, err := u.Push(10)
+	require.NoError(t, err)
+	assert.False(t, u.CanRedo())
+	undo, redo := u.Len()
+	assert.Equal(t, 3, undo)
+	assert.Equal(t, 0, redo)
+}
+
+func TestUndoStack_Limit(t *testing.T) {
+	store := NewMemorySnapshotStore[int]()
+	u := NewUndoStack[int](3, store)
+	for i := 0; i < 10; i++ {
+		_, err := u.Push(i)
+		require.NoError(t, err)
+	}
+
+	undo, _ := u.Len()
+	assert.Equal(t, 3, undo)
+	assert.Len(t, store.states, 3, "evicted snapshots are deleted from the store")
+
+	state, _ := u.Undo()
+	assert.Equal(t, 8, state)
+	state, _ = u.Undo()
+	assert.Equal(t, 7, state)
+	_, ok := u.Undo()
+	assert.False(t, ok)
+}
+
+func TestUndoStack_Cooldown(t *testing.T) {
+	u := NewUndoStack[int](0, nil)
+	u.SetCooldown(time.Hour)
+
+	pushed, err := u.Push(1)
+	require.NoError(t, err)
+	assert.True(t, pushed)
+
+	pushed, err = u.Push(2)
+	require.NoError(t, err)
+	assert.False(t, pushed, "push within the cooldown is skipped")
+}
+
+func TestUndoStack_AferoStore(t *testing.T) {
+	type state struct {
+		Pixels [4]int
+		Name   string
+	}
+
+	fs := afero.NewMemMapFs()
+	u := NewUndoStack[state](0, NewAferoSnapshotStore[state](fs))
+
+	s := state{Name: "a"}
+	_, err := u.Push(s)
+	require.NoError(t, err)
+	s.Pixels[0] = 5
+	s.Name = "b"
+	_, err = u.Push(s)
+	require.NoError(t, err)
+
+	prev, ok := u.Undo()
+	require.True(t, ok)
+	assert.Equal(t, state{Name: "a"}, prev)
+
+	u.Clear()
+	files, err := afero.ReadDir(fs, "/")
+	require.NoError(t, err)
+	assert.Empty(t, files, "Clear removes snapshot files")
+}
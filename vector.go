@@ -44,6 +44,11 @@ func (v Vector2D) Magnitude() float64 {
 	return math.Sqrt(v.X*v.X + v.Y*v.Y)
 }
 
+// Length returns the length of this vector. It is the same as Magnitude.
+func (v Vector2D) Length() float64 {
+	return v.Magnitude()
+}
+
 // Normalize returns a new vector in the same direction but with a length of 1
 // If the vector has zero length, it returns a zero vector
 func (v Vector2D) Normalize() Vector2D {
@@ -89,6 +94,28 @@ func (v Vector2D) AngleBetween(other Vector2D) float64 {
 	return math.Acos(cosTheta)
 }
 
+// Rotate returns this vector rotated by the given number of turns (1 = 360
+// degrees), using the PICO-8 angle convention: on screen, where y grows
+// downwards, positive turns rotate counter-clockwise.
+//
+// Example:
+//
+//	right := NewVector2D(1, 0)
+//	up := right.Rotate(0.25) // (0, -1)
+func (v Vector2D) Rotate(turns float64) Vector2D {
+	sin, cos := math.Sincos(turns * 2 * math.Pi)
+	return Vector2D{
+		X: v.X*cos + v.Y*sin,
+		Y: -v.X*sin + v.Y*cos,
+	}
+}
+
+// Lerp returns the point a fraction t of the way from this vector to
+// another, see LerpVector.
+func (v Vector2D) Lerp(other Vector2D, t float64) Vector2D {
+	return LerpVector(v, other, t)
+}
+
 // ToInt returns a new vector with the components rounded to integers
 func (v Vector2D) ToInt() (int, int) {
 	return int(math.Round(v.X)), int(math.Round(v.Y))
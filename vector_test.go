@@ -0,0 +1,49 @@
+package pigo8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertVector checks both components of a vector within a small tolerance.
+func assertVector(t *testing.T, want, got Vector2D, msg string) {
+	t.Helper()
+	assert.InDelta(t, want.X, got.X, 1e-9, "%s: X", msg)
+	assert.InDelta(t, want.Y, got.Y, 1e-9, "%s: Y", msg)
+}
+
+func TestVector2DArithmetic(t *testing.T) {
+	a := NewVector2D(3, 4)
+	b := NewVector2D(1, -2)
+
+	assert.Equal(t, NewVector2D(4, 2), a.Add(b))
+	assert.Equal(t, NewVector2D(2, 6), a.Sub(b))
+	assert.Equal(t, NewVector2D(6, 8), a.Scale(2))
+	assert.Equal(t, -5.0, a.Dot(b))
+	assert.Equal(t, 5.0, a.Length())
+	assert.Equal(t, a.Magnitude(), a.Length(), "Length should match Magnitude")
+	assert.InDelta(t, 6.3245553, a.Distance(b), 1e-6)
+}
+
+func TestVector2DNormalize(t *testing.T) {
+	assertVector(t, NewVector2D(0.6, 0.8), NewVector2D(3, 4).Normalize(), "normalized (3,4)")
+	assert.Equal(t, ZeroVector(), ZeroVector().Normalize(), "zero vector should stay zero")
+}
+
+func TestVector2DRotate(t *testing.T) {
+	right := NewVector2D(1, 0)
+	assertVector(t, NewVector2D(0, -1), right.Rotate(0.25), "quarter turn points up on screen")
+	assertVector(t, NewVector2D(-1, 0), right.Rotate(0.5), "half turn")
+	assertVector(t, NewVector2D(0, 1), right.Rotate(-0.25), "negative quarter turn points down")
+	assertVector(t, right, right.Rotate(1), "full turn")
+	assert.InDelta(t, 5, NewVector2D(3, 4).Rotate(0.1).Length(), 1e-9, "rotation keeps the length")
+}
+
+func TestVector2DLerp(t *testing.T) {
+	a := NewVector2D(0, 10)
+	b := NewVector2D(10, 0)
+	assert.Equal(t, a, a.Lerp(b, 0))
+	assert.Equal(t, NewVector2D(5, 5), a.Lerp(b, 0.5))
+	assert.Equal(t, b, a.Lerp(b, 1))
+}